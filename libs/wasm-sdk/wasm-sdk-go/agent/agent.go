@@ -0,0 +1,263 @@
+// Package agent runs a bounded tool-using loop around a chat model: call the
+// model, dispatch the tool calls it asks for to Go functions, feed the
+// results back and repeat until it answers or MaxSteps is reached.
+//
+//	a := agent.Agent{
+//		Bit:    bit,
+//		System: "You answer questions about orders.",
+//		Tools: []agent.Tool{{
+//			Name:        "get_order",
+//			Description: "Look up an order by ID",
+//			Parameters:  `{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`,
+//			Run: func(ctx *sdk.Context, args sdk.RawValue) (string, error) {
+//				id, _ := args.Field("id")
+//				return lookupOrder(ctx, id.String())
+//			},
+//		}},
+//	}
+//	res, err := a.Run(ctx, question)
+//
+// Tool failures do not end the run: unknown tools, invalid arguments, errors
+// and panics are reported to the model as the tool's result so it can
+// recover. Each step is streamed as JSON when the node streams.
+package agent
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+var (
+	// ErrModel is returned when a chat completion could not be run.
+	ErrModel = errors.New("agent: model call failed")
+	// ErrMaxSteps is returned when the model still wanted tools after
+	// MaxSteps rounds. The Result holds the steps taken so far.
+	ErrMaxSteps = errors.New("agent: step limit reached")
+	// ErrLoop is returned when the model repeats the same tool call with the
+	// same arguments instead of making progress.
+	ErrLoop = errors.New("agent: model repeats the same tool call")
+)
+
+// DefaultMaxSteps bounds the model rounds of a run.
+const DefaultMaxSteps = 8
+
+// DefaultMaxResultLen caps the tool result passed back to the model.
+const DefaultMaxResultLen = 16 * 1024
+
+// maxRepeats is how often an identical call may be made before ErrLoop.
+const maxRepeats = 3
+
+// Tool is a Go function the model can call. Parameters is the JSON Schema of
+// the arguments object; arguments are validated against it before Run.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  string
+	Run         func(ctx *sdk.Context, args sdk.RawValue) (string, error)
+}
+
+// StepKind tells what a Step records.
+type StepKind string
+
+const (
+	StepThought    StepKind = "thought"     // text the model produced alongside tool calls
+	StepToolCall   StepKind = "tool_call"   // a tool the model asked for
+	StepToolResult StepKind = "tool_result" // what the tool returned, or its error
+	StepAnswer     StepKind = "answer"      // the final answer
+)
+
+// Step is one event of a run.
+type Step struct {
+	Round     int
+	Kind      StepKind
+	Tool      string
+	Arguments string
+	Content   string
+	Err       string
+}
+
+// ToJSON encodes the step as streamed to the UI.
+func (s *Step) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"type":"agent_step","round":`)
+	b.WriteString(strconv.Itoa(s.Round))
+	b.WriteString(`,"kind":`)
+	b.WriteString(sdk.JSONString(string(s.Kind)))
+	if s.Tool != "" {
+		b.WriteString(`,"tool":`)
+		b.WriteString(sdk.JSONString(s.Tool))
+	}
+	if s.Arguments != "" {
+		b.WriteString(`,"arguments":`)
+		b.WriteString(sdk.JSONString(s.Arguments))
+	}
+	if s.Content != "" {
+		b.WriteString(`,"content":`)
+		b.WriteString(sdk.JSONString(s.Content))
+	}
+	if s.Err != "" {
+		b.WriteString(`,"error":`)
+		b.WriteString(sdk.JSONString(s.Err))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// Result is the outcome of a run.
+type Result struct {
+	Answer           string
+	Steps            []Step
+	Messages         []sdk.ChatMessage // full transcript, to continue a conversation
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// Agent configures the loop. Bit is required.
+type Agent struct {
+	Bit         string // raw Bit JSON of a chat model with tool support
+	System      string
+	Tools       []Tool
+	MaxSteps    int // model rounds; 0 means DefaultMaxSteps
+	Temperature float64
+	MaxTokens   int
+	// MaxResultLen truncates tool results; 0 means DefaultMaxResultLen.
+	MaxResultLen int
+	// OnStep is called for every step, after it is streamed.
+	OnStep func(Step)
+}
+
+// Run answers prompt, calling tools as the model requests.
+func (a *Agent) Run(ctx *sdk.Context, prompt string) (Result, error) {
+	return a.RunMessages(ctx, []sdk.ChatMessage{{Role: sdk.RoleUser, Content: prompt}})
+}
+
+// RunMessages continues a conversation. System is prepended unless the
+// messages already start with a system message.
+func (a *Agent) RunMessages(ctx *sdk.Context, messages []sdk.ChatMessage) (Result, error) {
+	var res Result
+	if a.System != "" && (len(messages) == 0 || messages[0].Role != sdk.RoleSystem) {
+		messages = append([]sdk.ChatMessage{{Role: sdk.RoleSystem, Content: a.System}}, messages...)
+	}
+	specs := make([]sdk.ToolSpec, len(a.Tools))
+	tools := make(map[string]*Tool, len(a.Tools))
+	for i := range a.Tools {
+		t := &a.Tools[i]
+		specs[i] = sdk.ToolSpec{Name: t.Name, Description: t.Description, Parameters: t.Parameters}
+		tools[t.Name] = t
+	}
+	maxSteps := a.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = DefaultMaxSteps
+	}
+	seen := make(map[string]int)
+
+	for round := 1; round <= maxSteps; round++ {
+		resp, err := ctx.Host().ChatComplete(a.Bit, sdk.ChatRequest{
+			Messages:    messages,
+			Temperature: a.Temperature,
+			MaxTokens:   a.MaxTokens,
+			Tools:       specs,
+		})
+		if err != nil {
+			ctx.Warn("agent: " + err.Error())
+			res.Messages = messages
+			return res, ErrModel
+		}
+		res.PromptTokens += resp.PromptTokens
+		res.CompletionTokens += resp.CompletionTokens
+		messages = append(messages, sdk.ChatMessage{
+			Role:      sdk.RoleAssistant,
+			Content:   resp.Content,
+			ToolCalls: resp.ToolCalls,
+		})
+
+		if len(resp.ToolCalls) == 0 {
+			res.Answer = resp.Content
+			a.step(ctx, &res, Step{Round: round, Kind: StepAnswer, Content: resp.Content})
+			res.Messages = messages
+			return res, nil
+		}
+		if strings.TrimSpace(resp.Content) != "" {
+			a.step(ctx, &res, Step{Round: round, Kind: StepThought, Content: resp.Content})
+		}
+		for _, call := range resp.ToolCalls {
+			a.step(ctx, &res, Step{Round: round, Kind: StepToolCall, Tool: call.Name, Arguments: call.Arguments})
+			key := call.Name + "\x00" + call.Arguments
+			seen[key]++
+			if seen[key] > maxRepeats {
+				res.Messages = messages
+				return res, ErrLoop
+			}
+			out, err := a.dispatch(ctx, tools[call.Name], call)
+			result := Step{Round: round, Kind: StepToolResult, Tool: call.Name, Content: out}
+			if err != nil {
+				result.Err = err.Error()
+				out = "error: " + err.Error()
+			}
+			a.step(ctx, &res, result)
+			messages = append(messages, sdk.ChatMessage{Role: sdk.RoleTool, Content: out, ToolCallID: call.ID})
+		}
+	}
+	res.Messages = messages
+	return res, ErrMaxSteps
+}
+
+// dispatch validates the arguments and runs the tool, turning panics into
+// errors so one broken tool cannot take down the run.
+func (a *Agent) dispatch(ctx *sdk.Context, t *Tool, call sdk.ToolCall) (out string, err error) {
+	if t == nil || t.Run == nil {
+		return "", errors.New("unknown tool " + strconv.Quote(call.Name))
+	}
+	raw := strings.TrimSpace(call.Arguments)
+	if raw == "" {
+		raw = "{}"
+	}
+	args := sdk.NewRawValue(raw)
+	if args.Kind() != sdk.KindObject {
+		return "", errors.New("arguments must be a JSON object")
+	}
+	if t.Parameters != "" {
+		if v := sdk.ValidateSchema(t.Parameters, args); len(v) > 0 {
+			parts := make([]string, len(v))
+			for i := range v {
+				parts[i] = v[i].String()
+			}
+			return "", errors.New("invalid arguments: " + strings.Join(parts, "; "))
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			out, err = "", errors.New("tool panicked: "+panicMessage(r))
+		}
+	}()
+	out, err = t.Run(ctx, args)
+	limit := a.MaxResultLen
+	if limit <= 0 {
+		limit = DefaultMaxResultLen
+	}
+	if len(out) > limit {
+		out = out[:limit] + "\n[truncated]"
+	}
+	return out, err
+}
+
+func (a *Agent) step(ctx *sdk.Context, res *Result, s Step) {
+	res.Steps = append(res.Steps, s)
+	ctx.StreamJSON(s.ToJSON())
+	if a.OnStep != nil {
+		a.OnStep(s)
+	}
+}
+
+func panicMessage(r any) string {
+	switch v := r.(type) {
+	case error:
+		return v.Error()
+	case string:
+		return v
+	}
+	return "unknown panic"
+}
@@ -0,0 +1,114 @@
+package sdk
+
+import "strconv"
+
+// AppInfo describes the app a run belongs to. Metadata holds the free-form
+// key-value pairs configured in the app settings, e.g. a cost center.
+type AppInfo struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Tags        []string          `json:"tags"`
+	Metadata    map[string]string `json:"metadata"`
+	CreatedAt   int64             `json:"created_at"` // Unix milliseconds
+}
+
+// AppMember is a user with access to the app.
+type AppMember struct {
+	UserID string   `json:"user_id"`
+	Name   string   `json:"name"`
+	Email  string   `json:"email"`
+	Roles  []string `json:"roles"`
+}
+
+// HasRole reports whether the member holds role.
+func (m *AppMember) HasRole(role string) bool {
+	for _, r := range m.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAppInfo returns the name and metadata of the current app.
+func GetAppInfo() (AppInfo, bool) {
+	packed := hostAppInfo()
+	if packed == -1 {
+		return AppInfo{}, false
+	}
+	f := jsonObjectFields(unpackString(packed))
+	if f == nil {
+		return AppInfo{}, false
+	}
+	created, _ := strconv.ParseInt(f["created_at"], 10, 64)
+	return AppInfo{
+		ID:          jsonUnquote(f["id"]),
+		Name:        jsonUnquote(f["name"]),
+		Description: jsonUnquote(f["description"]),
+		Tags:        parseStringArrayJSON(f["tags"]),
+		Metadata:    parseStringMapJSON(f["metadata"]),
+		CreatedAt:   created,
+	}, true
+}
+
+// ListAppMembers returns the members of the current app, only those holding
+// role unless it is "".
+func ListAppMembers(role string) ([]AppMember, bool) {
+	p, l := stringToPtr(role)
+	packed := hostAppMembers(p, l)
+	if packed == -1 {
+		return nil, false
+	}
+	items := jsonArrayItems(unpackString(packed))
+	members := make([]AppMember, 0, len(items))
+	for _, item := range items {
+		f := jsonObjectFields(item)
+		if f == nil {
+			continue
+		}
+		members = append(members, AppMember{
+			UserID: jsonUnquote(f["user_id"]),
+			Name:   jsonUnquote(f["name"]),
+			Email:  jsonUnquote(f["email"]),
+			Roles:  parseStringArrayJSON(f["roles"]),
+		})
+	}
+	return members, true
+}
+
+// Deprecated: Use c.Host().GetAppInfo, which reports why the call failed.
+func (c *Context) GetAppInfo() (AppInfo, bool) {
+	info, err := c.Host().GetAppInfo()
+	return info, err == nil
+}
+
+// Deprecated: Use c.Host().ListAppMembers, which reports why the call failed.
+func (c *Context) ListAppMembers(role string) ([]AppMember, bool) {
+	members, err := c.Host().ListAppMembers(role)
+	return members, err == nil
+}
+
+func (h HostCalls) GetAppInfo() (AppInfo, error) {
+	if !h.c.host("") {
+		return AppInfo{}, dryRunSkipped("GetAppInfo")
+	}
+	info, ok := GetAppInfo()
+	if !ok {
+		return AppInfo{}, hostFailure("GetAppInfo", ErrCodeInternal)
+	}
+	return info, nil
+}
+
+// ListAppMembers needs the members permission, since it reveals names and
+// email addresses of other users.
+func (h HostCalls) ListAppMembers(role string) ([]AppMember, error) {
+	if !h.c.host(PermMembers) {
+		return nil, dryRunSkipped("ListAppMembers")
+	}
+	members, ok := ListAppMembers(role)
+	if !ok {
+		return nil, hostFailure("ListAppMembers", ErrCodeInternal)
+	}
+	return members, nil
+}
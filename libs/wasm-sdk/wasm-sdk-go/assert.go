@@ -0,0 +1,139 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CategoryAssertions is the node category board test tooling lists
+// assertion nodes under.
+const CategoryAssertions = "Testing/Assertions"
+
+// Assertion kinds.
+const (
+	AssertEquals   = "equals"
+	AssertContains = "contains"
+	AssertSchema   = "schema"
+	AssertCustom   = "custom"
+)
+
+// AssertionResult is the outcome of one check of an assertion node. The
+// runtime collects the results of every assertion node in a board test run
+// into the test report. Expected and Actual are raw JSON.
+type AssertionResult struct {
+	Name     string
+	Kind     string
+	Passed   bool
+	Expected string
+	Actual   string
+	Message  string
+}
+
+func (a *AssertionResult) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"name":`)
+	b.WriteString(jsonString(a.Name))
+	b.WriteString(`,"kind":`)
+	b.WriteString(jsonString(a.Kind))
+	b.WriteString(`,"passed":`)
+	b.WriteString(strconv.FormatBool(a.Passed))
+	if a.Expected != "" {
+		b.WriteString(`,"expected":`)
+		b.WriteString(a.Expected)
+	}
+	if a.Actual != "" {
+		b.WriteString(`,"actual":`)
+		b.WriteString(a.Actual)
+	}
+	if a.Message != "" {
+		b.WriteString(`,"message":`)
+		b.WriteString(jsonString(a.Message))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// ExpectEquals passes when actual is structurally equal to expected: object
+// key order and insignificant whitespace do not matter.
+func ExpectEquals(name string, expected, actual RawValue) AssertionResult {
+	r := AssertionResult{Name: name, Kind: AssertEquals, Expected: expected.Raw(), Actual: actual.Raw()}
+	r.Passed = jsonEqual(expected, actual)
+	if !r.Passed {
+		r.Message = "values differ"
+	}
+	return r
+}
+
+// ExpectContains passes when actual contains needle: a substring of a
+// string, an element of an array, or a subset of the fields of an object.
+func ExpectContains(name string, actual, needle RawValue) AssertionResult {
+	r := AssertionResult{Name: name, Kind: AssertContains, Expected: needle.Raw(), Actual: actual.Raw()}
+	switch actual.Kind() {
+	case KindString:
+		r.Passed = needle.Kind() == KindString && strings.Contains(actual.String(), needle.String())
+	case KindArray:
+		for _, item := range actual.Items() {
+			if jsonEqual(item, needle) {
+				r.Passed = true
+				break
+			}
+		}
+	case KindObject:
+		if needle.Kind() == KindObject {
+			r.Passed = true
+			for key, want := range needle.Fields() {
+				if got, ok := actual.Field(key); !ok || !jsonEqual(got, want) {
+					r.Passed = false
+					r.Message = "field " + strconv.Quote(key) + " missing or different"
+					break
+				}
+			}
+		}
+	}
+	if !r.Passed && r.Message == "" {
+		r.Message = "value not found"
+	}
+	return r
+}
+
+// ExpectSchemaValid passes when value matches the JSON Schema; the message
+// lists the violations otherwise.
+func ExpectSchemaValid(name, schema string, value RawValue) AssertionResult {
+	r := AssertionResult{Name: name, Kind: AssertSchema, Expected: schema, Actual: value.Raw()}
+	violations := ValidateSchema(schema, value)
+	r.Passed = len(violations) == 0
+	if !r.Passed {
+		parts := make([]string, len(violations))
+		for i, v := range violations {
+			parts[i] = v.String()
+		}
+		r.Message = strings.Join(parts, "; ")
+	}
+	return r
+}
+
+// Expect records a custom check.
+func Expect(name string, passed bool, message string) AssertionResult {
+	return AssertionResult{Name: name, Kind: AssertCustom, Passed: passed, Message: message}
+}
+
+// Assert records r in the result of the run and returns r.Passed. Failed
+// assertions are logged but do not fail the node: the runtime aggregates
+// them into the board test report. Use AssertionsPassed to branch on them.
+func (c *Context) Assert(r AssertionResult) bool {
+	c.result.Assertions = append(c.result.Assertions, r)
+	if !r.Passed {
+		c.Warn("assertion " + strconv.Quote(r.Name) + " failed: " + r.Message)
+	}
+	return r.Passed
+}
+
+// AssertionsPassed reports whether every assertion recorded so far passed.
+func (c *Context) AssertionsPassed() bool {
+	for i := range c.result.Assertions {
+		if !c.result.Assertions[i].Passed {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultAttachmentsPin is the input a chat event delivers uploads on.
+const DefaultAttachmentsPin = "attachments"
+
+// AttachmentKind is the broad media class of a ChatAttachment.
+type AttachmentKind string
+
+const (
+	AttachmentImage AttachmentKind = "image"
+	AttachmentPDF   AttachmentKind = "pdf"
+	AttachmentAudio AttachmentKind = "audio"
+	AttachmentVideo AttachmentKind = "video"
+	AttachmentText  AttachmentKind = "text"
+	AttachmentFile  AttachmentKind = "file"
+)
+
+// ChatAttachmentSchema is the JSON Schema of one attachment as delivered
+// with chat-triggered runs. Hosts may also send a bare URL string.
+const ChatAttachmentSchema = `{"type":"object","properties":{` +
+	`"url":{"type":"string"},"path":{"type":"string"},"name":{"type":"string"},` +
+	`"type":{"type":"string"},"size":{"type":"integer"},` +
+	`"preview_text":{"type":"string"},"thumbnail_url":{"type":"string"},` +
+	`"page":{"type":"integer"}}}`
+
+// ChatAttachment is a file the user uploaded with a chat message. Path is
+// its location in flow storage when the host stored the upload; URL is a
+// (possibly signed, short-lived) download link. Either may be empty.
+type ChatAttachment struct {
+	Name         string `json:"name"`
+	MimeType     string `json:"type"`
+	Size         int64  `json:"size"`
+	URL          string `json:"url"`
+	Path         string `json:"path"`
+	PreviewText  string `json:"preview_text,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	Page         int    `json:"page,omitempty"`
+}
+
+// Kind classifies the attachment by its MIME type, falling back to the file
+// extension of Name, Path or URL.
+func (a *ChatAttachment) Kind() AttachmentKind {
+	mime := strings.ToLower(a.MimeType)
+	if i := strings.IndexByte(mime, ';'); i >= 0 {
+		mime = strings.TrimSpace(mime[:i])
+	}
+	switch {
+	case strings.HasPrefix(mime, "image/"):
+		return AttachmentImage
+	case mime == "application/pdf":
+		return AttachmentPDF
+	case strings.HasPrefix(mime, "audio/"):
+		return AttachmentAudio
+	case strings.HasPrefix(mime, "video/"):
+		return AttachmentVideo
+	case strings.HasPrefix(mime, "text/"):
+		return AttachmentText
+	case mime != "" && mime != "application/octet-stream":
+		return AttachmentFile
+	}
+	for _, name := range []string{a.Name, a.Path, a.URL} {
+		if kind, ok := attachmentKindByExt(name); ok {
+			return kind
+		}
+	}
+	return AttachmentFile
+}
+
+func (a *ChatAttachment) IsImage() bool { return a.Kind() == AttachmentImage }
+func (a *ChatAttachment) IsPDF() bool   { return a.Kind() == AttachmentPDF }
+func (a *ChatAttachment) IsAudio() bool { return a.Kind() == AttachmentAudio }
+
+func (a *ChatAttachment) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"name":`)
+	b.WriteString(jsonString(a.Name))
+	b.WriteString(`,"type":`)
+	b.WriteString(jsonString(a.MimeType))
+	b.WriteString(`,"size":`)
+	b.WriteString(strconv.FormatInt(a.Size, 10))
+	b.WriteString(`,"url":`)
+	b.WriteString(jsonString(a.URL))
+	b.WriteString(`,"path":`)
+	b.WriteString(jsonString(a.Path))
+	if a.PreviewText != "" {
+		b.WriteString(`,"preview_text":`)
+		b.WriteString(jsonString(a.PreviewText))
+	}
+	if a.ThumbnailURL != "" {
+		b.WriteString(`,"thumbnail_url":`)
+		b.WriteString(jsonString(a.ThumbnailURL))
+	}
+	if a.Page != 0 {
+		b.WriteString(`,"page":`)
+		b.WriteString(strconv.Itoa(a.Page))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// ChatAttachmentsPin declares the attachments input of a chat node.
+func ChatAttachmentsPin() PinDefinition {
+	return InputPin(DefaultAttachmentsPin, "Attachments", "Files uploaded with the chat message", DataTypeStruct).
+		WithValueType("Array").
+		WithSchema(ChatAttachmentSchema)
+}
+
+// ChatAttachments reads the attachments delivered on the given input, or on
+// DefaultAttachmentsPin when pin is "". Bare URL strings become attachments
+// with only URL set; entries that are neither are skipped.
+func (c *Context) ChatAttachments(pin string) []ChatAttachment {
+	if pin == "" {
+		pin = DefaultAttachmentsPin
+	}
+	raw, ok := c.lookup(pin)
+	if !ok {
+		return nil
+	}
+	return parseChatAttachmentsJSON(raw)
+}
+
+// Deprecated: Use c.Host().OpenAttachment, which reports why the call failed.
+func (c *Context) OpenAttachment(a ChatAttachment) (*StorageReader, bool) {
+	r, err := c.Host().OpenAttachment(a)
+	return r, err == nil
+}
+
+// OpenAttachment streams an attachment stored in flow storage. It fails with
+// ErrNotFound when the attachment has no Path; fetch URL instead.
+func (h HostCalls) OpenAttachment(a ChatAttachment) (*StorageReader, error) {
+	if a.Path == "" {
+		return nil, &HostError{Op: "OpenAttachment", Code: ErrCodeNotFound, Message: "attachment is not in flow storage"}
+	}
+	return h.OpenStorageReader(a.Path)
+}
+
+func parseChatAttachmentsJSON(s string) []ChatAttachment {
+	var out []ChatAttachment
+	for _, item := range jsonArrayItems(s) {
+		if strings.HasPrefix(item, `"`) {
+			out = append(out, ChatAttachment{URL: jsonUnquote(item)})
+			continue
+		}
+		f := jsonObjectFields(item)
+		if f == nil {
+			continue
+		}
+		size, _ := strconv.ParseInt(f["size"], 10, 64)
+		page, _ := strconv.Atoi(f["page"])
+		// Hosts send the storage location as a path string or a FlowPath object.
+		path := f["path"]
+		if strings.HasPrefix(path, "{") {
+			path = jsonObjectFields(path)["path"]
+		}
+		out = append(out, ChatAttachment{
+			Name:         jsonUnquote(f["name"]),
+			MimeType:     jsonUnquote(f["type"]),
+			Size:         size,
+			URL:          jsonUnquote(f["url"]),
+			Path:         jsonUnquote(path),
+			PreviewText:  jsonUnquote(f["preview_text"]),
+			ThumbnailURL: jsonUnquote(f["thumbnail_url"]),
+			Page:         page,
+		})
+	}
+	return out
+}
+
+func attachmentKindByExt(name string) (AttachmentKind, bool) {
+	if i := strings.IndexAny(name, "?#"); i >= 0 {
+		name = name[:i]
+	}
+	dot := strings.LastIndexByte(name, '.')
+	if dot < 0 || strings.ContainsRune(name[dot:], '/') {
+		return "", false
+	}
+	switch strings.ToLower(name[dot+1:]) {
+	case "png", "jpg", "jpeg", "gif", "webp", "bmp", "svg", "heic", "tif", "tiff":
+		return AttachmentImage, true
+	case "pdf":
+		return AttachmentPDF, true
+	case "mp3", "wav", "ogg", "oga", "m4a", "flac", "aac", "opus", "weba":
+		return AttachmentAudio, true
+	case "mp4", "webm", "mov", "mkv", "avi":
+		return AttachmentVideo, true
+	case "txt", "md", "csv", "json", "html", "xml":
+		return AttachmentText, true
+	}
+	return "", false
+}
@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrAuditFailed is returned when the host did not accept an audit entry.
+// Nodes that must not act without a record should stop on it.
+var ErrAuditFailed = errors.New("audit entry was not recorded")
+
+// AuditRecord identifies an entry in the platform's audit trail. The host
+// stamps the actor, app, board, run and node, and chains each entry to the
+// previous one: Hash covers the entry and PrevHash, so any later change to
+// the trail is detectable.
+type AuditRecord struct {
+	ID        string
+	Timestamp int64 // Unix milliseconds
+	Hash      string
+	PrevHash  string
+}
+
+// Audit appends an entry to the audit trail: action is what happened
+// ("export", "permission.grant"), target what it happened to and detail
+// any further facts. Unlike Info, the entry is kept by the platform
+// independently of log retention.
+func Audit(action, target string, detail map[string]string) (AuditRecord, bool) {
+	ap, al := stringToPtr(action)
+	tp, tl := stringToPtr(target)
+	dp, dl := stringToPtr(jsonStringMap(detail))
+	packed := hostAuditRecord(ap, al, tp, tl, dp, dl)
+	if packed == -1 {
+		return AuditRecord{}, false
+	}
+	f := jsonObjectFields(unpackString(packed))
+	if f == nil {
+		return AuditRecord{}, false
+	}
+	ts, _ := strconv.ParseInt(f["timestamp"], 10, 64)
+	return AuditRecord{
+		ID:        jsonUnquote(f["id"]),
+		Timestamp: ts,
+		Hash:      jsonUnquote(f["hash"]),
+		PrevHash:  jsonUnquote(f["prev_hash"]),
+	}, true
+}
+
+// Audit records action on target for the user and run of this context.
+// Record the entry before the action takes effect where possible:
+//
+//	if _, err := ctx.Audit("export", path, map[string]string{"format": "csv"}); err != nil {
+//		return ctx.Result(err)
+//	}
+func (c *Context) Audit(action, target string, detail map[string]string) (AuditRecord, error) {
+	if !c.host("") {
+		return AuditRecord{}, ErrAuditFailed
+	}
+	rec, ok := Audit(action, target, detail)
+	if !ok {
+		c.Error("audit: could not record " + action + " on " + target)
+		return AuditRecord{}, ErrAuditFailed
+	}
+	return rec, nil
+}
@@ -0,0 +1,268 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Attendee is a participant of a CalendarEvent. Response is "accepted",
+// "declined", "tentative" or "needs_action".
+type Attendee struct {
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email"`
+	Response string `json:"response,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// CalendarEvent is an event in a connected Google or Microsoft calendar.
+// Start and End are in Unix milliseconds; for all-day events they span whole
+// days in the calendar's time zone.
+type CalendarEvent struct {
+	ID          string     `json:"id,omitempty"`
+	CalendarID  string     `json:"calendar_id,omitempty"` // empty is the primary calendar
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Location    string     `json:"location,omitempty"`
+	Start       int64      `json:"start"`
+	End         int64      `json:"end"`
+	AllDay      bool       `json:"all_day,omitempty"`
+	Organizer   string     `json:"organizer,omitempty"`
+	Attendees   []Attendee `json:"attendees,omitempty"`
+	MeetingURL  string     `json:"meeting_url,omitempty"`
+}
+
+func (e *CalendarEvent) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"title":`)
+	b.WriteString(jsonString(e.Title))
+	b.WriteString(`,"start":`)
+	b.WriteString(strconv.FormatInt(e.Start, 10))
+	b.WriteString(`,"end":`)
+	b.WriteString(strconv.FormatInt(e.End, 10))
+	for _, f := range [][2]string{
+		{"id", e.ID}, {"calendar_id", e.CalendarID}, {"description", e.Description},
+		{"location", e.Location}, {"organizer", e.Organizer}, {"meeting_url", e.MeetingURL},
+	} {
+		if f[1] != "" {
+			b.WriteString(`,"` + f[0] + `":`)
+			b.WriteString(jsonString(f[1]))
+		}
+	}
+	if e.AllDay {
+		b.WriteString(`,"all_day":true`)
+	}
+	if len(e.Attendees) > 0 {
+		b.WriteString(`,"attendees":[`)
+		for i, a := range e.Attendees {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(`{"email":`)
+			b.WriteString(jsonString(a.Email))
+			if a.Name != "" {
+				b.WriteString(`,"name":`)
+				b.WriteString(jsonString(a.Name))
+			}
+			if a.Response != "" {
+				b.WriteString(`,"response":`)
+				b.WriteString(jsonString(a.Response))
+			}
+			if a.Optional {
+				b.WriteString(`,"optional":true`)
+			}
+			b.WriteByte('}')
+		}
+		b.WriteByte(']')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// EventQuery selects events overlapping [From, To) (Unix milliseconds).
+// Text matches title, description and location.
+type EventQuery struct {
+	CalendarID string `json:"calendar_id,omitempty"`
+	From       int64  `json:"from"`
+	To         int64  `json:"to"`
+	Text       string `json:"text,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+}
+
+func (q *EventQuery) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"from":`)
+	b.WriteString(strconv.FormatInt(q.From, 10))
+	b.WriteString(`,"to":`)
+	b.WriteString(strconv.FormatInt(q.To, 10))
+	if q.CalendarID != "" {
+		b.WriteString(`,"calendar_id":`)
+		b.WriteString(jsonString(q.CalendarID))
+	}
+	if q.Text != "" {
+		b.WriteString(`,"text":`)
+		b.WriteString(jsonString(q.Text))
+	}
+	if q.Limit != 0 {
+		b.WriteString(`,"limit":`)
+		b.WriteString(strconv.Itoa(q.Limit))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// Contact is an entry in a connected address book.
+type Contact struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Emails       []string `json:"emails"`
+	Phones       []string `json:"phones"`
+	Organization string   `json:"organization,omitempty"`
+	JobTitle     string   `json:"job_title,omitempty"`
+}
+
+// ListEvents returns the events matching q from the calendar of an OAuth
+// provider the user connected ("google", "microsoft"), ordered by start.
+// Requires the "calendar" permission; the host uses the provider's OAuth
+// token, so the node never handles it.
+func ListEvents(provider string, q EventQuery) ([]CalendarEvent, bool) {
+	pp, pl := stringToPtr(provider)
+	qp, ql := stringToPtr(q.ToJSON())
+	packed := hostCalendarListEvents(pp, pl, qp, ql)
+	if packed == -1 {
+		return nil, false
+	}
+	items := jsonArrayItems(unpackString(packed))
+	events := make([]CalendarEvent, 0, len(items))
+	for _, item := range items {
+		if e, ok := parseCalendarEventJSON(item); ok {
+			events = append(events, e)
+		}
+	}
+	return events, true
+}
+
+// CreateEvent adds e to the provider's calendar and returns it as stored,
+// with ID and MeetingURL filled in. Attendees receive the provider's
+// invitations.
+func CreateEvent(provider string, e CalendarEvent) (CalendarEvent, bool) {
+	pp, pl := stringToPtr(provider)
+	ep, el := stringToPtr(e.ToJSON())
+	packed := hostCalendarCreateEvent(pp, pl, ep, el)
+	if packed == -1 {
+		return CalendarEvent{}, false
+	}
+	return parseCalendarEventJSON(unpackString(packed))
+}
+
+// SearchContacts finds up to limit contacts whose name, email or phone
+// matches query. Requires the "contacts" permission.
+func SearchContacts(provider, query string, limit int) ([]Contact, bool) {
+	pp, pl := stringToPtr(provider)
+	qp, ql := stringToPtr(query)
+	packed := hostContactsSearch(pp, pl, qp, ql, int32(limit))
+	if packed == -1 {
+		return nil, false
+	}
+	items := jsonArrayItems(unpackString(packed))
+	contacts := make([]Contact, 0, len(items))
+	for _, item := range items {
+		f := jsonObjectFields(item)
+		if f == nil {
+			continue
+		}
+		contacts = append(contacts, Contact{
+			ID:           jsonUnquote(f["id"]),
+			Name:         jsonUnquote(f["name"]),
+			Emails:       parseStringArrayJSON(f["emails"]),
+			Phones:       parseStringArrayJSON(f["phones"]),
+			Organization: jsonUnquote(f["organization"]),
+			JobTitle:     jsonUnquote(f["job_title"]),
+		})
+	}
+	return contacts, true
+}
+
+// Deprecated: Use c.Host().ListEvents, which reports why the call failed.
+func (c *Context) ListEvents(provider string, q EventQuery) ([]CalendarEvent, bool) {
+	events, err := c.Host().ListEvents(provider, q)
+	return events, err == nil
+}
+
+// Deprecated: Use c.Host().CreateEvent, which reports why the call failed.
+func (c *Context) CreateEvent(provider string, e CalendarEvent) (CalendarEvent, bool) {
+	created, err := c.Host().CreateEvent(provider, e)
+	return created, err == nil
+}
+
+// Deprecated: Use c.Host().SearchContacts, which reports why the call failed.
+func (c *Context) SearchContacts(provider, query string, limit int) ([]Contact, bool) {
+	contacts, err := c.Host().SearchContacts(provider, query, limit)
+	return contacts, err == nil
+}
+
+func (h HostCalls) ListEvents(provider string, q EventQuery) ([]CalendarEvent, error) {
+	if !h.c.host(PermCalendar) {
+		return nil, dryRunSkipped("ListEvents")
+	}
+	events, ok := ListEvents(provider, q)
+	if !ok {
+		return nil, hostFailure("ListEvents", ErrCodeUpstream)
+	}
+	return events, nil
+}
+
+func (h HostCalls) CreateEvent(provider string, e CalendarEvent) (CalendarEvent, error) {
+	if !h.c.host(PermCalendar) {
+		return CalendarEvent{}, dryRunSkipped("CreateEvent")
+	}
+	created, ok := CreateEvent(provider, e)
+	if !ok {
+		return CalendarEvent{}, hostFailure("CreateEvent", ErrCodeUpstream)
+	}
+	return created, nil
+}
+
+func (h HostCalls) SearchContacts(provider, query string, limit int) ([]Contact, error) {
+	if !h.c.host(PermContacts) {
+		return nil, dryRunSkipped("SearchContacts")
+	}
+	contacts, ok := SearchContacts(provider, query, limit)
+	if !ok {
+		return nil, hostFailure("SearchContacts", ErrCodeUpstream)
+	}
+	return contacts, nil
+}
+
+func parseCalendarEventJSON(s string) (CalendarEvent, bool) {
+	f := jsonObjectFields(s)
+	if f == nil {
+		return CalendarEvent{}, false
+	}
+	start, _ := strconv.ParseInt(f["start"], 10, 64)
+	end, _ := strconv.ParseInt(f["end"], 10, 64)
+	e := CalendarEvent{
+		ID:          jsonUnquote(f["id"]),
+		CalendarID:  jsonUnquote(f["calendar_id"]),
+		Title:       jsonUnquote(f["title"]),
+		Description: jsonUnquote(f["description"]),
+		Location:    jsonUnquote(f["location"]),
+		Start:       start,
+		End:         end,
+		AllDay:      f["all_day"] == "true",
+		Organizer:   jsonUnquote(f["organizer"]),
+		MeetingURL:  jsonUnquote(f["meeting_url"]),
+	}
+	for _, item := range jsonArrayItems(f["attendees"]) {
+		a := jsonObjectFields(item)
+		if a == nil {
+			continue
+		}
+		e.Attendees = append(e.Attendees, Attendee{
+			Name:     jsonUnquote(a["name"]),
+			Email:    jsonUnquote(a["email"]),
+			Response: jsonUnquote(a["response"]),
+			Optional: a["optional"] == "true",
+		})
+	}
+	return e, true
+}
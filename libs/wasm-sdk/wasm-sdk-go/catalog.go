@@ -0,0 +1,101 @@
+package sdk
+
+import "strings"
+
+// AddTags adds store categories such as "crm" or "pdf" to the node. Tags are
+// lowercased and duplicates dropped, so packs can tag nodes from shared
+// lists.
+func (n *NodeDefinition) AddTags(tags ...string) *NodeDefinition {
+	n.Tags = appendUnique(n.Tags, tags, true)
+	return n
+}
+
+// AddKeywords adds free-text search terms, e.g. the names of the API
+// endpoints a node wraps, that match store searches without being shown as
+// tags.
+func (n *NodeDefinition) AddKeywords(keywords ...string) *NodeDefinition {
+	n.Keywords = appendUnique(n.Keywords, keywords, false)
+	return n
+}
+
+// SetAuthor attributes the node to author (a name, optionally followed by
+// "<email>") and links homepage, the project or documentation URL; homepage
+// may be empty.
+func (n *NodeDefinition) SetAuthor(author, homepage string) *NodeDefinition {
+	n.Author = author
+	n.Homepage = homepage
+	return n
+}
+
+// SetLicense sets the node's license as an SPDX expression, e.g. "MIT" or
+// "Apache-2.0 OR MIT". The store shows it next to the node and filters by
+// it.
+func (n *NodeDefinition) SetLicense(spdx string) *NodeDefinition {
+	n.License = spdx
+	return n
+}
+
+// ChangelogEntry holds the release notes of one version of a node.
+type ChangelogEntry struct {
+	Version string `json:"version"`
+	Notes   string `json:"notes"`
+}
+
+// SetChangelog records the release notes of version. Keep the calls of past
+// releases in Define so the changelog accumulates; the store and the board
+// editor show users the entries newer than the version they have:
+//
+//	def.SetChangelog("1.1.0", "Adds the `cc` input.").
+//		SetChangelog("1.2.0", "Retries rate-limited sends.")
+//
+// Setting a version again replaces its notes.
+func (n *NodeDefinition) SetChangelog(version, notes string) *NodeDefinition {
+	for i := range n.Changelog {
+		if n.Changelog[i].Version == version {
+			n.Changelog[i].Notes = notes
+			return n
+		}
+	}
+	n.Changelog = append(n.Changelog, ChangelogEntry{Version: version, Notes: notes})
+	return n
+}
+
+func changelogJSON(entries []ChangelogEntry) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, e := range entries {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(`{"version":`)
+		b.WriteString(jsonString(e.Version))
+		b.WriteString(`,"notes":`)
+		b.WriteString(jsonString(e.Notes))
+		b.WriteByte('}')
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+func appendUnique(list, items []string, lower bool) []string {
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if lower {
+			item = strings.ToLower(item)
+		}
+		if item == "" {
+			continue
+		}
+		dup := false
+		for _, have := range list {
+			if strings.EqualFold(have, item) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			list = append(list, item)
+		}
+	}
+	return list
+}
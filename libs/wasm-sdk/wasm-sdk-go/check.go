@@ -0,0 +1,198 @@
+package sdk
+
+import "strings"
+
+// Kinds of CheckIssue.
+const (
+	IssueUndeclaredPermission = "undeclared_permission"
+	IssueUnknownInput         = "unknown_input"
+	IssueUnknownOutput        = "unknown_output"
+	IssueUnknownExec          = "unknown_exec"
+	IssuePanic                = "panic"
+)
+
+// CheckIssue is an inconsistency between what a node declares and what its
+// handler did during a dry run.
+type CheckIssue struct {
+	Node   string `json:"node"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+func (i *CheckIssue) String() string {
+	return i.Node + ": " + i.Kind + ": " + i.Detail
+}
+
+func (i *CheckIssue) ToJSON() string {
+	return `{"node":` + jsonString(i.Node) + `,"kind":` + jsonString(i.Kind) +
+		`,"detail":` + jsonString(i.Detail) + `}`
+}
+
+// callTrace records what a handler touched during a dry run.
+type callTrace struct {
+	perms []string
+	reads []string
+}
+
+func (t *callTrace) use(perm string) {
+	if perm == "" {
+		return
+	}
+	for _, p := range t.perms {
+		if p == perm {
+			return
+		}
+	}
+	t.perms = append(t.perms, perm)
+}
+
+// host records a host capability use and reports whether the real host call
+// should be made. During a dry run nothing reaches the host and the calling
+// Context method returns its zero value. In deterministic runs, capabilities
+// that may vary between runs are reported once with a warning.
+func (c *Context) host(perm string) bool {
+	if c.trace == nil {
+		c.warnNondeterministic(perm)
+		return true
+	}
+	c.trace.use(perm)
+	return false
+}
+
+func (c *Context) lookup(name string) (string, bool) {
+	if c.trace != nil {
+		c.trace.reads = append(c.trace.reads, name)
+	}
+	v, ok := c.input.Inputs[name]
+	return v, ok
+}
+
+// DryRunInputs returns inputs built from the definition's default values,
+// suitable for CheckHandler.
+func DryRunInputs(def *NodeDefinition) map[string]string {
+	inputs := make(map[string]string)
+	for i := range def.Pins {
+		p := &def.Pins[i]
+		if p.PinType == "Input" && p.DefaultValue != nil {
+			inputs[p.Name] = *p.DefaultValue
+		}
+	}
+	return inputs
+}
+
+// CheckHandler dry-runs a handler with the given inputs and cross-references
+// what it did against its definition: host capabilities used without the
+// matching permission, reads of input pins that do not exist, and outputs or
+// exec pins that are not declared. Host calls made through the Context are
+// not executed; calls to the package-level host functions bypass the check.
+//
+// Only code paths taken for these inputs are covered, so call it with
+// several representative inputs before publishing.
+func CheckHandler(h NodeHandler, inputs map[string]string) []CheckIssue {
+	_, issues := CheckRun(h, ExecutionInput{Inputs: inputs, LogLevel: LogLevelDebug})
+	return issues
+}
+
+// CheckRun is CheckHandler for a complete ExecutionInput, for example a
+// deterministic one. It also returns the result of the dry run, so test
+// harnesses can inspect the outputs. A panic is reported as an IssuePanic.
+func CheckRun(h NodeHandler, input ExecutionInput) (ExecutionResult, []CheckIssue) {
+	def := h.Define()
+	input.NodeName = def.Name
+	ctx := NewContext(input)
+	ctx.BindDefinition(&def)
+	ctx.trace = &callTrace{}
+
+	var issues []CheckIssue
+	report := func(kind, detail string) {
+		issues = append(issues, CheckIssue{Node: def.Name, Kind: kind, Detail: detail})
+	}
+
+	result, panicked := runRecovered(h, ctx)
+	if panicked {
+		report(IssuePanic, "handler panicked during the dry run")
+	}
+
+	for _, perm := range ctx.trace.perms {
+		if !def.HasPermission(perm) {
+			report(IssueUndeclaredPermission, "uses "+perm+" without declaring the \""+perm+"\" permission")
+		}
+	}
+	seen := make(map[string]bool)
+	for _, name := range ctx.trace.reads {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if p := def.pin(name, "Input"); p == nil {
+			report(IssueUnknownInput, "reads input "+name+" which is not declared")
+		}
+	}
+	for name := range result.Outputs {
+		if p := def.pin(name, "Output"); p == nil || p.DataType == DataTypeExec {
+			report(IssueUnknownOutput, "sets output "+name+" which is not a declared data output")
+		}
+	}
+	for _, name := range result.ActivateExec {
+		if p := def.pin(name, "Output"); p == nil || p.DataType != DataTypeExec {
+			report(IssueUnknownExec, "activates "+name+" which is not a declared exec output")
+		}
+	}
+	return result, issues
+}
+
+func runRecovered(h NodeHandler, ctx *Context) (result ExecutionResult, panicked bool) {
+	defer func() {
+		if recover() != nil {
+			panicked = true
+			result = ctx.Finish()
+		}
+	}()
+	return h.Run(ctx), false
+}
+
+func (n *NodeDefinition) pin(name, pinType string) *PinDefinition {
+	for i := range n.Pins {
+		if n.Pins[i].Name == name && n.Pins[i].PinType == pinType {
+			return &n.Pins[i]
+		}
+	}
+	return nil
+}
+
+// Check dry-runs every registered node with its default input values and
+// returns all issues found. See CheckHandler.
+func (r *Registry) Check() []CheckIssue {
+	var issues []CheckIssue
+	for i, h := range r.handlers {
+		// Check against the registered definition, which includes the
+		// pack's shared permissions.
+		h = registeredHandler{h, r.defs[i]}
+		issues = append(issues, CheckHandler(h, DryRunInputs(&r.defs[i]))...)
+	}
+	return issues
+}
+
+type registeredHandler struct {
+	NodeHandler
+	def NodeDefinition
+}
+
+func (h registeredHandler) Define() NodeDefinition { return h.def }
+
+// CheckNodes serializes Check's issues as a JSON array and returns a packed
+// i64. Export it as check_nodes to let tooling verify a module before
+// publishing it.
+func (r *Registry) CheckNodes() int64 {
+	issues := r.Check()
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := range issues {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(issues[i].ToJSON())
+	}
+	b.WriteByte(']')
+	return PackResult(b.String())
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// buildProfiles are the recommended TinyGo flags per build profile. Node
+// modules never start goroutines, so every profile drops the scheduler.
+var buildProfiles = map[string][]string{
+	// Debug info and readable panics; fastest to iterate with.
+	"debug": {"-target", "wasm", "-opt", "1", "-scheduler", "none"},
+	// What the template ships: optimized for speed, no debug info.
+	"release": {"-target", "wasm", "-opt", "2", "-no-debug", "-scheduler", "none"},
+	// Smallest module: panics trap without a message.
+	"size": {"-target", "wasm", "-opt", "z", "-no-debug", "-scheduler", "none", "-panic", "trap"},
+}
+
+func profileNames() string {
+	names := make([]string, 0, len(buildProfiles))
+	for name := range buildProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func runBuild(args []string) error {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	dir := fs.String("dir", ".", "module source directory")
+	out := fs.String("o", "node.wasm", "output module, relative to -dir")
+	profile := fs.String("profile", "release", "build profile: "+profileNames())
+	budget := fs.String("budget", "", "fail if the module is larger than this, e.g. 512KiB or 2MB")
+	report := fs.Bool("report", false, "print the size of each package")
+	symbols := fs.Bool("symbols", false, "also write <out>.debug.wasm, the same build with debug info, for symbolize")
+	tinygo := fs.String("tinygo", "tinygo", "TinyGo binary")
+	ldflags := fs.String("ldflags", "", "extra linker flags, e.g. -X pkg.Var=value")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		return fmt.Errorf("build: unexpected arguments %v", fs.Args())
+	}
+	var limit int64
+	if *budget != "" {
+		var err error
+		if limit, err = parseSize(*budget); err != nil {
+			return fmt.Errorf("build: -budget: %w", err)
+		}
+	}
+
+	modulePath := *out
+	if !filepath.IsAbs(modulePath) {
+		modulePath = filepath.Join(*dir, modulePath)
+	}
+	output, err := tinygoBuild(*tinygo, *dir, modulePath, *profile, *ldflags, *report)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(modulePath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("built %s (%s profile): %s\n", modulePath, *profile, formatSize(info.Size()))
+	if *report {
+		printSizeReport(output)
+	}
+	if *symbols {
+		debugPath := strings.TrimSuffix(modulePath, ".wasm") + ".debug.wasm"
+		if _, err := tinygoBuild(*tinygo, *dir, debugPath, *profile+"+symbols", *ldflags, false); err != nil {
+			return err
+		}
+		fmt.Println("wrote symbols to", debugPath)
+	}
+	if limit > 0 && info.Size() > limit {
+		return fmt.Errorf("build: %s is %s, over the %s budget by %s",
+			filepath.Base(modulePath), formatSize(info.Size()), formatSize(limit), formatSize(info.Size()-limit))
+	}
+	return nil
+}
+
+// tinygoBuild builds the module in dir with the flags of profile and returns
+// TinyGo's output. With sizes set, the output holds the per-package size
+// table of -size=full.
+func tinygoBuild(tinygo, dir, out, profile, ldflags string, sizes bool) ([]byte, error) {
+	// "<profile>+symbols" is profile with debug info kept. Only debug info
+	// differs, so function indices and offsets match the stripped module.
+	base, withSymbols := strings.CutSuffix(profile, "+symbols")
+	flags, ok := buildProfiles[base]
+	if !ok {
+		return nil, fmt.Errorf("unknown build profile %q (want %s)", profile, profileNames())
+	}
+	args := []string{"build", "-o", out}
+	for _, f := range flags {
+		if !(withSymbols && f == "-no-debug") {
+			args = append(args, f)
+		}
+	}
+	if ldflags != "" {
+		args = append(args, "-ldflags", ldflags)
+	}
+	if sizes {
+		args = append(args, "-size", "full")
+	}
+	args = append(args, "./")
+	cmd := exec.Command(tinygo, args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w\n%s", tinygo, strings.Join(args, " "), err, bytes.TrimSpace(output))
+	}
+	return output, nil
+}
+
+// packageSize is one row of TinyGo's -size=full table.
+type packageSize struct {
+	name       string
+	flash, ram int64
+}
+
+// printSizeReport prints TinyGo's per-package table sorted by flash size,
+// which for wasm is the share of the module a package accounts for. Output
+// that does not look like the table is printed unchanged.
+func printSizeReport(output []byte) {
+	var rows []packageSize
+	var total packageSize
+	for _, line := range strings.Split(string(output), "\n") {
+		cols := strings.Split(line, "|")
+		if len(cols) != 3 {
+			continue
+		}
+		sums := strings.Fields(cols[1])
+		if len(sums) != 2 {
+			continue
+		}
+		flash, err1 := strconv.ParseInt(sums[0], 10, 64)
+		ram, err2 := strconv.ParseInt(sums[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue // header
+		}
+		row := packageSize{name: strings.TrimSpace(cols[2]), flash: flash, ram: ram}
+		if row.name == "total" {
+			total = row
+		} else {
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		os.Stdout.Write(output)
+		return
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].flash > rows[j].flash })
+	fmt.Printf("%10s %7s  %s\n", "size", "share", "package")
+	for _, r := range rows {
+		share := ""
+		if total.flash > 0 {
+			share = strconv.FormatFloat(float64(r.flash)*100/float64(total.flash), 'f', 1, 64) + "%"
+		}
+		fmt.Printf("%10s %7s  %s\n", formatSize(r.flash), share, r.name)
+	}
+	if total.flash > 0 {
+		fmt.Printf("%10s %7s  %s\n", formatSize(total.flash), "100%", "total")
+	}
+}
+
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"KB", 1000}, {"MB", 1000 * 1000}, {"K", 1 << 10}, {"M", 1 << 20}, {"B", 1},
+}
+
+// parseSize reads a byte count such as 350000, 512KiB, 2MB or 1.5MiB.
+func parseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	mult := int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			s, mult = strings.TrimSpace(strings.TrimSuffix(s, u.suffix)), u.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(n * float64(mult)), nil
+}
+
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return strconv.FormatFloat(float64(n)/(1<<20), 'f', 2, 64) + " MiB"
+	case n >= 1<<10:
+		return strconv.FormatFloat(float64(n)/(1<<10), 'f', 1, 64) + " KiB"
+	}
+	return strconv.FormatInt(n, 10) + " B"
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NodeDefinition mirrors the JSON the Go SDK serializes for get_nodes.
+type NodeDefinition struct {
+	Name         string           `json:"name"`
+	FriendlyName string           `json:"friendly_name"`
+	Description  string           `json:"description"`
+	Category     string           `json:"category"`
+	Icon         string           `json:"icon,omitempty"`
+	Pins         []PinDefinition  `json:"pins"`
+	Scores       *NodeScores      `json:"scores,omitempty"`
+	LongRunning  bool             `json:"long_running"`
+	Docs         string           `json:"docs,omitempty"`
+	Permissions  []string         `json:"permissions,omitempty"`
+	ABIVersion   int              `json:"abi_version"`
+	HTTPAllow    []string         `json:"http_allowlist,omitempty"`
+	Tags         []string         `json:"tags,omitempty"`
+	Keywords     []string         `json:"keywords,omitempty"`
+	Author       string           `json:"author,omitempty"`
+	Homepage     string           `json:"homepage,omitempty"`
+	License      string           `json:"license,omitempty"`
+	Config       *ConfigForm      `json:"config,omitempty"`
+	Changelog    []ChangelogEntry `json:"changelog,omitempty"`
+	Concurrency  *Concurrency     `json:"concurrency,omitempty"`
+	Retry        *RetryPolicy     `json:"retry,omitempty"`
+	TimeoutSecs  int              `json:"timeout_secs,omitempty"`
+	Resources    *ResourceHints   `json:"resources,omitempty"`
+}
+
+type Concurrency struct {
+	Max int    `json:"max"`
+	Key string `json:"key,omitempty"`
+}
+
+// String describes the limit, e.g. "1 at a time per user".
+func (c *Concurrency) String() string {
+	if c == nil {
+		return "unlimited"
+	}
+	s := fmt.Sprintf("%d at a time", c.Max)
+	switch {
+	case c.Key == "":
+	case strings.HasPrefix(c.Key, "input:"):
+		s += " per value of input " + strings.TrimPrefix(c.Key, "input:")
+	default:
+		s += " per " + c.Key
+	}
+	return s
+}
+
+type PinDefinition struct {
+	Name         string          `json:"name"`
+	FriendlyName string          `json:"friendly_name"`
+	Description  string          `json:"description"`
+	PinType      string          `json:"pin_type"`
+	DataType     string          `json:"data_type"`
+	DefaultValue json.RawMessage `json:"default_value,omitempty"`
+	ValueType    string          `json:"value_type,omitempty"`
+	Schema       string          `json:"schema,omitempty"`
+	DataClass    string          `json:"data_class,omitempty"`
+}
+
+type NodeScores struct {
+	Privacy     uint8 `json:"privacy"`
+	Security    uint8 `json:"security"`
+	Performance uint8 `json:"performance"`
+	Governance  uint8 `json:"governance"`
+	Reliability uint8 `json:"reliability"`
+	Cost        uint8 `json:"cost"`
+}
+
+// loadDefinitions reads node definitions from a compiled module (.wasm) or
+// from a JSON file holding a definition or an array of definitions.
+func loadDefinitions(path string) ([]NodeDefinition, error) {
+	var data []byte
+	var err error
+	if strings.HasSuffix(path, ".wasm") {
+		data, err = definitionsFromWasm(path)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseDefinitions(data)
+}
+
+func parseDefinitions(data []byte) ([]NodeDefinition, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{") {
+		var def NodeDefinition
+		if err := json.Unmarshal(data, &def); err != nil {
+			return nil, fmt.Errorf("parse definition: %w", err)
+		}
+		return []NodeDefinition{def}, nil
+	}
+	var defs []NodeDefinition
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("parse definitions: %w", err)
+	}
+	return defs, nil
+}
+
+type RetryPolicy struct {
+	MaxAttempts int      `json:"max_attempts"`
+	BackoffMs   int64    `json:"backoff_ms"`
+	RetryOn     []string `json:"retry_on"`
+}
+
+// String describes the policy, e.g. "3 attempts, 1000 ms backoff, on network, rate_limited".
+func (p *RetryPolicy) String() string {
+	if p == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%d attempts, %d ms backoff, on %s", p.MaxAttempts, p.BackoffMs, strings.Join(p.RetryOn, ", "))
+}
+
+// timeoutString describes a definition timeout, e.g. "30 s".
+func timeoutString(secs int) string {
+	if secs <= 0 {
+		return "platform default"
+	}
+	return fmt.Sprintf("%d s", secs)
+}
+
+type ResourceHints struct {
+	MemoryMB int    `json:"memory_mb"`
+	CPUClass string `json:"cpu_class"`
+}
+
+// String describes the hints, e.g. "512 MB, heavy CPU".
+func (r *ResourceHints) String() string {
+	if r == nil {
+		return "platform default"
+	}
+	var parts []string
+	if r.MemoryMB > 0 {
+		parts = append(parts, fmt.Sprintf("%d MB", r.MemoryMB))
+	}
+	if r.CPUClass != "" {
+		parts = append(parts, r.CPUClass+" CPU")
+	}
+	if len(parts) == 0 {
+		return "platform default"
+	}
+	return strings.Join(parts, ", ")
+}
+
+type ChangelogEntry struct {
+	Version string `json:"version"`
+	Notes   string `json:"notes"`
+}
+
+// ConfigForm is the per-instance configuration form of a node.
+type ConfigForm struct {
+	Fields []ConfigField `json:"fields"`
+}
+
+type ConfigField struct {
+	Name     string `json:"name"`
+	Label    string `json:"label"`
+	Type     string `json:"type"`
+	Required bool   `json:"required"`
+	Help     string `json:"help,omitempty"`
+}
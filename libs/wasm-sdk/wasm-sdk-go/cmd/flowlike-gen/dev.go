@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReloadRequest is the body dev POSTs to the running Flow-Like instance
+// after every successful build.
+type ReloadRequest struct {
+	PackageID    string          `json:"package_id,omitempty"`
+	ModulePath   string          `json:"module_path"`
+	ModuleSHA256 string          `json:"module_sha256"`
+	Nodes        json.RawMessage `json:"nodes"`
+}
+
+func runDev(args []string) error {
+	fs := flag.NewFlagSet("dev", flag.ExitOnError)
+	dir := fs.String("dir", ".", "module source directory (holds main.go and flow-like.toml)")
+	out := fs.String("o", "node.wasm", "output module, relative to -dir")
+	notify := fs.String("notify", os.Getenv("FLOWLIKE_DEV_URL"), "reload endpoint of the local Flow-Like instance (default $FLOWLIKE_DEV_URL)")
+	interval := fs.Duration("interval", 500*time.Millisecond, "how often to scan the sources for changes")
+	tinygo := fs.String("tinygo", "tinygo", "TinyGo binary")
+	debug := fs.Bool("debug", false, "build with the debug profile (larger module, readable panics)")
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		return fmt.Errorf("dev: unexpected arguments %v", fs.Args())
+	}
+
+	root, err := filepath.Abs(*dir)
+	if err != nil {
+		return err
+	}
+	modulePath := *out
+	if !filepath.IsAbs(modulePath) {
+		modulePath = filepath.Join(root, modulePath)
+	}
+	packageID := readPackageID(filepath.Join(root, "flow-like.toml"))
+	if *notify == "" {
+		fmt.Println("dev: no -notify endpoint set; rebuilding only")
+	}
+
+	b := devBuilder{root: root, module: modulePath, tinygo: *tinygo, debug: *debug, notify: *notify, packageID: packageID}
+	last, err := sourceState(root, modulePath)
+	if err != nil {
+		return err
+	}
+	b.rebuild()
+	fmt.Printf("dev: watching %s (Ctrl+C to stop)\n", root)
+	for {
+		time.Sleep(*interval)
+		cur, err := sourceState(root, modulePath)
+		if err != nil {
+			fmt.Println("dev:", err)
+			continue
+		}
+		if cur == last {
+			continue
+		}
+		// Editors often write a file in several steps; wait until the tree
+		// is quiet for one interval before building.
+		for {
+			time.Sleep(*interval)
+			next, err := sourceState(root, modulePath)
+			if err != nil || next == cur {
+				break
+			}
+			cur = next
+		}
+		last = cur
+		b.rebuild()
+	}
+}
+
+type devBuilder struct {
+	root, module string
+	tinygo       string
+	debug        bool
+	notify       string
+	packageID    string
+}
+
+// rebuild builds the module, checks it and notifies the instance. Failures
+// are printed; the watch loop keeps running so the next save can fix them.
+func (b *devBuilder) rebuild() {
+	start := time.Now()
+	if err := b.build(); err != nil {
+		fmt.Printf("dev: build failed:\n%v\n", err)
+		return
+	}
+	nodes, err := definitionsFromWasm(b.module)
+	if err != nil {
+		fmt.Println("dev: built module does not load:", err)
+		return
+	}
+	fmt.Printf("dev: built %s in %s\n", filepath.Base(b.module), time.Since(start).Round(time.Millisecond))
+	if issues, err := callExport(b.module, "check_nodes"); err == nil {
+		printIssues(issues)
+	}
+	if b.notify == "" {
+		return
+	}
+	if err := b.reload(nodes); err != nil {
+		fmt.Println("dev: reload failed:", err)
+		return
+	}
+	fmt.Println("dev: reloaded in Flow-Like")
+}
+
+// build runs TinyGo into a temporary file and renames it over the module,
+// so the instance never loads a half-written module.
+func (b *devBuilder) build() error {
+	// TinyGo picks the output format from the extension, so keep .wasm.
+	tmp := strings.TrimSuffix(b.module, ".wasm") + ".tmp.wasm"
+	profile := "release"
+	if b.debug {
+		profile = "debug"
+	}
+	if _, err := tinygoBuild(b.tinygo, b.root, tmp, profile, "", false); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, b.module)
+}
+
+func (b *devBuilder) reload(nodes []byte) error {
+	code, err := os.ReadFile(b.module)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(code)
+	body, err := json.Marshal(ReloadRequest{
+		PackageID:    b.packageID,
+		ModulePath:   b.module,
+		ModuleSHA256: hex.EncodeToString(sum[:]),
+		Nodes:        json.RawMessage(nodes),
+	})
+	if err != nil {
+		return err
+	}
+	client := http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(b.notify, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// printIssues prints the check_nodes report, if it has any entries.
+func printIssues(data []byte) {
+	var issues []struct {
+		Node   string `json:"node"`
+		Kind   string `json:"kind"`
+		Detail string `json:"detail"`
+	}
+	if json.Unmarshal(data, &issues) != nil {
+		return
+	}
+	for _, i := range issues {
+		fmt.Printf("dev: warning: %s: %s: %s\n", i.Node, i.Kind, i.Detail)
+	}
+}
+
+// sourceState fingerprints the files a build depends on: Go sources, module
+// files and the package manifest, by path, size and modification time.
+func sourceState(root, module string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path == module || !(strings.HasSuffix(name, ".go") || name == "go.mod" || name == "go.sum" || name == "flow-like.toml") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		io.WriteString(h, path+"\x00"+strconv.FormatInt(info.Size(), 10)+"\x00"+strconv.FormatInt(info.ModTime().UnixNano(), 10)+"\n")
+		return nil
+	})
+	return hex.EncodeToString(h.Sum(nil)), err
+}
+
+// readPackageID returns the top-level id of a flow-like.toml manifest, or ""
+// if there is none.
+func readPackageID(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, "[") {
+			// Keys after the first table belong to it, not to the package.
+			return ""
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.TrimSpace(key) == "id" {
+			if id, err := strconv.Unquote(strings.TrimSpace(value)); err == nil {
+				return id
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Change severities reported by diff.
+const (
+	severityBreaking = "breaking"
+	severityNotable  = "notable"
+)
+
+// Change is one difference between two versions of a module's definitions.
+type Change struct {
+	Severity string `json:"severity"`
+	Node     string `json:"node"`
+	Pin      string `json:"pin,omitempty"`
+	Message  string `json:"message"`
+}
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print changes as JSON")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("diff: expected <old> <new> modules or JSON files")
+	}
+	oldDefs, err := loadDefinitions(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	newDefs, err := loadDefinitions(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	changes := diffDefinitions(oldDefs, newDefs)
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(changes); err != nil {
+			return err
+		}
+	} else if len(changes) == 0 {
+		fmt.Println("no changes")
+	} else {
+		for _, c := range changes {
+			where := c.Node
+			if c.Pin != "" {
+				where += "." + c.Pin
+			}
+			fmt.Printf("%-9s %s: %s\n", c.Severity, where, c.Message)
+		}
+	}
+
+	for _, c := range changes {
+		if c.Severity == severityBreaking {
+			// Non-zero exit lets CI require a deliberate version bump.
+			os.Exit(3)
+		}
+	}
+	return nil
+}
+
+// diffDefinitions compares two sets of definitions. Breaking changes are those
+// that can disconnect or mistype wires on existing boards.
+func diffDefinitions(oldDefs, newDefs []NodeDefinition) []Change {
+	var changes []Change
+	add := func(sev, node, pin, msg string) {
+		changes = append(changes, Change{Severity: sev, Node: node, Pin: pin, Message: msg})
+	}
+
+	newByName := map[string]*NodeDefinition{}
+	for i := range newDefs {
+		newByName[newDefs[i].Name] = &newDefs[i]
+	}
+	oldByName := map[string]bool{}
+	for i := range oldDefs {
+		old := &oldDefs[i]
+		oldByName[old.Name] = true
+		cur, ok := newByName[old.Name]
+		if !ok {
+			add(severityBreaking, old.Name, "", "node removed")
+			continue
+		}
+		diffNode(old, cur, add)
+	}
+	for i := range newDefs {
+		if !oldByName[newDefs[i].Name] {
+			add(severityNotable, newDefs[i].Name, "", "node added")
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Severity != changes[j].Severity {
+			return changes[i].Severity == severityBreaking
+		}
+		return changes[i].Node < changes[j].Node
+	})
+	return changes
+}
+
+func diffNode(old, cur *NodeDefinition, add func(sev, node, pin, msg string)) {
+	name := old.Name
+	oldPins := pinIndex(old.Pins)
+	curPins := pinIndex(cur.Pins)
+
+	// Exec pins keyed by direction, so renames are only paired within one
+	// direction.
+	removedExec := map[string][]string{}
+	addedExec := map[string][]string{}
+	for _, p := range old.Pins {
+		np, ok := curPins[pinKey(p)]
+		if !ok {
+			if p.DataType == "Exec" {
+				removedExec[direction(p)] = append(removedExec[direction(p)], p.Name)
+			}
+			add(severityBreaking, name, p.Name, "removed "+direction(p)+" pin")
+			continue
+		}
+		if np.DataType != p.DataType {
+			add(severityBreaking, name, p.Name, "data type changed from "+p.DataType+" to "+np.DataType)
+		}
+		if valueType(np) != valueType(p) {
+			add(severityBreaking, name, p.Name, "value type changed from "+valueType(p)+" to "+valueType(np))
+		}
+		if np.Schema != p.Schema {
+			add(severityNotable, name, p.Name, "schema changed")
+		}
+		if np.DataClass != p.DataClass {
+			add(severityNotable, name, p.Name, "data class changed from "+orNone(p.DataClass)+" to "+orNone(np.DataClass))
+		}
+		if string(np.DefaultValue) != string(p.DefaultValue) {
+			add(severityNotable, name, p.Name, "default changed from "+orNone(string(p.DefaultValue))+" to "+orNone(string(np.DefaultValue)))
+		}
+	}
+	for _, p := range cur.Pins {
+		if _, ok := oldPins[pinKey(p)]; ok {
+			continue
+		}
+		if p.DataType == "Exec" {
+			addedExec[direction(p)] = append(addedExec[direction(p)], p.Name)
+		}
+		if p.PinType == "Input" && p.DataType != "Exec" && len(p.DefaultValue) == 0 {
+			add(severityBreaking, name, p.Name, "new input pin without default value")
+		} else {
+			add(severityNotable, name, p.Name, "added "+direction(p)+" pin")
+		}
+	}
+	for _, dir := range []string{"input", "output"} {
+		removed, added := removedExec[dir], addedExec[dir]
+		if len(removed) > 0 && len(added) > 0 {
+			add(severityBreaking, name, "", fmt.Sprintf("exec %s pins %v look renamed to %v; boards wired to the old names stop firing", dir, removed, added))
+		}
+	}
+
+	for _, perm := range cur.Permissions {
+		if !contains(old.Permissions, perm) {
+			add(severityNotable, name, "", "requires new permission "+perm)
+		}
+	}
+	for _, h := range cur.HTTPAllow {
+		if !contains(old.HTTPAllow, h) {
+			add(severityNotable, name, "", "HTTP allowlist now includes "+h)
+		}
+	}
+	if len(old.HTTPAllow) > 0 && len(cur.HTTPAllow) == 0 {
+		add(severityNotable, name, "", "HTTP allowlist removed, any host may be contacted")
+	}
+	if cur.LongRunning != old.LongRunning {
+		add(severityNotable, name, "", fmt.Sprintf("long_running changed to %v", cur.LongRunning))
+	}
+	for _, f := range configFields(cur) {
+		if f.Required && !hasConfigField(old, f.Name) {
+			add(severityBreaking, name, "", "new required config field "+f.Name+"; configured instances must be set up again")
+		}
+	}
+	for _, f := range configFields(old) {
+		if !hasConfigField(cur, f.Name) {
+			add(severityNotable, name, "", "removed config field "+f.Name)
+		}
+	}
+	if cur.License != old.License {
+		add(severityNotable, name, "", "license changed from "+orNone(old.License)+" to "+orNone(cur.License))
+	}
+	if cur.Concurrency.String() != old.Concurrency.String() {
+		add(severityNotable, name, "", "concurrency changed from "+old.Concurrency.String()+" to "+cur.Concurrency.String())
+	}
+	if cur.Retry.String() != old.Retry.String() {
+		add(severityNotable, name, "", "retry policy changed from "+old.Retry.String()+" to "+cur.Retry.String())
+	}
+	if cur.TimeoutSecs != old.TimeoutSecs {
+		add(severityNotable, name, "", "timeout changed from "+timeoutString(old.TimeoutSecs)+" to "+timeoutString(cur.TimeoutSecs))
+	}
+	if cur.Resources.String() != old.Resources.String() {
+		add(severityNotable, name, "", "resource hints changed from "+old.Resources.String()+" to "+cur.Resources.String())
+	}
+	if cur.ABIVersion != old.ABIVersion {
+		add(severityBreaking, name, "", fmt.Sprintf("ABI version changed from %d to %d", old.ABIVersion, cur.ABIVersion))
+	}
+}
+
+func pinKey(p PinDefinition) string { return p.PinType + "/" + p.Name }
+
+func pinIndex(pins []PinDefinition) map[string]PinDefinition {
+	m := make(map[string]PinDefinition, len(pins))
+	for _, p := range pins {
+		m[pinKey(p)] = p
+	}
+	return m
+}
+
+func direction(p PinDefinition) string {
+	if p.PinType == "Input" {
+		return "input"
+	}
+	return "output"
+}
+
+func valueType(p PinDefinition) string {
+	if p.ValueType == "" {
+		return "Normal"
+	}
+	return p.ValueType
+}
+
+func configFields(def *NodeDefinition) []ConfigField {
+	if def.Config == nil {
+		return nil
+	}
+	return def.Config.Fields
+}
+
+func hasConfigField(def *NodeDefinition, name string) bool {
+	for _, f := range configFields(def) {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
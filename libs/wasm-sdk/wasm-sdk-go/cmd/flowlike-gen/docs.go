@@ -0,0 +1,222 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func runDocs(args []string) error {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	outDir := fs.String("o", "docs", "output directory")
+	format := fs.String("format", "md", "page format: md or mdx")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("docs: expected one module or JSON file")
+	}
+	if *format != "md" && *format != "mdx" {
+		return fmt.Errorf("docs: unknown format %q", *format)
+	}
+
+	defs, err := loadDefinitions(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		return err
+	}
+	for i := range defs {
+		page := renderNodeDoc(&defs[i], *format == "mdx")
+		path := filepath.Join(*outDir, defs[i].Name+"."+*format)
+		if err := os.WriteFile(path, []byte(page), 0o644); err != nil {
+			return err
+		}
+		fmt.Println("wrote", path)
+	}
+	return nil
+}
+
+func renderNodeDoc(def *NodeDefinition, mdx bool) string {
+	var b strings.Builder
+	title := def.FriendlyName
+	if title == "" {
+		title = def.Name
+	}
+	if mdx {
+		b.WriteString("---\n")
+		b.WriteString("title: " + strconv.Quote(title) + "\n")
+		b.WriteString("description: " + strconv.Quote(def.Description) + "\n")
+		b.WriteString("---\n\n")
+	} else {
+		b.WriteString("# " + title + "\n\n")
+	}
+	if def.Description != "" {
+		b.WriteString(escapeText(def.Description, mdx) + "\n\n")
+	}
+
+	b.WriteString("| | |\n|---|---|\n")
+	b.WriteString("| Node | `" + def.Name + "` |\n")
+	b.WriteString("| Category | " + escapeCell(def.Category, mdx) + " |\n")
+	if def.LongRunning {
+		b.WriteString("| Long running | yes |\n")
+	}
+	if len(def.Tags) > 0 {
+		b.WriteString("| Tags | " + escapeCell(strings.Join(def.Tags, ", "), mdx) + " |\n")
+	}
+	if def.Author != "" {
+		author := escapeCell(def.Author, mdx)
+		if def.Homepage != "" {
+			author = "[" + author + "](" + def.Homepage + ")"
+		}
+		b.WriteString("| Author | " + author + " |\n")
+	}
+	if def.License != "" {
+		b.WriteString("| License | " + escapeCell(def.License, mdx) + " |\n")
+	}
+	if def.Concurrency != nil {
+		b.WriteString("| Concurrency | " + def.Concurrency.String() + " |\n")
+	}
+	if def.Retry != nil {
+		b.WriteString("| Retries | " + def.Retry.String() + " |\n")
+	}
+	if def.TimeoutSecs > 0 {
+		b.WriteString("| Timeout | " + timeoutString(def.TimeoutSecs) + " |\n")
+	}
+	if def.Resources != nil {
+		b.WriteString("| Resources | " + def.Resources.String() + " |\n")
+	}
+	b.WriteString("\n")
+
+	writePins(&b, "Inputs", def.Pins, "Input", mdx)
+	writePins(&b, "Outputs", def.Pins, "Output", mdx)
+
+	if def.Config != nil && len(def.Config.Fields) > 0 {
+		b.WriteString("## Configuration\n\n")
+		b.WriteString("| Field | Type | Required | Description |\n|---|---|---|---|\n")
+		for _, f := range def.Config.Fields {
+			required := ""
+			if f.Required {
+				required = "yes"
+			}
+			desc := f.Label
+			if f.Help != "" {
+				desc += ": " + f.Help
+			}
+			b.WriteString("| `" + f.Name + "` | " + f.Type + " | " + required + " | " + escapeCell(desc, mdx) + " |\n")
+		}
+		b.WriteString("\n")
+	}
+	if len(def.Permissions) > 0 {
+		b.WriteString("## Permissions\n\n")
+		for _, p := range def.Permissions {
+			b.WriteString("- `" + p + "`\n")
+		}
+		b.WriteString("\n")
+	}
+	if len(def.HTTPAllow) > 0 {
+		b.WriteString("HTTP requests are limited to:\n\n")
+		for _, h := range def.HTTPAllow {
+			b.WriteString("- `" + h + "`\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if s := def.Scores; s != nil {
+		b.WriteString("## Scores\n\n")
+		b.WriteString("| Privacy | Security | Performance | Governance | Reliability | Cost |\n")
+		b.WriteString("|---|---|---|---|---|---|\n")
+		fmt.Fprintf(&b, "| %d | %d | %d | %d | %d | %d |\n\n",
+			s.Privacy, s.Security, s.Performance, s.Governance, s.Reliability, s.Cost)
+	}
+
+	if def.Docs != "" {
+		b.WriteString("## Details\n\n")
+		b.WriteString(escapeText(strings.TrimSpace(def.Docs), mdx) + "\n")
+	}
+
+	if len(def.Changelog) > 0 {
+		if def.Docs != "" {
+			b.WriteString("\n")
+		}
+		b.WriteString("## Changelog\n\n")
+		for i := len(def.Changelog) - 1; i >= 0; i-- {
+			e := def.Changelog[i]
+			b.WriteString("### " + e.Version + "\n\n")
+			b.WriteString(escapeText(strings.TrimSpace(e.Notes), mdx) + "\n\n")
+		}
+	}
+	return b.String()
+}
+
+func writePins(b *strings.Builder, heading string, pins []PinDefinition, pinType string, mdx bool) {
+	var rows []PinDefinition
+	for _, p := range pins {
+		if p.PinType == pinType {
+			rows = append(rows, p)
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+	b.WriteString("## " + heading + "\n\n")
+	b.WriteString("| Pin | Name | Type | Default | Description |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, p := range rows {
+		typ := p.DataType
+		if p.ValueType != "" && p.ValueType != "Normal" {
+			typ += " (" + p.ValueType + ")"
+		}
+		if p.DataClass != "" {
+			typ += " · " + p.DataClass
+		}
+		def := ""
+		if len(p.DefaultValue) > 0 {
+			def = "`" + string(p.DefaultValue) + "`"
+		}
+		fmt.Fprintf(b, "| `%s` | %s | %s | %s | %s |\n",
+			p.Name, escapeCell(p.FriendlyName, mdx), typ, def, escapeCell(p.Description, mdx))
+	}
+	b.WriteString("\n")
+}
+
+// escapeCell keeps free text from breaking table rows (and MDX parsing).
+func escapeCell(s string, mdx bool) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\n", " ")
+	return escapeText(s, mdx)
+}
+
+var mdxEscaper = strings.NewReplacer("{", `\{`, "}", `\}`, "<", "&lt;", ">", "&gt;")
+
+// escapeText escapes the characters MDX would parse as JSX or expressions.
+// Code spans and fenced code blocks are left alone, since MDX keeps them
+// literal. Plain Markdown needs no escaping.
+func escapeText(s string, mdx bool) string {
+	if !mdx {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	fenced := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+		// Even segments lie outside backtick code spans; text after an
+		// unmatched backtick is outside too.
+		parts := strings.Split(line, "`")
+		for j := range parts {
+			if j%2 == 0 || j == len(parts)-1 {
+				parts[j] = mdxEscaper.Replace(parts[j])
+			}
+		}
+		lines[i] = strings.Join(parts, "`")
+	}
+	return strings.Join(lines, "\n")
+}
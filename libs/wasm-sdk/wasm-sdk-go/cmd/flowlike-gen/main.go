@@ -0,0 +1,77 @@
+// Command flowlike-gen works with compiled Flow-Like Go node modules from the
+// outside: it loads the node definitions a module reports via get_nodes (or a
+// JSON file containing them) and generates artifacts from them.
+//
+// Usage:
+//
+//	flowlike-gen docs [-o dir] [-format md|mdx] <module.wasm|nodes.json>
+//	flowlike-gen diff [-json] <old.wasm|old.json> <new.wasm|new.json>
+//	flowlike-gen snapshot [-dir dir] [-examples examples.json] [-update] <module.wasm>
+//	flowlike-gen build [-profile debug|release|size] [-budget 512KiB] [-report] [-symbols]
+//	flowlike-gen dev [-dir dir] [-o node.wasm] [-notify url] [-interval 500ms]
+//	flowlike-gen symbolize [-module node.debug.wasm] [trace.txt]
+//	flowlike-gen migrate [-w] [-o MIGRATION.md] [dir]
+//	flowlike-gen keygen [-o prefix]
+//	flowlike-gen sign [-key key.pem] [-o out.wasm] <module.wasm>
+//	flowlike-gen verify [-pubkey key.pub] <module.wasm>
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: flowlike-gen <command> [flags] <args>
+
+commands:
+  docs      render Markdown/MDX pages from node definitions
+  diff      report changes between two module versions (exit 3 if breaking)
+  snapshot  compare definitions and example run results with golden files
+  build     build with a TinyGo profile, report sizes, enforce a size budget
+  dev       rebuild on source changes and hot-reload into a local instance
+  symbolize map wasm backtraces to Go functions and source lines
+  migrate   list v1 SDK calls with their v2 replacements, rewrite common patterns
+  keygen    create an ed25519 signing key pair
+  sign      embed the module manifest and hash in a (signed) custom section
+  verify    check a module's provenance section and signature
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "docs":
+		err = runDocs(os.Args[2:])
+	case "diff":
+		err = runDiff(os.Args[2:])
+	case "snapshot":
+		err = runSnapshot(os.Args[2:])
+	case "build":
+		err = runBuild(os.Args[2:])
+	case "dev":
+		err = runDev(os.Args[2:])
+	case "symbolize":
+		err = runSymbolize(os.Args[2:])
+	case "migrate":
+		err = runMigrate(os.Args[2:])
+	case "keygen":
+		err = runKeygen(os.Args[2:])
+	case "sign":
+		err = runSign(os.Args[2:])
+	case "verify":
+		err = runVerify(os.Args[2:])
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "flowlike-gen:", err)
+		os.Exit(1)
+	}
+}
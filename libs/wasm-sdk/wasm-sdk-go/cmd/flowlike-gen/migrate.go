@@ -0,0 +1,628 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const sdkV1Path = "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+
+// Result shapes of v1 Context methods, which decide what migrate can rewrite.
+const (
+	shapeValue = iota // "" or nil on failure: listed only
+	shapeTuple        // (T, bool): v, ok := ...; if !ok { → v, err := ...; if err != nil {
+	shapeBool         // bool: if !... { → if err := ...; err != nil {
+	shapeGuide        // no error-returning v1 form; v2 only
+)
+
+// v1Replacement maps a v1 Context method to its error-returning v1 call
+// (ctx.Host().Method) and its v2 equivalent.
+type v1Replacement struct {
+	shape int
+	v2    string
+}
+
+var v1Replacements = map[string]v1Replacement{
+	"StorageRead":               {shapeValue, "ctx.Storage().ReadFile(ctx, path)"},
+	"StorageWrite":              {shapeBool, "ctx.Storage().WriteFile(ctx, path, data, sdk.WriteOptions{})"},
+	"StorageWriteWithPolicy":    {shapeBool, "ctx.Storage().WriteFile(ctx, path, data, sdk.WriteOptions{Retention: policy})"},
+	"StorageList":               {shapeValue, "ctx.V1().Host().StorageList(dir)"},
+	"StorageDir":                {shapeValue, "ctx.Storage().Dir(sdk.WithNodeScope())"},
+	"UserDir":                   {shapeValue, "ctx.Storage().Dir(sdk.WithUserScope(), sdk.WithNodeScope())"},
+	"CacheDirPath":              {shapeValue, "ctx.Storage().CacheDir(sdk.WithNodeScope(), sdk.WithUserScope())"},
+	"UploadDir":                 {shapeValue, "ctx.Storage().UploadDir()"},
+	"OpenStorageReader":         {shapeTuple, "ctx.Storage().Open(ctx, path)"},
+	"CreateStorageWriter":       {shapeTuple, "ctx.Storage().Create(ctx, path)"},
+	"EmbedText":                 {shapeValue, "ctx.Models().Embed(ctx, bit, texts)"},
+	"ChatComplete":              {shapeTuple, "ctx.Models().Chat(ctx, bit, req)"},
+	"DetectEntities":            {shapeValue, "ctx.Models().Entities(ctx, bit, text)"},
+	"ListModelBits":             {shapeValue, "ctx.Models().List(capability)"},
+	"DefaultBit":                {shapeTuple, "ctx.Models().Default(capability)"},
+	"Rerank":                    {shapeValue, "ctx.Models().Rerank(ctx, bit, query, candidates)"},
+	"VectorUpsert":              {shapeBool, "ctx.Vectors(collection).Upsert(ctx, records...)"},
+	"VectorSearch":              {shapeValue, "ctx.Vectors(collection).Search(ctx, vector, k)"},
+	"HTTPRequest":               {shapeBool, "ctx.HTTP().Do(ctx, sdk.Request{Method: ..., URL: url, Header: ..., Body: ...})"},
+	"HTTPFetch":                 {shapeTuple, "ctx.HTTP().Do(ctx, sdk.Request{Method: ..., URL: url, Header: ..., Body: ...})"},
+	"HTTPFetchWith":             {shapeTuple, "ctx.HTTP().Do(ctx, sdk.Request{..., Options: opts})"},
+	"HTTPRequestWithClientCert": {shapeTuple, "ctx.HTTP().Do(ctx, sdk.Request{..., Options: sdk.HTTPRequestOptions{ClientCertRef: ref}})"},
+	"TransferList":              {shapeTuple, "ctx.V1().Host().TransferList(conn, dir)"},
+	"TransferGet":               {shapeBool, "ctx.V1().Host().TransferGet(conn, remotePath, path)"},
+	"TransferPut":               {shapeBool, "ctx.V1().Host().TransferPut(conn, path, remotePath)"},
+	"BucketList":                {shapeTuple, "ctx.V1().Host().BucketList(b, prefix)"},
+	"BucketGet":                 {shapeBool, "ctx.V1().Host().BucketGet(b, key, path)"},
+	"BucketPut":                 {shapeBool, "ctx.V1().Host().BucketPut(b, path, key)"},
+	"BucketDelete":              {shapeBool, "ctx.V1().Host().BucketDelete(b, key)"},
+	"Exec":                      {shapeTuple, "ctx.V1().Host().Exec(cmd, args, stdin)"},
+	"GetOAuthToken":             {shapeValue, "ctx.OAuth(provider).Token(ctx)"},
+	"RefreshOAuthToken":         {shapeValue, "ctx.OAuth(provider).Refresh(ctx)"},
+	"ClipboardRead":             {shapeValue, "ctx.V1().Host().ClipboardRead()"},
+	"ClipboardWrite":            {shapeBool, "ctx.V1().Host().ClipboardWrite(text)"},
+	"OpenFile":                  {shapeBool, "ctx.V1().Host().OpenFile(flowPath)"},
+	"Notify":                    {shapeBool, "ctx.V1().Host().Notify(level, title, message, actions...)"},
+	"QuotaConsume":              {shapeBool, "ctx.ConsumeQuota(name, amount)"},
+	"GetQuota":                  {shapeTuple, "ctx.Quota(kind)"},
+	"KVGet":                     {shapeTuple, "ctx.KV(scope).Get(ctx, key)"},
+	"KVPut":                     {shapeTuple, "ctx.KV(scope).Put(ctx, key, value)"},
+	"KVPutIf":                   {shapeTuple, "ctx.KV(scope).PutIf(ctx, key, value, version)"},
+	"KVDelete":                  {shapeBool, "ctx.KV(scope).Delete(ctx, key)"},
+	"KVList":                    {shapeValue, "ctx.KV(scope).List(ctx, prefix, sdk.ListOptions{Limit: limit, Cursor: cursor})"},
+	"GetAppInfo":                {shapeTuple, "ctx.V1().Host().GetAppInfo()"},
+	"ListAppMembers":            {shapeTuple, "ctx.V1().Host().ListAppMembers(role)"},
+	"ListEvents":                {shapeTuple, "ctx.V1().Host().ListEvents(provider, q)"},
+	"CreateEvent":               {shapeTuple, "ctx.V1().Host().CreateEvent(provider, e)"},
+	"SearchContacts":            {shapeTuple, "ctx.V1().Host().SearchContacts(provider, query, limit)"},
+	"ListMail":                  {shapeTuple, "ctx.V1().Host().ListMail(mailbox, q)"},
+	"FetchMail":                 {shapeTuple, "ctx.V1().Host().FetchMail(mailbox, id)"},
+	"SaveMailAttachment":        {shapeBool, "ctx.V1().Host().SaveMailAttachment(mailbox, id, attachmentID, path)"},
+	"QueuePublish":              {shapeTuple, "ctx.V1().Host().QueuePublish(queue, body, attributes)"},
+	"QueuePoll":                 {shapeTuple, "ctx.V1().Host().QueuePoll(queue, max, visibilityMs)"},
+	"QueueAck":                  {shapeBool, "ctx.V1().Host().QueueAck(queue, receipt)"},
+	"QueueExtend":               {shapeBool, "ctx.V1().Host().QueueExtend(queue, receipt, visibilityMs)"},
+	"IndexDocument":             {shapeBool, "ctx.V1().Host().IndexDocument(collection, doc)"},
+	"DeleteDocument":            {shapeBool, "ctx.V1().Host().DeleteDocument(collection, id)"},
+	"Search":                    {shapeTuple, "ctx.V1().Host().Search(collection, query, opts)"},
+	"OpenAttachment":            {shapeTuple, "ctx.V1().Host().OpenAttachment(a)"},
+	"StreamInputArray":          {shapeTuple, "ctx.V1().Host().StreamInputArray(name)"},
+	"StreamStorageArray":        {shapeTuple, "ctx.V1().Host().StreamStorageArray(path)"},
+	// Pending answers fail with sdk.ErrInputPending, so the ok branch needs
+	// rewriting by hand.
+	"RequestUserInput":     {shapeValue, "ctx.V1().Host().RequestUserInput(prompt, schema)"},
+	"RequestUserInputWith": {shapeValue, "ctx.V1().Host().RequestUserInputWith(prompt, schema, opts)"},
+	"RequestForm":          {shapeValue, "ctx.V1().Host().RequestForm(prompt, form)"},
+	"Confirm":              {shapeValue, "ctx.V1().Host().Confirm(prompt)"},
+
+	"GetString":      {shapeGuide, "ctx.String(name) (missing inputs are errors; no default)"},
+	"GetI64":         {shapeGuide, "ctx.Int(name)"},
+	"GetF64":         {shapeGuide, "ctx.Float(name)"},
+	"GetBool":        {shapeGuide, "ctx.Bool(name)"},
+	"InputString":    {shapeGuide, "ctx.String(name)"},
+	"InputI64":       {shapeGuide, "ctx.Int(name)"},
+	"InputF64":       {shapeGuide, "ctx.Float(name)"},
+	"InputBool":      {shapeGuide, "ctx.Bool(name)"},
+	"GetInput":       {shapeGuide, "ctx.Input(name)"},
+	"SetOutput":      {shapeGuide, "ctx.Output(name, value) (values are encoded, pass sdk.RawJSON for JSON)"},
+	"Out":            {shapeGuide, "ctx.Output(name, value)"},
+	"ActivateExec":   {shapeGuide, "ctx.Activate(pin)"},
+	"Success":        {shapeGuide, "return nil"},
+	"Done":           {shapeGuide, "return nil"},
+	"Fail":           {shapeGuide, "return an error, e.g. sdk.NewError(code, message)"},
+	"Result":         {shapeGuide, "return err"},
+	"StreamText":     {shapeGuide, "io.WriteString(ctx.Stream(), text)"},
+	"StreamProgress": {shapeGuide, "ctx.Progress(fraction, message)"},
+}
+
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	write := fs.Bool("w", false, "rewrite the mechanical patterns in place")
+	out := fs.String("o", "", "write the migration guide to this file instead of stdout")
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		return fmt.Errorf("migrate: expected at most one directory")
+	}
+	root := "."
+	if fs.NArg() == 1 {
+		root = fs.Arg(0)
+	}
+
+	files, err := goFiles(root)
+	if err != nil {
+		return err
+	}
+	var report migrationReport
+	for _, path := range files {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		res, err := migrateFile(path, src)
+		if err != nil {
+			return err
+		}
+		report.files = append(report.files, res)
+		if *write && res.rewritten > 0 {
+			if err := os.WriteFile(path, res.output, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	report.write(w, *write)
+	return nil
+}
+
+func goFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(name, ".go") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// callSite is one use of a v1 API found by migrate.
+type callSite struct {
+	line      int
+	method    string
+	code      string
+	rewritten bool
+}
+
+type fileMigration struct {
+	path      string
+	sites     []callSite
+	handlers  []callSite // v1 Run methods
+	rewritten int
+	output    []byte
+}
+
+// migrateFile finds the v1 Context calls of a file and rewrites those whose
+// result is only checked for failure right away.
+func migrateFile(path string, src []byte) (*fileMigration, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	res := &fileMigration{path: path}
+	pkg := importName(file, sdkV1Path)
+	if pkg == "" {
+		return res, nil
+	}
+	m := &migrator{fset: fset, pkg: pkg, res: res, seen: map[*ast.CallExpr]bool{}}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch fn := n.(type) {
+		case *ast.FuncDecl:
+			if fn.Body != nil {
+				m.function(fn.Type, fn.Body)
+				if fn.Recv != nil && fn.Name.Name == "Run" && m.isV1Handler(fn.Type) {
+					res.handlers = append(res.handlers, callSite{line: fset.Position(fn.Pos()).Line, code: "func " + fn.Name.Name + m.render(fn.Type)[4:]})
+				}
+			}
+		case *ast.FuncLit:
+			m.function(fn.Type, fn.Body)
+		}
+		return true
+	})
+	sort.Slice(res.sites, func(i, j int) bool { return res.sites[i].line < res.sites[j].line })
+	if res.rewritten > 0 {
+		var b bytes.Buffer
+		if err := format.Node(&b, fset, file); err != nil {
+			return nil, err
+		}
+		res.output = b.Bytes()
+	}
+	return res, nil
+}
+
+func importName(file *ast.File, path string) string {
+	for _, imp := range file.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p == path {
+			if imp.Name != nil {
+				return imp.Name.Name
+			}
+			return "sdk"
+		}
+	}
+	return ""
+}
+
+type migrator struct {
+	fset *token.FileSet
+	pkg  string
+	res  *fileMigration
+	ctxs map[string]bool // names of *sdk.Context parameters in the current function
+	body *ast.BlockStmt
+	seen map[*ast.CallExpr]bool // calls already listed
+}
+
+func (m *migrator) isContextType(e ast.Expr) bool {
+	star, ok := e.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == m.pkg && sel.Sel.Name == "Context"
+}
+
+func (m *migrator) isV1Handler(t *ast.FuncType) bool {
+	if t.Params == nil || len(t.Params.List) != 1 || !m.isContextType(t.Params.List[0].Type) {
+		return false
+	}
+	if t.Results == nil || len(t.Results.List) != 1 {
+		return false
+	}
+	sel, ok := t.Results.List[0].Type.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "ExecutionResult"
+}
+
+func (m *migrator) function(t *ast.FuncType, body *ast.BlockStmt) {
+	ctxs := map[string]bool{}
+	for _, f := range t.Params.List {
+		if m.isContextType(f.Type) {
+			for _, name := range f.Names {
+				ctxs[name.Name] = true
+			}
+		}
+	}
+	if len(ctxs) == 0 {
+		return
+	}
+	m.ctxs, m.body = ctxs, body
+
+	// Rewrites first, while the statements still have their v1 shape.
+	// Closures are included: they usually capture the handler's ctx.
+	ast.Inspect(body, func(n ast.Node) bool {
+		if block, ok := n.(*ast.BlockStmt); ok {
+			m.rewriteBlock(block)
+		}
+		return true
+	})
+	ast.Inspect(body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if method, ok := m.v1Call(call); ok && !m.seen[call] {
+				m.site(call, method, false)
+			}
+		}
+		return true
+	})
+}
+
+// v1Call reports whether call is ctx.Method(...) on a v1 Context parameter
+// with a known replacement.
+func (m *migrator) v1Call(call *ast.CallExpr) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok || !m.ctxs[x.Name] {
+		return "", false
+	}
+	_, known := v1Replacements[sel.Sel.Name]
+	return sel.Sel.Name, known
+}
+
+func (m *migrator) site(call *ast.CallExpr, method string, rewritten bool) {
+	code := m.render(call)
+	if len(code) > 72 {
+		code = code[:69] + "..."
+	}
+	m.seen[call] = true
+	m.res.sites = append(m.res.sites, callSite{line: m.fset.Position(call.Pos()).Line, method: method, code: code, rewritten: rewritten})
+	if rewritten {
+		m.res.rewritten++
+	}
+}
+
+func (m *migrator) render(n ast.Node) string {
+	var b bytes.Buffer
+	printer.Fprint(&b, m.fset, n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// rewriteBlock rewrites the statements of block that check a v1 result for
+// failure immediately:
+//
+//	v, ok := ctx.M(...); if !ok {   →  v, err := ctx.Host().M(...); if err != nil {
+//	if !ctx.M(...) {                →  if err := ctx.Host().M(...); err != nil {
+//	ctx.M(...)                      →  ctx.Host().M(...)
+func (m *migrator) rewriteBlock(block *ast.BlockStmt) {
+	for i, stmt := range block.List {
+		switch s := stmt.(type) {
+		case *ast.AssignStmt:
+			if i+1 < len(block.List) {
+				m.rewriteTuple(block, i, s)
+			}
+		case *ast.IfStmt:
+			m.rewriteBoolIf(s)
+		case *ast.ExprStmt:
+			if call, ok := s.X.(*ast.CallExpr); ok {
+				if method, ok := m.v1Call(call); ok && v1Replacements[method].shape == shapeBool {
+					m.site(call, method, true)
+					m.useHost(call)
+				}
+			}
+		}
+	}
+}
+
+func (m *migrator) rewriteTuple(block *ast.BlockStmt, i int, s *ast.AssignStmt) {
+	if s.Tok != token.DEFINE || len(s.Lhs) != 2 || len(s.Rhs) != 1 {
+		return
+	}
+	call, ok := s.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	method, ok := m.v1Call(call)
+	if !ok || v1Replacements[method].shape != shapeTuple {
+		return
+	}
+	value, ok1 := s.Lhs[0].(*ast.Ident)
+	okIdent, ok2 := s.Lhs[1].(*ast.Ident)
+	next, ok3 := block.List[i+1].(*ast.IfStmt)
+	if !ok1 || !ok2 || !ok3 || next.Init != nil || value.Name == "err" {
+		return
+	}
+	not, isNot := next.Cond.(*ast.UnaryExpr)
+	if !isNot || not.Op != token.NOT {
+		return
+	}
+	if cond, isIdent := not.X.(*ast.Ident); !isIdent || cond.Name != okIdent.Name {
+		return
+	}
+	// ok must only serve such checks, and "v, err :=" must still declare a
+	// new variable.
+	if !m.onlyFailureChecks(okIdent.Name) {
+		return
+	}
+	if declaredBefore(block, i, "err") && (value.Name == "_" || declaredBefore(block, i, value.Name)) {
+		return
+	}
+	m.site(call, method, true)
+	m.useHost(call)
+	okIdent.Name = "err"
+	next.Cond = &ast.BinaryExpr{X: ast.NewIdent("err"), Op: token.NEQ, Y: ast.NewIdent("nil")}
+}
+
+func (m *migrator) rewriteBoolIf(s *ast.IfStmt) {
+	if s.Init != nil {
+		return
+	}
+	not, ok := s.Cond.(*ast.UnaryExpr)
+	if !ok || not.Op != token.NOT {
+		return
+	}
+	call, ok := not.X.(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	method, ok := m.v1Call(call)
+	if !ok || v1Replacements[method].shape != shapeBool || mentions(s.Body, "err") || mentions(s.Else, "err") {
+		return
+	}
+	m.site(call, method, true)
+	m.useHost(call)
+	s.Init = &ast.AssignStmt{Lhs: []ast.Expr{ast.NewIdent("err")}, Tok: token.DEFINE, Rhs: []ast.Expr{call}}
+	s.Cond = &ast.BinaryExpr{X: ast.NewIdent("err"), Op: token.NEQ, Y: ast.NewIdent("nil")}
+}
+
+// useHost turns ctx.M(...) into ctx.Host().M(...).
+func (m *migrator) useHost(call *ast.CallExpr) {
+	sel := call.Fun.(*ast.SelectorExpr)
+	sel.X = &ast.CallExpr{Fun: &ast.SelectorExpr{X: sel.X, Sel: ast.NewIdent("Host")}}
+}
+
+// onlyFailureChecks reports whether every use of name in the current
+// function is "v, name := ...; if !name {", so that renaming the rewritten
+// ones leaves the others intact.
+func (m *migrator) onlyFailureChecks(name string) bool {
+	allowed := map[*ast.Ident]bool{}
+	ast.Inspect(m.body, func(node ast.Node) bool {
+		block, ok := node.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+		for i := 0; i+1 < len(block.List); i++ {
+			s, ok1 := block.List[i].(*ast.AssignStmt)
+			next, ok2 := block.List[i+1].(*ast.IfStmt)
+			if !ok1 || !ok2 || s.Tok != token.DEFINE || len(s.Lhs) != 2 || next.Init != nil {
+				continue
+			}
+			def, ok1 := s.Lhs[1].(*ast.Ident)
+			not, ok2 := next.Cond.(*ast.UnaryExpr)
+			if !ok1 || !ok2 || def.Name != name || not.Op != token.NOT {
+				continue
+			}
+			if read, ok := not.X.(*ast.Ident); ok && read.Name == name {
+				allowed[def], allowed[read] = true, true
+			}
+		}
+		return true
+	})
+	only := true
+	ast.Inspect(m.body, func(node ast.Node) bool {
+		if id, ok := node.(*ast.Ident); ok && id.Name == name && !allowed[id] {
+			only = false
+		}
+		return only
+	})
+	return only
+}
+
+func mentions(n ast.Node, name string) bool {
+	if n == nil {
+		return false
+	}
+	found := false
+	ast.Inspect(n, func(node ast.Node) bool {
+		if id, ok := node.(*ast.Ident); ok && id.Name == name {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// declaredBefore reports whether one of the first i statements of block
+// declares name.
+func declaredBefore(block *ast.BlockStmt, i int, name string) bool {
+	for _, stmt := range block.List[:i] {
+		switch s := stmt.(type) {
+		case *ast.AssignStmt:
+			if s.Tok != token.DEFINE {
+				continue
+			}
+			for _, lhs := range s.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok && id.Name == name {
+					return true
+				}
+			}
+		case *ast.DeclStmt:
+			gen, ok := s.Decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				if vs, ok := spec.(*ast.ValueSpec); ok {
+					for _, id := range vs.Names {
+						if id.Name == name {
+							return true
+						}
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+type migrationReport struct {
+	files []*fileMigration
+}
+
+func (r *migrationReport) write(w io.Writer, wrote bool) {
+	sites, rewritten, files, handlers := 0, 0, 0, 0
+	used := map[string]bool{}
+	for _, f := range r.files {
+		if len(f.sites) == 0 && len(f.handlers) == 0 {
+			continue
+		}
+		files++
+		sites += len(f.sites)
+		rewritten += f.rewritten
+		handlers += len(f.handlers)
+		for _, s := range f.sites {
+			used[s.method] = true
+		}
+	}
+
+	fmt.Fprintln(w, "# Migrating to the v2 SDK")
+	fmt.Fprintln(w)
+	if files == 0 {
+		fmt.Fprintln(w, "No uses of the v1 Context API found.")
+		return
+	}
+	fmt.Fprintf(w, "%s and %s in %s.\n", plural(sites, "v1 call"), plural(handlers, "v1 handler"), plural(files, "file"))
+	if wrote {
+		fmt.Fprintf(w, "%s rewritten to the error-returning `ctx.Host()` form of v1; review with `git diff`.\n", plural(rewritten, "call"))
+	} else {
+		fmt.Fprintf(w, "%s can be rewritten to the error-returning `ctx.Host()` form of v1 with `flowlike-gen migrate -w`.\n", plural(rewritten, "call"))
+	}
+	fmt.Fprintln(w, "Every call is listed with its v2 equivalent.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Handlers move to v2 by importing `github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go/v2` as `sdk`,")
+	fmt.Fprintln(w, "changing `Run(ctx *sdk.Context) sdk.ExecutionResult` to `Run(ctx *sdk.Context) error` and registering")
+	fmt.Fprintln(w, "them with `sdk.NewRegistry` or, next to v1 nodes, `sdk.Node(handler)`. Calls v2 does not cover are")
+	fmt.Fprintln(w, "reached through `ctx.V1()`. The v2 `ctx` is also a `context.Context` bound to the run's execution")
+	fmt.Fprintln(w, "budget; calls that wait on the host take it, or a `sdk.WithTimeout` of it, as first argument.")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "## Replacements")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "| v1 | v1 with errors | v2 |")
+	fmt.Fprintln(w, "|---|---|---|")
+	methods := make([]string, 0, len(used))
+	for method := range used {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		rep := v1Replacements[method]
+		withErrors := "`ctx.Host()." + method + "`"
+		if rep.shape == shapeGuide {
+			withErrors = "—"
+		}
+		fmt.Fprintf(w, "| `ctx.%s` | %s | `%s` |\n", method, withErrors, rep.v2)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "## Call sites")
+	for _, f := range r.files {
+		if len(f.sites) == 0 && len(f.handlers) == 0 {
+			continue
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "### %s\n\n", filepath.ToSlash(f.path))
+		for _, h := range f.handlers {
+			fmt.Fprintf(w, "- line %d: handler `%s` → `Run(ctx *sdk.Context) error`\n", h.line, h.code)
+		}
+		for _, s := range f.sites {
+			note := ""
+			if s.rewritten {
+				if wrote {
+					note = " (rewritten)"
+				} else {
+					note = " (rewritable)"
+				}
+			}
+			fmt.Fprintf(w, "- line %d: `%s` → `%s`%s\n", s.line, s.code, v1Replacements[s.method].v2, note)
+		}
+	}
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
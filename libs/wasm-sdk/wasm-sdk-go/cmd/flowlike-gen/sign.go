@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// signatureSection is the custom section holding the signed provenance record.
+const signatureSection = "flowlike.signature"
+
+// Provenance is the payload stored in the signature section.
+type Provenance struct {
+	Manifest     json.RawMessage `json:"manifest"`
+	ModuleSHA256 string          `json:"module_sha256"`
+	PublicKey    string          `json:"public_key,omitempty"`
+	Signature    string          `json:"signature,omitempty"`
+}
+
+// signedBytes is what the signature covers: the manifest and the module hash.
+func (p *Provenance) signedBytes() []byte {
+	return []byte(string(p.Manifest) + "\n" + p.ModuleSHA256)
+}
+
+func runKeygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	out := fs.String("o", "flowlike-signing", "key file prefix (writes <prefix>.key and <prefix>.pub)")
+	fs.Parse(args)
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out+".key", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out+".pub", []byte(base64.StdEncoding.EncodeToString(pub)+"\n"), 0o644); err != nil {
+		return err
+	}
+	fmt.Println("wrote", *out+".key", "and", *out+".pub")
+	return nil
+}
+
+func runSign(args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	keyPath := fs.String("key", "", "PEM ed25519 private key; omit to embed an unsigned provenance record")
+	out := fs.String("o", "", "output path (default: overwrite the input)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("sign: expected one .wasm module")
+	}
+	path := fs.Arg(0)
+	if *out == "" {
+		*out = path
+	}
+
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	stripped, _, err := splitSignature(code)
+	if err != nil {
+		return err
+	}
+	manifest, err := callExport(path, "get_manifest")
+	if errors.Is(err, errNoExport) {
+		manifest, err = []byte("{}"), nil
+	}
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(stripped)
+	prov := Provenance{Manifest: json.RawMessage(manifest), ModuleSHA256: hex.EncodeToString(sum[:])}
+	if *keyPath != "" {
+		priv, err := readPrivateKey(*keyPath)
+		if err != nil {
+			return err
+		}
+		prov.PublicKey = base64.StdEncoding.EncodeToString(priv.Public().(ed25519.PublicKey))
+		prov.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(priv, prov.signedBytes()))
+	}
+	payload, err := json.Marshal(prov)
+	if err != nil {
+		return err
+	}
+
+	signed := append(stripped, customSection(signatureSection, payload)...)
+	if err := os.WriteFile(*out, signed, 0o644); err != nil {
+		return err
+	}
+	fmt.Println("wrote", *out, "sha256", prov.ModuleSHA256)
+	return nil
+}
+
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	pubPath := fs.String("pubkey", "", "expected signer public key file; omit to accept any valid signature")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("verify: expected one .wasm module")
+	}
+	code, err := os.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	stripped, payload, err := splitSignature(code)
+	if err != nil {
+		return err
+	}
+	if payload == nil {
+		return fmt.Errorf("verify: module has no %s section", signatureSection)
+	}
+	var prov Provenance
+	if err := json.Unmarshal(payload, &prov); err != nil {
+		return fmt.Errorf("verify: malformed provenance: %w", err)
+	}
+
+	sum := sha256.Sum256(stripped)
+	if hex.EncodeToString(sum[:]) != prov.ModuleSHA256 {
+		return fmt.Errorf("verify: module hash mismatch, the module was modified after signing")
+	}
+	if prov.Signature == "" {
+		return fmt.Errorf("verify: provenance record is not signed")
+	}
+	pub, err := base64.StdEncoding.DecodeString(prov.PublicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("verify: malformed public key")
+	}
+	if *pubPath != "" {
+		want, err := os.ReadFile(*pubPath)
+		if err != nil {
+			return err
+		}
+		if string(bytes.TrimSpace(want)) != prov.PublicKey {
+			return fmt.Errorf("verify: signed by a different key")
+		}
+	}
+	sig, err := base64.StdEncoding.DecodeString(prov.Signature)
+	if err != nil || !ed25519.Verify(pub, prov.signedBytes(), sig) {
+		return fmt.Errorf("verify: invalid signature")
+	}
+	fmt.Printf("ok: sha256 %s signed by %s\nmanifest: %s\n", prov.ModuleSHA256, prov.PublicKey, prov.Manifest)
+	return nil
+}
+
+func readPrivateKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: not a PEM file", path)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an ed25519 key", path)
+	}
+	return priv, nil
+}
+
+// splitSignature returns the module without its signature section, and the
+// section payload if present.
+func splitSignature(code []byte) (stripped, payload []byte, err error) {
+	if len(code) < 8 || !bytes.Equal(code[:4], []byte("\x00asm")) {
+		return nil, nil, fmt.Errorf("not a wasm module")
+	}
+	stripped = append(stripped, code[:8]...)
+	for off := 8; off < len(code); {
+		start := off
+		id := code[off]
+		off++
+		size, n := readULEB(code[off:])
+		if n == 0 || off+n+int(size) > len(code) {
+			return nil, nil, fmt.Errorf("malformed section at offset %d", start)
+		}
+		off += n
+		body := code[off : off+int(size)]
+		off += int(size)
+		if id == 0 {
+			nameLen, m := readULEB(body)
+			if m > 0 && m+int(nameLen) <= len(body) && string(body[m:m+int(nameLen)]) == signatureSection {
+				payload = body[m+int(nameLen):]
+				continue
+			}
+		}
+		stripped = append(stripped, code[start:off]...)
+	}
+	return stripped, payload, nil
+}
+
+func customSection(name string, payload []byte) []byte {
+	var body []byte
+	body = appendULEB(body, uint64(len(name)))
+	body = append(body, name...)
+	body = append(body, payload...)
+	out := []byte{0}
+	out = appendULEB(out, uint64(len(body)))
+	return append(out, body...)
+}
+
+func readULEB(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7f) << (7 * i)
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+func appendULEB(b []byte, v uint64) []byte {
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			b = append(b, c|0x80)
+			continue
+		}
+		return append(b, c)
+	}
+}
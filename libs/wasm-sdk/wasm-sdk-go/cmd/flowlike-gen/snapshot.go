@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Example is one run recorded by snapshot, read from the -examples file.
+type Example struct {
+	Name   string                     `json:"name"`
+	Node   string                     `json:"node"`
+	Inputs map[string]json.RawMessage `json:"inputs"`
+	// Seed and Time make the run deterministic (see sdk.DeterministicInput).
+	Seed int64 `json:"seed"`
+	Time int64 `json:"time"`
+}
+
+// snapshotTime is the clock of example runs that do not set one:
+// 2024-01-01T00:00:00Z in Unix milliseconds.
+const snapshotTime = 1704067200000
+
+func runSnapshot(args []string) error {
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	dir := fs.String("dir", "testdata/snapshots", "directory holding the golden files")
+	examplesPath := fs.String("examples", "", "JSON file with example runs to snapshot")
+	update := fs.Bool("update", false, "rewrite the golden files instead of comparing")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("snapshot: expected one module")
+	}
+
+	snapshots, err := takeSnapshots(fs.Arg(0), *examplesPath)
+	if err != nil {
+		return err
+	}
+
+	if *update {
+		if err := os.MkdirAll(*dir, 0o755); err != nil {
+			return err
+		}
+		for _, s := range snapshots {
+			path := filepath.Join(*dir, s.name)
+			if err := os.WriteFile(path, s.data, 0o644); err != nil {
+				return err
+			}
+			fmt.Println("wrote", path)
+		}
+		return nil
+	}
+
+	failed := 0
+	for _, s := range snapshots {
+		path := filepath.Join(*dir, s.name)
+		golden, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			fmt.Printf("%s: missing golden file\n", path)
+			failed++
+			continue
+		} else if err != nil {
+			return err
+		}
+		if !bytes.Equal(golden, s.data) {
+			fmt.Printf("--- %s (golden)\n+++ %s (current)\n%s", path, path, lineDiff(string(golden), string(s.data)))
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("snapshot: %d of %d snapshots differ; rerun with -update if the change is intended", failed, len(snapshots))
+	}
+	fmt.Printf("%d snapshots match\n", len(snapshots))
+	return nil
+}
+
+type snapshot struct {
+	name string
+	data []byte
+}
+
+// takeSnapshots instantiates the module once and captures its definitions
+// and the result of every example run as canonical JSON.
+func takeSnapshots(modulePath, examplesPath string) ([]snapshot, error) {
+	var examples []Example
+	if examplesPath != "" {
+		data, err := os.ReadFile(examplesPath)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &examples); err != nil {
+			return nil, fmt.Errorf("parse examples: %w", err)
+		}
+	}
+
+	ctx := context.Background()
+	inst, err := instantiate(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	defer inst.Close(ctx)
+
+	defs, err := inst.call(ctx, "get_nodes")
+	if err == errNoExport {
+		defs, err = inst.call(ctx, "get_node")
+	}
+	if err != nil {
+		return nil, err
+	}
+	canon, err := canonicalJSON(defs)
+	if err != nil {
+		return nil, fmt.Errorf("get_nodes: %w", err)
+	}
+	out := []snapshot{{name: "nodes.json", data: canon}}
+
+	seen := map[string]bool{}
+	for _, ex := range examples {
+		if ex.Name == "" || ex.Name == "nodes" || strings.ContainsAny(ex.Name, `/\`) {
+			return nil, fmt.Errorf("example %q: name must be non-empty, not \"nodes\" and contain no path separators", ex.Name)
+		}
+		if seen[ex.Name] {
+			return nil, fmt.Errorf("example %q: duplicate name", ex.Name)
+		}
+		seen[ex.Name] = true
+
+		if ex.Time == 0 {
+			ex.Time = snapshotTime
+		}
+		inputs := ex.Inputs
+		if inputs == nil {
+			inputs = map[string]json.RawMessage{}
+		}
+		input, err := json.Marshal(map[string]any{
+			"inputs":        inputs,
+			"node_id":       "snapshot",
+			"node_name":     ex.Node,
+			"run_id":        "snapshot-" + ex.Name,
+			"deterministic": map[string]int64{"seed": ex.Seed, "time": ex.Time},
+		})
+		if err != nil {
+			return nil, err
+		}
+		result, err := inst.call(ctx, "run", input)
+		if err != nil {
+			return nil, fmt.Errorf("example %q: %w", ex.Name, err)
+		}
+		canon, err := canonicalJSON(result)
+		if err != nil {
+			return nil, fmt.Errorf("example %q: %w", ex.Name, err)
+		}
+		out = append(out, snapshot{name: ex.Name + ".json", data: canon})
+	}
+	return out, nil
+}
+
+// canonicalJSON re-encodes data with sorted keys and two-space indentation so
+// golden files are stable and diff line by line.
+func canonicalJSON(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 2
+
+// lineDiff renders the line changes from a to b with "-" and "+" markers and
+// a few lines of context, computed from their longest common subsequence.
+func lineDiff(a, b string) string {
+	x := strings.Split(strings.TrimSuffix(a, "\n"), "\n")
+	y := strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+
+	// lcs[i][j] is the LCS length of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			lines = append(lines, line{' ', x[i]})
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', x[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', y[j]})
+			j++
+		}
+	}
+
+	var out strings.Builder
+	lastShown := -1
+	for k, l := range lines {
+		near := false
+		for d := -diffContext; d <= diffContext; d++ {
+			if n := k + d; n >= 0 && n < len(lines) && lines[n].op != ' ' {
+				near = true
+				break
+			}
+		}
+		if !near {
+			continue
+		}
+		if lastShown >= 0 && k > lastShown+1 {
+			out.WriteString("@@\n")
+		}
+		out.WriteByte(l.op)
+		out.WriteString(l.text)
+		out.WriteByte('\n')
+		lastShown = k
+	}
+	return out.String()
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"debug/dwarf"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+func runSymbolize(args []string) error {
+	fs := flag.NewFlagSet("symbolize", flag.ExitOnError)
+	modulePath := fs.String("module", "node.wasm", "module built with debug info (see build -symbols)")
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		return fmt.Errorf("symbolize: expected at most one trace file")
+	}
+
+	code, err := os.ReadFile(*modulePath)
+	if err != nil {
+		return err
+	}
+	syms, err := loadSymbols(code)
+	if err != nil {
+		return fmt.Errorf("symbolize: %s: %w", *modulePath, err)
+	}
+
+	var in io.Reader = os.Stdin
+	if fs.NArg() == 1 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	s := bufio.NewScanner(in)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	for s.Scan() {
+		line := s.Text()
+		fmt.Println(line)
+		if loc := syms.symbolizeLine(line); loc != "" {
+			fmt.Println("\t-> " + loc)
+		}
+	}
+	return s.Err()
+}
+
+// Frame formats of common runtimes. Offsets are byte offsets into the module.
+var (
+	// V8 ("wasm-function[12]:0x3f0") and Wasmer ("<module>[12]:0x3f0").
+	reIndexOffset = regexp.MustCompile(`\[(\d+)\]:0x([0-9a-fA-F]+)`)
+	// Wasmtime ("0x3f0 - <unknown>!<wasm function 12>" or "0x3f0 - m!name").
+	reOffsetDash = regexp.MustCompile(`0x([0-9a-fA-F]+) - `)
+	reWasmtimeFn = regexp.MustCompile(`<wasm function (\d+)>`)
+	// wazero (".$12(i32,i32)").
+	reDollarIndex = regexp.MustCompile(`\.\$(\d+)\(`)
+)
+
+// symbols maps function indices and code offsets of a module back to Go.
+type symbols struct {
+	names     map[uint32]string
+	bodies    []funcBody // sorted by start
+	codeStart uint64     // module offset of the code section payload
+	dwarf     *dwarf.Data
+}
+
+type funcBody struct {
+	index      uint32
+	start, end uint64
+}
+
+func (s *symbols) symbolizeLine(line string) string {
+	var index uint32
+	var offset uint64
+	haveIndex, haveOffset := false, false
+	if m := reIndexOffset.FindStringSubmatch(line); m != nil {
+		index, haveIndex = parseIndex(m[1])
+		offset, haveOffset = parseOffset(m[2])
+	} else {
+		if m := reOffsetDash.FindStringSubmatch(line); m != nil {
+			offset, haveOffset = parseOffset(m[1])
+		}
+		if m := reWasmtimeFn.FindStringSubmatch(line); m != nil {
+			index, haveIndex = parseIndex(m[1])
+		} else if m := reDollarIndex.FindStringSubmatch(line); m != nil {
+			index, haveIndex = parseIndex(m[1])
+		}
+	}
+	if !haveIndex && haveOffset {
+		index, haveIndex = s.functionAt(offset)
+	}
+	if !haveIndex {
+		return ""
+	}
+	name := s.names[index]
+	if name == "" {
+		name = "function " + strconv.FormatUint(uint64(index), 10)
+	}
+	if haveOffset {
+		if file, line, ok := s.lineAt(offset); ok {
+			return name + " at " + file + ":" + strconv.Itoa(line)
+		}
+	}
+	if s.names[index] == "" {
+		return ""
+	}
+	return name
+}
+
+func parseIndex(s string) (uint32, bool) {
+	n, err := strconv.ParseUint(s, 10, 32)
+	return uint32(n), err == nil
+}
+
+func parseOffset(s string) (uint64, bool) {
+	n, err := strconv.ParseUint(s, 16, 64)
+	return n, err == nil
+}
+
+func (s *symbols) functionAt(offset uint64) (uint32, bool) {
+	i := sort.Search(len(s.bodies), func(i int) bool { return s.bodies[i].end > offset })
+	if i < len(s.bodies) && s.bodies[i].start <= offset {
+		return s.bodies[i].index, true
+	}
+	return 0, false
+}
+
+// lineAt resolves a module offset with the DWARF line table, whose
+// addresses are relative to the code section payload.
+func (s *symbols) lineAt(offset uint64) (string, int, bool) {
+	if s.dwarf == nil || offset < s.codeStart {
+		return "", 0, false
+	}
+	pc := offset - s.codeStart
+	r := s.dwarf.Reader()
+	for {
+		e, err := r.Next()
+		if err != nil || e == nil {
+			return "", 0, false
+		}
+		if e.Tag != dwarf.TagCompileUnit {
+			r.SkipChildren()
+			continue
+		}
+		ranges, err := s.dwarf.Ranges(e)
+		if err != nil {
+			continue
+		}
+		for _, rg := range ranges {
+			if pc < rg[0] || pc >= rg[1] {
+				continue
+			}
+			lr, err := s.dwarf.LineReader(e)
+			if err != nil || lr == nil {
+				return "", 0, false
+			}
+			var entry dwarf.LineEntry
+			if lr.SeekPC(pc, &entry) != nil {
+				return "", 0, false
+			}
+			return entry.File.Name, entry.Line, true
+		}
+		r.SkipChildren()
+	}
+}
+
+var errNoSymbols = errors.New("no name section or DWARF info; build it with `flowlike-gen build -symbols` or `-profile debug`")
+
+// loadSymbols reads the function names, code layout and DWARF sections of a
+// module.
+func loadSymbols(code []byte) (*symbols, error) {
+	if len(code) < 8 || !bytes.Equal(code[:4], []byte("\x00asm")) {
+		return nil, fmt.Errorf("not a wasm module")
+	}
+	s := &symbols{names: map[uint32]string{}}
+	debug := map[string][]byte{}
+	var imported uint32
+	for off := 8; off < len(code); {
+		start := off
+		id := code[off]
+		off++
+		size, n := readULEB(code[off:])
+		if n == 0 || off+n+int(size) > len(code) {
+			return nil, fmt.Errorf("malformed section at offset %d", start)
+		}
+		off += n
+		payloadStart := off
+		body := code[off : off+int(size)]
+		off += int(size)
+
+		switch id {
+		case 0:
+			nameLen, m := readULEB(body)
+			if m == 0 || m+int(nameLen) > len(body) {
+				continue
+			}
+			name, data := string(body[m:m+int(nameLen)]), body[m+int(nameLen):]
+			if name == "name" {
+				parseNameSection(data, s.names)
+			} else if strings.HasPrefix(name, ".debug_") {
+				debug[name] = data
+			}
+		case 2:
+			imported = countImportedFuncs(body)
+		case 10:
+			s.codeStart = uint64(payloadStart)
+			s.bodies = parseCodeSection(body, uint64(payloadStart), imported)
+		}
+	}
+	if len(debug[".debug_info"]) > 0 {
+		d, err := dwarf.New(debug[".debug_abbrev"], debug[".debug_aranges"], debug[".debug_frame"],
+			debug[".debug_info"], debug[".debug_line"], debug[".debug_pubnames"],
+			debug[".debug_ranges"], debug[".debug_str"])
+		if err == nil {
+			for _, name := range []string{".debug_addr", ".debug_line_str", ".debug_str_offsets", ".debug_rnglists"} {
+				if data, ok := debug[name]; ok {
+					d.AddSection(name, data)
+				}
+			}
+			s.dwarf = d
+		}
+	}
+	if len(s.names) == 0 && s.dwarf == nil {
+		return nil, errNoSymbols
+	}
+	return s, nil
+}
+
+// parseNameSection reads the function names subsection (id 1).
+func parseNameSection(data []byte, names map[uint32]string) {
+	for off := 0; off < len(data); {
+		id := data[off]
+		off++
+		size, n := readULEB(data[off:])
+		if n == 0 || off+n+int(size) > len(data) {
+			return
+		}
+		off += n
+		sub := data[off : off+int(size)]
+		off += int(size)
+		if id != 1 {
+			continue
+		}
+		count, p := readULEB(sub)
+		for i := uint64(0); i < count && p > 0 && p < len(sub); i++ {
+			idx, m := readULEB(sub[p:])
+			if m == 0 {
+				return
+			}
+			p += m
+			nameLen, m := readULEB(sub[p:])
+			if m == 0 || p+m+int(nameLen) > len(sub) {
+				return
+			}
+			p += m
+			names[uint32(idx)] = string(sub[p : p+int(nameLen)])
+			p += int(nameLen)
+		}
+	}
+}
+
+// countImportedFuncs counts the function imports, which come before the
+// module's own functions in the function index space.
+func countImportedFuncs(body []byte) uint32 {
+	count, p := readULEB(body)
+	var funcs uint32
+	skipName := func() bool {
+		n, m := readULEB(body[p:])
+		if m == 0 || p+m+int(n) > len(body) {
+			return false
+		}
+		p += m + int(n)
+		return true
+	}
+	skipULEB := func() {
+		_, m := readULEB(body[p:])
+		p += m
+	}
+	skipLimits := func() {
+		flags := body[p]
+		p++
+		skipULEB()
+		if flags&1 != 0 {
+			skipULEB()
+		}
+	}
+	for i := uint64(0); i < count && p > 0 && p < len(body); i++ {
+		if !skipName() || !skipName() || p >= len(body) {
+			break
+		}
+		kind := body[p]
+		p++
+		switch kind {
+		case 0: // function: type index
+			funcs++
+			skipULEB()
+		case 1: // table: element type, limits
+			p++
+			skipLimits()
+		case 2: // memory: limits
+			skipLimits()
+		case 3: // global: value type, mutability
+			p += 2
+		case 4: // tag: attribute, type index
+			p++
+			skipULEB()
+		}
+	}
+	return funcs
+}
+
+func parseCodeSection(body []byte, base uint64, imported uint32) []funcBody {
+	count, p := readULEB(body)
+	bodies := make([]funcBody, 0, count)
+	for i := uint64(0); i < count && p > 0 && p < len(body); i++ {
+		size, m := readULEB(body[p:])
+		if m == 0 {
+			break
+		}
+		p += m
+		start := base + uint64(p)
+		bodies = append(bodies, funcBody{index: imported + uint32(i), start: start, end: start + size})
+		p += int(size)
+	}
+	return bodies
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// definitionsFromWasm instantiates a node module with inert host imports and
+// returns the JSON produced by its get_nodes export (get_node as a fallback).
+func definitionsFromWasm(path string) ([]byte, error) {
+	out, err := callExport(path, "get_nodes")
+	if err == errNoExport {
+		out, err = callExport(path, "get_node")
+	}
+	return out, err
+}
+
+var errNoExport = fmt.Errorf("export not found")
+
+// callExport instantiates the module at path and calls a parameterless export
+// returning a packed (ptr<<32 | len) string, which is read from guest memory.
+func callExport(path, export string) ([]byte, error) {
+	ctx := context.Background()
+	inst, err := instantiate(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer inst.Close(ctx)
+	return inst.call(ctx, export)
+}
+
+// instance is an instantiated node module with inert host imports.
+type instance struct {
+	rt  wazero.Runtime
+	mod api.Module
+}
+
+func instantiate(ctx context.Context, path string) (*instance, error) {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rt := wazero.NewRuntime(ctx)
+	wasi_snapshot_preview1.MustInstantiate(ctx, rt)
+
+	compiled, err := rt.CompileModule(ctx, code)
+	if err != nil {
+		rt.Close(ctx)
+		return nil, fmt.Errorf("compile %s: %w", path, err)
+	}
+	if err := stubHostImports(ctx, rt, compiled); err != nil {
+		rt.Close(ctx)
+		return nil, err
+	}
+
+	// TinyGo modules run their initializers in _initialize (reactor) or
+	// _start (command); neither is called automatically here.
+	mod, err := rt.InstantiateModule(ctx, compiled, wazero.NewModuleConfig().WithStartFunctions())
+	if err != nil {
+		rt.Close(ctx)
+		return nil, fmt.Errorf("instantiate %s: %w", path, err)
+	}
+	if init := mod.ExportedFunction("_initialize"); init != nil {
+		if _, err := init.Call(ctx); err != nil {
+			rt.Close(ctx)
+			return nil, fmt.Errorf("_initialize: %w", err)
+		}
+	}
+	return &instance{rt: rt, mod: mod}, nil
+}
+
+func (in *instance) Close(ctx context.Context) { in.rt.Close(ctx) }
+
+// call invokes an export and reads the packed string it returns. Arguments
+// are written to guest memory via the alloc export and passed as (ptr, len).
+func (in *instance) call(ctx context.Context, export string, arg ...[]byte) ([]byte, error) {
+	fn := in.mod.ExportedFunction(export)
+	if fn == nil {
+		return nil, errNoExport
+	}
+	var params []uint64
+	for _, a := range arg {
+		ptr, err := in.write(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, uint64(ptr), uint64(len(a)))
+	}
+	res, err := fn.Call(ctx, params...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", export, err)
+	}
+	packed := res[0]
+	ptr, length := uint32(packed>>32), uint32(packed)
+	data, ok := in.mod.Memory().Read(ptr, length)
+	if !ok {
+		return nil, fmt.Errorf("%s returned out-of-range memory %d+%d", export, ptr, length)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (in *instance) write(ctx context.Context, data []byte) (uint32, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	alloc := in.mod.ExportedFunction("alloc")
+	if alloc == nil {
+		return 0, fmt.Errorf("module does not export alloc")
+	}
+	res, err := alloc.Call(ctx, uint64(len(data)))
+	if err != nil {
+		return 0, fmt.Errorf("alloc: %w", err)
+	}
+	ptr := uint32(res[0])
+	if !in.mod.Memory().Write(ptr, data) {
+		return 0, fmt.Errorf("alloc returned out-of-range memory %d+%d", ptr, len(data))
+	}
+	return ptr, nil
+}
+
+// stubHostImports provides every non-WASI import as a function returning
+// zeros. Definition exports must not depend on the host, so inert imports
+// are enough to instantiate any node module.
+func stubHostImports(ctx context.Context, rt wazero.Runtime, compiled wazero.CompiledModule) error {
+	builders := map[string]wazero.HostModuleBuilder{}
+	for _, fn := range compiled.ImportedFunctions() {
+		moduleName, name, _ := fn.Import()
+		if moduleName == wasi_snapshot_preview1.ModuleName {
+			continue
+		}
+		b, ok := builders[moduleName]
+		if !ok {
+			b = rt.NewHostModuleBuilder(moduleName)
+			builders[moduleName] = b
+		}
+		results := fn.ResultTypes()
+		b.NewFunctionBuilder().
+			WithGoModuleFunction(api.GoModuleFunc(func(_ context.Context, _ api.Module, stack []uint64) {
+				for i := range results {
+					stack[i] = 0
+				}
+			}), fn.ParamTypes(), results).
+			Export(name)
+	}
+	for moduleName, b := range builders {
+		if _, err := b.Instantiate(ctx); err != nil {
+			return fmt.Errorf("stub imports for %s: %w", moduleName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package sdk
+
+import (
+	"math"
+	"strconv"
+)
+
+// MissingInputError reports that an input pin has no value.
+type MissingInputError struct {
+	Pin string
+}
+
+func (e *MissingInputError) Error() string {
+	return "input " + strconv.Quote(e.Pin) + " is missing"
+}
+
+func (e *MissingInputError) errorInfo() ErrorInfo {
+	return ErrorInfo{Code: ErrCodeInvalidInput, Message: e.Error(), Pin: e.Pin}
+}
+
+// TypeMismatchError reports an input value that cannot be converted to the
+// type a node asked for. Received holds the raw JSON value, truncated.
+type TypeMismatchError struct {
+	Pin      string
+	Expected string
+	Received string
+}
+
+func (e *TypeMismatchError) Error() string {
+	return "input " + strconv.Quote(e.Pin) + ": expected " + e.Expected + ", received " + e.Received
+}
+
+func (e *TypeMismatchError) errorInfo() ErrorInfo {
+	return ErrorInfo{Code: ErrCodeInvalidInput, Message: e.Error(), Pin: e.Pin}
+}
+
+const maxReceivedLen = 64
+
+func mismatch(pin, expected, raw string) *TypeMismatchError {
+	if len(raw) > maxReceivedLen {
+		raw = raw[:maxReceivedLen] + "…"
+	}
+	return &TypeMismatchError{Pin: pin, Expected: expected, Received: raw}
+}
+
+// InputString reads an input as a string. Strings are unquoted; numbers and
+// booleans are converted to their textual form.
+func (c *Context) InputString(name string) (string, error) {
+	raw, ok := c.lookup(name)
+	if !ok {
+		return "", &MissingInputError{Pin: name}
+	}
+	return coerceString(name, raw)
+}
+
+// InputI64 reads an input as an integer. Numeric strings ("42") and floats
+// without a fractional part are accepted.
+func (c *Context) InputI64(name string) (int64, error) {
+	raw, ok := c.lookup(name)
+	if !ok {
+		return 0, &MissingInputError{Pin: name}
+	}
+	return coerceI64(name, raw)
+}
+
+// InputF64 reads an input as a float. Integers and numeric strings are accepted.
+func (c *Context) InputF64(name string) (float64, error) {
+	raw, ok := c.lookup(name)
+	if !ok {
+		return 0, &MissingInputError{Pin: name}
+	}
+	return coerceF64(name, raw)
+}
+
+// InputBool reads an input as a boolean. The numbers 1 and 0 and the strings
+// "true", "false", "1" and "0" are accepted.
+func (c *Context) InputBool(name string) (bool, error) {
+	raw, ok := c.lookup(name)
+	if !ok {
+		return false, &MissingInputError{Pin: name}
+	}
+	return coerceBool(name, raw)
+}
+
+func coerceString(pin, raw string) (string, error) {
+	if len(raw) > 0 && raw[0] == '"' {
+		return jsonUnquote(raw), nil
+	}
+	if raw == "true" || raw == "false" {
+		return raw, nil
+	}
+	if _, err := strconv.ParseFloat(raw, 64); err == nil {
+		return raw, nil
+	}
+	return "", mismatch(pin, "String", raw)
+}
+
+func coerceI64(pin, raw string) (int64, error) {
+	s := raw
+	if len(s) > 0 && s[0] == '"' {
+		s = jsonUnquote(s)
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && f == math.Trunc(f) &&
+		f >= math.MinInt64 && f <= math.MaxInt64 {
+		return int64(f), nil
+	}
+	return 0, mismatch(pin, "I64", raw)
+}
+
+func coerceF64(pin, raw string) (float64, error) {
+	s := raw
+	if len(s) > 0 && s[0] == '"' {
+		s = jsonUnquote(s)
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	return 0, mismatch(pin, "F64", raw)
+}
+
+func coerceBool(pin, raw string) (bool, error) {
+	switch raw {
+	case "true", "1", `"true"`, `"1"`:
+		return true, nil
+	case "false", "0", `"false"`, `"0"`:
+		return false, nil
+	}
+	return false, mismatch(pin, "Bool", raw)
+}
@@ -0,0 +1,354 @@
+// Package compress provides streaming compression that plugs into the
+// storage reader/writer and HTTP bodies. Gzip is implemented in the module
+// with compress/gzip; zstd and brotli are delegated to the host through
+// sdk.OpenCodecStream, since their Go implementations are too large for
+// TinyGo builds.
+//
+//	r, _ := compress.Open(ctx, path) // "data.json.gz" or "data.json.zst"
+//	defer r.Close()
+//	w, _ := compress.Create(ctx, "out.ndjson.gz", compress.Gzip, 0)
+//	io.Copy(w, r)
+//	w.Close()
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+var (
+	ErrUnsupported = errors.New("compress: codec not supported")
+	ErrCorrupt     = errors.New("compress: corrupt input")
+	ErrStorage     = errors.New("compress: storage file not available")
+)
+
+// Codec identifies a compression format.
+type Codec string
+
+const (
+	None   Codec = ""
+	Gzip   Codec = "gzip"
+	Zstd   Codec = "zstd"
+	Brotli Codec = "br"
+)
+
+// ByExtension picks a codec from a file name: ".gz", ".zst" or ".br".
+func ByExtension(name string) Codec {
+	switch {
+	case strings.HasSuffix(name, ".gz"), strings.HasSuffix(name, ".gzip"):
+		return Gzip
+	case strings.HasSuffix(name, ".zst"), strings.HasSuffix(name, ".zstd"):
+		return Zstd
+	case strings.HasSuffix(name, ".br"):
+		return Brotli
+	}
+	return None
+}
+
+// Detect recognizes gzip and zstd data by their magic bytes. Brotli has no
+// magic number and is never detected.
+func Detect(prefix []byte) Codec {
+	switch {
+	case len(prefix) >= 2 && prefix[0] == 0x1f && prefix[1] == 0x8b:
+		return Gzip
+	case len(prefix) >= 4 && prefix[0] == 0x28 && prefix[1] == 0xb5 && prefix[2] == 0x2f && prefix[3] == 0xfd:
+		return Zstd
+	}
+	return None
+}
+
+// NewReader decompresses r with the given codec. None passes data through.
+// Closing the result closes r if it is an io.Closer.
+func NewReader(r io.Reader, codec Codec) (io.ReadCloser, error) {
+	switch codec {
+	case None:
+		return readCloser{r, closerOf(r)}, nil
+	case Gzip:
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, ErrCorrupt
+		}
+		return readCloser{zr, closeBoth{zr, closerOf(r)}}, nil
+	}
+	h, ok := sdk.OpenCodecStream(string(codec), true, 0)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return &hostReader{src: r, handle: h}, nil
+}
+
+// NewAutoReader detects gzip or zstd from the first bytes of r and
+// decompresses accordingly; other data passes through unchanged.
+func NewAutoReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	prefix, _ := br.Peek(4)
+	rc, err := NewReader(br, Detect(prefix))
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := r.(io.Closer); ok {
+		return readCloser{rc, closeBoth{rc, c}}, nil
+	}
+	return rc, nil
+}
+
+// NewWriter compresses into w with the given codec. level 0 uses the codec
+// default. Close flushes the compressor and then closes w if it is an
+// io.Closer, which commits a StorageWriter. If flushing fails, a w with an
+// Abort method such as StorageWriter is aborted instead, so no truncated
+// file is committed.
+func NewWriter(w io.Writer, codec Codec, level int) (io.WriteCloser, error) {
+	switch codec {
+	case None:
+		return writeCloser{w, closerOf(w)}, nil
+	case Gzip:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		zw, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			return nil, err
+		}
+		return writeCloser{zw, closeBoth{zw, closerOf(w)}}, nil
+	}
+	h, ok := sdk.OpenCodecStream(string(codec), false, level)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return &hostWriter{dst: w, handle: h}, nil
+}
+
+// Open streams a file from flow storage, decompressing it by extension or,
+// failing that, by content. It requires the "storage" permission.
+func Open(ctx *sdk.Context, path string) (io.ReadCloser, error) {
+	r, err := ctx.Host().OpenStorageReader(path)
+	if err != nil {
+		return nil, ErrStorage
+	}
+	var rc io.ReadCloser
+	if codec := ByExtension(path); codec != None {
+		rc, err = NewReader(r, codec)
+	} else {
+		rc, err = NewAutoReader(r)
+	}
+	if err != nil {
+		r.Close()
+	}
+	return rc, err
+}
+
+// Create streams a compressed file into flow storage; it appears once Close
+// succeeds. It requires the "storage" permission.
+func Create(ctx *sdk.Context, path string, codec Codec, level int) (io.WriteCloser, error) {
+	w, err := ctx.Host().CreateStorageWriter(path)
+	if err != nil {
+		return nil, ErrStorage
+	}
+	wc, err := NewWriter(w, codec, level)
+	if err != nil {
+		w.Abort()
+	}
+	return wc, err
+}
+
+// Compress compresses data in one go.
+func Compress(data []byte, codec Codec, level int) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, codec, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress decompresses data in one go.
+func Decompress(data []byte, codec Codec) ([]byte, error) {
+	r, err := NewReader(bytes.NewReader(data), codec)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// --- host-backed codecs ---
+
+const hostChunkSize = 32 * 1024
+
+type hostReader struct {
+	src    io.Reader
+	handle int32
+	out    []byte
+	chunk  []byte
+	done   bool
+	err    error
+}
+
+func (r *hostReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.done {
+			return 0, io.EOF
+		}
+		if r.chunk == nil {
+			r.chunk = make([]byte, hostChunkSize)
+		}
+		n, err := r.src.Read(r.chunk)
+		if n > 0 {
+			out, ok := sdk.CodecStreamUpdate(r.handle, r.chunk[:n])
+			if !ok {
+				r.err, r.done = ErrCorrupt, true
+				continue
+			}
+			r.out = out
+		}
+		if err == io.EOF {
+			out, ok := sdk.CodecStreamFinish(r.handle)
+			r.done = true
+			if !ok {
+				r.err = ErrCorrupt
+			}
+			r.out = append(r.out, out...)
+		} else if err != nil {
+			r.err = err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *hostReader) Close() error {
+	if !r.done {
+		r.done = true
+		sdk.CodecStreamFinish(r.handle)
+	}
+	if c, ok := r.src.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+type hostWriter struct {
+	dst    io.Writer
+	handle int32
+	closed bool
+}
+
+func (w *hostWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, sdk.ErrStorageClosed
+	}
+	out, ok := sdk.CodecStreamUpdate(w.handle, p)
+	if !ok {
+		return 0, ErrCorrupt
+	}
+	if len(out) > 0 {
+		if _, err := w.dst.Write(out); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *hostWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	out, ok := sdk.CodecStreamFinish(w.handle)
+	var err error
+	if !ok {
+		err = ErrCorrupt
+	} else if len(out) > 0 {
+		_, err = w.dst.Write(out)
+	}
+	if err != nil && abort(w.dst) {
+		return err
+	}
+	if c, ok := w.dst.(io.Closer); ok {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// --- plumbing ---
+
+type readCloser struct {
+	io.Reader
+	c io.Closer
+}
+
+func (r readCloser) Close() error {
+	if r.c == nil {
+		return nil
+	}
+	return r.c.Close()
+}
+
+type writeCloser struct {
+	io.Writer
+	c io.Closer
+}
+
+func (w writeCloser) Close() error {
+	if w.c == nil {
+		return nil
+	}
+	return w.c.Close()
+}
+
+// closeBoth closes the codec first, then the underlying stream. If the codec
+// fails to flush, the underlying stream is aborted when it supports that.
+type closeBoth struct {
+	codec, under io.Closer
+}
+
+func (c closeBoth) Close() error {
+	err := c.codec.Close()
+	if err != nil && abort(c.under) {
+		return err
+	}
+	if c.under != nil {
+		if cerr := c.under.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// aborter is implemented by writers that can discard their output, such as
+// sdk.StorageWriter.
+type aborter interface {
+	Abort() error
+}
+
+// abort aborts v if it is an aborter and reports whether it was.
+func abort(v any) bool {
+	a, ok := v.(aborter)
+	if ok {
+		a.Abort()
+	}
+	return ok
+}
+
+func closerOf(v any) io.Closer {
+	c, _ := v.(io.Closer)
+	return c
+}
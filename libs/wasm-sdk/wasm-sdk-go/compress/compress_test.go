@@ -0,0 +1,147 @@
+package compress
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestByExtension(t *testing.T) {
+	tests := map[string]Codec{
+		"data.json.gz":  Gzip,
+		"a.gzip":        Gzip,
+		"logs.zst":      Zstd,
+		"x.zstd":        Zstd,
+		"page.html.br":  Brotli,
+		"plain.json":    None,
+		"gz":            None,
+		"archive.tar.x": None,
+	}
+	for name, want := range tests {
+		if got := ByExtension(name); got != want {
+			t.Errorf("ByExtension(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		prefix []byte
+		want   Codec
+	}{
+		{[]byte{0x1f, 0x8b, 0x08}, Gzip},
+		{[]byte{0x28, 0xb5, 0x2f, 0xfd}, Zstd},
+		{[]byte{0x28, 0xb5, 0x2f}, None},
+		{[]byte("{}"), None},
+		{nil, None},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.prefix); got != tt.want {
+			t.Errorf("Detect(% x) = %q, want %q", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	data := []byte(strings.Repeat("flow-like compresses well. ", 500))
+	for _, tt := range []struct {
+		codec Codec
+		level int
+	}{
+		{None, 0},
+		{Gzip, 0},
+		{Gzip, 1},
+		{Gzip, 9},
+	} {
+		packed, err := Compress(data, tt.codec, tt.level)
+		if err != nil {
+			t.Fatalf("Compress(%q, %d): %v", tt.codec, tt.level, err)
+		}
+		if tt.codec == Gzip && len(packed) >= len(data)/10 {
+			t.Errorf("gzip level %d: %d bytes from %d", tt.level, len(packed), len(data))
+		}
+		got, err := Decompress(packed, tt.codec)
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("Decompress(%q, %d) = %d bytes, %v", tt.codec, tt.level, len(got), err)
+		}
+		r, err := NewAutoReader(bytes.NewReader(packed))
+		if err != nil {
+			t.Fatalf("NewAutoReader(%q): %v", tt.codec, err)
+		}
+		got, err = io.ReadAll(r)
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("NewAutoReader(%q) = %d bytes, %v", tt.codec, len(got), err)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := NewWriter(io.Discard, Gzip, 42); err == nil {
+		t.Error("NewWriter(gzip, level 42) succeeded")
+	}
+	if _, err := Decompress([]byte("not gzip"), Gzip); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Decompress(garbage) error = %v, want ErrCorrupt", err)
+	}
+	packed, _ := Compress([]byte("hello, world"), Gzip, 0)
+	if _, err := Decompress(packed[:len(packed)-6], Gzip); err == nil {
+		t.Error("Decompress(truncated) succeeded")
+	}
+}
+
+type closeRecorder struct {
+	bytes.Buffer
+	closed int
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestCloseUnderlying(t *testing.T) {
+	var dst closeRecorder
+	w, err := NewWriter(&dst, Gzip, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "payload")
+	if err := w.Close(); err != nil || dst.closed != 1 {
+		t.Fatalf("writer Close = %v, underlying closed %d times", err, dst.closed)
+	}
+	src := &closeRecorder{Buffer: *bytes.NewBuffer(dst.Bytes())}
+	r, err := NewAutoReader(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := io.ReadAll(r); string(got) != "payload" {
+		t.Errorf("read back %q", got)
+	}
+	if err := r.Close(); err != nil || src.closed == 0 {
+		t.Errorf("reader Close = %v, underlying closed %d times", err, src.closed)
+	}
+}
+
+// abortRecorder is a storage-like writer that fails every write.
+type abortRecorder struct {
+	closed, aborted int
+}
+
+func (a *abortRecorder) Write(p []byte) (int, error) { return 0, errors.New("disk full") }
+func (a *abortRecorder) Close() error                { a.closed++; return nil }
+func (a *abortRecorder) Abort() error                { a.aborted++; return nil }
+
+func TestAbortOnFailedFlush(t *testing.T) {
+	dst := &abortRecorder{}
+	w, err := NewWriter(dst, Gzip, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err == nil {
+		t.Fatal("Close succeeded although the underlying writer failed")
+	}
+	if dst.aborted != 1 || dst.closed != 0 {
+		t.Errorf("underlying writer aborted %d and closed %d times, want 1 and 0", dst.aborted, dst.closed)
+	}
+}
@@ -6,19 +6,53 @@ import (
 )
 
 type Context struct {
-	input   ExecutionInput
-	result  ExecutionResult
-	outputs map[string]string
+	input       ExecutionInput
+	result      ExecutionResult
+	outputs     map[string]string
+	successExec string
+	def         *NodeDefinition
+	trace       *callTrace
+	strict      bool
+	problems    []string
+	verifyOut   bool
+	skipped     map[string]bool
+	det         *deterministicState
+	jar         *cookieJar
+	rec         *modelRecorder
+	dryEffects  []string
+	secrets     []string
+	packForm    *FormDefinition
 }
 
 func NewContext(input ExecutionInput) *Context {
-	return &Context{
-		input:   input,
-		result:  SuccessResult(),
-		outputs: make(map[string]string),
+	c := &Context{
+		input:       input,
+		result:      SuccessResult(),
+		outputs:     make(map[string]string),
+		successExec: DefaultExecPin,
 	}
+	if input.Deterministic != nil {
+		c.det = newDeterministicState(input.Deterministic, input.NodeID)
+	}
+	return c
+}
+
+// SetDefaultExecPin changes the exec output activated by Success.
+// Registry calls this with the node definition's resolved default.
+func (c *Context) SetDefaultExecPin(pin string) {
+	c.successExec = pin
+}
+
+// BindDefinition tells the context which node it executes, enabling checks
+// against the declared pins. Registry binds every context it creates.
+func (c *Context) BindDefinition(def *NodeDefinition) {
+	c.def = def
+	c.successExec = def.ResolveDefaultExecPin()
 }
 
+// Definition returns the bound node definition, or nil.
+func (c *Context) Definition() *NodeDefinition { return c.def }
+
 // --- Metadata ---
 
 func (c *Context) NodeID() string      { return c.input.NodeID }
@@ -33,13 +67,14 @@ func (c *Context) LogLevelValue() uint8 { return c.input.LogLevel }
 // --- Input getters ---
 
 func (c *Context) GetInput(name string) (string, bool) {
-	v, ok := c.input.Inputs[name]
+	v, ok := c.lookup(name)
 	return v, ok
 }
 
 func (c *Context) GetString(name, defaultValue string) string {
-	v, ok := c.input.Inputs[name]
+	v, ok := c.lookup(name)
 	if !ok {
+		c.noteMissing(name)
 		return defaultValue
 	}
 	if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
@@ -48,36 +83,51 @@ func (c *Context) GetString(name, defaultValue string) string {
 	return v
 }
 
+// GetI64 reads an integer input, coercing numeric strings and integral
+// floats. Missing inputs yield defaultValue; so do values that cannot be
+// coerced, which are also logged as a warning. Use InputI64 to handle them.
 func (c *Context) GetI64(name string, defaultValue int64) int64 {
-	v, ok := c.input.Inputs[name]
+	v, ok := c.lookup(name)
 	if !ok {
+		c.noteMissing(name)
 		return defaultValue
 	}
-	n, err := strconv.ParseInt(v, 10, 64)
+	n, err := coerceI64(name, v)
 	if err != nil {
+		c.noteProblem(err)
 		return defaultValue
 	}
 	return n
 }
 
+// GetF64 reads a float input with the same coercion and fallback as GetI64.
 func (c *Context) GetF64(name string, defaultValue float64) float64 {
-	v, ok := c.input.Inputs[name]
+	v, ok := c.lookup(name)
 	if !ok {
+		c.noteMissing(name)
 		return defaultValue
 	}
-	f, err := strconv.ParseFloat(v, 64)
+	f, err := coerceF64(name, v)
 	if err != nil {
+		c.noteProblem(err)
 		return defaultValue
 	}
 	return f
 }
 
+// GetBool reads a boolean input with the same coercion and fallback as GetI64.
 func (c *Context) GetBool(name string, defaultValue bool) bool {
-	v, ok := c.input.Inputs[name]
+	v, ok := c.lookup(name)
 	if !ok {
+		c.noteMissing(name)
+		return defaultValue
+	}
+	b, err := coerceBool(name, v)
+	if err != nil {
+		c.noteProblem(err)
 		return defaultValue
 	}
-	return v == "true"
+	return b
 }
 
 // --- Output setters ---
@@ -101,49 +151,49 @@ func (c *Context) SetError(err string) {
 // --- Level-gated logging ---
 
 func (c *Context) shouldLog(level int) bool {
-	return level >= int(c.input.LogLevel)
+	return level >= int(c.input.LogLevel) && c.host("")
 }
 
 func (c *Context) Debug(msg string) {
 	if c.shouldLog(LogLevelDebug) {
-		LogDebug(msg)
+		LogDebug(c.redact(msg))
 	}
 }
 
 func (c *Context) Info(msg string) {
 	if c.shouldLog(LogLevelInfo) {
-		LogInfo(msg)
+		LogInfo(c.redact(msg))
 	}
 }
 
 func (c *Context) Warn(msg string) {
 	if c.shouldLog(LogLevelWarn) {
-		LogWarn(msg)
+		LogWarn(c.redact(msg))
 	}
 }
 
 func (c *Context) Error(msg string) {
 	if c.shouldLog(LogLevelError) {
-		LogError(msg)
+		LogError(c.redact(msg))
 	}
 }
 
 // --- Conditional streaming ---
 
 func (c *Context) StreamText(text string) {
-	if c.StreamEnabled() {
+	if c.StreamEnabled() && c.host(PermStreaming) {
 		StreamText(text)
 	}
 }
 
 func (c *Context) StreamJSON(data string) {
-	if c.StreamEnabled() {
+	if c.StreamEnabled() && c.host(PermStreaming) {
 		StreamEmit("json", data)
 	}
 }
 
 func (c *Context) StreamProgress(progress float32, message string) {
-	if c.StreamEnabled() {
+	if c.StreamEnabled() && c.host(PermStreaming) {
 		var b strings.Builder
 		b.WriteString(`{"progress":`)
 		b.WriteString(strconv.FormatFloat(float64(progress), 'f', -1, 32))
@@ -154,58 +204,396 @@ func (c *Context) StreamProgress(progress float32, message string) {
 	}
 }
 
+// --- Notifications ---
+
+// Deprecated: Use c.Host().Notify, which reports why the call failed.
+func (c *Context) Notify(level, title, message string, actions ...NotificationAction) bool {
+	return c.Host().Notify(level, title, message, actions...) == nil
+}
+
+// --- Desktop bridge ---
+
+func (c *Context) DesktopAvailable() bool { return c.host("") && DesktopAvailable() }
+
+// Deprecated: Use c.Host().ClipboardRead, which reports why the call failed.
+func (c *Context) ClipboardRead() string {
+	text, _ := c.Host().ClipboardRead()
+	return text
+}
+
+// Deprecated: Use c.Host().ClipboardWrite, which reports why the call failed.
+func (c *Context) ClipboardWrite(text string) bool {
+	return c.Host().ClipboardWrite(text) == nil
+}
+
+// Deprecated: Use c.Host().OpenFile, which reports why the call failed.
+func (c *Context) OpenFile(flowPathJSON string) bool {
+	return c.Host().OpenFile(flowPathJSON) == nil
+}
+
 // --- Cache ---
 
-func (c *Context) CacheGet(key string) string        { return CacheGet(key) }
-func (c *Context) CacheSet(key, value string)        { CacheSet(key, value) }
-func (c *Context) CacheDelete(key string)            { CacheDelete(key) }
-func (c *Context) CacheHas(key string) bool          { return CacheHas(key) }
+func (c *Context) CacheGet(key string) string {
+	if !c.host("") {
+		return ""
+	}
+	return CacheGet(key)
+}
+
+func (c *Context) CacheSet(key, value string) {
+	if c.host("") {
+		CacheSet(key, value)
+	}
+}
+
+func (c *Context) CacheDelete(key string) {
+	if c.host("") {
+		CacheDelete(key)
+	}
+}
+
+func (c *Context) CacheHas(key string) bool { return c.host("") && CacheHas(key) }
+
+// --- Counters / Quotas ---
+
+func (c *Context) CounterIncr(name string, delta int64) int64 {
+	if !c.host("") {
+		return 0
+	}
+	return CounterIncr(name, delta)
+}
+
+// Deprecated: Use c.Host().QuotaConsume, which reports why the call failed.
+func (c *Context) QuotaConsume(name string, amount int64) bool {
+	return c.Host().QuotaConsume(name, amount) == nil
+}
+
+// GetQuota reports the remaining budget of kind so nodes can degrade
+// gracefully, e.g. pick a smaller model or skip enrichment, instead of
+// failing once it is exhausted. ok is false when the host does not know
+// the quota.
+//
+// Deprecated: Use c.Host().GetQuota, which reports why the call failed.
+func (c *Context) GetQuota(kind string) (Quota, bool) {
+	q, err := c.Host().GetQuota(kind)
+	return q, err == nil
+}
+
+// --- Cost tracking / Analytics ---
+
+func (c *Context) ReportCost(units float64, kind string) bool {
+	return c.host("") && ReportCost(units, kind)
+}
+
+func (c *Context) TrackEvent(name string, properties map[string]string) bool {
+	return c.host("") && TrackEvent(name, properties)
+}
 
 // --- Variables ---
 
 func (c *Context) GetVariable(name string) string {
+	if !c.host("") {
+		return ""
+	}
 	return GetVariable(name)
 }
 
 func (c *Context) SetVariable(name, value string) {
-	SetVariable(name, value)
+	if c.host("") {
+		SetVariable(name, value)
+	}
+}
+
+func (c *Context) DeleteVariable(name string) {
+	if c.host("") {
+		DeleteVariable(name)
+	}
 }
 
-func (c *Context) DeleteVariable(name string)        { DeleteVariable(name) }
-func (c *Context) HasVariable(name string) bool      { return HasVariable(name) }
+func (c *Context) HasVariable(name string) bool { return c.host("") && HasVariable(name) }
 
 // --- Dirs ---
 
-func (c *Context) StorageDir(nodeScoped bool) string              { return StorageDir(nodeScoped) }
-func (c *Context) UploadDir() string                              { return UploadDir() }
-func (c *Context) CacheDirPath(nodeScoped, userScoped bool) string { return CacheDirPath(nodeScoped, userScoped) }
-func (c *Context) UserDir(nodeScoped bool) string                 { return UserDir(nodeScoped) }
+// Deprecated: Use c.Host().StorageDir with WithNodeScope, which reports why
+// the call failed.
+func (c *Context) StorageDir(nodeScoped bool) string {
+	dir, _ := c.Host().StorageDir(scopeOptions(nodeScoped, false)...)
+	return dir
+}
+
+// Deprecated: Use c.Host().UploadDir, which reports why the call failed.
+func (c *Context) UploadDir() string {
+	dir, _ := c.Host().UploadDir()
+	return dir
+}
+
+// Deprecated: Use c.Host().CacheDirPath with WithNodeScope and
+// WithUserScope, which reports why the call failed.
+func (c *Context) CacheDirPath(nodeScoped, userScoped bool) string {
+	dir, _ := c.Host().CacheDirPath(scopeOptions(nodeScoped, userScoped)...)
+	return dir
+}
+
+// Deprecated: Use c.Host().StorageDir with WithUserScope, which reports why
+// the call failed.
+func (c *Context) UserDir(nodeScoped bool) string {
+	dir, _ := c.Host().UserDir(scopeOptions(nodeScoped, false)...)
+	return dir
+}
 
 // --- Storage I/O ---
 
-func (c *Context) StorageRead(path string) string             { return StorageRead(path) }
-func (c *Context) StorageWrite(path, data string) bool        { return StorageWrite(path, data) }
-func (c *Context) StorageList(flowPathJSON string) string     { return StorageList(flowPathJSON) }
+// Deprecated: Use c.Host().StorageRead, which reports why the call failed.
+func (c *Context) StorageRead(path string) string {
+	data, _ := c.Host().StorageRead(path)
+	return data
+}
+
+// Deprecated: Use c.Host().StorageWrite, which reports why the call failed.
+func (c *Context) StorageWrite(path, data string) bool {
+	return c.Host().StorageWrite(path, data) == nil
+}
+
+// StorageWriteWithPolicy writes a file the platform expires or holds
+// according to policy, e.g. RetentionPolicy{TTL: 7 * 24 * time.Hour} for
+// transient exports.
+//
+// Deprecated: Use c.Host().StorageWriteWithPolicy, which reports why the call failed.
+func (c *Context) StorageWriteWithPolicy(path, data string, policy RetentionPolicy) bool {
+	return c.Host().StorageWriteWithPolicy(path, data, policy) == nil
+}
+
+// Deprecated: Use c.Host().StorageList, which reports why the call failed.
+func (c *Context) StorageList(flowPathJSON string) string {
+	list, _ := c.Host().StorageList(flowPathJSON)
+	return list
+}
+
+// Deprecated: Use c.Host().OpenStorageReader, which reports why the call failed.
+func (c *Context) OpenStorageReader(path string) (*StorageReader, bool) {
+	r, err := c.Host().OpenStorageReader(path)
+	return r, err == nil
+}
+
+// Deprecated: Use c.Host().CreateStorageWriter, which reports why the call failed.
+func (c *Context) CreateStorageWriter(path string) (*StorageWriter, bool) {
+	w, err := c.Host().CreateStorageWriter(path)
+	return w, err == nil
+}
 
 // --- Embeddings ---
 
-func (c *Context) EmbedText(bitJSON, textsJSON string) string { return EmbedText(bitJSON, textsJSON) }
+// Deprecated: Use c.Host().EmbedText, which reports why the call failed.
+func (c *Context) EmbedText(bitJSON, textsJSON string) string {
+	vectors, _ := c.Host().EmbedText(bitJSON, textsJSON)
+	return vectors
+}
+
+// --- Chat ---
+
+// Deprecated: Use c.Host().ChatComplete, which reports why the call failed.
+func (c *Context) ChatComplete(bitJSON string, req ChatRequest) (ChatResponse, bool) {
+	resp, err := c.Host().ChatComplete(bitJSON, req)
+	return resp, err == nil
+}
+
+// --- Entity recognition ---
+
+// Deprecated: Use c.Host().DetectEntities, which reports why the call failed.
+func (c *Context) DetectEntities(bitJSON, text string) []Entity {
+	entities, _ := c.Host().DetectEntities(bitJSON, text)
+	return entities
+}
+
+// --- Model discovery ---
+
+// Deprecated: Use c.Host().ListModelBits, which reports why the call failed.
+func (c *Context) ListModelBits(capability string) []ModelBit {
+	bits, _ := c.Host().ListModelBits(capability)
+	return bits
+}
+
+// Deprecated: Use c.Host().DefaultBit, which reports why the call failed.
+func (c *Context) DefaultBit(capability string) (ModelBit, bool) {
+	bit, err := c.Host().DefaultBit(capability)
+	return bit, err == nil
+}
+
+// --- Reranking ---
+
+// Deprecated: Use c.Host().Rerank, which reports why the call failed.
+func (c *Context) Rerank(bitJSON, query string, candidates []string) []ScoredResult {
+	results, _ := c.Host().Rerank(bitJSON, query, candidates)
+	return results
+}
+
+// --- Vector search ---
+
+// Deprecated: Use c.Host().VectorUpsert, which reports why the call failed.
+func (c *Context) VectorUpsert(collection string, records []VectorRecord) bool {
+	return c.Host().VectorUpsert(collection, records) == nil
+}
+
+// Deprecated: Use c.Host().VectorSearch, which reports why the call failed.
+func (c *Context) VectorSearch(collection, vectorJSON string, limit int) []VectorMatch {
+	matches, _ := c.Host().VectorSearch(collection, vectorJSON, limit)
+	return matches
+}
 
 // --- HTTP ---
 
+// Deprecated: Use c.Host().HTTPRequest, which reports why the call failed.
 func (c *Context) HTTPRequest(method int, url, headers, body string) bool {
-	return HTTPRequest(method, url, headers, body)
+	return c.Host().HTTPRequest(method, url, headers, body) == nil
+}
+
+// Deprecated: Use c.Host().HTTPFetch, which reports why the call failed.
+func (c *Context) HTTPFetch(method int, url, headers, body string) (HTTPResponse, bool) {
+	resp, err := c.Host().HTTPFetch(method, url, headers, body)
+	return resp, err == nil
+}
+
+// HTTPFetchWith is HTTPFetch with per-request proxy, TLS and timeout options.
+//
+// Deprecated: Use c.Host().HTTPFetchWith, which reports why the call failed.
+func (c *Context) HTTPFetchWith(method int, url, headers, body string, opts HTTPRequestOptions) (HTTPResponse, bool) {
+	resp, err := c.Host().HTTPFetchWith(method, url, headers, body, opts)
+	return resp, err == nil
+}
+
+// HTTPRequestWithClientCert sends req over mutual TLS, presenting the
+// platform-managed client certificate certRef. Use it for internal APIs that
+// authenticate callers by certificate; the key is never exposed to the node.
+//
+// Deprecated: Use c.Host().HTTPRequestWithClientCert, which reports why the call failed.
+func (c *Context) HTTPRequestWithClientCert(certRef string, req HTTPCall) (HTTPResponse, bool) {
+	resp, err := c.Host().HTTPRequestWithClientCert(certRef, req)
+	return resp, err == nil
+}
+
+// --- File transfer ---
+
+// Deprecated: Use c.Host().TransferList, which reports why the call failed.
+func (c *Context) TransferList(conn TransferConn, dir string) ([]RemoteFile, bool) {
+	files, err := c.Host().TransferList(conn, dir)
+	return files, err == nil
+}
+
+// Deprecated: Use c.Host().TransferGet, which reports why the call failed.
+func (c *Context) TransferGet(conn TransferConn, remotePath, path string) bool {
+	return c.Host().TransferGet(conn, remotePath, path) == nil
+}
+
+// Deprecated: Use c.Host().TransferPut, which reports why the call failed.
+func (c *Context) TransferPut(conn TransferConn, path, remotePath string) bool {
+	return c.Host().TransferPut(conn, path, remotePath) == nil
+}
+
+// --- External buckets ---
+
+// Deprecated: Use c.Host().BucketList, which reports why the call failed.
+func (c *Context) BucketList(b Bucket, prefix string) ([]BucketObject, bool) {
+	objects, err := c.Host().BucketList(b, prefix)
+	return objects, err == nil
+}
+
+// Deprecated: Use c.Host().BucketGet, which reports why the call failed.
+func (c *Context) BucketGet(b Bucket, key, path string) bool {
+	return c.Host().BucketGet(b, key, path) == nil
+}
+
+// Deprecated: Use c.Host().BucketPut, which reports why the call failed.
+func (c *Context) BucketPut(b Bucket, path, key string) bool {
+	return c.Host().BucketPut(b, path, key) == nil
+}
+
+// Deprecated: Use c.Host().BucketDelete, which reports why the call failed.
+func (c *Context) BucketDelete(b Bucket, key string) bool {
+	return c.Host().BucketDelete(b, key) == nil
+}
+
+// --- Local processes ---
+
+// Deprecated: Use c.Host().Exec, which reports why the call failed.
+func (c *Context) Exec(cmd string, args []string, stdin string) (ExecOutput, bool) {
+	out, err := c.Host().Exec(cmd, args, stdin)
+	return out, err == nil
 }
 
 // --- Auth ---
 
-func (c *Context) GetOAuthToken(provider string) string { return GetOAuthToken(provider) }
-func (c *Context) HasOAuthToken(provider string) bool   { return HasOAuthToken(provider) }
+// Deprecated: Use c.Host().GetOAuthToken, which reports why the call failed.
+func (c *Context) GetOAuthToken(provider string) string {
+	token, _ := c.Host().GetOAuthToken(provider)
+	return token
+}
+
+func (c *Context) HasOAuthToken(provider string) bool {
+	return c.host(PermOAuth) && HasOAuthToken(provider)
+}
+
+// Deprecated: Use c.Host().RefreshOAuthToken, which reports why the call failed.
+func (c *Context) RefreshOAuthToken(provider string) string {
+	token, _ := c.Host().RefreshOAuthToken(provider)
+	return token
+}
+
+// UserHasRole reports whether the user of the run holds role. It is false
+// when the host cannot tell, so gated behavior stays off.
+func (c *Context) UserHasRole(role string) bool {
+	return c.host("") && HasRole(role)
+}
+
+// UserCan reports whether the user of the run may perform action on
+// resource, following the platform's permission model. Like UserHasRole it
+// fails closed.
+func (c *Context) UserCan(action, resource string) bool {
+	return c.host("") && Can(action, resource)
+}
 
 // --- Time / Random ---
 
-func (c *Context) TimeNow() int64 { return TimeNow() }
-func (c *Context) Random() int64  { return Random() }
+// TimeNow returns the host clock. In deterministic runs it starts at the
+// host-provided time and advances by one on every call.
+func (c *Context) TimeNow() int64 {
+	if !c.host("") {
+		return 0
+	}
+	if c.det != nil {
+		t := c.det.clock
+		c.det.clock++
+		return t
+	}
+	return TimeNow()
+}
+
+// Random returns a random number; in deterministic runs it is drawn from a
+// stream seeded by the host and the node ID.
+func (c *Context) Random() int64 {
+	if !c.host("") {
+		return 0
+	}
+	if c.det != nil {
+		return c.det.next()
+	}
+	return Random()
+}
+
+// Deadline returns when the run's execution budget ends (Unix milliseconds
+// on the host clock) and false when the run has no budget or is a dry run.
+func (c *Context) Deadline() (int64, bool) {
+	if !c.host("") {
+		return 0, false
+	}
+	d := ExecutionDeadline()
+	return d, d > 0
+}
+
+// Cancelled reports whether the run was cancelled. Long-running nodes should
+// check it between steps and stop early.
+func (c *Context) Cancelled() bool {
+	return c.host("") && IsCancelled()
+}
 
 // --- Finalize ---
 
@@ -213,11 +601,30 @@ func (c *Context) Finish() ExecutionResult {
 	for k, v := range c.outputs {
 		c.result.Outputs[k] = v
 	}
+	if c.strict {
+		c.enforceStrict()
+	} else if c.verifyOut {
+		c.enforceOutputs()
+	}
+	if c.result.Error != nil {
+		msg := c.redact(*c.result.Error)
+		c.result.Error = &msg
+	}
+	if c.result.ErrorInfo != nil {
+		c.result.ErrorInfo.Message = c.redact(c.result.ErrorInfo.Message)
+	}
 	return c.result
 }
 
+// Success activates the node's default exec output ("exec_out" unless
+// configured otherwise) and finishes.
 func (c *Context) Success() ExecutionResult {
-	c.ActivateExec("exec_out")
+	return c.SuccessVia(c.successExec)
+}
+
+// SuccessVia activates the given exec output and finishes.
+func (c *Context) SuccessVia(pin string) ExecutionResult {
+	c.ActivateExec(pin)
 	return c.Finish()
 }
 
@@ -0,0 +1,406 @@
+package sdk
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cookie is an HTTP cookie held in a session's cookie jar.
+type Cookie struct {
+	Name     string
+	Value    string
+	Domain   string // without a leading dot
+	Path     string
+	HostOnly bool // sent only to Domain itself, not its subdomains
+	Secure   bool
+	HTTPOnly bool
+	// Expires is the expiry on the TimeNow clock (Unix milliseconds), or 0
+	// for a session cookie, which lives until ClearSession.
+	Expires int64
+}
+
+// cookieJar is the per-run view of a session persisted in the cache.
+type cookieJar struct {
+	key     string
+	cookies []Cookie
+}
+
+// UseCookieJar opts the node into cookie handling for HTTPFetch and
+// HTTPRequest: matching cookies are sent with every request and Set-Cookie
+// response headers update the jar. The jar is stored in the node-scoped
+// cache under session ("" means "default"), so a login in one run is still
+// valid in the next run of the same node until the cookies expire or
+// ClearSession is called. Without this call no cookies are kept.
+func (c *Context) UseCookieJar(session string) {
+	if session == "" {
+		session = "default"
+	}
+	c.jar = &cookieJar{key: "flowlike.cookies:" + c.NodeID() + ":" + session}
+	c.jar.cookies = parseCookiesJSON(c.CacheGet(c.jar.key))
+}
+
+// ClearSession drops every cookie of the current jar, logging the session
+// out. The jar stays enabled.
+func (c *Context) ClearSession() {
+	if c.jar == nil {
+		return
+	}
+	c.jar.cookies = nil
+	c.CacheDelete(c.jar.key)
+}
+
+// Cookies returns the cookies the jar would send to url, most specific path
+// first. It returns nil when UseCookieJar has not been called.
+func (c *Context) Cookies(url string) []Cookie {
+	if c.jar == nil {
+		return nil
+	}
+	return c.jar.matching(url, c.TimeNow())
+}
+
+// withCookies adds the jar's cookies for url to a JSON headers object.
+func (c *Context) withCookies(url, headers string) string {
+	if c.jar == nil {
+		return headers
+	}
+	cookies := c.jar.matching(url, c.TimeNow())
+	if len(cookies) == 0 {
+		return headers
+	}
+	pairs := make([]string, len(cookies))
+	for i, ck := range cookies {
+		pairs[i] = ck.Name + "=" + ck.Value
+	}
+	return addCookieHeader(headers, strings.Join(pairs, "; "))
+}
+
+// storeCookies records the Set-Cookie headers of resp and persists the jar
+// if anything changed.
+func (c *Context) storeCookies(url string, resp *HTTPResponse) {
+	if c.jar == nil {
+		return
+	}
+	header := resp.Header("set-cookie")
+	if header == "" {
+		return
+	}
+	now := c.TimeNow()
+	changed := false
+	for _, line := range splitSetCookie(header) {
+		if ck, ok := parseSetCookie(line, url, now); ok {
+			c.jar.put(ck, now)
+			changed = true
+		}
+	}
+	if changed {
+		c.CacheSet(c.jar.key, cookiesToJSON(c.jar.cookies))
+	}
+}
+
+// put replaces the cookie with the same name, domain and path, and drops
+// cookies that have expired. An already expired ck only deletes.
+func (j *cookieJar) put(ck Cookie, now int64) {
+	kept := j.cookies[:0]
+	for _, old := range j.cookies {
+		if old.Name == ck.Name && old.Domain == ck.Domain && old.Path == ck.Path {
+			continue
+		}
+		if old.Expires != 0 && old.Expires <= now {
+			continue
+		}
+		kept = append(kept, old)
+	}
+	if ck.Expires == 0 || ck.Expires > now {
+		kept = append(kept, ck)
+	}
+	j.cookies = kept
+}
+
+func (j *cookieJar) matching(url string, now int64) []Cookie {
+	scheme, host, path := splitCookieURL(url)
+	var out []Cookie
+	for _, ck := range j.cookies {
+		if ck.Expires != 0 && ck.Expires <= now {
+			continue
+		}
+		if ck.Secure && scheme != "https" {
+			continue
+		}
+		if !cookieDomainMatch(host, ck.Domain, ck.HostOnly) || !cookiePathMatch(path, ck.Path) {
+			continue
+		}
+		out = append(out, ck)
+	}
+	sort.SliceStable(out, func(a, b int) bool { return len(out[a].Path) > len(out[b].Path) })
+	return out
+}
+
+// parseSetCookie reads one Set-Cookie header value received from url
+// (RFC 6265, section 5.2). Cookies for a domain the URL cannot set, for a
+// public suffix (see publicSuffixes) or marked Secure but received over plain
+// HTTP are rejected.
+func parseSetCookie(line, url string, now int64) (Cookie, bool) {
+	parts := strings.Split(line, ";")
+	name, value, ok := strings.Cut(parts[0], "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return Cookie{}, false
+	}
+	scheme, host, path := splitCookieURL(url)
+	ck := Cookie{
+		Name:     name,
+		Value:    strings.Trim(strings.TrimSpace(value), `"`),
+		Domain:   host,
+		Path:     defaultCookiePath(path),
+		HostOnly: true,
+	}
+	maxAge, hasMaxAge := int64(0), false
+	for _, attr := range parts[1:] {
+		k, v, _ := strings.Cut(attr, "=")
+		v = strings.TrimSpace(v)
+		switch strings.ToLower(strings.TrimSpace(k)) {
+		case "domain":
+			d := strings.ToLower(strings.TrimPrefix(v, "."))
+			if d == "" {
+				continue
+			}
+			if !cookieDomainMatch(host, d, false) {
+				return Cookie{}, false
+			}
+			if d != host && (!strings.Contains(d, ".") || publicSuffixes[d]) {
+				return Cookie{}, false
+			}
+			ck.Domain, ck.HostOnly = d, false
+		case "path":
+			if strings.HasPrefix(v, "/") {
+				ck.Path = v
+			}
+		case "expires":
+			if hasMaxAge {
+				continue
+			}
+			if t, ok := parseCookieTime(v); ok {
+				ck.Expires = t.UnixMilli()
+				// The epoch is the usual way to delete a cookie, but 0 means
+				// "session cookie" in the jar.
+				if ck.Expires == 0 {
+					ck.Expires = -1
+				}
+			}
+		case "max-age":
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				continue
+			}
+			maxAge, hasMaxAge = n, true
+		case "secure":
+			ck.Secure = true
+		case "httponly":
+			ck.HTTPOnly = true
+		}
+	}
+	if ck.Secure && scheme != "https" {
+		return Cookie{}, false
+	}
+	if hasMaxAge {
+		if maxAge <= 0 {
+			ck.Expires = now - 1
+		} else {
+			ck.Expires = now + maxAge*1000
+		}
+	}
+	return ck, true
+}
+
+// publicSuffixes lists common multi-label public suffixes that a Domain
+// attribute may not name, so x.co.uk cannot set a cookie for every .co.uk
+// site. It is a small excerpt of the Public Suffix List, not all of it:
+// suffixes missing here, such as those of hosting platforms, are accepted.
+// Single-label domains ("com", "uk") are always rejected.
+var publicSuffixes = map[string]bool{
+	"co.uk": true, "org.uk": true, "me.uk": true, "ltd.uk": true, "plc.uk": true,
+	"net.uk": true, "ac.uk": true, "gov.uk": true, "nhs.uk": true, "sch.uk": true,
+	"com.au": true, "net.au": true, "org.au": true, "edu.au": true, "gov.au": true,
+	"co.nz": true, "org.nz": true, "net.nz": true, "govt.nz": true,
+	"co.jp": true, "ne.jp": true, "or.jp": true, "ac.jp": true, "go.jp": true,
+	"co.kr": true, "or.kr": true, "co.in": true, "net.in": true, "org.in": true,
+	"co.za": true, "org.za": true, "co.il": true, "org.il": true,
+	"com.br": true, "net.br": true, "org.br": true, "com.cn": true, "net.cn": true,
+	"org.cn": true, "com.mx": true, "com.ar": true, "com.tr": true, "com.tw": true,
+	"com.hk": true, "com.sg": true, "com.my": true, "co.id": true, "co.th": true,
+}
+
+var cookieTimeLayouts = []string{
+	time.RFC1123,
+	"Mon, 02-Jan-2006 15:04:05 MST",
+	"Monday, 02-Jan-06 15:04:05 MST",
+	time.ANSIC,
+}
+
+func parseCookieTime(s string) (time.Time, bool) {
+	for _, layout := range cookieTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// splitSetCookie separates Set-Cookie values the host folded into one
+// header, by newline or by comma. A comma only starts a new cookie when a
+// name=value pair follows, so the comma in an Expires date is kept.
+func splitSetCookie(header string) []string {
+	var out []string
+	for _, line := range strings.Split(header, "\n") {
+		start := 0
+		for i := 0; i < len(line); i++ {
+			if line[i] == ',' && startsCookiePair(line[i+1:]) {
+				out = append(out, strings.TrimSpace(line[start:i]))
+				start = i + 1
+			}
+		}
+		if s := strings.TrimSpace(line[start:]); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func startsCookiePair(s string) bool {
+	s = strings.TrimLeft(s, " \t")
+	end := strings.IndexAny(s, ";,")
+	if end >= 0 {
+		s = s[:end]
+	}
+	name, _, ok := strings.Cut(s, "=")
+	return ok && name != "" && !strings.ContainsAny(name, " \t")
+}
+
+// splitCookieURL returns the scheme, host without port and path of url.
+func splitCookieURL(url string) (scheme, host, path string) {
+	if i := strings.Index(url, "://"); i >= 0 {
+		scheme = strings.ToLower(url[:i])
+	}
+	host = urlHost(url)
+	if strings.HasPrefix(host, "[") {
+		if i := strings.IndexByte(host, ']'); i >= 0 {
+			host = host[1:i]
+		}
+	} else if i := strings.LastIndexByte(host, ':'); i >= 0 {
+		host = host[:i]
+	}
+	path = "/"
+	if i := strings.Index(url, "://"); i >= 0 {
+		rest := url[i+3:]
+		if j := strings.IndexByte(rest, '/'); j >= 0 {
+			path = rest[j:]
+		}
+	}
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	return scheme, host, path
+}
+
+func defaultCookiePath(path string) string {
+	i := strings.LastIndexByte(path, '/')
+	if i <= 0 {
+		return "/"
+	}
+	return path[:i]
+}
+
+func cookieDomainMatch(host, domain string, hostOnly bool) bool {
+	if host == domain {
+		return true
+	}
+	return !hostOnly && strings.HasSuffix(host, "."+domain)
+}
+
+func cookiePathMatch(path, cookiePath string) bool {
+	if path == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(path, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || path[len(cookiePath)] == '/'
+}
+
+// addCookieHeader sets the Cookie header of a JSON headers object, appending
+// to a Cookie header the caller already set.
+func addCookieHeader(headers, cookie string) string {
+	fields := jsonObjectFields(headers)
+	if fields == nil {
+		fields = make(map[string]string)
+	}
+	key := "Cookie"
+	for k, v := range fields {
+		if strings.EqualFold(k, "cookie") {
+			key = k
+			cookie = jsonUnquote(v) + "; " + cookie
+		}
+	}
+	fields[key] = jsonString(cookie)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(jsonString(k))
+		b.WriteByte(':')
+		b.WriteString(fields[k])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func cookiesToJSON(cookies []Cookie) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, ck := range cookies {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(`{"name":` + jsonString(ck.Name) +
+			`,"value":` + jsonString(ck.Value) +
+			`,"domain":` + jsonString(ck.Domain) +
+			`,"path":` + jsonString(ck.Path) +
+			`,"host_only":` + strconv.FormatBool(ck.HostOnly) +
+			`,"secure":` + strconv.FormatBool(ck.Secure) +
+			`,"http_only":` + strconv.FormatBool(ck.HTTPOnly) +
+			`,"expires":` + strconv.FormatInt(ck.Expires, 10) + `}`)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+func parseCookiesJSON(s string) []Cookie {
+	var out []Cookie
+	for _, item := range jsonArrayItems(s) {
+		f := jsonObjectFields(item)
+		if f == nil {
+			continue
+		}
+		expires, _ := strconv.ParseInt(f["expires"], 10, 64)
+		out = append(out, Cookie{
+			Name:     jsonUnquote(f["name"]),
+			Value:    jsonUnquote(f["value"]),
+			Domain:   jsonUnquote(f["domain"]),
+			Path:     jsonUnquote(f["path"]),
+			HostOnly: f["host_only"] == "true",
+			Secure:   f["secure"] == "true",
+			HTTPOnly: f["http_only"] == "true",
+			Expires:  expires,
+		})
+	}
+	return out
+}
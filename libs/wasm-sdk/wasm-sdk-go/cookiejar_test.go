@@ -0,0 +1,149 @@
+package sdk
+
+import (
+	"reflect"
+	"testing"
+)
+
+// cookieNow is 2024-01-01 00:00:00 UTC on the TimeNow clock.
+const cookieNow = int64(1704067200000)
+
+func TestSplitSetCookie(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []string
+	}{
+		{"a=1", []string{"a=1"}},
+		{"a=1; Path=/, b=2", []string{"a=1; Path=/", "b=2"}},
+		{"a=1\nb=2; Secure", []string{"a=1", "b=2; Secure"}},
+		{
+			"id=x; Expires=Wed, 21 Oct 2026 07:28:00 GMT; Path=/, theme=dark",
+			[]string{"id=x; Expires=Wed, 21 Oct 2026 07:28:00 GMT; Path=/", "theme=dark"},
+		},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := splitSetCookie(tt.header); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitSetCookie(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestParseSetCookie(t *testing.T) {
+	const url = "https://www.example.com/app/login?next=/"
+	tests := []struct {
+		name string
+		line string
+		url  string
+		want Cookie
+		ok   bool
+	}{
+		{
+			name: "host-only default path",
+			line: `sid="abc"; HttpOnly`,
+			want: Cookie{Name: "sid", Value: "abc", Domain: "www.example.com", Path: "/app", HostOnly: true, HTTPOnly: true},
+			ok:   true,
+		},
+		{
+			name: "parent domain",
+			line: "sid=abc; Domain=.Example.com; Path=/",
+			want: Cookie{Name: "sid", Value: "abc", Domain: "example.com", Path: "/"},
+			ok:   true,
+		},
+		{
+			name: "expires with comma",
+			line: "sid=abc; Expires=Wed, 21 Oct 2026 07:28:00 GMT",
+			want: Cookie{Name: "sid", Value: "abc", Domain: "www.example.com", Path: "/app", HostOnly: true, Expires: 1792567680000},
+			ok:   true,
+		},
+		{
+			name: "max-age wins over later expires",
+			line: "sid=abc; Max-Age=60; Expires=Wed, 21 Oct 2026 07:28:00 GMT",
+			want: Cookie{Name: "sid", Value: "abc", Domain: "www.example.com", Path: "/app", HostOnly: true, Expires: cookieNow + 60000},
+			ok:   true,
+		},
+		{
+			name: "max-age wins over earlier expires",
+			line: "sid=abc; Expires=Wed, 21 Oct 2026 07:28:00 GMT; Max-Age=0",
+			want: Cookie{Name: "sid", Value: "abc", Domain: "www.example.com", Path: "/app", HostOnly: true, Expires: cookieNow - 1},
+			ok:   true,
+		},
+		{
+			name: "epoch expiry deletes",
+			line: "sid=; Expires=Thu, 01 Jan 1970 00:00:00 GMT",
+			want: Cookie{Name: "sid", Domain: "www.example.com", Path: "/app", HostOnly: true, Expires: -1},
+			ok:   true,
+		},
+		{
+			name: "secure over https",
+			line: "sid=abc; Secure; Path=/",
+			want: Cookie{Name: "sid", Value: "abc", Domain: "www.example.com", Path: "/", HostOnly: true, Secure: true},
+			ok:   true,
+		},
+		{name: "secure over http", line: "sid=abc; Secure", url: "http://www.example.com/"},
+		{name: "foreign domain", line: "sid=abc; Domain=evil.com"},
+		{name: "sibling domain", line: "sid=abc; Domain=api.example.com"},
+		{name: "top-level domain", line: "sid=abc; Domain=com"},
+		{name: "public suffix", line: "sid=abc; Domain=co.uk", url: "https://x.co.uk/"},
+		{name: "no name", line: "=abc"},
+		{name: "no value", line: "sid"},
+	}
+	for _, tt := range tests {
+		u := tt.url
+		if u == "" {
+			u = url
+		}
+		got, ok := parseSetCookie(tt.line, u, cookieNow)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("%s: parseSetCookie(%q) = %+v, %v, want %+v, %v", tt.name, tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+	if ck, ok := parseSetCookie("sid=abc; Domain=x.co.uk", "https://x.co.uk/", cookieNow); !ok || ck.Domain != "x.co.uk" {
+		t.Errorf("domain below a public suffix = %+v, %v", ck, ok)
+	}
+}
+
+func TestCookieJarMatching(t *testing.T) {
+	j := &cookieJar{}
+	for _, ck := range []Cookie{
+		{Name: "host", Domain: "example.com", Path: "/", HostOnly: true},
+		{Name: "wide", Domain: "example.com", Path: "/"},
+		{Name: "api", Domain: "example.com", Path: "/api"},
+		{Name: "slash", Domain: "example.com", Path: "/docs/"},
+		{Name: "secure", Domain: "example.com", Path: "/", Secure: true},
+		{Name: "expired", Domain: "example.com", Path: "/", Expires: cookieNow - 1},
+		{Name: "later", Domain: "example.com", Path: "/", Expires: cookieNow + 1000},
+	} {
+		j.put(ck, cookieNow-10)
+	}
+	tests := []struct {
+		url  string
+		want []string
+	}{
+		{"https://example.com/", []string{"host", "wide", "secure", "later"}},
+		{"http://example.com/", []string{"host", "wide", "later"}},
+		{"https://www.example.com/", []string{"wide", "secure", "later"}},
+		{"https://example.com/api/v1?x=1", []string{"api", "host", "wide", "secure", "later"}},
+		{"https://example.com/api", []string{"api", "host", "wide", "secure", "later"}},
+		{"https://example.com/apix", []string{"host", "wide", "secure", "later"}},
+		{"https://example.com/docs/a", []string{"slash", "host", "wide", "secure", "later"}},
+		{"https://example.com:8443/docs", []string{"host", "wide", "secure", "later"}},
+		{"https://notexample.com/", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, ck := range j.matching(tt.url, cookieNow) {
+			got = append(got, ck.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("matching(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+
+	j.put(Cookie{Name: "wide", Domain: "example.com", Path: "/", Expires: cookieNow - 1}, cookieNow)
+	for _, ck := range j.cookies {
+		if ck.Name == "wide" || ck.Name == "expired" {
+			t.Errorf("put kept %q", ck.Name)
+		}
+	}
+}
@@ -0,0 +1,374 @@
+// Package cron parses cron expressions and computes their next and previous
+// occurrences.
+//
+// Supported syntax:
+//
+//   - 5 fields (minute hour day-of-month month day-of-week) or 6 fields with a
+//     leading seconds field
+//   - *, ?, lists (1,15), ranges (1-5), steps (*/10, 0-30/5, 5/15)
+//   - month names (JAN-DEC) and weekday names (SUN-SAT); 7 is also Sunday
+//   - L in day-of-month for the last day of the month
+//   - nL in day-of-week for the last such weekday of the month (5L: last Friday)
+//   - n#k in day-of-week for the k-th such weekday (1#2: second Monday)
+//   - @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly
+//
+// As in classic cron, when both day-of-month and day-of-week are restricted a
+// day matches if either does. Schedules are evaluated in the location of the
+// time passed to Next or Prev; use package timex to get times in a user's zone.
+package cron
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrSyntax wraps every parse error.
+var ErrSyntax = errors.New("cron: invalid expression")
+
+// searchYears bounds how far Next and Prev look before giving up, e.g. for
+// "0 0 30 2 *" which never matches.
+const searchYears = 5
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	expr string
+
+	second, minute, hour, dom, month, dow uint64
+
+	domAll, dowAll bool
+	lastDom        bool
+	lastDow        uint8    // bit per weekday: last <weekday> of the month
+	nthDow         [7]uint8 // bit k set: k-th <weekday> of the month
+}
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+}
+
+var dayNames = map[string]int{
+	"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+}
+
+// Parse parses a cron expression.
+func Parse(expr string) (*Schedule, error) {
+	spec := strings.TrimSpace(expr)
+	if strings.HasPrefix(spec, "@") {
+		m, ok := macros[strings.ToLower(spec)]
+		if !ok {
+			return nil, syntaxError("unknown macro " + spec)
+		}
+		spec = m
+	}
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, syntaxError("expected 5 or 6 fields, got " + strconv.Itoa(len(fields)))
+	}
+
+	s := &Schedule{expr: expr}
+	var err error
+	if s.second, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, err
+	}
+	if s.minute, err = parseField(fields[1], 0, 59, nil); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[2], 0, 23, nil); err != nil {
+		return nil, err
+	}
+	if err = s.parseDom(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[4], 1, 12, monthNames); err != nil {
+		return nil, err
+	}
+	if err = s.parseDow(fields[5]); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MustParse is like Parse but panics on error. Use it for constant expressions.
+func MustParse(expr string) *Schedule {
+	s, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// String returns the expression the schedule was parsed from.
+func (s *Schedule) String() string { return s.expr }
+
+func (s *Schedule) parseDom(field string) error {
+	s.domAll = field == "*" || field == "?"
+	var parts []string
+	for _, p := range strings.Split(field, ",") {
+		if strings.ToUpper(p) == "L" {
+			s.lastDom = true
+			continue
+		}
+		parts = append(parts, p)
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	var err error
+	s.dom, err = parseField(strings.Join(parts, ","), 1, 31, nil)
+	return err
+}
+
+func (s *Schedule) parseDow(field string) error {
+	s.dowAll = field == "*" || field == "?"
+	var parts []string
+	for _, p := range strings.Split(field, ",") {
+		up := strings.ToUpper(p)
+		switch {
+		case len(up) > 1 && strings.HasSuffix(up, "L"):
+			d, err := parseValue(up[:len(up)-1], 0, 7, dayNames)
+			if err != nil {
+				return err
+			}
+			s.lastDow |= 1 << uint(d%7)
+		case strings.Contains(up, "#"):
+			i := strings.IndexByte(up, '#')
+			d, err := parseValue(up[:i], 0, 7, dayNames)
+			if err != nil {
+				return err
+			}
+			k, err := parseValue(up[i+1:], 1, 5, nil)
+			if err != nil {
+				return err
+			}
+			s.nthDow[d%7] |= 1 << uint(k)
+		default:
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	bits, err := parseField(strings.Join(parts, ","), 0, 7, dayNames)
+	if err != nil {
+		return err
+	}
+	if bits&(1<<7) != 0 {
+		bits = bits&^(1<<7) | 1
+	}
+	s.dow = bits
+	return nil
+}
+
+// parseField parses a comma-separated list of values, ranges and steps into
+// a bit set.
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, syntaxError("bad step in " + part)
+			}
+			step = n
+			part = part[:i]
+			if !strings.Contains(part, "-") && part != "*" && part != "?" {
+				part += "-" + strconv.Itoa(max)
+			}
+		}
+		lo, hi := min, max
+		switch {
+		case part == "*" || part == "?":
+		case strings.Contains(part, "-"):
+			i := strings.IndexByte(part, '-')
+			var err error
+			if lo, err = parseValue(part[:i], min, max, names); err != nil {
+				return 0, err
+			}
+			if hi, err = parseValue(part[i+1:], min, max, names); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, syntaxError("descending range " + part)
+			}
+		default:
+			v, err := parseValue(part, min, max, names)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = v, v
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, syntaxError("bad value " + strconv.Quote(s))
+	}
+	if v < min || v > max {
+		return 0, syntaxError(s + " out of range " + strconv.Itoa(min) + "-" + strconv.Itoa(max))
+	}
+	return v, nil
+}
+
+func syntaxError(msg string) error {
+	return &parseError{msg: msg}
+}
+
+type parseError struct{ msg string }
+
+func (e *parseError) Error() string { return ErrSyntax.Error() + ": " + e.msg }
+func (e *parseError) Unwrap() error { return ErrSyntax }
+
+func has(bits uint64, v int) bool { return bits&(1<<uint(v)) != 0 }
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	day, wd := t.Day(), int(t.Weekday())
+	last := daysIn(t.Year(), t.Month())
+	domMatch := has(s.dom, day) || (s.lastDom && day == last)
+	dowMatch := has(s.dow, wd) ||
+		(s.lastDow&(1<<uint(wd)) != 0 && day+7 > last) ||
+		s.nthDow[wd]&(1<<uint((day-1)/7+1)) != 0
+	switch {
+	case s.domAll && s.dowAll:
+		return true
+	case s.domAll:
+		return dowMatch
+	case s.dowAll:
+		return domMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next returns the first occurrence strictly after t, or the zero time if
+// there is none within the next few years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Add(time.Second - time.Duration(t.Nanosecond()))
+	limit := t.Year() + searchYears
+
+wrap:
+	if t.Year() > limit {
+		return time.Time{}
+	}
+	for !has(s.month, int(t.Month())) {
+		t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+	for !has(s.hour, t.Hour()) {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+	for !has(s.minute, t.Minute()) {
+		t = t.Truncate(time.Minute).Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+	for !has(s.second, t.Second()) {
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto wrap
+		}
+	}
+	return t
+}
+
+// Prev returns the last occurrence strictly before t, or the zero time if
+// there is none within the previous few years.
+func (s *Schedule) Prev(t time.Time) time.Time {
+	loc := t.Location()
+	if ns := t.Nanosecond(); ns > 0 {
+		t = t.Add(-time.Duration(ns))
+	} else {
+		t = t.Add(-time.Second)
+	}
+	limit := t.Year() - searchYears
+
+wrap:
+	if t.Year() < limit {
+		return time.Time{}
+	}
+	for !has(s.month, int(t.Month())) {
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).Add(-time.Second)
+		if t.Month() == time.December {
+			goto wrap
+		}
+	}
+	for !s.dayMatches(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc).Add(-time.Second)
+		if t.Day() == daysIn(t.Year(), t.Month()) {
+			goto wrap
+		}
+	}
+	for !has(s.hour, t.Hour()) {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc).Add(-time.Second)
+		if t.Hour() == 23 {
+			goto wrap
+		}
+	}
+	for !has(s.minute, t.Minute()) {
+		t = t.Truncate(time.Minute).Add(-time.Second)
+		if t.Minute() == 59 {
+			goto wrap
+		}
+	}
+	for !has(s.second, t.Second()) {
+		t = t.Add(-time.Second)
+		if t.Second() == 59 {
+			goto wrap
+		}
+	}
+	return t
+}
+
+// NextN returns up to n consecutive occurrences after t.
+func (s *Schedule) NextN(t time.Time, n int) []time.Time {
+	var out []time.Time
+	for len(out) < n {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		out = append(out, t)
+	}
+	return out
+}
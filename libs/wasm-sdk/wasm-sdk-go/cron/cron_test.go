@@ -0,0 +1,74 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"* * * FOO *",
+		"* * * * 1#6",
+		"@never",
+	} {
+		if _, err := Parse(expr); !errors.Is(err, ErrSyntax) {
+			t.Errorf("Parse(%q) error = %v, want ErrSyntax", expr, err)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC) // a Monday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 15, 10, 45, 0, 0, time.UTC)},
+		{"30 * * * * *", time.Date(2024, 1, 15, 10, 30, 30, 0, time.UTC)},
+		{"0 9 * * MON-FRI", time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 L * *", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 L 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 5L", time.Date(2024, 1, 26, 12, 0, 0, 0, time.UTC)},
+		{"0 8 * * 1#3", time.Date(2024, 2, 19, 8, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 JAN *", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got := MustParse(tt.expr).Next(start)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextPrevRoundTrip(t *testing.T) {
+	start := time.Date(2024, 3, 10, 7, 13, 0, 0, time.UTC)
+	for _, expr := range []string{"*/7 * * * *", "0 9 * * MON-FRI", "0 0 L * *", "0 12 * * 5L", "15 3 1,15 * *"} {
+		s := MustParse(expr)
+		for _, next := range s.NextN(start, 5) {
+			if prev := s.Next(s.Prev(next)); !prev.Equal(next) {
+				t.Errorf("%s: Next(Prev(%v)) = %v", expr, next, prev)
+			}
+		}
+	}
+}
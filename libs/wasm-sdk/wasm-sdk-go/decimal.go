@@ -0,0 +1,609 @@
+package sdk
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// Decimal is a fixed-point decimal number: a 64-bit coefficient and a scale of
+// up to 18 fractional digits. Unlike float64 it represents amounts such as
+// 0.10 exactly, so money math does not lose cents.
+//
+// Operations never panic. A result that overflows, or a division by zero,
+// yields an invalid Decimal that stays invalid through further arithmetic;
+// check IsValid before using the final value.
+type Decimal struct {
+	coef    int64
+	scale   uint8
+	invalid bool
+}
+
+// MaxDecimalScale is the largest number of fractional digits a Decimal holds.
+const MaxDecimalScale = 18
+
+var (
+	ErrDecimalSyntax   = errors.New("decimal: invalid syntax")
+	ErrDecimalOverflow = errors.New("decimal: value out of range")
+)
+
+// RoundingMode selects how Round, Div and FormatCurrency drop digits.
+type RoundingMode uint8
+
+const (
+	RoundHalfUp   RoundingMode = iota // 2.5 → 3, -2.5 → -3
+	RoundHalfEven                     // 2.5 → 2, 3.5 → 4 (banker's rounding)
+	RoundHalfDown                     // 2.5 → 2, -2.5 → -2
+	RoundDown                         // toward zero (truncate)
+	RoundUp                           // away from zero
+	RoundFloor                        // toward negative infinity
+	RoundCeiling                      // toward positive infinity
+)
+
+var pow10 = [MaxDecimalScale + 1]uint64{
+	1, 10, 100, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9,
+	1e10, 1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18,
+}
+
+var invalidDecimal = Decimal{invalid: true}
+
+// NewDecimal returns coef × 10^-scale, e.g. NewDecimal(1999, 2) is 19.99.
+func NewDecimal(coef int64, scale int) Decimal {
+	if scale < 0 || scale > MaxDecimalScale {
+		return invalidDecimal
+	}
+	return Decimal{coef: coef, scale: uint8(scale)}
+}
+
+// DecimalFromInt returns n as a Decimal with no fractional digits.
+func DecimalFromInt(n int64) Decimal { return Decimal{coef: n} }
+
+// DecimalFromFloat converts f, rounded half-even to the given number of
+// fractional digits. Use it at the boundary with float pins only.
+func DecimalFromFloat(f float64, scale int) Decimal {
+	if math.IsNaN(f) || math.IsInf(f, 0) || scale < 0 || scale > MaxDecimalScale {
+		return invalidDecimal
+	}
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'f', scale, 64))
+	if err != nil {
+		return invalidDecimal
+	}
+	return d
+}
+
+// ParseDecimal parses "-1234.5678", "+0.1" or "1.5e3". The scale of the
+// result is the number of fractional digits written (after the exponent).
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Decimal{}, ErrDecimalSyntax
+	}
+	exp := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return Decimal{}, ErrDecimalSyntax
+		}
+		exp, s = e, s[:i]
+	}
+	if s == "" {
+		return Decimal{}, ErrDecimalSyntax
+	}
+	neg := false
+	if s[0] == '-' || s[0] == '+' {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	var mag uint64
+	digits, frac, point := 0, 0, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '.' && !point {
+			point = true
+			continue
+		}
+		if c < '0' || c > '9' {
+			return Decimal{}, ErrDecimalSyntax
+		}
+		hi, lo := bits.Mul64(mag, 10)
+		lo, carry := bits.Add64(lo, uint64(c-'0'), 0)
+		if hi != 0 || carry != 0 {
+			return Decimal{}, ErrDecimalOverflow
+		}
+		mag = lo
+		digits++
+		if point {
+			frac++
+		}
+	}
+	if digits == 0 {
+		return Decimal{}, ErrDecimalSyntax
+	}
+	switch {
+	case exp < -MaxDecimalScale:
+		return Decimal{}, ErrDecimalOverflow
+	case mag == 0 && exp > frac:
+		// Zero needs no scaling however large the exponent.
+		exp = frac
+	case exp > frac+19:
+		// No int64 magnitude survives 19 more digits.
+		return Decimal{}, ErrDecimalOverflow
+	}
+	scale := frac - exp
+	for scale < 0 {
+		hi, lo := bits.Mul64(mag, 10)
+		if hi != 0 {
+			return Decimal{}, ErrDecimalOverflow
+		}
+		mag = lo
+		scale++
+	}
+	if scale > MaxDecimalScale {
+		return Decimal{}, ErrDecimalOverflow
+	}
+	d, ok := fromMagnitude(neg, 0, mag, scale)
+	if !ok {
+		return Decimal{}, ErrDecimalOverflow
+	}
+	return d, nil
+}
+
+// MustParseDecimal is like ParseDecimal but returns an invalid Decimal on error.
+// Use it for constants.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		return invalidDecimal
+	}
+	return d
+}
+
+// InputDecimal reads an input as a Decimal. JSON strings ("19.99") keep their
+// exact digits; JSON numbers are accepted too but may already have lost
+// precision upstream.
+func (c *Context) InputDecimal(name string) (Decimal, error) {
+	raw, ok := c.lookup(name)
+	if !ok {
+		return Decimal{}, &MissingInputError{Pin: name}
+	}
+	s := raw
+	if len(s) > 0 && s[0] == '"' {
+		s = jsonUnquote(s)
+	}
+	d, err := ParseDecimal(s)
+	if err != nil {
+		return Decimal{}, mismatch(name, "Decimal", raw)
+	}
+	return d, nil
+}
+
+// IsValid reports whether d holds a value (see Decimal).
+func (d Decimal) IsValid() bool { return !d.invalid }
+
+// Scale returns the number of fractional digits.
+func (d Decimal) Scale() int { return int(d.scale) }
+
+// Sign returns -1, 0 or 1.
+func (d Decimal) Sign() int {
+	switch {
+	case d.coef < 0:
+		return -1
+	case d.coef > 0:
+		return 1
+	}
+	return 0
+}
+
+// IsZero reports whether d is zero (at any scale).
+func (d Decimal) IsZero() bool { return d.coef == 0 && !d.invalid }
+
+func (d Decimal) magnitude() uint64 {
+	if d.coef < 0 {
+		return uint64(-d.coef)
+	}
+	return uint64(d.coef)
+}
+
+func fromMagnitude(neg bool, hi, lo uint64, scale int) (Decimal, bool) {
+	if hi != 0 || lo > 1<<63 || (lo == 1<<63 && !neg) {
+		return invalidDecimal, false
+	}
+	coef := int64(lo)
+	if neg {
+		coef = -coef
+	}
+	return Decimal{coef: coef, scale: uint8(scale)}, true
+}
+
+// widen returns |d| × 10^(scale-d.scale) as a 128-bit value.
+func (d Decimal) widen(scale int) (hi, lo uint64, ok bool) {
+	lo = d.magnitude()
+	for n := scale - int(d.scale); n > 0; {
+		step := n
+		if step > MaxDecimalScale {
+			step = MaxDecimalScale
+		}
+		if hi, lo, ok = mul128(hi, lo, pow10[step]); !ok {
+			return 0, 0, false
+		}
+		n -= step
+	}
+	return hi, lo, true
+}
+
+func mul128(hi, lo, m uint64) (uint64, uint64, bool) {
+	h1, l1 := bits.Mul64(lo, m)
+	h2, l2 := bits.Mul64(hi, m)
+	if h2 != 0 {
+		return 0, 0, false
+	}
+	h, carry := bits.Add64(h1, l2, 0)
+	return h, l1, carry == 0
+}
+
+func div128(hi, lo, d uint64) (qhi, qlo, rem uint64) {
+	qhi, r := hi/d, hi%d
+	qlo, rem = bits.Div64(r, lo, d)
+	return qhi, qlo, rem
+}
+
+// roundAway decides whether a truncated magnitude must be incremented.
+// half compares the dropped part with one half (-1, 0, 1); inexact reports
+// whether anything non-zero was dropped.
+func roundAway(mode RoundingMode, neg, odd bool, half int, inexact bool) bool {
+	if !inexact {
+		return false
+	}
+	switch mode {
+	case RoundHalfUp:
+		return half >= 0
+	case RoundHalfEven:
+		return half > 0 || (half == 0 && odd)
+	case RoundHalfDown:
+		return half > 0
+	case RoundUp:
+		return true
+	case RoundFloor:
+		return neg
+	case RoundCeiling:
+		return !neg
+	}
+	return false
+}
+
+// shrink divides a 128-bit magnitude by 10^drop, rounding with mode.
+// sticky reports that a non-zero remainder was already discarded below the
+// dropped digits.
+func shrink(neg bool, hi, lo uint64, drop int, mode RoundingMode, sticky bool) (uint64, uint64) {
+	var rem uint64
+	for i := 0; i < drop; i++ {
+		if rem != 0 {
+			sticky = true
+		}
+		hi, lo, rem = div128(hi, lo, 10)
+	}
+	if drop == 0 {
+		return hi, lo
+	}
+	half := -1
+	if rem > 5 || (rem == 5 && sticky) {
+		half = 1
+	} else if rem == 5 {
+		half = 0
+	}
+	if roundAway(mode, neg, lo&1 == 1, half, rem != 0 || sticky) {
+		var carry uint64
+		lo, carry = bits.Add64(lo, 1, 0)
+		hi += carry
+	}
+	return hi, lo
+}
+
+// Round returns d with exactly scale fractional digits.
+func (d Decimal) Round(scale int, mode RoundingMode) Decimal {
+	if d.invalid || scale < 0 || scale > MaxDecimalScale {
+		return invalidDecimal
+	}
+	if scale >= int(d.scale) {
+		hi, lo, ok := d.widen(scale)
+		if !ok {
+			return invalidDecimal
+		}
+		r, _ := fromMagnitude(d.coef < 0, hi, lo, scale)
+		return r
+	}
+	hi, lo := shrink(d.coef < 0, 0, d.magnitude(), int(d.scale)-scale, mode, false)
+	r, _ := fromMagnitude(d.coef < 0, hi, lo, scale)
+	return r
+}
+
+// Truncate drops fractional digits beyond scale.
+func (d Decimal) Truncate(scale int) Decimal { return d.Round(scale, RoundDown) }
+
+func align(a, b Decimal) (Decimal, Decimal, bool) {
+	if a.scale == b.scale {
+		return a, b, true
+	}
+	if a.scale < b.scale {
+		a = a.Round(int(b.scale), RoundDown)
+	} else {
+		b = b.Round(int(a.scale), RoundDown)
+	}
+	return a, b, !a.invalid && !b.invalid
+}
+
+// Add returns d + o at the larger of the two scales.
+func (d Decimal) Add(o Decimal) Decimal {
+	if d.invalid || o.invalid {
+		return invalidDecimal
+	}
+	a, b, ok := align(d, o)
+	if !ok {
+		return invalidDecimal
+	}
+	c := a.coef + b.coef
+	if (c > a.coef) != (b.coef > 0) {
+		return invalidDecimal
+	}
+	return Decimal{coef: c, scale: a.scale}
+}
+
+// Sub returns d - o at the larger of the two scales.
+func (d Decimal) Sub(o Decimal) Decimal { return d.Add(o.Neg()) }
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	if d.invalid || d.coef == math.MinInt64 {
+		return invalidDecimal
+	}
+	return Decimal{coef: -d.coef, scale: d.scale}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	if d.coef < 0 {
+		return d.Neg()
+	}
+	return d
+}
+
+// Mul returns d × o. The result carries the sum of both scales, reduced
+// (half-even) as far as needed to fit, but never below the larger input scale.
+func (d Decimal) Mul(o Decimal) Decimal {
+	if d.invalid || o.invalid {
+		return invalidDecimal
+	}
+	neg := (d.coef < 0) != (o.coef < 0)
+	hi, lo := bits.Mul64(d.magnitude(), o.magnitude())
+	scale := int(d.scale) + int(o.scale)
+	floor := int(d.scale)
+	if o.scale > d.scale {
+		floor = int(o.scale)
+	}
+	drop := 0
+	if scale > MaxDecimalScale {
+		drop = scale - MaxDecimalScale
+	}
+	for {
+		h, l := shrink(neg, hi, lo, drop, RoundHalfEven, false)
+		if r, ok := fromMagnitude(neg, h, l, scale-drop); ok {
+			return r
+		}
+		if scale-drop <= floor {
+			return invalidDecimal
+		}
+		drop++
+	}
+}
+
+// MulInt returns d × n at d's scale.
+func (d Decimal) MulInt(n int64) Decimal { return d.Mul(DecimalFromInt(n)) }
+
+// Div returns d ÷ o rounded to scale fractional digits. Division by zero
+// yields an invalid Decimal.
+func (d Decimal) Div(o Decimal, scale int, mode RoundingMode) Decimal {
+	if d.invalid || o.invalid || o.coef == 0 || scale < 0 || scale > MaxDecimalScale {
+		return invalidDecimal
+	}
+	neg := (d.coef < 0) != (o.coef < 0)
+	// Compute at the target scale, or at a larger one when d carries more
+	// digits than scale+o.scale, then round those extra digits away.
+	extra := int(d.scale) - int(o.scale) - scale
+	work := scale
+	if extra > 0 {
+		work += extra
+	} else {
+		extra = 0
+	}
+	hi, lo, ok := d.widen(work + int(o.scale))
+	if !ok {
+		return invalidDecimal
+	}
+	den := o.magnitude()
+	qhi, qlo, rem := div128(hi, lo, den)
+	if extra > 0 {
+		qhi, qlo = shrink(neg, qhi, qlo, extra, mode, rem != 0)
+	} else if rem != 0 {
+		half := 1
+		r2hi, r2lo := bits.Mul64(rem, 2)
+		if r2hi == 0 && r2lo < den {
+			half = -1
+		} else if r2hi == 0 && r2lo == den {
+			half = 0
+		}
+		if roundAway(mode, neg, qlo&1 == 1, half, true) {
+			var carry uint64
+			qlo, carry = bits.Add64(qlo, 1, 0)
+			qhi += carry
+		}
+	}
+	r, _ := fromMagnitude(neg, qhi, qlo, scale)
+	return r
+}
+
+// Cmp returns -1, 0 or 1 depending on whether d is less than, equal to or
+// greater than o. Invalid values compare as equal to each other and less
+// than any valid value.
+func (d Decimal) Cmp(o Decimal) int {
+	if d.invalid || o.invalid {
+		switch {
+		case d.invalid && o.invalid:
+			return 0
+		case d.invalid:
+			return -1
+		}
+		return 1
+	}
+	if ds, os := d.Sign(), o.Sign(); ds != os {
+		if ds < os {
+			return -1
+		}
+		return 1
+	}
+	scale := int(d.scale)
+	if int(o.scale) > scale {
+		scale = int(o.scale)
+	}
+	dh, dl, _ := d.widen(scale)
+	oh, ol, _ := o.widen(scale)
+	c := 0
+	switch {
+	case dh != oh:
+		c = 1
+		if dh < oh {
+			c = -1
+		}
+	case dl != ol:
+		c = 1
+		if dl < ol {
+			c = -1
+		}
+	}
+	if d.coef < 0 {
+		return -c
+	}
+	return c
+}
+
+// Equal reports whether d and o have the same value, regardless of scale.
+func (d Decimal) Equal(o Decimal) bool { return d.Cmp(o) == 0 }
+
+// Int64 returns the integer part, truncated toward zero.
+func (d Decimal) Int64() int64 {
+	return d.coef / int64(pow10[d.scale])
+}
+
+// Float64 returns the nearest float64. Use it only for display or for float pins.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d with all of its fractional digits, e.g. "-12.50".
+// Invalid values format as "NaN".
+func (d Decimal) String() string {
+	if d.invalid {
+		return "NaN"
+	}
+	digits := strconv.FormatUint(d.magnitude(), 10)
+	if d.scale > 0 {
+		for len(digits) <= int(d.scale) {
+			digits = "0" + digits
+		}
+		cut := len(digits) - int(d.scale)
+		digits = digits[:cut] + "." + digits[cut:]
+	}
+	if d.coef < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// ToJSON encodes d as a JSON string so consumers keep every digit. Invalid
+// values encode as null.
+func (d Decimal) ToJSON() string {
+	if d.invalid {
+		return "null"
+	}
+	return `"` + d.String() + `"`
+}
+
+// Format rounds d half-up to decimals fractional digits and renders it with
+// the given thousands separator and decimal point, e.g.
+// Format(2, ".", ",") → "1.234,50".
+func (d Decimal) Format(decimals int, thousands, point string) string {
+	r := d.Round(decimals, RoundHalfUp)
+	if r.invalid {
+		return "NaN"
+	}
+	s := r.String()
+	neg := strings.HasPrefix(s, "-")
+	if neg {
+		s = s[1:]
+	}
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	var b strings.Builder
+	if neg {
+		b.WriteByte('-')
+	}
+	for i := 0; i < len(intPart); i++ {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(thousands)
+		}
+		b.WriteByte(intPart[i])
+	}
+	if fracPart != "" {
+		b.WriteString(point)
+		b.WriteString(fracPart)
+	}
+	return b.String()
+}
+
+// Currency describes how amounts in a currency are displayed.
+type Currency struct {
+	Code        string
+	Symbol      string
+	Decimals    int  // minor unit digits, e.g. 2 for USD, 0 for JPY
+	SymbolAfter bool // "10,00 €" style placement
+	Thousands   string
+	Point       string
+}
+
+// Currencies holds display rules for common ISO 4217 codes. Add entries to
+// support others; unknown codes format as "1,234.50 XYZ".
+var Currencies = map[string]Currency{
+	"USD": {Code: "USD", Symbol: "$", Decimals: 2, Thousands: ",", Point: "."},
+	"EUR": {Code: "EUR", Symbol: "€", Decimals: 2, SymbolAfter: true, Thousands: ".", Point: ","},
+	"GBP": {Code: "GBP", Symbol: "£", Decimals: 2, Thousands: ",", Point: "."},
+	"CHF": {Code: "CHF", Symbol: "CHF ", Decimals: 2, Thousands: "'", Point: "."},
+	"JPY": {Code: "JPY", Symbol: "¥", Decimals: 0, Thousands: ",", Point: "."},
+	"CNY": {Code: "CNY", Symbol: "¥", Decimals: 2, Thousands: ",", Point: "."},
+	"INR": {Code: "INR", Symbol: "₹", Decimals: 2, Thousands: ",", Point: "."},
+	"CAD": {Code: "CAD", Symbol: "CA$", Decimals: 2, Thousands: ",", Point: "."},
+	"AUD": {Code: "AUD", Symbol: "A$", Decimals: 2, Thousands: ",", Point: "."},
+	"SEK": {Code: "SEK", Symbol: "kr", Decimals: 2, SymbolAfter: true, Thousands: " ", Point: ","},
+	"KWD": {Code: "KWD", Symbol: "KWD ", Decimals: 3, Thousands: ",", Point: "."},
+}
+
+// FormatCurrency rounds d half-up to the currency's minor unit and formats it
+// with its symbol, e.g. "$1,234.50", "1.234,50 €" or "-¥1,235".
+func (d Decimal) FormatCurrency(code string) string {
+	cur, ok := Currencies[code]
+	if !ok {
+		return d.Format(2, ",", ".") + " " + code
+	}
+	s := d.Abs().Format(cur.Decimals, cur.Thousands, cur.Point)
+	sign := ""
+	if d.Round(cur.Decimals, RoundHalfUp).Sign() < 0 {
+		sign = "-"
+	}
+	if cur.SymbolAfter {
+		return sign + s + " " + cur.Symbol
+	}
+	return sign + cur.Symbol + s
+}
@@ -0,0 +1,120 @@
+package sdk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  error
+	}{
+		{"0", "0", nil},
+		{"-1234.5678", "-1234.5678", nil},
+		{"+0.1", "0.1", nil},
+		{" 19.990 ", "19.990", nil},
+		{".5", "0.5", nil},
+		{"5.", "5", nil},
+		{"1.5e3", "1500", nil},
+		{"1.5E-2", "0.015", nil},
+		{"9223372036854775807", "9223372036854775807", nil},
+		{"-9223372036854775808", "-9223372036854775808", nil},
+		{"", "", ErrDecimalSyntax},
+		{"-", "", ErrDecimalSyntax},
+		{".", "", ErrDecimalSyntax},
+		{"1.2.3", "", ErrDecimalSyntax},
+		{"12a", "", ErrDecimalSyntax},
+		{"1e", "", ErrDecimalSyntax},
+		{"1ex", "", ErrDecimalSyntax},
+		{"e5", "", ErrDecimalSyntax},
+		{"E1", "", ErrDecimalSyntax},
+		{"-e5", "", ErrDecimalSyntax},
+		{"+", "", ErrDecimalSyntax},
+		{"0e99999999999", "0", nil},
+		{"0.00e1", "0.0", nil},
+		{"1e99999999999", "", ErrDecimalOverflow},
+		{"1e-99999999999", "", ErrDecimalOverflow},
+		{"1e-9223372036854775808", "", ErrDecimalOverflow},
+		{"9223372036854775808", "", ErrDecimalOverflow},
+		{"99999999999999999999", "", ErrDecimalOverflow},
+		{"1e19", "", ErrDecimalOverflow},
+		{"1e-19", "", ErrDecimalOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			d, err := ParseDecimal(tt.in)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ParseDecimal(%q) error = %v, want %v", tt.in, err, tt.err)
+			}
+			if err == nil && d.String() != tt.want {
+				t.Errorf("ParseDecimal(%q) = %s, want %s", tt.in, d, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalRoundTrip(t *testing.T) {
+	for _, s := range []string{"0", "0.00", "-0.5", "1", "-1234.5678", "0.000000000000000001", "922337203685477580.7"} {
+		d := MustParseDecimal(s)
+		if got := d.String(); got != s {
+			t.Errorf("String(ParseDecimal(%q)) = %q", s, got)
+		}
+		if back := MustParseDecimal(d.String()); !back.Equal(d) || back.Scale() != d.Scale() {
+			t.Errorf("%q does not round-trip", s)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	d := MustParseDecimal
+	tests := []struct {
+		name string
+		got  Decimal
+		want string
+	}{
+		{"add", d("0.1").Add(d("0.2")), "0.3"},
+		{"add scales", d("1.5").Add(d("2.25")), "3.75"},
+		{"sub", d("1").Sub(d("1.01")), "-0.01"},
+		{"mul", d("19.99").MulInt(3), "59.97"},
+		{"mul scales", d("1.5").Mul(d("-0.25")), "-0.375"},
+		{"div", d("10").Div(d("3"), 4, RoundHalfEven), "3.3333"},
+		{"div up", d("2").Div(d("3"), 2, RoundHalfUp), "0.67"},
+		{"div zero", d("1").Div(d("0"), 2, RoundHalfUp), "NaN"},
+		{"half up", d("2.5").Round(0, RoundHalfUp), "3"},
+		{"half up neg", d("-2.5").Round(0, RoundHalfUp), "-3"},
+		{"half even", d("2.5").Round(0, RoundHalfEven), "2"},
+		{"half even odd", d("3.5").Round(0, RoundHalfEven), "4"},
+		{"half down", d("2.5").Round(0, RoundHalfDown), "2"},
+		{"floor", d("-1.21").Round(1, RoundFloor), "-1.3"},
+		{"ceiling", d("1.21").Round(1, RoundCeiling), "1.3"},
+		{"truncate", d("-1.29").Truncate(1), "-1.2"},
+		{"widen", d("1.5").Round(3, RoundHalfUp), "1.500"},
+		{"overflow", d("9223372036854775807").Add(d("1")), "NaN"},
+		{"invalid", d("x").Add(d("1")), "NaN"},
+	}
+	for _, tt := range tests {
+		if got := tt.got.String(); got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalFormat(t *testing.T) {
+	tests := []struct {
+		in, code, want string
+	}{
+		{"1234.5", "USD", "$1,234.50"},
+		{"1234.5", "EUR", "1.234,50 €"},
+		{"-1234.5", "JPY", "-¥1,235"},
+		{"0.0004", "KWD", "KWD 0.000"},
+		{"-0.001", "USD", "$0.00"},
+		{"1234567.891", "XYZ", "1,234,567.89 XYZ"},
+	}
+	for _, tt := range tests {
+		if got := MustParseDecimal(tt.in).FormatCurrency(tt.code); got != tt.want {
+			t.Errorf("FormatCurrency(%s, %s) = %q, want %q", tt.in, tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package sdk
+
+import "strconv"
+
+// DeterministicInput is sent by the host when a run must be reproducible, for
+// example in test runs or when replaying a board for debugging.
+type DeterministicInput struct {
+	Seed int64 `json:"seed"`
+	Time int64 `json:"time"`
+}
+
+func parseDeterministicJSON(s string) *DeterministicInput {
+	fields := jsonObjectFields(s)
+	if fields == nil {
+		return nil
+	}
+	d := &DeterministicInput{}
+	d.Seed, _ = strconv.ParseInt(fields["seed"], 10, 64)
+	d.Time, _ = strconv.ParseInt(fields["time"], 10, 64)
+	return d
+}
+
+// deterministicState derives reproducible clock and random values from the
+// host seed. Each node gets its own random stream so adding a node to a board
+// does not shift the values seen by the others.
+type deterministicState struct {
+	clock  int64
+	rng    uint64
+	warned map[string]bool
+}
+
+func newDeterministicState(d *DeterministicInput, nodeID string) *deterministicState {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(nodeID); i++ {
+		h ^= uint64(nodeID[i])
+		h *= 1099511628211
+	}
+	return &deterministicState{clock: d.Time, rng: uint64(d.Seed) ^ h}
+}
+
+// next is splitmix64.
+func (d *deterministicState) next() int64 {
+	d.rng += 0x9e3779b97f4a7c15
+	z := d.rng
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// nondeterministicPerms are capabilities whose results can differ between
+// otherwise identical runs.
+var nondeterministicPerms = map[string]bool{
+	PermHTTP:        true,
+	PermExec:        true,
+	PermClipboard:   true,
+	PermModels:      true,
+	PermTransfer:    true,
+	PermBucket:      true,
+	PermMail:        true,
+	PermCalendar:    true,
+	PermContacts:    true,
+	PermQueue:       true,
+	PermInteraction: true,
+}
+
+// IsDeterministic reports whether the host asked for a reproducible run. In
+// this mode TimeNow and Random return values derived from the host seed.
+func (c *Context) IsDeterministic() bool { return c.det != nil }
+
+// warnNondeterministic logs, once per capability and run, that a host call
+// may break reproducibility.
+func (c *Context) warnNondeterministic(perm string) {
+	if c.det == nil || !nondeterministicPerms[perm] || c.det.warned[perm] {
+		return
+	}
+	if c.det.warned == nil {
+		c.det.warned = make(map[string]bool)
+	}
+	c.det.warned[perm] = true
+	c.Warn("deterministic run: " + perm + " calls may return different results between runs")
+}
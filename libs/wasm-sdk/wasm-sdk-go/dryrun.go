@@ -0,0 +1,28 @@
+package sdk
+
+// IsDryRun reports whether the user is previewing the board. Nodes should
+// then compute and return outputs as usual but not change anything outside
+// the run: wrap writes to external systems in SideEffect.
+func (c *Context) IsDryRun() bool { return c.input.DryRun }
+
+// SideEffect runs fn, an action with effects outside the run such as an
+// HTTP POST, a mail or a bucket upload. In dry runs fn is skipped: the
+// intended action is logged and streamed to the preview instead, and
+// SideEffect returns nil.
+//
+//	err := ctx.SideEffect("create ticket in "+project, func() error {
+//		return createTicket(ctx, project, title)
+//	})
+func (c *Context) SideEffect(description string, fn func() error) error {
+	if !c.input.DryRun {
+		return fn()
+	}
+	c.dryEffects = append(c.dryEffects, description)
+	c.Info("dry run: skipped " + description)
+	c.StreamJSON(`{"type":"dry_run_skipped","action":` + jsonString(description) + `}`)
+	return nil
+}
+
+// SkippedSideEffects lists the descriptions of the side effects skipped so
+// far in this dry run.
+func (c *Context) SkippedSideEffects() []string { return c.dryEffects }
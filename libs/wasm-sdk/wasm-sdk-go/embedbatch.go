@@ -0,0 +1,138 @@
+package sdk
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ErrEmbedFailed is returned when embeddings cannot be computed at all, e.g.
+// because the node may not use models.
+var ErrEmbedFailed = errors.New("embedding failed")
+
+// Defaults of EmbedOptions.
+const (
+	DefaultEmbedBatchSize  = 64
+	DefaultEmbedBatchChars = 32 * 1024
+	DefaultEmbedRetries    = 2
+)
+
+// EmbedOptions controls EmbedBatched. Zero fields use the defaults.
+type EmbedOptions struct {
+	// BatchSize is the most texts sent in one host call.
+	BatchSize int
+	// MaxBatchChars is the most characters sent in one host call; a longer
+	// single text is still sent on its own.
+	MaxBatchChars int
+	// Retries is how often a single text is retried before giving up; nil
+	// means DefaultEmbedRetries and a pointer to 0 disables retries. Failed
+	// batches of several texts are split in half instead.
+	Retries *int
+	// NoProgress disables the progress events streamed after every batch.
+	NoProgress bool
+}
+
+// EmbedBatchError reports the text EmbedBatched could not embed. Vectors
+// before Index were embedded and are returned with the error. Err is the
+// host's reason for the last failed attempt, if it gave one.
+type EmbedBatchError struct {
+	Index    int
+	Attempts int
+	Err      error
+}
+
+func (e *EmbedBatchError) Error() string {
+	msg := "embedding text " + strconv.Itoa(e.Index) + " failed after " + strconv.Itoa(e.Attempts) + " attempts"
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *EmbedBatchError) Unwrap() error { return e.Err }
+
+func (e *EmbedBatchError) errorInfo() ErrorInfo {
+	code := ErrCodeUpstream
+	var he *HostError
+	if errors.As(e.Err, &he) && he.Code != "" {
+		code = he.Code
+	}
+	return ErrorInfo{Code: code, Message: e.Error()}
+}
+
+// permanentEmbedError reports whether err is a host failure that neither a
+// smaller batch nor a retry can fix, e.g. a denied permission or an unknown
+// model.
+func permanentEmbedError(err error) bool {
+	var he *HostError
+	if !errors.As(err, &he) {
+		return false
+	}
+	switch he.Code {
+	case ErrCodePermissionDenied, ErrCodeNotFound, ErrCodeQuotaExceeded,
+		ErrCodeCancelled, ErrCodeDeadlineExceeded, ErrCodePolicy:
+		return true
+	}
+	return false
+}
+
+// EmbedBatched embeds texts with the model bit and returns one vector (raw
+// JSON array) per text, in input order. Texts are sent in batches bounded by
+// opts; when the host rejects a batch it is split in half and the smaller
+// size is kept for the rest of the run, so nodes need not know the model's
+// limits. Failures the host reports as permanent, such as a denied
+// permission or an unknown model, end the run at once. Progress is streamed
+// after every batch.
+func (c *Context) EmbedBatched(bitJSON string, texts []string, opts EmbedOptions) ([]string, error) {
+	if !c.host(PermModels) {
+		return nil, ErrEmbedFailed
+	}
+	size := opts.BatchSize
+	if size <= 0 {
+		size = DefaultEmbedBatchSize
+	}
+	maxChars := opts.MaxBatchChars
+	if maxChars <= 0 {
+		maxChars = DefaultEmbedBatchChars
+	}
+	retries := DefaultEmbedRetries
+	if opts.Retries != nil {
+		retries = *opts.Retries
+	}
+
+	out := make([]string, 0, len(texts))
+	attempts := 0
+	for i := 0; i < len(texts); {
+		n, chars := 0, 0
+		for i+n < len(texts) && n < size {
+			chars += len(texts[i+n])
+			if n > 0 && chars > maxChars {
+				break
+			}
+			n++
+		}
+		embedded, err := c.embedText(bitJSON, jsonStringArray(texts[i:i+n]))
+		vectors := JSONArrayItems(embedded)
+		if len(vectors) != n {
+			if permanentEmbedError(err) {
+				return out, &EmbedBatchError{Index: i, Attempts: attempts + 1, Err: err}
+			}
+			if n > 1 {
+				size = n / 2
+				c.Debug("embedding batch of " + strconv.Itoa(n) + " failed, retrying with " + strconv.Itoa(size))
+				continue
+			}
+			attempts++
+			if attempts > retries {
+				return out, &EmbedBatchError{Index: i, Attempts: attempts, Err: err}
+			}
+			continue
+		}
+		attempts = 0
+		out = append(out, vectors...)
+		i += n
+		if !opts.NoProgress {
+			c.StreamProgress(float32(i)/float32(len(texts)), "Embedded "+strconv.Itoa(i)+" of "+strconv.Itoa(len(texts)))
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,146 @@
+package sdk
+
+import (
+	"errors"
+	"strings"
+)
+
+// Error codes used by NodeError. Hosts display them alongside the message
+// and may use them to decide whether a failure is worth retrying.
+const (
+	ErrCodeInternal         = "internal"
+	ErrCodeInvalidInput     = "invalid_input"
+	ErrCodeNotFound         = "not_found"
+	ErrCodePermissionDenied = "permission_denied"
+	ErrCodeUpstream         = "upstream"
+)
+
+// NodeError is a structured node failure. Returning one from an error-style
+// handler reports Code and Pin to the runtime in addition to the message.
+type NodeError struct {
+	Code    string
+	Message string
+	// Pin optionally names the input pin the failure relates to.
+	Pin   string
+	Cause error
+}
+
+// NewError creates a NodeError with the given code and message.
+func NewError(code, message string) *NodeError {
+	return &NodeError{Code: code, Message: message}
+}
+
+// WithPin returns a copy of e that refers to the given pin.
+func (e *NodeError) WithPin(pin string) *NodeError {
+	c := *e
+	c.Pin = pin
+	return &c
+}
+
+// Wrap returns a copy of e caused by err.
+func (e *NodeError) Wrap(err error) *NodeError {
+	c := *e
+	c.Cause = err
+	return &c
+}
+
+func (e *NodeError) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+func (e *NodeError) Unwrap() error { return e.Cause }
+
+func (e *NodeError) errorInfo() ErrorInfo {
+	code := e.Code
+	if code == "" {
+		code = ErrCodeInternal
+	}
+	return ErrorInfo{Code: code, Message: e.Error(), Pin: e.Pin}
+}
+
+// structuredError is implemented by errors that carry their own ErrorInfo.
+type structuredError interface {
+	error
+	errorInfo() ErrorInfo
+}
+
+// ErrorInfo is the structured error detail serialized next to the plain
+// error message of an ExecutionResult.
+type ErrorInfo struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Pin     string `json:"pin,omitempty"`
+}
+
+func (e *ErrorInfo) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"code":`)
+	b.WriteString(jsonString(e.Code))
+	b.WriteString(`,"message":`)
+	b.WriteString(jsonString(e.Message))
+	if e.Pin != "" {
+		b.WriteString(`,"pin":`)
+		b.WriteString(jsonString(e.Pin))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func errorInfoFor(err error) ErrorInfo {
+	var se structuredError
+	if errors.As(err, &se) {
+		info := se.errorInfo()
+		info.Message = err.Error()
+		return info
+	}
+	return ErrorInfo{Code: ErrCodeInternal, Message: err.Error()}
+}
+
+// Result finishes the run from a Go error: nil behaves like Done, a non-nil
+// error fails the node with structured error info.
+func (c *Context) Result(err error) ExecutionResult {
+	if err == nil {
+		return c.Done()
+	}
+	info := errorInfoFor(err)
+	c.result.ErrorInfo = &info
+	return c.Fail(info.Message)
+}
+
+// ErrorHandler is a NodeHandler variant whose Run reports failure as an error.
+type ErrorHandler interface {
+	Define() NodeDefinition
+	Run(ctx *Context) error
+}
+
+type errorHandlerAdapter struct{ h ErrorHandler }
+
+func (a errorHandlerAdapter) Define() NodeDefinition { return a.h.Define() }
+
+func (a errorHandlerAdapter) Run(ctx *Context) ExecutionResult {
+	return ctx.Result(a.h.Run(ctx))
+}
+
+// HandleErrors adapts an ErrorHandler so it can be registered in a Registry.
+func HandleErrors(h ErrorHandler) NodeHandler {
+	return errorHandlerAdapter{h: h}
+}
+
+type funcHandler struct {
+	def NodeDefinition
+	run func(*Context) error
+}
+
+func (f funcHandler) Define() NodeDefinition { return f.def }
+
+func (f funcHandler) Run(ctx *Context) ExecutionResult {
+	return ctx.Result(f.run(ctx))
+}
+
+// NewNode builds a NodeHandler from a definition and an error-returning function.
+func NewNode(def NodeDefinition, run func(ctx *Context) error) NodeHandler {
+	return funcHandler{def: def, run: run}
+}
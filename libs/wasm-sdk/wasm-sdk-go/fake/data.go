@@ -0,0 +1,53 @@
+package fake
+
+var firstNames = []string{
+	"Ada", "Alan", "Amara", "Anna", "Ben", "Carla", "Chen", "Clara", "David", "Elena",
+	"Emma", "Felix", "Grace", "Hana", "Hugo", "Ines", "Ivan", "Jonas", "Julia", "Kai",
+	"Laura", "Leo", "Lina", "Luca", "Maya", "Mia", "Noah", "Nora", "Omar", "Paul",
+	"Priya", "Rosa", "Sam", "Sara", "Sofia", "Tom", "Yara", "Yuki", "Zoe", "Ravi",
+}
+
+var lastNames = []string{
+	"Andersen", "Bauer", "Becker", "Costa", "Dubois", "Fischer", "Garcia", "Hansen",
+	"Ito", "Jensen", "Kim", "Kowalski", "Lee", "Martin", "Meyer", "Müller", "Nguyen",
+	"Novak", "Okafor", "Patel", "Rossi", "Santos", "Schmidt", "Silva", "Smith",
+	"Tanaka", "Taylor", "Wagner", "Weber", "Wong",
+}
+
+var streetNames = []string{
+	"Oak", "Maple", "Cedar", "Elm", "Pine", "Lake", "Hill", "River", "Park", "Station",
+	"Church", "Mill", "Garden", "Bridge", "Market", "King", "Queen", "Harbor",
+}
+
+var streetSuffixes = []string{"Street", "Road", "Avenue", "Lane", "Way", "Drive", "Place"}
+
+var cities = []struct{ City, Country, Code string }{
+	{"Amsterdam", "Netherlands", "NL"}, {"Austin", "United States", "US"},
+	{"Berlin", "Germany", "DE"}, {"Lisbon", "Portugal", "PT"},
+	{"London", "United Kingdom", "GB"}, {"Lyon", "France", "FR"},
+	{"Melbourne", "Australia", "AU"}, {"Munich", "Germany", "DE"},
+	{"Osaka", "Japan", "JP"}, {"Oslo", "Norway", "NO"},
+	{"Seattle", "United States", "US"}, {"Seoul", "South Korea", "KR"},
+	{"Toronto", "Canada", "CA"}, {"Vienna", "Austria", "AT"},
+	{"Warsaw", "Poland", "PL"}, {"Zurich", "Switzerland", "CH"},
+}
+
+var companyWords = []string{
+	"Acme", "Apex", "Blue", "Bright", "Cloud", "Delta", "Evergreen", "Falcon", "Globex",
+	"Harbor", "Initech", "Lumen", "Nimbus", "Nova", "Orbit", "Pioneer", "Quantum", "Summit",
+	"Vertex", "Zenith",
+}
+
+var companySuffixes = []string{"Labs", "Systems", "Group", "Industries", "Logistics", "Analytics", "GmbH", "Inc.", "Ltd."}
+
+var emailDomains = []string{"example.com", "example.org", "example.net"}
+
+var loremWords = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed",
+	"do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna",
+	"aliqua", "enim", "ad", "minim", "veniam", "quis", "nostrud", "exercitation",
+	"ullamco", "laboris", "nisi", "aliquip", "ex", "ea", "commodo", "consequat", "duis",
+	"aute", "irure", "in", "reprehenderit", "voluptate", "velit", "esse", "cillum",
+	"fugiat", "nulla", "pariatur", "excepteur", "sint", "occaecat", "cupidatat", "non",
+	"proident", "sunt", "culpa", "qui", "officia", "deserunt", "mollit", "anim", "id", "est",
+}
@@ -0,0 +1,329 @@
+// Package fake generates realistic synthetic data for test-data and demo
+// nodes: names, emails, addresses, companies, timestamps, lorem text and
+// numbers from common distributions. Generators are seeded, so the same seed
+// always produces the same values:
+//
+//	f := fake.FromContext(ctx) // reproducible in deterministic runs
+//	for i := 0; i < 100; i++ {
+//		p := f.Person()
+//		rows = append(rows, p.Name+","+p.Email)
+//	}
+//
+// Emails use reserved example domains and phone numbers the 555 range, so
+// generated data cannot reach real people.
+package fake
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// Faker is a seeded generator. It is not safe for concurrent use.
+type Faker struct {
+	state uint64
+}
+
+// New returns a generator for seed.
+func New(seed int64) *Faker {
+	return &Faker{state: uint64(seed)}
+}
+
+// FromContext seeds a generator from ctx.Random, which is reproducible when
+// the host requests a deterministic run.
+func FromContext(ctx *sdk.Context) *Faker {
+	return New(ctx.Random())
+}
+
+// Uint64 returns the next raw value (splitmix64).
+func (f *Faker) Uint64() uint64 {
+	f.state += 0x9e3779b97f4a7c15
+	z := f.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Intn returns a value in [0, n). It returns 0 for n <= 0.
+func (f *Faker) Intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return int(f.Uint64() % uint64(n))
+}
+
+// IntRange returns a value in [min, max].
+func (f *Faker) IntRange(min, max int) int {
+	if max <= min {
+		return min
+	}
+	return min + f.Intn(max-min+1)
+}
+
+// Float64 returns a value in [0, 1).
+func (f *Faker) Float64() float64 {
+	return float64(f.Uint64()>>11) / (1 << 53)
+}
+
+// Bool returns true with probability p.
+func (f *Faker) Bool(p float64) bool { return f.Float64() < p }
+
+// Pick returns a random element of items, or "" if it is empty.
+func (f *Faker) Pick(items ...string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	return items[f.Intn(len(items))]
+}
+
+// Weighted returns an index into weights, chosen with probability
+// proportional to its weight. It returns -1 if no weight is positive.
+func (f *Faker) Weighted(weights ...float64) int {
+	total := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total == 0 {
+		return -1
+	}
+	x := f.Float64() * total
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		if x < w {
+			return i
+		}
+		x -= w
+	}
+	return len(weights) - 1
+}
+
+// --- Numbers ---
+
+// Uniform returns a value in [min, max).
+func (f *Faker) Uniform(min, max float64) float64 { return min + f.Float64()*(max-min) }
+
+// Normal returns a normally distributed value (Box-Muller).
+func (f *Faker) Normal(mean, stddev float64) float64 {
+	u1 := 1 - f.Float64() // (0, 1], keeps the log finite
+	u2 := f.Float64()
+	return mean + stddev*math.Sqrt(-2*math.Log(u1))*math.Cos(2*math.Pi*u2)
+}
+
+// LogNormal returns a value whose logarithm is Normal(mu, sigma), a good fit
+// for prices, incomes and durations.
+func (f *Faker) LogNormal(mu, sigma float64) float64 { return math.Exp(f.Normal(mu, sigma)) }
+
+// Exponential returns the waiting time between events occurring at rate per
+// unit, e.g. request inter-arrival times.
+func (f *Faker) Exponential(rate float64) float64 {
+	return -math.Log(1-f.Float64()) / rate
+}
+
+// Poisson returns the number of events in an interval with mean lambda.
+func (f *Faker) Poisson(lambda float64) int {
+	if lambda <= 0 {
+		return 0
+	}
+	if lambda > 30 {
+		// Knuth's method underflows for large lambda; use the normal
+		// approximation instead.
+		n := int(math.Round(f.Normal(lambda, math.Sqrt(lambda))))
+		if n < 0 {
+			return 0
+		}
+		return n
+	}
+	limit, p, k := math.Exp(-lambda), 1.0, 0
+	for {
+		p *= f.Float64()
+		if p <= limit {
+			return k
+		}
+		k++
+	}
+}
+
+// Price returns an amount in [min, max) rounded to cents.
+func (f *Faker) Price(min, max float64) float64 {
+	return math.Round(f.Uniform(min, max)*100) / 100
+}
+
+// --- Time ---
+
+// Time returns an instant in [from, to).
+func (f *Faker) Time(from, to time.Time) time.Time {
+	span := to.Sub(from)
+	if span <= 0 {
+		return from
+	}
+	return from.Add(time.Duration(f.Uint64() % uint64(span)))
+}
+
+// Timestamp returns Unix milliseconds in [fromMs, toMs).
+func (f *Faker) Timestamp(fromMs, toMs int64) int64 {
+	if toMs <= fromMs {
+		return fromMs
+	}
+	return fromMs + int64(f.Uint64()%uint64(toMs-fromMs))
+}
+
+// --- Identifiers ---
+
+// UUID returns a random version 4 UUID.
+func (f *Faker) UUID() string {
+	const hex = "0123456789abcdef"
+	var b [16]byte
+	for i := 0; i < 16; i += 8 {
+		v := f.Uint64()
+		for j := 0; j < 8; j++ {
+			b[i+j] = byte(v >> (8 * j))
+		}
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	out := make([]byte, 0, 36)
+	for i, c := range b {
+		if i == 4 || i == 6 || i == 8 || i == 10 {
+			out = append(out, '-')
+		}
+		out = append(out, hex[c>>4], hex[c&0x0f])
+	}
+	return string(out)
+}
+
+// Digits returns n random decimal digits.
+func (f *Faker) Digits(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('0' + f.Intn(10))
+	}
+	return string(b)
+}
+
+// --- People ---
+
+func (f *Faker) FirstName() string { return f.Pick(firstNames...) }
+func (f *Faker) LastName() string  { return f.Pick(lastNames...) }
+func (f *Faker) Name() string      { return f.FirstName() + " " + f.LastName() }
+
+// Username derives a handle such as "nora.weber42".
+func (f *Faker) Username() string {
+	return slug(f.FirstName()) + "." + slug(f.LastName()) + strconv.Itoa(f.Intn(100))
+}
+
+// Email returns an address at a reserved example domain.
+func (f *Faker) Email() string {
+	return f.Username() + "@" + f.Pick(emailDomains...)
+}
+
+// Phone returns a number in the fictional 555-01xx range.
+func (f *Faker) Phone() string {
+	return "+1 " + f.Digits(3) + "-555-01" + f.Digits(2)
+}
+
+// Person is a consistent set of personal details.
+type Person struct {
+	FirstName string
+	LastName  string
+	Name      string
+	Email     string
+	Phone     string
+	Address   Address
+}
+
+// Person returns a person whose email matches their name.
+func (f *Faker) Person() Person {
+	p := Person{FirstName: f.FirstName(), LastName: f.LastName()}
+	p.Name = p.FirstName + " " + p.LastName
+	p.Email = slug(p.FirstName) + "." + slug(p.LastName) + "@" + f.Pick(emailDomains...)
+	p.Phone = f.Phone()
+	p.Address = f.Address()
+	return p
+}
+
+// --- Places ---
+
+// Address is a postal address.
+type Address struct {
+	Street      string
+	City        string
+	PostalCode  string
+	Country     string
+	CountryCode string // ISO 3166-1 alpha-2
+}
+
+// String formats the address on one line.
+func (a Address) String() string {
+	return a.Street + ", " + a.PostalCode + " " + a.City + ", " + a.Country
+}
+
+func (f *Faker) Street() string {
+	return strconv.Itoa(f.IntRange(1, 250)) + " " + f.Pick(streetNames...) + " " + f.Pick(streetSuffixes...)
+}
+
+func (f *Faker) Address() Address {
+	c := cities[f.Intn(len(cities))]
+	return Address{
+		Street:      f.Street(),
+		City:        c.City,
+		PostalCode:  f.Digits(5),
+		Country:     c.Country,
+		CountryCode: c.Code,
+	}
+}
+
+func (f *Faker) City() string { return cities[f.Intn(len(cities))].City }
+
+// Company returns a company name such as "Nimbus Analytics".
+func (f *Faker) Company() string {
+	return f.Pick(companyWords...) + " " + f.Pick(companySuffixes...)
+}
+
+// --- Text ---
+
+func (f *Faker) Word() string { return f.Pick(loremWords...) }
+
+// Words returns n lorem ipsum words separated by spaces.
+func (f *Faker) Words(n int) string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = f.Word()
+	}
+	return strings.Join(words, " ")
+}
+
+// Sentence returns a capitalized lorem ipsum sentence of 6 to 14 words.
+func (f *Faker) Sentence() string {
+	s := f.Words(f.IntRange(6, 14))
+	return strings.ToUpper(s[:1]) + s[1:] + "."
+}
+
+// Paragraph returns n sentences.
+func (f *Faker) Paragraph(n int) string {
+	sentences := make([]string, n)
+	for i := range sentences {
+		sentences[i] = f.Sentence()
+	}
+	return strings.Join(sentences, " ")
+}
+
+// slug lower-cases s and drops everything but ASCII letters and digits.
+func slug(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == 'ü':
+			b.WriteString("ue")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package sdk
+
+import "strconv"
+
+// RawJSON marks a string as already encoded JSON, so Out writes it verbatim
+// instead of quoting it.
+type RawJSON string
+
+// Out sets an output pin and returns the context for chaining:
+//
+//	return ctx.Out("result", text).Out("count", n).Done()
+//
+// Go strings, bools, integers, floats and []string are JSON-encoded; RawJSON
+// is written as is. nil and unsupported types produce null.
+func (c *Context) Out(name string, value any) *Context {
+	c.SetOutput(name, encodeValue(value))
+	return c
+}
+
+// Activate activates an exec output and returns the context for chaining.
+func (c *Context) Activate(pin string) *Context {
+	c.ActivateExec(pin)
+	return c
+}
+
+// Done finishes the run. If no exec output was activated via Activate or
+// ActivateExec, the node's default exec output is activated, like Success.
+func (c *Context) Done() ExecutionResult {
+	if len(c.result.ActivateExec) == 0 && !c.result.Pending {
+		c.ActivateExec(c.successExec)
+	}
+	return c.Finish()
+}
+
+func encodeValue(value any) string {
+	switch v := value.(type) {
+	case RawJSON:
+		return string(v)
+	case string:
+		return jsonString(v)
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case int:
+		return strconv.Itoa(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		return jsonStringArray(v)
+	case interface{ ToJSON() string }:
+		return v.ToJSON()
+	default:
+		return "null"
+	}
+}
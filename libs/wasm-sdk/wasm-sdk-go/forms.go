@@ -0,0 +1,356 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Form field types.
+const (
+	FieldText        = "text"
+	FieldTextArea    = "textarea"
+	FieldNumber      = "number"
+	FieldInteger     = "integer"
+	FieldCheckbox    = "checkbox"
+	FieldSelect      = "select"
+	FieldMultiSelect = "multiselect"
+	FieldDate        = "date"
+	FieldEmail       = "email"
+	// Reference fields are only valid in configuration forms (SetConfig,
+	// PackManifest.Config). The editor renders a picker bound to the
+	// platform's credentials and saves a reference; the host resolves it
+	// when the node reads its config.
+	FieldSecretRef        = "secretRef"
+	FieldOAuthProviderRef = "oauthProviderRef"
+)
+
+// FormOption is a choice of a select or multiselect field.
+type FormOption struct {
+	Value string
+	Label string
+}
+
+// FormField is one input of a FormDefinition. Build fields with the *Field
+// constructors and the With* methods, like pins:
+//
+//	sdk.NumberField("amount", "Amount").Required().WithRange(0, 1000).
+//		VisibleIf("kind", `"partial"`)
+type FormField struct {
+	Name        string
+	Label       string
+	Type        string
+	Help        string
+	Placeholder string
+	IsRequired  bool
+	// Default is the raw JSON initial value.
+	Default string
+	Options []FormOption
+	// Min and Max bound numbers; MinLength and MaxLength bound text. Nil
+	// means unbounded.
+	Min, Max             *float64
+	MinLength, MaxLength *int
+	// Pattern is a regular expression text must match, checked by the UI.
+	Pattern string
+	// The field is only shown, and only required, while the field named
+	// VisibleField has the raw JSON value VisibleEquals.
+	VisibleField  string
+	VisibleEquals string
+}
+
+func newField(typ, name, label string) FormField {
+	return FormField{Name: name, Label: label, Type: typ}
+}
+
+func TextField(name, label string) FormField     { return newField(FieldText, name, label) }
+func TextAreaField(name, label string) FormField { return newField(FieldTextArea, name, label) }
+func NumberField(name, label string) FormField   { return newField(FieldNumber, name, label) }
+func IntegerField(name, label string) FormField  { return newField(FieldInteger, name, label) }
+func CheckboxField(name, label string) FormField { return newField(FieldCheckbox, name, label) }
+func DateField(name, label string) FormField     { return newField(FieldDate, name, label) }
+func EmailField(name, label string) FormField    { return newField(FieldEmail, name, label) }
+
+// SecretRefField picks a secret stored on the platform, e.g. an API key. The
+// config holds a reference; GetConfig resolves it to the secret's value.
+func SecretRefField(name, label string) FormField {
+	return newField(FieldSecretRef, name, label)
+}
+
+// OAuthProviderRefField picks an OAuth connection, limited to providers when
+// given. GetConfig resolves it to an access token of the connection.
+func OAuthProviderRefField(name, label string, providers ...string) FormField {
+	return newField(FieldOAuthProviderRef, name, label).WithOptions(providers...)
+}
+
+// isRef reports whether the field holds a credential reference.
+func (f *FormField) isRef() bool {
+	return f.Type == FieldSecretRef || f.Type == FieldOAuthProviderRef
+}
+
+// SelectField offers a single choice; values double as labels.
+func SelectField(name, label string, values ...string) FormField {
+	return newField(FieldSelect, name, label).WithOptions(values...)
+}
+
+// MultiSelectField offers any number of choices; values double as labels.
+func MultiSelectField(name, label string, values ...string) FormField {
+	return newField(FieldMultiSelect, name, label).WithOptions(values...)
+}
+
+func (f FormField) Required() FormField {
+	f.IsRequired = true
+	return f
+}
+
+func (f FormField) WithHelp(help string) FormField {
+	f.Help = help
+	return f
+}
+
+func (f FormField) WithPlaceholder(placeholder string) FormField {
+	f.Placeholder = placeholder
+	return f
+}
+
+// WithDefault sets the raw JSON initial value.
+func (f FormField) WithDefault(value string) FormField {
+	f.Default = value
+	return f
+}
+
+// WithOptions appends choices whose labels equal their values.
+func (f FormField) WithOptions(values ...string) FormField {
+	for _, v := range values {
+		f.Options = append(f.Options, FormOption{Value: v, Label: v})
+	}
+	return f
+}
+
+// WithOption appends a choice with a separate label.
+func (f FormField) WithOption(value, label string) FormField {
+	f.Options = append(f.Options, FormOption{Value: value, Label: label})
+	return f
+}
+
+func (f FormField) WithRange(min, max float64) FormField {
+	f.Min, f.Max = &min, &max
+	return f
+}
+
+func (f FormField) WithLength(min, max int) FormField {
+	f.MinLength, f.MaxLength = &min, &max
+	return f
+}
+
+func (f FormField) WithPattern(pattern string) FormField {
+	f.Pattern = pattern
+	return f
+}
+
+// VisibleIf shows the field only while field has the raw JSON value equals.
+func (f FormField) VisibleIf(field, equals string) FormField {
+	f.VisibleField, f.VisibleEquals = field, equals
+	return f
+}
+
+func (f *FormField) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"name":`)
+	b.WriteString(jsonString(f.Name))
+	b.WriteString(`,"label":`)
+	b.WriteString(jsonString(f.Label))
+	b.WriteString(`,"type":`)
+	b.WriteString(jsonString(f.Type))
+	b.WriteString(`,"required":`)
+	b.WriteString(strconv.FormatBool(f.IsRequired))
+	if f.Help != "" {
+		b.WriteString(`,"help":`)
+		b.WriteString(jsonString(f.Help))
+	}
+	if f.Placeholder != "" {
+		b.WriteString(`,"placeholder":`)
+		b.WriteString(jsonString(f.Placeholder))
+	}
+	if f.Default != "" {
+		b.WriteString(`,"default":`)
+		b.WriteString(f.Default)
+	}
+	if len(f.Options) > 0 {
+		b.WriteString(`,"options":[`)
+		for i, o := range f.Options {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(`{"value":`)
+			b.WriteString(jsonString(o.Value))
+			b.WriteString(`,"label":`)
+			b.WriteString(jsonString(o.Label))
+			b.WriteByte('}')
+		}
+		b.WriteByte(']')
+	}
+	writeFloat(&b, "min", f.Min)
+	writeFloat(&b, "max", f.Max)
+	writeInt(&b, "min_length", f.MinLength)
+	writeInt(&b, "max_length", f.MaxLength)
+	if f.Pattern != "" {
+		b.WriteString(`,"pattern":`)
+		b.WriteString(jsonString(f.Pattern))
+	}
+	if f.VisibleField != "" {
+		b.WriteString(`,"visible_if":{"field":`)
+		b.WriteString(jsonString(f.VisibleField))
+		b.WriteString(`,"equals":`)
+		b.WriteString(f.VisibleEquals)
+		b.WriteByte('}')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// schema returns the JSON Schema of the field's value.
+func (f *FormField) schema() string {
+	var b strings.Builder
+	switch f.Type {
+	case FieldNumber:
+		b.WriteString(`{"type":"number"`)
+	case FieldInteger:
+		b.WriteString(`{"type":"integer"`)
+	case FieldCheckbox:
+		b.WriteString(`{"type":"boolean"`)
+	case FieldMultiSelect:
+		b.WriteString(`{"type":"array","uniqueItems":true,"items":{"enum":`)
+		b.WriteString(jsonStringArray(f.optionValues()))
+		b.WriteByte('}')
+	case FieldSelect:
+		b.WriteString(`{"enum":`)
+		b.WriteString(jsonStringArray(f.optionValues()))
+	default:
+		b.WriteString(`{"type":"string"`)
+		switch f.Type {
+		case FieldDate:
+			b.WriteString(`,"format":"date"`)
+		case FieldEmail:
+			b.WriteString(`,"format":"email"`)
+		case FieldSecretRef:
+			b.WriteString(`,"format":"secret-ref"`)
+		case FieldOAuthProviderRef:
+			b.WriteString(`,"format":"oauth-provider-ref"`)
+		}
+	}
+	writeFloat(&b, "minimum", f.Min)
+	writeFloat(&b, "maximum", f.Max)
+	writeInt(&b, "minLength", f.MinLength)
+	writeInt(&b, "maxLength", f.MaxLength)
+	if f.Pattern != "" {
+		b.WriteString(`,"pattern":`)
+		b.WriteString(jsonString(f.Pattern))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func (f *FormField) optionValues() []string {
+	values := make([]string, len(f.Options))
+	for i, o := range f.Options {
+		values[i] = o.Value
+	}
+	return values
+}
+
+func writeFloat(b *strings.Builder, key string, v *float64) {
+	if v != nil {
+		b.WriteString(`,"` + key + `":`)
+		b.WriteString(strconv.FormatFloat(*v, 'f', -1, 64))
+	}
+}
+
+func writeInt(b *strings.Builder, key string, v *int) {
+	if v != nil {
+		b.WriteString(`,"` + key + `":`)
+		b.WriteString(strconv.Itoa(*v))
+	}
+}
+
+// FormDefinition is a structured form shown to the user by
+// Context.RequestForm. The answer is an object keyed by field name.
+type FormDefinition struct {
+	Title       string
+	Description string
+	SubmitLabel string
+	Fields      []FormField
+}
+
+// NewForm creates an empty form.
+func NewForm(title string) *FormDefinition {
+	return &FormDefinition{Title: title}
+}
+
+func (d *FormDefinition) AddField(field FormField) *FormDefinition {
+	d.Fields = append(d.Fields, field)
+	return d
+}
+
+func (d *FormDefinition) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"title":`)
+	b.WriteString(jsonString(d.Title))
+	if d.Description != "" {
+		b.WriteString(`,"description":`)
+		b.WriteString(jsonString(d.Description))
+	}
+	if d.SubmitLabel != "" {
+		b.WriteString(`,"submit_label":`)
+		b.WriteString(jsonString(d.SubmitLabel))
+	}
+	b.WriteString(`,"fields":[`)
+	for i := range d.Fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(d.Fields[i].ToJSON())
+	}
+	b.WriteString(`]}`)
+	return b.String()
+}
+
+// Schema returns the JSON Schema of the form's answer, used to validate it.
+// A required field that is conditionally visible is only required while it
+// is shown.
+func (d *FormDefinition) Schema() string {
+	var b strings.Builder
+	b.WriteString(`{"type":"object","properties":{`)
+	var required, conditional []string
+	for i := range d.Fields {
+		f := &d.Fields[i]
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(jsonString(f.Name))
+		b.WriteByte(':')
+		b.WriteString(f.schema())
+		if !f.IsRequired {
+			continue
+		}
+		if f.VisibleField == "" {
+			required = append(required, f.Name)
+			continue
+		}
+		// Required only while shown: either the condition does not hold
+		// or the field is present.
+		conditional = append(conditional, `{"anyOf":[{"not":{"properties":{`+
+			jsonString(f.VisibleField)+`:{"const":`+f.VisibleEquals+`}},"required":[`+
+			jsonString(f.VisibleField)+`]}},{"required":[`+jsonString(f.Name)+`]}]}`)
+	}
+	b.WriteByte('}')
+	if len(required) > 0 {
+		b.WriteString(`,"required":`)
+		b.WriteString(jsonStringArray(required))
+	}
+	if len(conditional) > 0 {
+		b.WriteString(`,"allOf":[`)
+		b.WriteString(strings.Join(conditional, ","))
+		b.WriteByte(']')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
@@ -0,0 +1,161 @@
+// Package geo provides geographic helpers for location-aware nodes: great
+// circle distances, bounding boxes, point-in-polygon tests and GeoJSON
+// encoding and decoding without encoding/json.
+//
+// Coordinates are WGS84 degrees. Note that GeoJSON orders positions as
+// [longitude, latitude]; Point keeps named fields to avoid mix-ups.
+package geo
+
+import "math"
+
+// EarthRadius is the mean Earth radius in meters.
+const EarthRadius = 6371008.8
+
+// Point is a WGS84 position.
+type Point struct {
+	Lat float64
+	Lon float64
+}
+
+// Valid reports whether the coordinates are within range.
+func (p Point) Valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+func rad(deg float64) float64 { return deg * math.Pi / 180 }
+func deg(rad float64) float64 { return rad * 180 / math.Pi }
+
+// Distance returns the great-circle distance between a and b in meters
+// (haversine formula).
+func Distance(a, b Point) float64 {
+	dLat := rad(b.Lat - a.Lat)
+	dLon := rad(b.Lon - a.Lon)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(rad(a.Lat))*math.Cos(rad(b.Lat))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Bearing returns the initial compass bearing from a to b in degrees [0, 360).
+func Bearing(a, b Point) float64 {
+	lat1, lat2 := rad(a.Lat), rad(b.Lat)
+	dLon := rad(b.Lon - a.Lon)
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return math.Mod(deg(math.Atan2(y, x))+360, 360)
+}
+
+// Destination returns the point reached by travelling distance meters from p
+// along the given initial bearing.
+func Destination(p Point, bearing, distance float64) Point {
+	lat1, lon1 := rad(p.Lat), rad(p.Lon)
+	brg, ang := rad(bearing), distance/EarthRadius
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(ang) + math.Cos(lat1)*math.Sin(ang)*math.Cos(brg))
+	lon2 := lon1 + math.Atan2(math.Sin(brg)*math.Sin(ang)*math.Cos(lat1), math.Cos(ang)-math.Sin(lat1)*math.Sin(lat2))
+	return Point{Lat: deg(lat2), Lon: math.Mod(deg(lon2)+540, 360) - 180}
+}
+
+// PathLength returns the length of a polyline in meters.
+func PathLength(path []Point) float64 {
+	total := 0.0
+	for i := 1; i < len(path); i++ {
+		total += Distance(path[i-1], path[i])
+	}
+	return total
+}
+
+// BBox is an axis-aligned bounding box. Boxes crossing the antimeridian are
+// not supported.
+type BBox struct {
+	MinLat, MinLon, MaxLat, MaxLon float64
+}
+
+// Bounds returns the smallest box containing all points.
+func Bounds(points ...Point) BBox {
+	if len(points) == 0 {
+		return BBox{}
+	}
+	b := BBox{points[0].Lat, points[0].Lon, points[0].Lat, points[0].Lon}
+	for _, p := range points[1:] {
+		b = b.Extend(p)
+	}
+	return b
+}
+
+// Around returns a box enclosing the circle of radius meters around p, useful
+// as a cheap pre-filter before exact Distance checks.
+func Around(p Point, radius float64) BBox {
+	dLat := deg(radius / EarthRadius)
+	dLon := 180.0
+	if c := math.Cos(rad(p.Lat)); c > 1e-12 {
+		dLon = math.Min(180, dLat/c)
+	}
+	return BBox{
+		MinLat: math.Max(-90, p.Lat-dLat),
+		MinLon: math.Max(-180, p.Lon-dLon),
+		MaxLat: math.Min(90, p.Lat+dLat),
+		MaxLon: math.Min(180, p.Lon+dLon),
+	}
+}
+
+// Contains reports whether p lies inside the box, edges included.
+func (b BBox) Contains(p Point) bool {
+	return p.Lat >= b.MinLat && p.Lat <= b.MaxLat && p.Lon >= b.MinLon && p.Lon <= b.MaxLon
+}
+
+// Intersects reports whether two boxes overlap.
+func (b BBox) Intersects(o BBox) bool {
+	return b.MinLat <= o.MaxLat && o.MinLat <= b.MaxLat && b.MinLon <= o.MaxLon && o.MinLon <= b.MaxLon
+}
+
+// Extend grows the box to include p.
+func (b BBox) Extend(p Point) BBox {
+	b.MinLat = math.Min(b.MinLat, p.Lat)
+	b.MinLon = math.Min(b.MinLon, p.Lon)
+	b.MaxLat = math.Max(b.MaxLat, p.Lat)
+	b.MaxLon = math.Max(b.MaxLon, p.Lon)
+	return b
+}
+
+// Center returns the midpoint of the box.
+func (b BBox) Center() Point {
+	return Point{Lat: (b.MinLat + b.MaxLat) / 2, Lon: (b.MinLon + b.MaxLon) / 2}
+}
+
+// Polygon is a list of linear rings: the first is the outer boundary, any
+// further rings are holes. Rings may or may not repeat their first point.
+type Polygon [][]Point
+
+// Contains reports whether p is inside the outer ring and outside every hole
+// (even-odd ray casting on planar coordinates, fine for city-to-country sized
+// shapes away from the poles).
+func (poly Polygon) Contains(p Point) bool {
+	if len(poly) == 0 || !inRing(poly[0], p) {
+		return false
+	}
+	for _, hole := range poly[1:] {
+		if inRing(hole, p) {
+			return false
+		}
+	}
+	return true
+}
+
+// Bounds returns the bounding box of the outer ring.
+func (poly Polygon) Bounds() BBox {
+	if len(poly) == 0 {
+		return BBox{}
+	}
+	return Bounds(poly[0]...)
+}
+
+func inRing(ring []Point, p Point) bool {
+	in := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lon < (b.Lon-a.Lon)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			in = !in
+		}
+	}
+	return in
+}
@@ -0,0 +1,138 @@
+package geo
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+var (
+	berlin = Point{Lat: 52.5200, Lon: 13.4050}
+	paris  = Point{Lat: 48.8566, Lon: 2.3522}
+)
+
+func near(a, b, tol float64) bool { return math.Abs(a-b) <= tol }
+
+func TestDistanceAndBearing(t *testing.T) {
+	if d := Distance(berlin, paris); !near(d, 877_500, 1_500) {
+		t.Errorf("Distance(Berlin, Paris) = %.0f m", d)
+	}
+	if d := Distance(berlin, berlin); d != 0 {
+		t.Errorf("Distance to itself = %v", d)
+	}
+	if b := Bearing(Point{0, 0}, Point{1, 0}); !near(b, 0, 1e-9) {
+		t.Errorf("Bearing north = %v", b)
+	}
+	if b := Bearing(Point{0, 0}, Point{0, -1}); !near(b, 270, 1e-9) {
+		t.Errorf("Bearing west = %v", b)
+	}
+	dest := Destination(berlin, Bearing(berlin, paris), Distance(berlin, paris))
+	if !near(dest.Lat, paris.Lat, 1e-6) || !near(dest.Lon, paris.Lon, 1e-6) {
+		t.Errorf("Destination = %+v, want %+v", dest, paris)
+	}
+}
+
+func TestPolygonContains(t *testing.T) {
+	square := Polygon{
+		{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}},
+		{{4, 4}, {4, 6}, {6, 6}, {6, 4}, {4, 4}}, // hole
+	}
+	tests := []struct {
+		p    Point
+		want bool
+	}{
+		{Point{2, 2}, true},
+		{Point{5, 5}, false},
+		{Point{11, 5}, false},
+		{Point{-1, -1}, false},
+		{Point{9.9, 0.1}, true},
+	}
+	for _, tt := range tests {
+		if got := square.Contains(tt.p); got != tt.want {
+			t.Errorf("Contains(%+v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	b := square.Bounds()
+	if b.MinLat != 0 || b.MaxLat != 10 || b.MinLon != 0 || b.MaxLon != 10 {
+		t.Errorf("Bounds = %+v", b)
+	}
+	if around := Around(berlin, 1000); !around.Contains(berlin) || around.Contains(paris) {
+		t.Errorf("Around(Berlin, 1 km) = %+v", around)
+	}
+}
+
+func TestParsePoint(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Point
+		err  error
+	}{
+		{`{"lat":52.52,"lon":13.405}`, Point{52.52, 13.405}, nil},
+		{`{"latitude":"1.5","longitude":"-2"}`, Point{1.5, -2}, nil},
+		{`{"lat":1,"lng":2}`, Point{1, 2}, nil},
+		{`[13.405, 52.52]`, Point{52.52, 13.405}, nil},
+		{`{"type":"Point","coordinates":[2,1]}`, Point{1, 2}, nil},
+		{`{"lat":91,"lon":0}`, Point{}, ErrInvalid},
+		{`{"lat":1}`, Point{}, ErrInvalid},
+		{`{"lat":"x","lon":1}`, Point{}, ErrInvalid},
+		{`{"type":"LineString","coordinates":[[0,0],[1,1]]}`, Point{}, ErrInvalid},
+		{`[1]`, Point{}, ErrInvalid},
+		{`nope`, Point{}, ErrInvalid},
+	}
+	for _, tt := range tests {
+		got, err := ParsePoint(tt.raw)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("ParsePoint(%s) = %+v, %v, want %+v, %v", tt.raw, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestGeometryRoundTrip(t *testing.T) {
+	for _, raw := range []string{
+		`{"type":"Point","coordinates":[13.405,52.52]}`,
+		`{"type":"LineString","coordinates":[[0,0],[1,1],[2,0.5]]}`,
+		`{"type":"Polygon","coordinates":[[[0,0],[0,10],[10,10],[10,0],[0,0]]]}`,
+		`{"type":"MultiPolygon","coordinates":[[[[0,0],[0,1],[1,1],[0,0]]],[[[5,5],[5,6],[6,6],[5,5]]]]}`,
+	} {
+		g, err := ParseGeometry(raw)
+		if err != nil {
+			t.Fatalf("ParseGeometry(%s): %v", raw, err)
+		}
+		if got := g.ToJSON(); got != raw {
+			t.Errorf("ToJSON = %s, want %s", got, raw)
+		}
+	}
+	for _, raw := range []string{
+		`{"type":"Circle","coordinates":[0,0]}`,
+		`{"type":"Point","coordinates":"x"}`,
+		`{"type":"Polygon","coordinates":[[[0,"a"]]]}`,
+		`[]`,
+	} {
+		if _, err := ParseGeometry(raw); !errors.Is(err, ErrInvalid) {
+			t.Errorf("ParseGeometry(%s) error = %v, want ErrInvalid", raw, err)
+		}
+	}
+}
+
+func TestFeatureCollection(t *testing.T) {
+	raw := `{"type":"FeatureCollection","features":[` +
+		`{"type":"Feature","id":"a","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"A","n":1}},` +
+		`{"type":"Feature","geometry":null,"properties":null}]}`
+	features, err := ParseFeatureCollection(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(features) != 2 || features[0].ID != "a" || features[1].Geometry != nil {
+		t.Fatalf("features = %+v", features)
+	}
+	if name, ok := features[0].Property("name"); !ok || name != "A" {
+		t.Errorf("Property(name) = %q, %v", name, ok)
+	}
+	want := `{"type":"Feature","id":"a","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"n":1,"name":"A"}}`
+	if got := features[0].ToJSON(); got != want {
+		t.Errorf("ToJSON = %s, want %s", got, want)
+	}
+	if _, err := ParseFeatureCollection(`{"type":"Feature"}`); !errors.Is(err, ErrInvalid) {
+		t.Errorf("wrong type error = %v", err)
+	}
+}
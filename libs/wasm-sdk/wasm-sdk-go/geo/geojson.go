@@ -0,0 +1,416 @@
+package geo
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// ErrInvalid is returned for malformed GeoJSON or point values.
+var ErrInvalid = errors.New("geo: invalid geometry")
+
+// GeoJSON geometry types.
+const (
+	TypePoint           = "Point"
+	TypeMultiPoint      = "MultiPoint"
+	TypeLineString      = "LineString"
+	TypeMultiLineString = "MultiLineString"
+	TypePolygon         = "Polygon"
+	TypeMultiPolygon    = "MultiPolygon"
+)
+
+// PointSchema is the recommended JSON Schema for Struct pins carrying a
+// single location, encoded as {"lat": 52.52, "lon": 13.40}.
+const PointSchema = `{"type":"object","title":"Point","required":["lat","lon"],"properties":{` +
+	`"lat":{"type":"number","minimum":-90,"maximum":90},` +
+	`"lon":{"type":"number","minimum":-180,"maximum":180}}}`
+
+// GeometrySchema is the recommended JSON Schema for Struct pins carrying
+// shapes, encoded as a GeoJSON geometry object (RFC 7946).
+const GeometrySchema = `{"type":"object","title":"GeoJSON Geometry","required":["type","coordinates"],"properties":{` +
+	`"type":{"enum":["Point","MultiPoint","LineString","MultiLineString","Polygon","MultiPolygon"]},` +
+	`"coordinates":{"type":"array"}}}`
+
+// FeatureSchema is the recommended JSON Schema for Struct pins carrying a
+// GeoJSON Feature with properties.
+const FeatureSchema = `{"type":"object","title":"GeoJSON Feature","required":["type","geometry"],"properties":{` +
+	`"type":{"const":"Feature"},"id":{"type":["string","number"]},` +
+	`"geometry":` + GeometrySchema + `,"properties":{"type":["object","null"]}}}`
+
+// InputPoint declares a Struct input pin holding a Point (see PointSchema).
+func InputPoint(name, friendlyName, description string) sdk.PinDefinition {
+	return sdk.InputPin(name, friendlyName, description, sdk.DataTypeStruct).WithSchema(PointSchema)
+}
+
+// OutputPoint declares a Struct output pin holding a Point.
+func OutputPoint(name, friendlyName, description string) sdk.PinDefinition {
+	return sdk.OutputPin(name, friendlyName, description, sdk.DataTypeStruct).WithSchema(PointSchema)
+}
+
+// InputGeometry declares a Struct input pin holding a GeoJSON geometry.
+func InputGeometry(name, friendlyName, description string) sdk.PinDefinition {
+	return sdk.InputPin(name, friendlyName, description, sdk.DataTypeStruct).WithSchema(GeometrySchema)
+}
+
+// OutputGeometry declares a Struct output pin holding a GeoJSON geometry.
+func OutputGeometry(name, friendlyName, description string) sdk.PinDefinition {
+	return sdk.OutputPin(name, friendlyName, description, sdk.DataTypeStruct).WithSchema(GeometrySchema)
+}
+
+// ToJSON encodes p as {"lat":…,"lon":…}.
+func (p Point) ToJSON() string {
+	return `{"lat":` + formatCoord(p.Lat) + `,"lon":` + formatCoord(p.Lon) + `}`
+}
+
+// ParsePoint decodes a location from {"lat","lon"} (also "lng", "latitude",
+// "longitude"), a GeoJSON Point geometry or a bare [lon, lat] position.
+func ParsePoint(raw string) (Point, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(raw, "[") {
+		return parsePosition(raw)
+	}
+	fields := sdk.JSONObjectFields(raw)
+	if fields == nil {
+		return Point{}, ErrInvalid
+	}
+	if _, ok := fields["coordinates"]; ok {
+		g, err := ParseGeometry(raw)
+		if err != nil || g.Type != TypePoint {
+			return Point{}, ErrInvalid
+		}
+		return g.Point, nil
+	}
+	lat, ok1 := firstNumber(fields, "lat", "latitude")
+	lon, ok2 := firstNumber(fields, "lon", "lng", "longitude")
+	if !ok1 || !ok2 {
+		return Point{}, ErrInvalid
+	}
+	p := Point{Lat: lat, Lon: lon}
+	if !p.Valid() {
+		return Point{}, ErrInvalid
+	}
+	return p, nil
+}
+
+func firstNumber(fields map[string]string, keys ...string) (float64, bool) {
+	for _, k := range keys {
+		if v, ok := fields[k]; ok {
+			f, err := strconv.ParseFloat(sdk.JSONUnquote(v), 64)
+			return f, err == nil
+		}
+	}
+	return 0, false
+}
+
+// Geometry is a decoded GeoJSON geometry. Only the field matching Type is set.
+type Geometry struct {
+	Type     string
+	Point    Point     // Point
+	Points   []Point   // MultiPoint, LineString
+	Lines    [][]Point // MultiLineString
+	Polygon  Polygon   // Polygon
+	Polygons []Polygon // MultiPolygon
+}
+
+// Geometry constructors.
+
+func NewPoint(p Point) Geometry            { return Geometry{Type: TypePoint, Point: p} }
+func NewLineString(path []Point) Geometry  { return Geometry{Type: TypeLineString, Points: path} }
+func NewPolygon(poly Polygon) Geometry     { return Geometry{Type: TypePolygon, Polygon: poly} }
+func NewMultiPoint(pts []Point) Geometry   { return Geometry{Type: TypeMultiPoint, Points: pts} }
+func NewMultiPolygon(p []Polygon) Geometry { return Geometry{Type: TypeMultiPolygon, Polygons: p} }
+
+// Contains reports whether a Polygon or MultiPolygon geometry contains p.
+// Other geometry types contain nothing.
+func (g Geometry) Contains(p Point) bool {
+	switch g.Type {
+	case TypePolygon:
+		return g.Polygon.Contains(p)
+	case TypeMultiPolygon:
+		for _, poly := range g.Polygons {
+			if poly.Contains(p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Bounds returns the bounding box of every position in the geometry.
+func (g Geometry) Bounds() BBox {
+	var all []Point
+	switch g.Type {
+	case TypePoint:
+		all = []Point{g.Point}
+	case TypeMultiPoint, TypeLineString:
+		all = g.Points
+	case TypeMultiLineString:
+		for _, l := range g.Lines {
+			all = append(all, l...)
+		}
+	case TypePolygon:
+		all = g.Polygon.ringPoints()
+	case TypeMultiPolygon:
+		for _, poly := range g.Polygons {
+			all = append(all, poly.ringPoints()...)
+		}
+	}
+	return Bounds(all...)
+}
+
+func (poly Polygon) ringPoints() []Point {
+	if len(poly) == 0 {
+		return nil
+	}
+	return poly[0]
+}
+
+// ParseGeometry decodes a GeoJSON geometry object.
+func ParseGeometry(raw string) (Geometry, error) {
+	fields := sdk.JSONObjectFields(raw)
+	if fields == nil {
+		return Geometry{}, ErrInvalid
+	}
+	g := Geometry{Type: sdk.JSONUnquote(fields["type"])}
+	coords := fields["coordinates"]
+	var err error
+	switch g.Type {
+	case TypePoint:
+		g.Point, err = parsePosition(coords)
+	case TypeMultiPoint, TypeLineString:
+		g.Points, err = parsePositions(coords)
+	case TypeMultiLineString:
+		g.Lines, err = parseRings(coords)
+	case TypePolygon:
+		g.Polygon, err = parseRings(coords)
+	case TypeMultiPolygon:
+		for _, item := range sdk.JSONArrayItems(coords) {
+			var poly Polygon
+			if poly, err = parseRings(item); err != nil {
+				break
+			}
+			g.Polygons = append(g.Polygons, poly)
+		}
+	default:
+		err = ErrInvalid
+	}
+	if err != nil {
+		return Geometry{}, err
+	}
+	return g, nil
+}
+
+func parsePosition(raw string) (Point, error) {
+	items := sdk.JSONArrayItems(raw)
+	if len(items) < 2 {
+		return Point{}, ErrInvalid
+	}
+	lon, err1 := strconv.ParseFloat(items[0], 64)
+	lat, err2 := strconv.ParseFloat(items[1], 64)
+	if err1 != nil || err2 != nil {
+		return Point{}, ErrInvalid
+	}
+	return Point{Lat: lat, Lon: lon}, nil
+}
+
+func parsePositions(raw string) ([]Point, error) {
+	items := sdk.JSONArrayItems(raw)
+	if items == nil {
+		return nil, ErrInvalid
+	}
+	pts := make([]Point, 0, len(items))
+	for _, item := range items {
+		p, err := parsePosition(item)
+		if err != nil {
+			return nil, err
+		}
+		pts = append(pts, p)
+	}
+	return pts, nil
+}
+
+func parseRings(raw string) ([][]Point, error) {
+	items := sdk.JSONArrayItems(raw)
+	if items == nil {
+		return nil, ErrInvalid
+	}
+	rings := make([][]Point, 0, len(items))
+	for _, item := range items {
+		ring, err := parsePositions(item)
+		if err != nil {
+			return nil, err
+		}
+		rings = append(rings, ring)
+	}
+	return rings, nil
+}
+
+// ToJSON encodes the geometry as GeoJSON.
+func (g Geometry) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"type":"`)
+	b.WriteString(g.Type)
+	b.WriteString(`","coordinates":`)
+	switch g.Type {
+	case TypePoint:
+		writePosition(&b, g.Point)
+	case TypeMultiPoint, TypeLineString:
+		writePositions(&b, g.Points)
+	case TypeMultiLineString:
+		writeRings(&b, g.Lines)
+	case TypePolygon:
+		writeRings(&b, g.Polygon)
+	case TypeMultiPolygon:
+		b.WriteByte('[')
+		for i, poly := range g.Polygons {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeRings(&b, poly)
+		}
+		b.WriteByte(']')
+	default:
+		b.WriteString("[]")
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatCoord(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+func writePosition(b *strings.Builder, p Point) {
+	b.WriteByte('[')
+	b.WriteString(formatCoord(p.Lon))
+	b.WriteByte(',')
+	b.WriteString(formatCoord(p.Lat))
+	b.WriteByte(']')
+}
+
+func writePositions(b *strings.Builder, pts []Point) {
+	b.WriteByte('[')
+	for i, p := range pts {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		writePosition(b, p)
+	}
+	b.WriteByte(']')
+}
+
+func writeRings(b *strings.Builder, rings [][]Point) {
+	b.WriteByte('[')
+	for i, r := range rings {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		writePositions(b, r)
+	}
+	b.WriteByte(']')
+}
+
+// Feature is a GeoJSON Feature. Properties hold raw JSON values; ID holds the
+// feature id as text (numeric ids are kept as their digits).
+type Feature struct {
+	ID         string
+	Geometry   *Geometry
+	Properties map[string]string
+}
+
+// Property returns a property as a string, unquoting JSON strings.
+func (f *Feature) Property(name string) (string, bool) {
+	v, ok := f.Properties[name]
+	if !ok {
+		return "", false
+	}
+	return sdk.JSONUnquote(v), true
+}
+
+// ParseFeature decodes a GeoJSON Feature. A null geometry is allowed.
+func ParseFeature(raw string) (Feature, error) {
+	fields := sdk.JSONObjectFields(raw)
+	if fields == nil || sdk.JSONUnquote(fields["type"]) != "Feature" {
+		return Feature{}, ErrInvalid
+	}
+	f := Feature{ID: sdk.JSONUnquote(fields["id"]), Properties: sdk.JSONObjectFields(fields["properties"])}
+	if g := fields["geometry"]; g != "" && g != "null" {
+		geom, err := ParseGeometry(g)
+		if err != nil {
+			return Feature{}, err
+		}
+		f.Geometry = &geom
+	}
+	return f, nil
+}
+
+// ParseFeatureCollection decodes a GeoJSON FeatureCollection.
+func ParseFeatureCollection(raw string) ([]Feature, error) {
+	fields := sdk.JSONObjectFields(raw)
+	if fields == nil || sdk.JSONUnquote(fields["type"]) != "FeatureCollection" {
+		return nil, ErrInvalid
+	}
+	items := sdk.JSONArrayItems(fields["features"])
+	features := make([]Feature, 0, len(items))
+	for _, item := range items {
+		f, err := ParseFeature(item)
+		if err != nil {
+			return nil, err
+		}
+		features = append(features, f)
+	}
+	return features, nil
+}
+
+// ToJSON encodes the feature as GeoJSON, with properties in sorted key order.
+func (f *Feature) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"type":"Feature"`)
+	if f.ID != "" {
+		b.WriteString(`,"id":`)
+		b.WriteString(sdk.JSONString(f.ID))
+	}
+	b.WriteString(`,"geometry":`)
+	if f.Geometry != nil {
+		b.WriteString(f.Geometry.ToJSON())
+	} else {
+		b.WriteString("null")
+	}
+	b.WriteString(`,"properties":`)
+	if f.Properties == nil {
+		b.WriteString("null")
+	} else {
+		keys := make([]string, 0, len(f.Properties))
+		for k := range f.Properties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(sdk.JSONString(k))
+			b.WriteByte(':')
+			b.WriteString(f.Properties[k])
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// FeatureCollectionJSON encodes features as a GeoJSON FeatureCollection.
+func FeatureCollectionJSON(features []Feature) string {
+	var b strings.Builder
+	b.WriteString(`{"type":"FeatureCollection","features":[`)
+	for i := range features {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(features[i].ToJSON())
+	}
+	b.WriteString(`]}`)
+	return b.String()
+}
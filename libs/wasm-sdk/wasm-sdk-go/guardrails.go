@@ -0,0 +1,278 @@
+package sdk
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrCodePolicy marks a run stopped by an output filter or moderation.
+const ErrCodePolicy = "policy_violation"
+
+// ModerationCategory is one policy category scored by a moderation model,
+// e.g. "hate", "self_harm" or "violence".
+type ModerationCategory struct {
+	Name    string  `json:"name"`
+	Score   float64 `json:"score"`
+	Flagged bool    `json:"flagged"`
+}
+
+// ModerationResult is the verdict of Moderate. Unavailable is set when the
+// host could not moderate the text; such results are also Flagged, so
+// callers that only check Flagged fail closed.
+type ModerationResult struct {
+	Flagged     bool                 `json:"flagged"`
+	Categories  []ModerationCategory `json:"categories"`
+	Unavailable bool                 `json:"-"`
+}
+
+// FlaggedCategories returns the names of the categories that were flagged.
+func (m *ModerationResult) FlaggedCategories() []string {
+	var names []string
+	for _, c := range m.Categories {
+		if c.Flagged {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// Moderate classifies text with a moderation model bit (raw Bit JSON), or
+// the host's default moderation model when bitJSON is "".
+func Moderate(bitJSON, text string) ModerationResult {
+	bp, bl := stringToPtr(bitJSON)
+	tp, tl := stringToPtr(text)
+	fields := jsonObjectFields(unpackString(hostModerate(bp, bl, tp, tl)))
+	if fields == nil {
+		return ModerationResult{Flagged: true, Unavailable: true}
+	}
+	res := ModerationResult{Flagged: fields["flagged"] == "true"}
+	for _, item := range jsonArrayItems(fields["categories"]) {
+		f := jsonObjectFields(item)
+		score, _ := strconv.ParseFloat(f["score"], 64)
+		res.Categories = append(res.Categories, ModerationCategory{
+			Name:    jsonUnquote(f["name"]),
+			Score:   score,
+			Flagged: f["flagged"] == "true",
+		})
+	}
+	return res
+}
+
+func (c *Context) Moderate(bitJSON, text string) ModerationResult {
+	if !c.host(PermModels) {
+		return ModerationResult{Flagged: true, Unavailable: true}
+	}
+	return Moderate(bitJSON, text)
+}
+
+// PolicyError reports an output rejected by an OutputFilter.
+type PolicyError struct {
+	Pin    string
+	Filter string
+	Reason string
+}
+
+func (e *PolicyError) Error() string {
+	return "output " + strconv.Quote(e.Pin) + " blocked by " + e.Filter + ": " + e.Reason
+}
+
+func (e *PolicyError) errorInfo() ErrorInfo {
+	return ErrorInfo{Code: ErrCodePolicy, Message: e.Error(), Pin: e.Pin}
+}
+
+// OutputFilter inspects an output before it leaves the node. It returns the
+// value to send, possibly rewritten, or an error (usually a *PolicyError) to
+// fail the run instead.
+type OutputFilter interface {
+	FilterOutput(ctx *Context, pin string, value RawValue) (RawValue, error)
+}
+
+// OutputFilterFunc adapts a function to OutputFilter.
+type OutputFilterFunc func(ctx *Context, pin string, value RawValue) (RawValue, error)
+
+func (f OutputFilterFunc) FilterOutput(ctx *Context, pin string, value RawValue) (RawValue, error) {
+	return f(ctx, pin, value)
+}
+
+// GuardOutputs wraps h so every successful run passes its outputs through
+// filters, in order, before the result reaches the host. Exec pins are not
+// filtered. A filter error replaces the result with a policy_violation
+// failure, so blocked content is never emitted.
+func GuardOutputs(h NodeHandler, filters ...OutputFilter) NodeHandler {
+	return guardedHandler{h: h, filters: filters}
+}
+
+type guardedHandler struct {
+	h       NodeHandler
+	filters []OutputFilter
+}
+
+func (g guardedHandler) Define() NodeDefinition { return g.h.Define() }
+
+func (g guardedHandler) Run(ctx *Context) ExecutionResult {
+	res := g.h.Run(ctx)
+	if res.Error != nil || res.Pending {
+		return res
+	}
+	names := make([]string, 0, len(res.Outputs))
+	for name := range res.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := NewRawValue(res.Outputs[name])
+		for _, f := range g.filters {
+			var err error
+			if value, err = f.FilterOutput(ctx, name, value); err != nil {
+				info := errorInfoFor(err)
+				failed := FailResult(info.Message)
+				failed.ErrorInfo = &info
+				return failed
+			}
+		}
+		res.Outputs[name] = value.Raw()
+	}
+	return res
+}
+
+// appliesTo reports whether a filter limited to pins covers pin.
+func appliesTo(pins []string, pin string) bool {
+	if len(pins) == 0 {
+		return true
+	}
+	for _, p := range pins {
+		if p == pin {
+			return true
+		}
+	}
+	return false
+}
+
+// Blocklist rejects string outputs containing any of Terms as a whole word,
+// ignoring case. With Replacement set, matches are replaced instead.
+type Blocklist struct {
+	Terms       []string
+	Replacement string
+	Pins        []string // empty filters every output
+}
+
+func (b Blocklist) FilterOutput(_ *Context, pin string, value RawValue) (RawValue, error) {
+	if !appliesTo(b.Pins, pin) || value.Kind() != KindString {
+		return value, nil
+	}
+	text := value.String()
+	lower := asciiLower(text)
+	changed := false
+	for _, term := range b.Terms {
+		t := asciiLower(term)
+		if t == "" {
+			continue
+		}
+		for from := 0; ; {
+			i := strings.Index(lower[from:], t)
+			if i < 0 {
+				break
+			}
+			start, end := from+i, from+i+len(t)
+			if !wordBoundary(lower, start, end) {
+				from = start + 1
+				continue
+			}
+			if b.Replacement == "" {
+				return value, &PolicyError{Pin: pin, Filter: "blocklist", Reason: "contains a blocked term"}
+			}
+			text = text[:start] + b.Replacement + text[end:]
+			lower = lower[:start] + asciiLower(b.Replacement) + lower[end:]
+			from = start + len(b.Replacement)
+			changed = true
+		}
+	}
+	if !changed {
+		return value, nil
+	}
+	return NewRawValue(jsonString(text)), nil
+}
+
+// asciiLower lower-cases ASCII letters only, so byte offsets stay aligned
+// with the original text.
+func asciiLower(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+func wordBoundary(s string, start, end int) bool {
+	isWord := func(c byte) bool {
+		return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 0x80
+	}
+	return (start == 0 || !isWord(s[start-1])) && (end == len(s) || !isWord(s[end]))
+}
+
+// MaxLength limits string outputs to Max characters. Longer values fail the
+// run, or are cut at a character boundary with Truncate set.
+type MaxLength struct {
+	Max      int
+	Truncate bool
+	Pins     []string
+}
+
+func (m MaxLength) FilterOutput(_ *Context, pin string, value RawValue) (RawValue, error) {
+	if !appliesTo(m.Pins, pin) || value.Kind() != KindString {
+		return value, nil
+	}
+	runes := []rune(value.String())
+	if len(runes) <= m.Max {
+		return value, nil
+	}
+	if !m.Truncate {
+		return value, &PolicyError{Pin: pin, Filter: "max length", Reason: strconv.Itoa(len(runes)) + " characters, at most " + strconv.Itoa(m.Max) + " allowed"}
+	}
+	return NewRawValue(jsonString(string(runes[:m.Max]))), nil
+}
+
+// SchemaCheck fails the run when an output does not match Schema.
+type SchemaCheck struct {
+	Schema string
+	Pins   []string
+}
+
+func (s SchemaCheck) FilterOutput(_ *Context, pin string, value RawValue) (RawValue, error) {
+	if !appliesTo(s.Pins, pin) {
+		return value, nil
+	}
+	if v := ValidateSchema(s.Schema, value); len(v) > 0 {
+		parts := make([]string, len(v))
+		for i := range v {
+			parts[i] = v[i].String()
+		}
+		return value, &PolicyError{Pin: pin, Filter: "schema", Reason: strings.Join(parts, "; ")}
+	}
+	return value, nil
+}
+
+// ModerationCheck fails the run when a moderation model flags a string
+// output. Bit "" uses the host's default moderation model.
+type ModerationCheck struct {
+	Bit  string
+	Pins []string
+}
+
+func (m ModerationCheck) FilterOutput(ctx *Context, pin string, value RawValue) (RawValue, error) {
+	if !appliesTo(m.Pins, pin) || value.Kind() != KindString {
+		return value, nil
+	}
+	res := ctx.Moderate(m.Bit, value.String())
+	if !res.Flagged {
+		return value, nil
+	}
+	reason := "flagged for " + strings.Join(res.FlaggedCategories(), ", ")
+	if res.Unavailable {
+		reason = "moderation unavailable"
+	}
+	return value, &PolicyError{Pin: pin, Filter: "moderation", Reason: reason}
+}
@@ -1,5 +1,10 @@
 package sdk
 
+import (
+	"strconv"
+	"strings"
+)
+
 // ============================================================================
 // Host Imports — flowlike_log
 // ============================================================================
@@ -98,6 +103,24 @@ func hostTimeNow() int64
 //go:wasmimport flowlike_meta random
 func hostRandom() int64
 
+//go:wasmimport flowlike_meta last_error
+func hostLastError() int64
+
+//go:wasmimport flowlike_meta execution_deadline
+func hostExecutionDeadline() int64
+
+//go:wasmimport flowlike_meta is_cancelled
+func hostIsCancelled() int32
+
+//go:wasmimport flowlike_meta set_call_deadline
+func hostSetCallDeadline(deadline int64)
+
+//go:wasmimport flowlike_meta get_pack_config
+func hostGetPackConfig() int64
+
+//go:wasmimport flowlike_meta get_node_config
+func hostGetNodeConfig() int64
+
 // ============================================================================
 // Host Imports — flowlike_storage
 // ============================================================================
@@ -108,6 +131,33 @@ func hostStorageRead(pathPtr uint32, pathLen uint32) int64
 //go:wasmimport flowlike_storage write_request
 func hostStorageWrite(pathPtr uint32, pathLen uint32, dataPtr uint32, dataLen uint32) int32
 
+//go:wasmimport flowlike_storage write_with_policy
+func hostStorageWriteWithPolicy(pathPtr uint32, pathLen uint32, dataPtr uint32, dataLen uint32, policyPtr uint32, policyLen uint32) int32
+
+//go:wasmimport flowlike_storage append_request
+func hostStorageAppend(pathPtr uint32, pathLen uint32, dataPtr uint32, dataLen uint32) int32
+
+//go:wasmimport flowlike_storage read_range
+func hostStorageReadRange(pathPtr uint32, pathLen uint32, offset int64, length int64) int64
+
+//go:wasmimport flowlike_storage stat
+func hostStorageStat(pathPtr uint32, pathLen uint32) int64
+
+//go:wasmimport flowlike_storage write_if
+func hostStorageWriteIf(pathPtr uint32, pathLen uint32, dataPtr uint32, dataLen uint32, etagPtr uint32, etagLen uint32) int64
+
+//go:wasmimport flowlike_storage write_verified
+func hostStorageWriteVerified(pathPtr uint32, pathLen uint32, dataPtr uint32, dataLen uint32) int64
+
+//go:wasmimport flowlike_storage hash
+func hostStorageHash(pathPtr uint32, pathLen uint32) int64
+
+//go:wasmimport flowlike_storage watch
+func hostStorageWatch(prefixPtr uint32, prefixLen uint32, kindsPtr uint32, kindsLen uint32) int64
+
+//go:wasmimport flowlike_storage unwatch
+func hostStorageUnwatch(idPtr uint32, idLen uint32) int32
+
 //go:wasmimport flowlike_storage storage_dir
 func hostStorageDir(nodeScoped int32) int64
 
@@ -123,6 +173,27 @@ func hostUserDir(nodeScoped int32) int64
 //go:wasmimport flowlike_storage list_request
 func hostStorageList(pathPtr uint32, pathLen uint32) int64
 
+//go:wasmimport flowlike_storage open_reader
+func hostStorageOpenReader(pathPtr uint32, pathLen uint32) int32
+
+//go:wasmimport flowlike_storage read_chunk
+func hostStorageReadChunk(handle int32, maxLen int32) int64
+
+//go:wasmimport flowlike_storage close_reader
+func hostStorageCloseReader(handle int32)
+
+//go:wasmimport flowlike_storage open_writer
+func hostStorageOpenWriter(pathPtr uint32, pathLen uint32) int32
+
+//go:wasmimport flowlike_storage write_chunk
+func hostStorageWriteChunk(handle int32, dataPtr uint32, dataLen uint32) int32
+
+//go:wasmimport flowlike_storage close_writer
+func hostStorageCloseWriter(handle int32) int32
+
+//go:wasmimport flowlike_storage abort_writer
+func hostStorageAbortWriter(handle int32)
+
 // ============================================================================
 // Host Imports — flowlike_models
 // ============================================================================
@@ -130,6 +201,37 @@ func hostStorageList(pathPtr uint32, pathLen uint32) int64
 //go:wasmimport flowlike_models embed_text
 func hostEmbedText(bitPtr uint32, bitLen uint32, textsPtr uint32, textsLen uint32) int64
 
+//go:wasmimport flowlike_models chat_complete
+func hostChatComplete(bitPtr uint32, bitLen uint32, reqPtr uint32, reqLen uint32) int64
+
+//go:wasmimport flowlike_models chat_complete_json
+func hostChatCompleteJSON(bitPtr uint32, bitLen uint32, reqPtr uint32, reqLen uint32, schemaPtr uint32, schemaLen uint32) int64
+
+//go:wasmimport flowlike_models detect_entities
+func hostDetectEntities(bitPtr uint32, bitLen uint32, textPtr uint32, textLen uint32) int64
+
+//go:wasmimport flowlike_models rerank
+func hostRerank(bitPtr uint32, bitLen uint32, queryPtr uint32, queryLen uint32, docsPtr uint32, docsLen uint32) int64
+
+//go:wasmimport flowlike_models list_bits
+func hostListModelBits(capPtr uint32, capLen uint32) int64
+
+//go:wasmimport flowlike_models default_bit
+func hostDefaultBit(capPtr uint32, capLen uint32) int64
+
+//go:wasmimport flowlike_models moderate
+func hostModerate(bitPtr uint32, bitLen uint32, textPtr uint32, textLen uint32) int64
+
+// ============================================================================
+// Host Imports — flowlike_vector
+// ============================================================================
+
+//go:wasmimport flowlike_vector upsert
+func hostVectorUpsert(collPtr uint32, collLen uint32, recordsPtr uint32, recordsLen uint32) int32
+
+//go:wasmimport flowlike_vector search
+func hostVectorSearch(collPtr uint32, collLen uint32, vecPtr uint32, vecLen uint32, limit int32) int64
+
 // ============================================================================
 // Host Imports — flowlike_http
 // ============================================================================
@@ -137,6 +239,12 @@ func hostEmbedText(bitPtr uint32, bitLen uint32, textsPtr uint32, textsLen uint3
 //go:wasmimport flowlike_http request
 func hostHTTPRequest(method int32, urlPtr uint32, urlLen uint32, headersPtr uint32, headersLen uint32, bodyPtr uint32, bodyLen uint32) int32
 
+//go:wasmimport flowlike_http fetch
+func hostHTTPFetch(method int32, urlPtr uint32, urlLen uint32, headersPtr uint32, headersLen uint32, bodyPtr uint32, bodyLen uint32) int64
+
+//go:wasmimport flowlike_http fetch_with
+func hostHTTPFetchWith(method int32, urlPtr uint32, urlLen uint32, headersPtr uint32, headersLen uint32, bodyPtr uint32, bodyLen uint32, optsPtr uint32, optsLen uint32) int64
+
 // ============================================================================
 // Host Imports — flowlike_stream
 // ============================================================================
@@ -157,6 +265,231 @@ func hostGetOAuthToken(providerPtr uint32, providerLen uint32) int64
 //go:wasmimport flowlike_auth has_oauth_token
 func hostHasOAuthToken(providerPtr uint32, providerLen uint32) int32
 
+//go:wasmimport flowlike_auth refresh_oauth_token
+func hostRefreshOAuthToken(providerPtr uint32, providerLen uint32) int64
+
+//go:wasmimport flowlike_auth has_role
+func hostHasRole(rolePtr uint32, roleLen uint32) int32
+
+//go:wasmimport flowlike_auth can
+func hostCan(actionPtr uint32, actionLen uint32, resourcePtr uint32, resourceLen uint32) int32
+
+// ============================================================================
+// Host Imports — flowlike_counter
+// ============================================================================
+
+//go:wasmimport flowlike_counter incr
+func hostCounterIncr(namePtr uint32, nameLen uint32, delta int64) int64
+
+//go:wasmimport flowlike_counter quota_consume
+func hostQuotaConsume(namePtr uint32, nameLen uint32, amount int64) int32
+
+//go:wasmimport flowlike_counter quota_status
+func hostQuotaStatus(namePtr uint32, nameLen uint32) int64
+
+// ============================================================================
+// Host Imports — flowlike_notify
+// ============================================================================
+
+//go:wasmimport flowlike_notify send
+func hostNotify(levelPtr uint32, levelLen uint32, titlePtr uint32, titleLen uint32, msgPtr uint32, msgLen uint32, actionsPtr uint32, actionsLen uint32) int32
+
+// ============================================================================
+// Host Imports — flowlike_desktop
+// ============================================================================
+
+//go:wasmimport flowlike_desktop is_available
+func hostDesktopAvailable() int32
+
+//go:wasmimport flowlike_desktop clipboard_read
+func hostClipboardRead() int64
+
+//go:wasmimport flowlike_desktop clipboard_write
+func hostClipboardWrite(textPtr uint32, textLen uint32) int32
+
+//go:wasmimport flowlike_desktop open_file
+func hostOpenFile(pathPtr uint32, pathLen uint32) int32
+
+// ============================================================================
+// Host Imports — flowlike_exec
+// ============================================================================
+
+//go:wasmimport flowlike_exec run
+func hostExec(cmdPtr uint32, cmdLen uint32, argsPtr uint32, argsLen uint32, stdinPtr uint32, stdinLen uint32) int64
+
+// ============================================================================
+// Host Imports — flowlike_time
+// ============================================================================
+
+//go:wasmimport flowlike_time tz_data
+func hostTZData(namePtr uint32, nameLen uint32) int64
+
+// ============================================================================
+// Host Imports — flowlike_metrics
+// ============================================================================
+
+//go:wasmimport flowlike_metrics report_cost
+func hostReportCost(units float64, kindPtr uint32, kindLen uint32) int32
+
+//go:wasmimport flowlike_metrics track_event
+func hostTrackEvent(namePtr uint32, nameLen uint32, propsPtr uint32, propsLen uint32) int32
+
+// ============================================================================
+// Host Imports — flowlike_compress
+// ============================================================================
+
+//go:wasmimport flowlike_compress open
+func hostCodecOpen(codecPtr uint32, codecLen uint32, decompress int32, level int32) int32
+
+//go:wasmimport flowlike_compress update
+func hostCodecUpdate(handle int32, dataPtr uint32, dataLen uint32) int64
+
+//go:wasmimport flowlike_compress finish
+func hostCodecFinish(handle int32) int64
+
+// ============================================================================
+// Host Imports — flowlike_transfer
+// ============================================================================
+
+//go:wasmimport flowlike_transfer list
+func hostTransferList(connPtr uint32, connLen uint32, dirPtr uint32, dirLen uint32) int64
+
+//go:wasmimport flowlike_transfer get
+func hostTransferGet(connPtr uint32, connLen uint32, remotePtr uint32, remoteLen uint32, localPtr uint32, localLen uint32) int32
+
+//go:wasmimport flowlike_transfer put
+func hostTransferPut(connPtr uint32, connLen uint32, localPtr uint32, localLen uint32, remotePtr uint32, remoteLen uint32) int32
+
+// ============================================================================
+// Host Imports — flowlike_bucket
+// ============================================================================
+
+//go:wasmimport flowlike_bucket list
+func hostBucketList(bucketPtr uint32, bucketLen uint32, prefixPtr uint32, prefixLen uint32) int64
+
+//go:wasmimport flowlike_bucket get
+func hostBucketGet(bucketPtr uint32, bucketLen uint32, keyPtr uint32, keyLen uint32, localPtr uint32, localLen uint32) int32
+
+//go:wasmimport flowlike_bucket put
+func hostBucketPut(bucketPtr uint32, bucketLen uint32, localPtr uint32, localLen uint32, keyPtr uint32, keyLen uint32) int32
+
+//go:wasmimport flowlike_bucket delete
+func hostBucketDelete(bucketPtr uint32, bucketLen uint32, keyPtr uint32, keyLen uint32) int32
+
+// ============================================================================
+// Host Imports — flowlike_mail
+// ============================================================================
+
+//go:wasmimport flowlike_mail list
+func hostMailList(mailboxPtr uint32, mailboxLen uint32, queryPtr uint32, queryLen uint32) int64
+
+//go:wasmimport flowlike_mail fetch
+func hostMailFetch(mailboxPtr uint32, mailboxLen uint32, idPtr uint32, idLen uint32) int64
+
+//go:wasmimport flowlike_mail save_attachment
+func hostMailSaveAttachment(mailboxPtr uint32, mailboxLen uint32, idPtr uint32, idLen uint32, attPtr uint32, attLen uint32, pathPtr uint32, pathLen uint32) int32
+
+// ============================================================================
+// Host Imports — flowlike_calendar / flowlike_contacts
+// ============================================================================
+
+//go:wasmimport flowlike_calendar list_events
+func hostCalendarListEvents(providerPtr uint32, providerLen uint32, queryPtr uint32, queryLen uint32) int64
+
+//go:wasmimport flowlike_calendar create_event
+func hostCalendarCreateEvent(providerPtr uint32, providerLen uint32, eventPtr uint32, eventLen uint32) int64
+
+//go:wasmimport flowlike_contacts search
+func hostContactsSearch(providerPtr uint32, providerLen uint32, queryPtr uint32, queryLen uint32, limit int32) int64
+
+// ============================================================================
+// Host Imports — flowlike_queue
+// ============================================================================
+
+//go:wasmimport flowlike_queue publish
+func hostQueuePublish(queuePtr uint32, queueLen uint32, bodyPtr uint32, bodyLen uint32, attrsPtr uint32, attrsLen uint32) int64
+
+//go:wasmimport flowlike_queue poll
+func hostQueuePoll(queuePtr uint32, queueLen uint32, max int32, visibilityMs int64) int64
+
+//go:wasmimport flowlike_queue ack
+func hostQueueAck(queuePtr uint32, queueLen uint32, receiptPtr uint32, receiptLen uint32) int32
+
+//go:wasmimport flowlike_queue extend
+func hostQueueExtend(queuePtr uint32, queueLen uint32, receiptPtr uint32, receiptLen uint32, visibilityMs int64) int32
+
+// ============================================================================
+// Host Imports — flowlike_kv
+// ============================================================================
+
+//go:wasmimport flowlike_kv get
+func hostKVGet(scopePtr uint32, scopeLen uint32, keyPtr uint32, keyLen uint32) int64
+
+//go:wasmimport flowlike_kv put
+func hostKVPut(scopePtr uint32, scopeLen uint32, keyPtr uint32, keyLen uint32, valPtr uint32, valLen uint32, ifVersion int64) int64
+
+//go:wasmimport flowlike_kv delete
+func hostKVDelete(scopePtr uint32, scopeLen uint32, keyPtr uint32, keyLen uint32) int32
+
+//go:wasmimport flowlike_kv list
+func hostKVList(scopePtr uint32, scopeLen uint32, prefixPtr uint32, prefixLen uint32, limit int32, cursorPtr uint32, cursorLen uint32) int64
+
+// ============================================================================
+// Host Imports — flowlike_search
+// ============================================================================
+
+//go:wasmimport flowlike_search index
+func hostSearchIndex(collPtr uint32, collLen uint32, docPtr uint32, docLen uint32) int32
+
+//go:wasmimport flowlike_search delete
+func hostSearchDelete(collPtr uint32, collLen uint32, idPtr uint32, idLen uint32) int32
+
+//go:wasmimport flowlike_search query
+func hostSearchQuery(collPtr uint32, collLen uint32, queryPtr uint32, queryLen uint32, optsPtr uint32, optsLen uint32) int64
+
+// ============================================================================
+// Host Imports — flowlike_sql
+// ============================================================================
+
+//go:wasmimport flowlike_sql query
+func hostSQLQuery(queryPtr uint32, queryLen uint32) int64
+
+//go:wasmimport flowlike_sql next
+func hostSQLNext(cursorPtr uint32, cursorLen uint32) int64
+
+//go:wasmimport flowlike_sql close
+func hostSQLClose(cursorPtr uint32, cursorLen uint32) int32
+
+// ============================================================================
+// Host Imports — flowlike_interaction
+// ============================================================================
+
+//go:wasmimport flowlike_interaction request
+func hostInteractionRequest(promptPtr uint32, promptLen uint32, schemaPtr uint32, schemaLen uint32) int64
+
+//go:wasmimport flowlike_interaction request_with
+func hostInteractionRequestWith(promptPtr uint32, promptLen uint32, schemaPtr uint32, schemaLen uint32, optsPtr uint32, optsLen uint32) int64
+
+//go:wasmimport flowlike_interaction response
+func hostInteractionResponse(idPtr uint32, idLen uint32) int64
+
+// ============================================================================
+// Host Imports — flowlike_app
+// ============================================================================
+
+//go:wasmimport flowlike_app info
+func hostAppInfo() int64
+
+//go:wasmimport flowlike_app members
+func hostAppMembers(rolePtr uint32, roleLen uint32) int64
+
+// ============================================================================
+// Host Imports — flowlike_audit
+// ============================================================================
+
+//go:wasmimport flowlike_audit record
+func hostAuditRecord(actionPtr uint32, actionLen uint32, targetPtr uint32, targetLen uint32, detailPtr uint32, detailLen uint32) int64
+
 // ============================================================================
 // Go wrapper functions
 // ============================================================================
@@ -261,6 +594,27 @@ func GetLogLevel() int     { return int(hostGetLogLevel()) }
 func TimeNow() int64       { return hostTimeNow() }
 func Random() int64         { return hostRandom() }
 
+// ExecutionDeadline returns when the run's execution budget ends on the
+// TimeNow clock (Unix milliseconds), or 0 when the run has no budget.
+func ExecutionDeadline() int64 { return hostExecutionDeadline() }
+
+// IsCancelled reports whether the run was cancelled, e.g. because the user
+// stopped the board.
+func IsCancelled() bool { return hostIsCancelled() != 0 }
+
+// SetCallDeadline makes the host abort host calls still running at deadline
+// (Unix milliseconds) with a deadline_exceeded error; 0 removes it. The
+// execution budget bounds every call regardless.
+func SetCallDeadline(deadline int64) { hostSetCallDeadline(deadline) }
+
+// GetPackConfig returns the pack settings an admin saved, as a JSON object,
+// or "" when the pack has not been configured.
+func GetPackConfig() string { return unpackString(hostGetPackConfig()) }
+
+// GetNodeConfig returns the configuration of the running node instance as a
+// JSON object with credential references resolved, or "" when it has none.
+func GetNodeConfig() string { return unpackString(hostGetNodeConfig()) }
+
 func StorageRead(path string) string {
 	p, l := stringToPtr(path)
 	return unpackString(hostStorageRead(p, l))
@@ -272,6 +626,32 @@ func StorageWrite(path string, data string) bool {
 	return hostStorageWrite(pp, pl, dp, dl) != 0
 }
 
+// StorageWriteWithPolicy writes data like StorageWrite and attaches a
+// retention policy the platform enforces on the file.
+func StorageWriteWithPolicy(path, data string, policy RetentionPolicy) bool {
+	if policy.IsZero() {
+		return StorageWrite(path, data)
+	}
+	pp, pl := stringToPtr(path)
+	dp, dl := stringToPtr(data)
+	rp, rl := stringToPtr(policy.ToJSON())
+	return hostStorageWriteWithPolicy(pp, pl, dp, dl, rp, rl) != 0
+}
+
+// StorageAppend appends data to the file at path, creating it if needed.
+func StorageAppend(path, data string) bool {
+	pp, pl := stringToPtr(path)
+	dp, dl := stringToPtr(data)
+	return hostStorageAppend(pp, pl, dp, dl) != 0
+}
+
+// StorageReadRange returns up to length bytes of the file at path starting
+// at offset; fewer near the end of the file and none past it.
+func StorageReadRange(path string, offset, length int64) string {
+	p, l := stringToPtr(path)
+	return unpackString(hostStorageReadRange(p, l, offset, length))
+}
+
 func StorageDir(nodeScoped bool) string {
 	v := int32(0)
 	if nodeScoped {
@@ -312,6 +692,80 @@ func EmbedText(bitJSON, textsJSON string) string {
 	return unpackString(hostEmbedText(bp, bl, tp, tl))
 }
 
+// ChatComplete runs a chat completion against the given model bit (raw Bit JSON).
+func ChatComplete(bitJSON string, req ChatRequest) (ChatResponse, bool) {
+	bp, bl := stringToPtr(bitJSON)
+	rp, rl := stringToPtr(req.ToJSON())
+	return parseChatResponseJSON(unpackString(hostChatComplete(bp, bl, rp, rl)))
+}
+
+// DetectEntities runs named-entity recognition over text with the given model
+// bit (raw Bit JSON), or the host's default NER model when bitJSON is "".
+func DetectEntities(bitJSON, text string) []Entity {
+	bp, bl := stringToPtr(bitJSON)
+	tp, tl := stringToPtr(text)
+	return parseEntitiesJSON(unpackString(hostDetectEntities(bp, bl, tp, tl)))
+}
+
+// Rerank scores candidates by relevance to query with a reranking model bit
+// (raw Bit JSON), or the host's default reranker when bitJSON is "". Results
+// are sorted best first; Index refers back into candidates.
+func Rerank(bitJSON, query string, candidates []string) []ScoredResult {
+	if len(candidates) == 0 {
+		return nil
+	}
+	bp, bl := stringToPtr(bitJSON)
+	qp, ql := stringToPtr(query)
+	dp, dl := stringToPtr(jsonStringArray(candidates))
+	return parseScoredResultsJSON(unpackString(hostRerank(bp, bl, qp, ql, dp, dl)), candidates)
+}
+
+// ListModelBits returns the model bits available to the current app that
+// have capability (one of the Capability constants; "" lists all).
+func ListModelBits(capability string) []ModelBit {
+	p, l := stringToPtr(capability)
+	items := jsonArrayItems(unpackString(hostListModelBits(p, l)))
+	bits := make([]ModelBit, 0, len(items))
+	for _, item := range items {
+		if b, ok := parseModelBitJSON(item); ok {
+			bits = append(bits, b)
+		}
+	}
+	return bits
+}
+
+// DefaultBit returns the bit the deployment prefers for capability. ok is
+// false when no model with that capability is available.
+func DefaultBit(capability string) (ModelBit, bool) {
+	p, l := stringToPtr(capability)
+	return parseModelBitJSON(unpackString(hostDefaultBit(p, l)))
+}
+
+// VectorUpsert inserts or replaces records in a vector collection of the
+// current app. Requires the "vector" permission.
+func VectorUpsert(collection string, records []VectorRecord) bool {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := range records {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(records[i].ToJSON())
+	}
+	b.WriteByte(']')
+	cp, cl := stringToPtr(collection)
+	rp, rl := stringToPtr(b.String())
+	return hostVectorUpsert(cp, cl, rp, rl) != 0
+}
+
+// VectorSearch returns the records nearest to vectorJSON (a raw JSON array of
+// numbers), at most limit of them. Requires the "vector" permission.
+func VectorSearch(collection, vectorJSON string, limit int) []VectorMatch {
+	cp, cl := stringToPtr(collection)
+	vp, vl := stringToPtr(vectorJSON)
+	return parseVectorMatchesJSON(unpackString(hostVectorSearch(cp, cl, vp, vl, int32(limit))))
+}
+
 func HTTPRequest(method int, url, headers, body string) bool {
 	up, ul := stringToPtr(url)
 	hp, hl := stringToPtr(headers)
@@ -319,6 +773,40 @@ func HTTPRequest(method int, url, headers, body string) bool {
 	return hostHTTPRequest(int32(method), up, ul, hp, hl, bp, bl) != 0
 }
 
+// HTTPFetch performs a request and waits for the response. ok is false when the
+// request could not be sent (for example, the "http" permission is missing);
+// HTTP error statuses are returned with ok set to true. Compressed bodies
+// (Content-Encoding gzip, deflate, br, zstd) are decompressed and text in
+// Latin-1, Windows-1252 or UTF-16 is converted to UTF-8.
+func HTTPFetch(method int, url, headers, body string) (HTTPResponse, bool) {
+	up, ul := stringToPtr(url)
+	hp, hl := stringToPtr(headers)
+	bp, bl := stringToPtr(body)
+	resp, ok := parseHTTPResponseJSON(unpackString(hostHTTPFetch(int32(method), up, ul, hp, hl, bp, bl)))
+	if ok {
+		resp.decodeBody()
+	}
+	return resp, ok
+}
+
+// HTTPFetchWith is HTTPFetch with per-request proxy, TLS and timeout options.
+// Zero options use the plain fetch binding, so such calls also work on hosts
+// without fetch_with.
+func HTTPFetchWith(method int, url, headers, body string, opts HTTPRequestOptions) (HTTPResponse, bool) {
+	if opts.IsZero() {
+		return HTTPFetch(method, url, headers, body)
+	}
+	up, ul := stringToPtr(url)
+	hp, hl := stringToPtr(headers)
+	bp, bl := stringToPtr(body)
+	op, ol := stringToPtr(opts.ToJSON())
+	resp, ok := parseHTTPResponseJSON(unpackString(hostHTTPFetchWith(int32(method), up, ul, hp, hl, bp, bl, op, ol)))
+	if ok {
+		resp.decodeBody()
+	}
+	return resp, ok
+}
+
 func StreamEmit(eventType, data string) {
 	ep, el := stringToPtr(eventType)
 	dp, dl := stringToPtr(data)
@@ -339,3 +827,249 @@ func HasOAuthToken(provider string) bool {
 	p, l := stringToPtr(provider)
 	return hostHasOAuthToken(p, l) != 0
 }
+
+// CounterIncr atomically adds delta to the named host counter and returns the
+// new value. Counters are shared across runs and users of the same app, so use
+// a delta of 0 to read the current value without changing it.
+func CounterIncr(name string, delta int64) int64 {
+	p, l := stringToPtr(name)
+	return hostCounterIncr(p, l, delta)
+}
+
+// QuotaConsume atomically deducts amount from the named host quota. It returns
+// false (and consumes nothing) when the remaining budget is smaller than amount.
+func QuotaConsume(name string, amount int64) bool {
+	p, l := stringToPtr(name)
+	return hostQuotaConsume(p, l, amount) != 0
+}
+
+// LastHostError returns the reason the host gave for the failure of its most
+// recent call, as a HostError for op, or nil if that call succeeded or the
+// host gave no reason. Call it right after the failed call: any other host
+// call, logging included, replaces the recorded failure.
+func LastHostError(op string) error {
+	packed := hostLastError()
+	if packed == 0 || packed == -1 {
+		return nil
+	}
+	f := jsonObjectFields(unpackString(packed))
+	code := jsonUnquote(f["code"])
+	if code == "" {
+		return nil
+	}
+	return &HostError{Op: op, Code: code, Message: jsonUnquote(f["message"])}
+}
+
+// GetQuota returns the state of a platform quota (QuotaModelTokens, ...) or
+// of a named quota used with QuotaConsume.
+func GetQuota(kind string) (Quota, bool) {
+	p, l := stringToPtr(kind)
+	packed := hostQuotaStatus(p, l)
+	if packed == -1 {
+		return Quota{}, false
+	}
+	f := jsonObjectFields(unpackString(packed))
+	if f == nil {
+		return Quota{}, false
+	}
+	q := Quota{Kind: kind, Unlimited: f["unlimited"] == "true"}
+	q.Limit, _ = strconv.ParseInt(f["limit"], 10, 64)
+	q.Used, _ = strconv.ParseInt(f["used"], 10, 64)
+	q.Remaining, _ = strconv.ParseInt(f["remaining"], 10, 64)
+	q.ResetsAt, _ = strconv.ParseInt(f["resets_at"], 10, 64)
+	return q, true
+}
+
+// Notify sends a persistent notification to the user's notification center.
+// Requires the "notifications" permission; returns false if it was rejected.
+func Notify(level, title, message string, actions []NotificationAction) bool {
+	lp, ll := stringToPtr(level)
+	tp, tl := stringToPtr(title)
+	mp, ml := stringToPtr(message)
+	ap, al := stringToPtr(notificationActionsJSON(actions))
+	return hostNotify(lp, ll, tp, tl, mp, ml, ap, al) != 0
+}
+
+// DesktopAvailable reports whether the node runs inside the desktop app, where
+// the clipboard and file bridge bindings are backed by the local environment.
+func DesktopAvailable() bool { return hostDesktopAvailable() != 0 }
+
+// ClipboardRead returns the current clipboard text. Requires the "clipboard"
+// permission and returns "" outside the desktop app.
+func ClipboardRead() string { return unpackString(hostClipboardRead()) }
+
+// ClipboardWrite replaces the clipboard text. Requires the "clipboard" permission.
+func ClipboardWrite(text string) bool {
+	p, l := stringToPtr(text)
+	return hostClipboardWrite(p, l) != 0
+}
+
+// OpenFile asks the desktop app to open a flow storage file (FlowPath JSON)
+// with the user's default application. Requires the "open_file" permission.
+func OpenFile(flowPathJSON string) bool {
+	p, l := stringToPtr(flowPathJSON)
+	return hostOpenFile(p, l) != 0
+}
+
+// Exec runs a local command and captures its output. The binding is only
+// provided on self-hosted and desktop profiles and requires the "exec"
+// permission; ok is false when the host refused or could not start the process.
+// A non-zero exit code is still reported with ok set to true.
+func Exec(cmd string, args []string, stdin string) (ExecOutput, bool) {
+	cp, cl := stringToPtr(cmd)
+	ap, al := stringToPtr(jsonStringArray(args))
+	sp, sl := stringToPtr(stdin)
+	return parseExecOutputJSON(unpackString(hostExec(cp, cl, ap, al, sp, sl)))
+}
+
+// RefreshOAuthToken forces the host to refresh the provider's access token and
+// returns the new token, or "" if the refresh failed and the user must re-consent.
+func RefreshOAuthToken(provider string) string {
+	p, l := stringToPtr(provider)
+	return unpackString(hostRefreshOAuthToken(p, l))
+}
+
+// HasRole reports whether the user of the run holds role in the app.
+func HasRole(role string) bool {
+	p, l := stringToPtr(role)
+	return hostHasRole(p, l) != 0
+}
+
+// Can reports whether the user of the run may perform action on resource
+// under the app's permission model, e.g. Can("delete", "storage/exports").
+// An empty resource checks the action app-wide.
+func Can(action, resource string) bool {
+	ap, al := stringToPtr(action)
+	rp, rl := stringToPtr(resource)
+	return hostCan(ap, al, rp, rl) != 0
+}
+
+// ReportCost records billable consumption (e.g. 1200 "llm_tokens", 1 "sms")
+// against the current run so it shows up in the run's cost summary. It
+// returns false if the host rejected the report.
+func ReportCost(units float64, kind string) bool {
+	p, l := stringToPtr(kind)
+	return hostReportCost(units, p, l) != 0
+}
+
+// TrackEvent sends a usage analytics event for the node pack. The host applies
+// the user's consent and privacy settings and may drop the event, in which case
+// it returns false. Keep properties free of user data.
+func TrackEvent(name string, properties map[string]string) bool {
+	np, nl := stringToPtr(name)
+	pp, pl := stringToPtr(jsonStringMap(properties))
+	return hostTrackEvent(np, nl, pp, pl) != 0
+}
+
+// TimezoneData returns the TZif database entry for an IANA zone name such as
+// "Europe/Berlin", or "" if the host does not know the zone. The sandbox has
+// no zoneinfo files, so time.LoadLocation cannot be used; see package timex.
+func TimezoneData(name string) string {
+	p, l := stringToPtr(name)
+	return unpackString(hostTZData(p, l))
+}
+
+// OpenCodecStream starts a host-side (de)compression stream for codecs the
+// module cannot afford to compile in, such as "zstd" or "br". level 0 picks
+// the codec default. ok is false if the host does not support the codec.
+// See package compress for io.Reader/io.Writer adapters.
+func OpenCodecStream(codec string, decompress bool, level int) (handle int32, ok bool) {
+	p, l := stringToPtr(codec)
+	d := int32(0)
+	if decompress {
+		d = 1
+	}
+	h := hostCodecOpen(p, l, d, int32(level))
+	return h, h >= 0
+}
+
+// CodecStreamUpdate feeds data into a codec stream and returns the output
+// produced so far, which may be empty. ok is false if the host rejected the
+// data, e.g. corrupt compressed input; the stream is then released.
+func CodecStreamUpdate(handle int32, data []byte) (out []byte, ok bool) {
+	if len(data) == 0 {
+		return nil, true
+	}
+	packed := hostCodecUpdate(handle, bytesPtr(data), uint32(len(data)))
+	if packed == -1 {
+		return nil, false
+	}
+	return []byte(unpackString(packed)), true
+}
+
+// CodecStreamFinish flushes and releases a codec stream, returning the
+// remaining output. ok is false if the stream ended in an invalid state,
+// e.g. truncated compressed input.
+func CodecStreamFinish(handle int32) (out []byte, ok bool) {
+	packed := hostCodecFinish(handle)
+	if packed == -1 {
+		return nil, false
+	}
+	return []byte(unpackString(packed)), true
+}
+
+// TransferList lists a directory on an SFTP or FTPS server. ok is false when
+// the connection or listing failed. Requires the "transfer" permission.
+func TransferList(conn TransferConn, dir string) ([]RemoteFile, bool) {
+	cp, cl := stringToPtr(conn.ToJSON())
+	dp, dl := stringToPtr(dir)
+	packed := hostTransferList(cp, cl, dp, dl)
+	if packed == -1 {
+		return nil, false
+	}
+	return parseRemoteFilesJSON(unpackString(packed)), true
+}
+
+// TransferGet downloads remotePath into flow storage at path. The file is
+// copied by the host and never passes through module memory.
+func TransferGet(conn TransferConn, remotePath, path string) bool {
+	cp, cl := stringToPtr(conn.ToJSON())
+	rp, rl := stringToPtr(remotePath)
+	lp, ll := stringToPtr(path)
+	return hostTransferGet(cp, cl, rp, rl, lp, ll) != 0
+}
+
+// TransferPut uploads the flow storage file at path to remotePath,
+// replacing an existing remote file.
+func TransferPut(conn TransferConn, path, remotePath string) bool {
+	cp, cl := stringToPtr(conn.ToJSON())
+	lp, ll := stringToPtr(path)
+	rp, rl := stringToPtr(remotePath)
+	return hostTransferPut(cp, cl, lp, ll, rp, rl) != 0
+}
+
+// BucketList lists the objects of an external S3-compatible bucket whose keys
+// start with prefix. ok is false when the bucket could not be reached.
+// Requires the "bucket" permission.
+func BucketList(b Bucket, prefix string) ([]BucketObject, bool) {
+	bp, bl := stringToPtr(b.ToJSON())
+	pp, pl := stringToPtr(prefix)
+	packed := hostBucketList(bp, bl, pp, pl)
+	if packed == -1 {
+		return nil, false
+	}
+	return parseBucketObjectsJSON(unpackString(packed)), true
+}
+
+// BucketGet copies the object key into flow storage at path.
+func BucketGet(b Bucket, key, path string) bool {
+	bp, bl := stringToPtr(b.ToJSON())
+	kp, kl := stringToPtr(key)
+	lp, ll := stringToPtr(path)
+	return hostBucketGet(bp, bl, kp, kl, lp, ll) != 0
+}
+
+// BucketPut uploads the flow storage file at path as object key.
+func BucketPut(b Bucket, path, key string) bool {
+	bp, bl := stringToPtr(b.ToJSON())
+	lp, ll := stringToPtr(path)
+	kp, kl := stringToPtr(key)
+	return hostBucketPut(bp, bl, lp, ll, kp, kl) != 0
+}
+
+// BucketDelete removes the object key. Deleting a missing object succeeds.
+func BucketDelete(b Bucket, key string) bool {
+	bp, bl := stringToPtr(b.ToJSON())
+	kp, kl := stringToPtr(key)
+	return hostBucketDelete(bp, bl, kp, kl) != 0
+}
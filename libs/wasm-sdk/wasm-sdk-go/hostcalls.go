@@ -0,0 +1,534 @@
+package sdk
+
+import "strconv"
+
+// HostCalls makes the host calls of a Context and reports failures as
+// errors. Each method matches the Context method of the same name, which
+// only signals failure with an empty result or false. Errors are *HostError
+// values carrying the host's code and message, so handlers can tell a
+// missing permission from a missing file or a used-up quota:
+//
+//	data, err := ctx.Host().StorageRead(path)
+//	if errors.Is(err, sdk.ErrNotFound) {
+//		data = "[]"
+//	} else if err != nil {
+//		return err
+//	}
+//
+// During a dry run no call reaches the host and every method returns an
+// error.
+type HostCalls struct{ c *Context }
+
+// Host returns the error-returning host calls of c.
+func (c *Context) Host() HostCalls { return HostCalls{c} }
+
+// --- Dirs ---
+
+// DirOption scopes the directory returned by StorageDir, CacheDirPath and
+// UserDir. Without options a directory is shared by the whole board.
+type DirOption func(*dirScope)
+
+type dirScope struct{ node, user bool }
+
+// WithNodeScope narrows a directory to this node instance.
+func WithNodeScope() DirOption { return func(s *dirScope) { s.node = true } }
+
+// WithUserScope narrows a directory to the user of the run.
+func WithUserScope() DirOption { return func(s *dirScope) { s.user = true } }
+
+func dirScopeOf(opts []DirOption) dirScope {
+	var s dirScope
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
+}
+
+// scopeOptions converts the booleans of the deprecated Context methods.
+func scopeOptions(nodeScoped, userScoped bool) []DirOption {
+	var opts []DirOption
+	if nodeScoped {
+		opts = append(opts, WithNodeScope())
+	}
+	if userScoped {
+		opts = append(opts, WithUserScope())
+	}
+	return opts
+}
+
+// StorageDir returns the board's storage directory, or the user's with
+// WithUserScope:
+//
+//	dir, err := ctx.Host().StorageDir(sdk.WithNodeScope())
+func (h HostCalls) StorageDir(opts ...DirOption) (string, error) {
+	s := dirScopeOf(opts)
+	if s.user {
+		return h.dir("UserDir", func() string { return UserDir(s.node) })
+	}
+	return h.dir("StorageDir", func() string { return StorageDir(s.node) })
+}
+
+func (h HostCalls) UploadDir() (string, error) {
+	return h.dir("UploadDir", UploadDir)
+}
+
+// CacheDirPath returns the cache directory, which the platform may clear
+// between runs.
+func (h HostCalls) CacheDirPath(opts ...DirOption) (string, error) {
+	s := dirScopeOf(opts)
+	return h.dir("CacheDirPath", func() string { return CacheDirPath(s.node, s.user) })
+}
+
+// UserDir is StorageDir with WithUserScope.
+func (h HostCalls) UserDir(opts ...DirOption) (string, error) {
+	return h.StorageDir(append(opts, WithUserScope())...)
+}
+
+func (h HostCalls) dir(op string, call func() string) (string, error) {
+	if !h.c.host(PermStorage) {
+		return "", dryRunSkipped(op)
+	}
+	if dir := call(); dir != "" {
+		return dir, nil
+	}
+	return "", hostFailure(op, ErrCodeInternal)
+}
+
+// --- Storage I/O ---
+
+// StorageRead returns the content of the file at path. An empty file and a
+// failure the host gives no reason for both read as "" with a nil error.
+func (h HostCalls) StorageRead(path string) (string, error) {
+	if !h.c.host(PermStorage) {
+		return "", dryRunSkipped("StorageRead")
+	}
+	data := StorageRead(path)
+	if data == "" {
+		return "", LastHostError("StorageRead")
+	}
+	return data, nil
+}
+
+func (h HostCalls) StorageWrite(path, data string) error {
+	if !h.c.host(PermStorage) {
+		return dryRunSkipped("StorageWrite")
+	}
+	if !StorageWrite(path, data) {
+		return hostFailure("StorageWrite", ErrCodeInternal)
+	}
+	return nil
+}
+
+func (h HostCalls) StorageWriteWithPolicy(path, data string, policy RetentionPolicy) error {
+	if !h.c.host(PermStorage) {
+		return dryRunSkipped("StorageWriteWithPolicy")
+	}
+	if !StorageWriteWithPolicy(path, data, policy) {
+		return hostFailure("StorageWriteWithPolicy", ErrCodeInternal)
+	}
+	return nil
+}
+
+// StorageAppend appends data to the file at path, creating it if needed,
+// so log-style nodes do not rewrite the whole file on every run.
+func (h HostCalls) StorageAppend(path, data string) error {
+	if !h.c.host(PermStorage) {
+		return dryRunSkipped("StorageAppend")
+	}
+	if !StorageAppend(path, data) {
+		return hostFailure("StorageAppend", ErrCodeInternal)
+	}
+	return nil
+}
+
+// ReadRange returns up to length bytes of the file at path starting at
+// offset, e.g. to resume a download where the stored part ends. Fewer bytes
+// come back near the end of the file and "" with a nil error past it.
+func (h HostCalls) ReadRange(path string, offset, length int64) (string, error) {
+	if offset < 0 || length <= 0 {
+		return "", &HostError{Op: "ReadRange", Code: ErrCodeInvalidInput,
+			Message: "invalid range " + strconv.FormatInt(offset, 10) + "+" + strconv.FormatInt(length, 10)}
+	}
+	if !h.c.host(PermStorage) {
+		return "", dryRunSkipped("ReadRange")
+	}
+	data := StorageReadRange(path, offset, length)
+	if data == "" {
+		return "", LastHostError("ReadRange")
+	}
+	return data, nil
+}
+
+func (h HostCalls) StorageList(flowPathJSON string) (string, error) {
+	if !h.c.host(PermStorage) {
+		return "", dryRunSkipped("StorageList")
+	}
+	if list := StorageList(flowPathJSON); list != "" {
+		return list, nil
+	}
+	return "", hostFailure("StorageList", ErrCodeInternal)
+}
+
+func (h HostCalls) OpenStorageReader(path string) (*StorageReader, error) {
+	if !h.c.host(PermStorage) {
+		return nil, dryRunSkipped("OpenStorageReader")
+	}
+	r, ok := OpenStorageReader(path)
+	if !ok {
+		return nil, hostFailure("OpenStorageReader", ErrCodeNotFound)
+	}
+	return r, nil
+}
+
+func (h HostCalls) CreateStorageWriter(path string) (*StorageWriter, error) {
+	if !h.c.host(PermStorage) {
+		return nil, dryRunSkipped("CreateStorageWriter")
+	}
+	w, ok := CreateStorageWriter(path)
+	if !ok {
+		return nil, hostFailure("CreateStorageWriter", ErrCodeInternal)
+	}
+	return w, nil
+}
+
+// --- Models ---
+
+func (h HostCalls) EmbedText(bitJSON, textsJSON string) (string, error) {
+	if !h.c.host(PermModels) {
+		return "", dryRunSkipped("EmbedText")
+	}
+	return h.c.embedText(bitJSON, textsJSON)
+}
+
+func (h HostCalls) ChatComplete(bitJSON string, req ChatRequest) (ChatResponse, error) {
+	if !h.c.host(PermModels) {
+		return ChatResponse{}, dryRunSkipped("ChatComplete")
+	}
+	// The reason is taken inside call: recording the call makes host calls
+	// of its own.
+	var err error
+	resp, _ := h.c.chatComplete("chat", bitJSON, req, func() (ChatResponse, bool) {
+		resp, ok := ChatComplete(bitJSON, req)
+		if !ok {
+			err = hostFailure("ChatComplete", ErrCodeUpstream)
+		}
+		return resp, ok
+	})
+	return resp, err
+}
+
+func (h HostCalls) DetectEntities(bitJSON, text string) ([]Entity, error) {
+	if !h.c.host(PermModels) {
+		return nil, dryRunSkipped("DetectEntities")
+	}
+	entities := DetectEntities(bitJSON, text)
+	if len(entities) == 0 {
+		return nil, LastHostError("DetectEntities")
+	}
+	return entities, nil
+}
+
+func (h HostCalls) ListModelBits(capability string) ([]ModelBit, error) {
+	if !h.c.host(PermModels) {
+		return nil, dryRunSkipped("ListModelBits")
+	}
+	bits := ListModelBits(capability)
+	if len(bits) == 0 {
+		return bits, LastHostError("ListModelBits")
+	}
+	return bits, nil
+}
+
+func (h HostCalls) DefaultBit(capability string) (ModelBit, error) {
+	if !h.c.host(PermModels) {
+		return ModelBit{}, dryRunSkipped("DefaultBit")
+	}
+	bit, ok := DefaultBit(capability)
+	if !ok {
+		return ModelBit{}, hostFailure("DefaultBit", ErrCodeNotFound)
+	}
+	return bit, nil
+}
+
+func (h HostCalls) Rerank(bitJSON, query string, candidates []string) ([]ScoredResult, error) {
+	if !h.c.host(PermModels) {
+		return nil, dryRunSkipped("Rerank")
+	}
+	results := Rerank(bitJSON, query, candidates)
+	if len(results) == 0 && len(candidates) > 0 {
+		return nil, hostFailure("Rerank", ErrCodeUpstream)
+	}
+	return results, nil
+}
+
+// --- Vector search ---
+
+func (h HostCalls) VectorUpsert(collection string, records []VectorRecord) error {
+	if !h.c.host(PermVector) {
+		return dryRunSkipped("VectorUpsert")
+	}
+	if !VectorUpsert(collection, records) {
+		return hostFailure("VectorUpsert", ErrCodeInternal)
+	}
+	return nil
+}
+
+func (h HostCalls) VectorSearch(collection, vectorJSON string, limit int) ([]VectorMatch, error) {
+	if !h.c.host(PermVector) {
+		return nil, dryRunSkipped("VectorSearch")
+	}
+	matches := VectorSearch(collection, vectorJSON, limit)
+	if len(matches) == 0 {
+		return nil, LastHostError("VectorSearch")
+	}
+	return matches, nil
+}
+
+// --- HTTP ---
+
+// HTTPRequest sends a request without waiting for the response. URLs outside
+// the node's HTTP allowlist fail with ErrPermissionDenied.
+func (h HostCalls) HTTPRequest(method int, url, headers, body string) error {
+	if err := h.httpAllowed("HTTPRequest", url); err != nil {
+		return err
+	}
+	if !HTTPRequest(method, url, h.c.withCookies(url, headers), body) {
+		return hostFailure("HTTPRequest", ErrCodeUpstream)
+	}
+	return nil
+}
+
+func (h HostCalls) HTTPFetch(method int, url, headers, body string) (HTTPResponse, error) {
+	return h.HTTPFetchWith(method, url, headers, body, HTTPRequestOptions{})
+}
+
+// HTTPFetchWith is HTTPFetch with per-request proxy, TLS and timeout
+// options. HTTP error statuses are responses, not errors.
+func (h HostCalls) HTTPFetchWith(method int, url, headers, body string, opts HTTPRequestOptions) (HTTPResponse, error) {
+	if err := h.httpAllowed("HTTPFetch", url); err != nil {
+		return HTTPResponse{}, err
+	}
+	resp, ok := HTTPFetchWith(method, url, h.c.withCookies(url, headers), body, opts)
+	if !ok {
+		return HTTPResponse{}, hostFailure("HTTPFetch", ErrCodeUpstream)
+	}
+	h.c.storeCookies(url, &resp)
+	return resp, nil
+}
+
+// HTTPRequestWithClientCert sends req over mutual TLS, presenting the
+// platform-managed client certificate certRef.
+func (h HostCalls) HTTPRequestWithClientCert(certRef string, req HTTPCall) (HTTPResponse, error) {
+	req.Options.ClientCertRef = certRef
+	return h.HTTPFetchWith(req.Method, req.URL, req.Headers, req.Body, req.Options)
+}
+
+func (h HostCalls) httpAllowed(op, url string) error {
+	if !h.c.checkHTTP(url) {
+		return &HostError{Op: op, Code: ErrCodePermissionDenied, Message: url + " is not in the node's HTTP allowlist"}
+	}
+	if !h.c.host(PermHTTP) {
+		return dryRunSkipped(op)
+	}
+	return nil
+}
+
+// --- File transfer ---
+
+func (h HostCalls) TransferList(conn TransferConn, dir string) ([]RemoteFile, error) {
+	if !h.c.host(PermTransfer) {
+		return nil, dryRunSkipped("TransferList")
+	}
+	files, ok := TransferList(conn, dir)
+	if !ok {
+		return nil, hostFailure("TransferList", ErrCodeUpstream)
+	}
+	return files, nil
+}
+
+func (h HostCalls) TransferGet(conn TransferConn, remotePath, path string) error {
+	if !h.c.host(PermTransfer) {
+		return dryRunSkipped("TransferGet")
+	}
+	if !TransferGet(conn, remotePath, path) {
+		return hostFailure("TransferGet", ErrCodeUpstream)
+	}
+	return nil
+}
+
+func (h HostCalls) TransferPut(conn TransferConn, path, remotePath string) error {
+	if !h.c.host(PermTransfer) {
+		return dryRunSkipped("TransferPut")
+	}
+	if !TransferPut(conn, path, remotePath) {
+		return hostFailure("TransferPut", ErrCodeUpstream)
+	}
+	return nil
+}
+
+// --- External buckets ---
+
+func (h HostCalls) BucketList(b Bucket, prefix string) ([]BucketObject, error) {
+	if !h.c.host(PermBucket) {
+		return nil, dryRunSkipped("BucketList")
+	}
+	objects, ok := BucketList(b, prefix)
+	if !ok {
+		return nil, hostFailure("BucketList", ErrCodeUpstream)
+	}
+	return objects, nil
+}
+
+func (h HostCalls) BucketGet(b Bucket, key, path string) error {
+	if !h.c.host(PermBucket) {
+		return dryRunSkipped("BucketGet")
+	}
+	if !BucketGet(b, key, path) {
+		return hostFailure("BucketGet", ErrCodeUpstream)
+	}
+	return nil
+}
+
+func (h HostCalls) BucketPut(b Bucket, path, key string) error {
+	if !h.c.host(PermBucket) {
+		return dryRunSkipped("BucketPut")
+	}
+	if !BucketPut(b, path, key) {
+		return hostFailure("BucketPut", ErrCodeUpstream)
+	}
+	return nil
+}
+
+func (h HostCalls) BucketDelete(b Bucket, key string) error {
+	if !h.c.host(PermBucket) {
+		return dryRunSkipped("BucketDelete")
+	}
+	if !BucketDelete(b, key) {
+		return hostFailure("BucketDelete", ErrCodeUpstream)
+	}
+	return nil
+}
+
+// --- Local processes ---
+
+// Exec runs a local command. A non-zero exit code is reported in the output,
+// not as an error.
+func (h HostCalls) Exec(cmd string, args []string, stdin string) (ExecOutput, error) {
+	if !h.c.host(PermExec) {
+		return ExecOutput{ExitCode: -1}, dryRunSkipped("Exec")
+	}
+	out, ok := Exec(cmd, args, stdin)
+	if !ok {
+		return ExecOutput{ExitCode: -1}, hostFailure("Exec", ErrCodeInternal)
+	}
+	return out, nil
+}
+
+// --- Auth ---
+
+// GetOAuthToken returns the access token for provider. Without one, e.g.
+// before the user consented, it fails with ErrNotFound unless the host says
+// otherwise.
+func (h HostCalls) GetOAuthToken(provider string) (string, error) {
+	if !h.c.host(PermOAuth) {
+		return "", dryRunSkipped("GetOAuthToken")
+	}
+	if token := GetOAuthToken(provider); token != "" {
+		return token, nil
+	}
+	return "", hostFailure("GetOAuthToken", ErrCodeNotFound)
+}
+
+// RefreshOAuthToken forces a token refresh. It fails with
+// ErrPermissionDenied unless the host says otherwise when the user must
+// consent again.
+func (h HostCalls) RefreshOAuthToken(provider string) (string, error) {
+	if !h.c.host(PermOAuth) {
+		return "", dryRunSkipped("RefreshOAuthToken")
+	}
+	if token := RefreshOAuthToken(provider); token != "" {
+		return token, nil
+	}
+	return "", hostFailure("RefreshOAuthToken", ErrCodePermissionDenied)
+}
+
+// --- Desktop bridge and notifications ---
+
+func (h HostCalls) ClipboardRead() (string, error) {
+	if !h.c.host(PermClipboard) {
+		return "", dryRunSkipped("ClipboardRead")
+	}
+	text := ClipboardRead()
+	if text == "" {
+		return "", LastHostError("ClipboardRead")
+	}
+	return text, nil
+}
+
+func (h HostCalls) ClipboardWrite(text string) error {
+	if !h.c.host(PermClipboard) {
+		return dryRunSkipped("ClipboardWrite")
+	}
+	if !ClipboardWrite(text) {
+		return hostFailure("ClipboardWrite", ErrCodeInternal)
+	}
+	return nil
+}
+
+func (h HostCalls) OpenFile(flowPathJSON string) error {
+	if !h.c.host(PermOpenFile) {
+		return dryRunSkipped("OpenFile")
+	}
+	if !OpenFile(flowPathJSON) {
+		return hostFailure("OpenFile", ErrCodeInternal)
+	}
+	return nil
+}
+
+func (h HostCalls) Notify(level, title, message string, actions ...NotificationAction) error {
+	if !h.c.host(PermNotifications) {
+		return dryRunSkipped("Notify")
+	}
+	if !Notify(level, title, message, actions) {
+		return hostFailure("Notify", ErrCodePermissionDenied)
+	}
+	return nil
+}
+
+// --- Quotas ---
+
+// QuotaConsume takes amount from the named quota, failing with
+// ErrQuotaExceeded when it does not fit.
+func (h HostCalls) QuotaConsume(name string, amount int64) error {
+	if !h.c.host("") {
+		return dryRunSkipped("QuotaConsume")
+	}
+	if !QuotaConsume(name, amount) {
+		return hostFailure("QuotaConsume", ErrCodeQuotaExceeded)
+	}
+	return nil
+}
+
+// GetQuota reports the state of quota kind, failing with ErrNotFound when
+// the host does not know it.
+func (h HostCalls) GetQuota(kind string) (Quota, error) {
+	if !h.c.host("") {
+		return Quota{}, dryRunSkipped("GetQuota")
+	}
+	q, ok := GetQuota(kind)
+	if !ok {
+		return Quota{}, hostFailure("GetQuota", ErrCodeNotFound)
+	}
+	return q, nil
+}
+
+// --- Deadlines ---
+
+// SetCallDeadline makes the host abort the following host calls when they
+// still run at deadline (Unix milliseconds), failing them with
+// ErrCodeDeadlineExceeded; 0 removes it.
+func (h HostCalls) SetCallDeadline(deadline int64) {
+	if h.c.host("") {
+		SetCallDeadline(deadline)
+	}
+}
@@ -0,0 +1,78 @@
+package sdk
+
+const (
+	// ErrCodeQuotaExceeded marks a host call rejected because a platform or
+	// named quota is used up.
+	ErrCodeQuotaExceeded = "quota_exceeded"
+	// ErrCodeConflict marks a conditional write whose expected version no
+	// longer matches.
+	ErrCodeConflict = "conflict"
+	// ErrCodeDeadlineExceeded marks a host call or run that outlived its
+	// deadline or the run's execution budget.
+	ErrCodeDeadlineExceeded = "deadline_exceeded"
+	// ErrCodeCancelled marks a host call or run stopped because the run was
+	// cancelled.
+	ErrCodeCancelled = "cancelled"
+	// ErrCodeIntegrity marks stored content whose hash does not match the
+	// expected one.
+	ErrCodeIntegrity = "integrity"
+)
+
+// HostError is the failure of a host call made through Context.Host. Code
+// and Message come from the host when it reports why the call failed;
+// otherwise Code is a best guess for the call and Message is empty.
+type HostError struct {
+	// Op is the Context method that failed, e.g. "StorageRead".
+	Op      string
+	Code    string
+	Message string
+}
+
+// Sentinels for errors.Is. A HostError matches the sentinel with its Code.
+var (
+	ErrPermissionDenied = &HostError{Code: ErrCodePermissionDenied, Message: "permission denied"}
+	ErrNotFound         = &HostError{Code: ErrCodeNotFound, Message: "not found"}
+	ErrQuotaExceeded    = &HostError{Code: ErrCodeQuotaExceeded, Message: "quota exceeded"}
+	ErrConflict         = &HostError{Code: ErrCodeConflict, Message: "version conflict"}
+	ErrIntegrity        = &HostError{Code: ErrCodeIntegrity, Message: "content hash mismatch"}
+)
+
+func (e *HostError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = "failed (" + e.Code + ")"
+	}
+	if e.Op == "" {
+		return msg
+	}
+	return e.Op + ": " + msg
+}
+
+// Is reports whether target is the sentinel for e's Code, so that
+// errors.Is(err, ErrNotFound) holds for any not-found host failure.
+func (e *HostError) Is(target error) bool {
+	t, ok := target.(*HostError)
+	return ok && t.Op == "" && t.Code == e.Code
+}
+
+func (e *HostError) errorInfo() ErrorInfo {
+	code := e.Code
+	if code == "" {
+		code = ErrCodeInternal
+	}
+	return ErrorInfo{Code: code, Message: e.Error()}
+}
+
+// hostFailure returns the reason the host gave for the failed call op, or a
+// HostError with code when it gave none.
+func hostFailure(op, code string) error {
+	if err := LastHostError(op); err != nil {
+		return err
+	}
+	return &HostError{Op: op, Code: code}
+}
+
+// dryRunSkipped is the error of host calls that a dry run does not make.
+func dryRunSkipped(op string) error {
+	return &HostError{Op: op, Code: ErrCodeInternal, Message: "not called during a dry run"}
+}
@@ -0,0 +1,159 @@
+package sdk
+
+import "strings"
+
+// AllowHTTP restricts the node's outbound HTTP to the given patterns and
+// declares the "http" permission. The runtime can enforce the list as a
+// tighter sandbox; the SDK also rejects non-matching requests made through
+// the Context before they reach the host. Patterns are either
+//
+//   - a host, optionally with port: "api.github.com", "localhost:8080"
+//   - a wildcard subdomain: "*.example.com" (does not match example.com)
+//   - a URL prefix: "https://api.example.com/v2/"; scheme and host must
+//     match exactly and the path (after resolving ".." segments) must start
+//     with the prefix's path, continuing with "/" unless that ends in "/"
+//
+// Without any AllowHTTP call the node may reach any URL the runtime allows.
+func (n *NodeDefinition) AllowHTTP(patterns ...string) *NodeDefinition {
+	n.HTTPAllow = append(n.HTTPAllow, patterns...)
+	if !n.HasPermission(PermHTTP) {
+		n.AddPermission(PermHTTP)
+	}
+	return n
+}
+
+// HTTPAllowed reports whether url matches the definition's allowlist.
+func (n *NodeDefinition) HTTPAllowed(url string) bool {
+	if len(n.HTTPAllow) == 0 {
+		return true
+	}
+	host := urlHost(url)
+	for _, pattern := range n.HTTPAllow {
+		if strings.Contains(pattern, "://") {
+			if matchURLPrefix(pattern, url) {
+				return true
+			}
+			continue
+		}
+		if matchHost(strings.ToLower(pattern), host) {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPAllowed reports whether the bound definition permits a request to url.
+func (c *Context) HTTPAllowed(url string) bool {
+	return c.def == nil || c.def.HTTPAllowed(url)
+}
+
+// checkHTTP logs and reports requests blocked by the allowlist.
+func (c *Context) checkHTTP(url string) bool {
+	if c.HTTPAllowed(url) {
+		return true
+	}
+	c.Warn("HTTP request to " + url + " blocked: not in the node's HTTP allowlist")
+	return false
+}
+
+// matchURLPrefix reports whether url falls under the URL prefix pattern.
+// Scheme and host[:port] must match exactly (ignoring case and the scheme's
+// default port), so neither "https://api.example.com.evil.com" nor
+// "https://api.example.com:x@evil.com" matches "https://api.example.com".
+// The path is compared after resolving "." and ".." segments, and unless the
+// pattern's path ends in "/" it must continue with "/" or end there.
+func matchURLPrefix(pattern, url string) bool {
+	pScheme, pHost, pPath := splitURL(pattern)
+	scheme, host, path := splitURL(url)
+	if scheme != pScheme || host != pHost {
+		return false
+	}
+	path = removeDotSegments(path)
+	if pPath == "" || pPath == "/" {
+		return true
+	}
+	if !strings.HasPrefix(path, pPath) {
+		return false
+	}
+	return len(path) == len(pPath) || strings.HasSuffix(pPath, "/") || path[len(pPath)] == '/'
+}
+
+// splitURL splits an absolute URL into its lower-cased scheme, its host[:port]
+// without a default port, and its path without query and fragment.
+func splitURL(url string) (scheme, host, path string) {
+	i := strings.Index(url, "://")
+	if i < 0 {
+		return "", "", ""
+	}
+	scheme = strings.ToLower(url[:i])
+	host = urlHost(url)
+	switch {
+	case scheme == "https" && strings.HasSuffix(host, ":443"),
+		scheme == "http" && strings.HasSuffix(host, ":80"):
+		host = host[:strings.LastIndexByte(host, ':')]
+	}
+	rest := url[i+3:]
+	j := strings.IndexAny(rest, "/?#\\")
+	if j < 0 || rest[j] == '?' || rest[j] == '#' {
+		return scheme, host, ""
+	}
+	path = rest[j:]
+	if k := strings.IndexAny(path, "?#"); k >= 0 {
+		path = path[:k]
+	}
+	return scheme, host, path
+}
+
+// removeDotSegments resolves the "." and ".." segments of path, including
+// their percent-encoded forms, the way the host resolves them before sending.
+func removeDotSegments(path string) string {
+	segs := strings.Split(strings.ReplaceAll(path, "\\", "/"), "/")
+	out := make([]string, 0, len(segs))
+	for i, seg := range segs {
+		last := i == len(segs)-1
+		switch strings.ReplaceAll(strings.ToLower(seg), "%2e", ".") {
+		case ".":
+			if last {
+				out = append(out, "")
+			}
+		case "..":
+			if len(out) > 1 {
+				out = out[:len(out)-1]
+			}
+			if last {
+				out = append(out, "")
+			}
+		default:
+			out = append(out, seg)
+		}
+	}
+	return strings.Join(out, "/")
+}
+
+func matchHost(pattern, host string) bool {
+	if !strings.Contains(pattern, ":") {
+		if i := strings.LastIndexByte(host, ':'); i >= 0 {
+			host = host[:i]
+		}
+	}
+	if strings.HasPrefix(pattern, "*.") {
+		return strings.HasSuffix(host, pattern[1:])
+	}
+	return host == pattern
+}
+
+// urlHost extracts the lower-cased host[:port] of an absolute URL.
+func urlHost(url string) string {
+	i := strings.Index(url, "://")
+	if i < 0 {
+		return ""
+	}
+	rest := url[i+3:]
+	if j := strings.IndexAny(rest, "/?#\\"); j >= 0 {
+		rest = rest[:j]
+	}
+	if j := strings.LastIndexByte(rest, '@'); j >= 0 {
+		rest = rest[j+1:]
+	}
+	return strings.ToLower(rest)
+}
@@ -0,0 +1,44 @@
+package sdk
+
+import "testing"
+
+func TestHTTPAllowed(t *testing.T) {
+	def := NewNodeDefinition()
+	def.AllowHTTP("https://api.example.com", "https://files.example.com/v2/", "*.cdn.example.net", "localhost:8080")
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://api.example.com", true},
+		{"https://api.example.com/users?id=1", true},
+		{"https://api.example.com?q=1", true},
+		{"https://api.example.com:443/x", true},
+		{"https://api.example.com.evil.com/", false},
+		{"https://api.example.comevil.com", false},
+		{"http://api.example.com/", false},
+		{"https://files.example.com/v2/a.txt", true},
+		{"https://files.example.com/v3/a.txt", false},
+		{"https://img.cdn.example.net/a.png", true},
+		{"https://cdn.example.net/a.png", false},
+		{"http://localhost:8080/health", true},
+		{"http://localhost:9090/health", false},
+		{"https://api.example.com:x@evil.com/", false},
+		{"https://api.example.com@evil.com/", false},
+		{"https://evil.com\\@api.example.com/", false},
+		{"HTTPS://API.Example.COM/users", true},
+		{"https://user:pw@api.example.com/users", true},
+		{"https://api.example.com:8443/users", false},
+		{"https://files.example.com/v2/../admin", false},
+		{"https://files.example.com/v2/%2e%2e/admin", false},
+		{"https://files.example.com/v2/./a/../b.txt", true},
+		{"https://files.example.com/v2", false},
+	}
+	for _, tt := range tests {
+		if got := def.HTTPAllowed(tt.url); got != tt.want {
+			t.Errorf("HTTPAllowed(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+	if open := NewNodeDefinition(); !open.HTTPAllowed("https://anything.example") {
+		t.Error("empty allowlist should allow every URL")
+	}
+}
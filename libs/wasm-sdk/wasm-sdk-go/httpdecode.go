@@ -0,0 +1,194 @@
+package sdk
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// decodeBody undoes the response's Content-Encoding and converts a text body
+// to UTF-8, so handlers see the same text a browser would. Decompression runs
+// in the host (see OpenCodecStream) to keep gzip and brotli out of the module.
+// A body that cannot be decoded is left as received, with its
+// Content-Encoding header still set so callers can tell.
+func (r *HTTPResponse) decodeBody() {
+	if enc := r.Header("content-encoding"); enc != "" {
+		body, ok := decompressBody(enc, r.Body)
+		if !ok {
+			return
+		}
+		r.Body = body
+		delete(r.Headers, "content-encoding")
+		delete(r.Headers, "content-length")
+	}
+	if cs := r.Charset(); cs != "" {
+		if body, ok := ToUTF8(r.Body, cs); ok {
+			r.Body = body
+		}
+	}
+}
+
+// decompressBody applies the codings listed in a Content-Encoding header in
+// reverse order.
+func decompressBody(header, body string) (string, bool) {
+	codings := strings.Split(strings.ToLower(header), ",")
+	for i := len(codings) - 1; i >= 0; i-- {
+		codec := strings.TrimSpace(codings[i])
+		switch codec {
+		case "identity", "":
+			continue
+		case "x-gzip":
+			codec = "gzip"
+		}
+		h, ok := OpenCodecStream(codec, true, 0)
+		if !ok {
+			return "", false
+		}
+		out, ok := CodecStreamUpdate(h, []byte(body))
+		if !ok {
+			// Finish releases the host's handle; its result is moot.
+			CodecStreamFinish(h)
+			return "", false
+		}
+		rest, ok := CodecStreamFinish(h)
+		if !ok {
+			return "", false
+		}
+		body = string(append(out, rest...))
+	}
+	return body, true
+}
+
+// Charset returns the lower-cased charset of a textual response: the
+// Content-Type charset parameter, or "utf-16le"/"utf-16be" when the body
+// starts with a UTF-16 byte order mark. It returns "" when neither says. The
+// byte order mark is only trusted for textual or missing Content-Types, so
+// binary bodies that happen to start with one are left alone.
+func (r *HTTPResponse) Charset() string {
+	ct := strings.ToLower(r.Header("content-type"))
+	params := strings.Split(ct, ";")
+	for _, param := range params[1:] {
+		k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if ok && strings.TrimSpace(k) == "charset" {
+			return strings.Trim(strings.TrimSpace(v), `"'`)
+		}
+	}
+	if !isTextMediaType(strings.TrimSpace(params[0])) {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(r.Body, "\xff\xfe"):
+		return "utf-16le"
+	case strings.HasPrefix(r.Body, "\xfe\xff"):
+		return "utf-16be"
+	}
+	return ""
+}
+
+// isTextMediaType reports whether a lower-cased media type (without
+// parameters) holds text; "" counts as text since the body may be anything.
+func isTextMediaType(mt string) bool {
+	switch {
+	case mt == "", strings.HasPrefix(mt, "text/"),
+		strings.HasSuffix(mt, "+json"), strings.HasSuffix(mt, "+xml"):
+		return true
+	}
+	switch mt {
+	case "application/json", "application/xml", "application/javascript",
+		"application/ecmascript", "application/x-www-form-urlencoded",
+		"application/x-ndjson", "application/csv":
+		return true
+	}
+	return false
+}
+
+// ToUTF8 converts text in the named charset to UTF-8 and strips a leading
+// byte order mark. Supported are UTF-8, US-ASCII, ISO-8859-1 (Latin-1),
+// ISO-8859-15, Windows-1252 and UTF-16 (LE, BE, or by BOM). ok is false for
+// other charsets, in which case s is returned unchanged.
+func ToUTF8(s, charset string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return strings.TrimPrefix(s, "\xef\xbb\xbf"), true
+	case "iso-8859-1", "latin1", "latin-1", "l1", "iso_8859-1":
+		return decodeSingleByte(s, nil), true
+	case "iso-8859-15", "latin-9", "latin9":
+		return decodeSingleByte(s, latin9), true
+	case "windows-1252", "cp1252", "x-cp1252":
+		return decodeSingleByte(s, cp1252), true
+	case "utf-16", "utf16":
+		if strings.HasPrefix(s, "\xfe\xff") {
+			return decodeUTF16(s[2:], true), true
+		}
+		return decodeUTF16(strings.TrimPrefix(s, "\xff\xfe"), false), true
+	case "utf-16le":
+		return decodeUTF16(strings.TrimPrefix(s, "\xff\xfe"), false), true
+	case "utf-16be":
+		return decodeUTF16(strings.TrimPrefix(s, "\xfe\xff"), true), true
+	}
+	return s, false
+}
+
+// decodeSingleByte maps bytes to runes, consulting overrides for the bytes
+// where a charset differs from Latin-1.
+func decodeSingleByte(s string, overrides map[byte]rune) string {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + len(s)/4)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x80 {
+			b.WriteByte(c)
+			continue
+		}
+		r := rune(c)
+		if o, ok := overrides[c]; ok {
+			r = o
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func decodeUTF16(s string, bigEndian bool) string {
+	units := make([]uint16, len(s)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(s[2*i])<<8 | uint16(s[2*i+1])
+		} else {
+			units[i] = uint16(s[2*i+1])<<8 | uint16(s[2*i])
+		}
+	}
+	var b strings.Builder
+	b.Grow(len(units))
+	for _, r := range utf16.Decode(units) {
+		b.WriteRune(r)
+	}
+	if len(s)%2 == 1 {
+		b.WriteRune(utf8.RuneError)
+	}
+	return b.String()
+}
+
+// cp1252 holds the Windows-1252 characters in 0x80–0x9F, where Latin-1 has
+// control codes.
+var cp1252 = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+	0x88: 'ˆ', 0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž',
+	0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—',
+	0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+// latin9 holds the ISO-8859-15 characters that differ from Latin-1.
+var latin9 = map[byte]rune{
+	0xA4: '€', 0xA6: 'Š', 0xA8: 'š', 0xB4: 'Ž', 0xB8: 'ž', 0xBC: 'Œ', 0xBD: 'œ', 0xBE: 'Ÿ',
+}
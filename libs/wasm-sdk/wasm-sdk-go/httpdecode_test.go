@@ -0,0 +1,30 @@
+package sdk
+
+import "testing"
+
+func TestCharset(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+		want        string
+	}{
+		{"text/html; charset=ISO-8859-1", "abc", "iso-8859-1"},
+		{`application/json; charset="utf-8"`, "{}", "utf-8"},
+		{"text/plain", "\xff\xfeh\x00i\x00", "utf-16le"},
+		{"", "\xfe\xff\x00h\x00i", "utf-16be"},
+		{"application/problem+json", "\xff\xfe{\x00}\x00", "utf-16le"},
+		{"text/plain", "hi", ""},
+		{"application/octet-stream", "\xff\xfe\x00\x01", ""},
+		{"image/png", "\xfe\xff\x89P", ""},
+		{"application/zip; name=a.zip", "\xff\xfePK", ""},
+	}
+	for _, tt := range tests {
+		r := &HTTPResponse{Headers: map[string]string{}, Body: tt.body}
+		if tt.contentType != "" {
+			r.Headers["content-type"] = tt.contentType
+		}
+		if got := r.Charset(); got != tt.want {
+			t.Errorf("Charset(%q, %q) = %q, want %q", tt.contentType, tt.body, got, tt.want)
+		}
+	}
+}
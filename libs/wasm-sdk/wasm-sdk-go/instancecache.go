@@ -0,0 +1,107 @@
+package sdk
+
+// Invalidation scopes the host may signal through the invalidate_cache export.
+// An empty scope invalidates every instance cache.
+const (
+	InvalidateAll       = ""
+	InvalidateConfig    = "config"    // app or pack configuration changed
+	InvalidateVariables = "variables" // board variables changed
+	InvalidateModels    = "models"    // model bits were added, removed or updated
+)
+
+// InstanceCache keeps values warm across runs of the same module instance,
+// e.g. parsed configs or compiled templates. Package-level variables already
+// survive between runs; InstanceCache adds invalidation so values derived from
+// host state are dropped when the host reports that state changed.
+//
+// Declare caches as package variables:
+//
+//	var templates = sdk.NewInstanceCache[*template.Template](sdk.InvalidateConfig)
+//
+// Values must never depend on per-run data such as the user or the inputs,
+// since the next run may belong to a different user.
+type InstanceCache[V any] struct {
+	scopes  []string
+	entries map[string]V
+}
+
+type invalidator interface {
+	invalidate(scope string)
+}
+
+var instanceCaches []invalidator
+
+// NewInstanceCache creates a cache that is cleared when the host invalidates
+// any of the given scopes. A cache is always cleared by InvalidateAll.
+func NewInstanceCache[V any](scopes ...string) *InstanceCache[V] {
+	c := &InstanceCache[V]{scopes: scopes, entries: make(map[string]V)}
+	instanceCaches = append(instanceCaches, c)
+	return c
+}
+
+// Get returns the cached value for key.
+func (c *InstanceCache[V]) Get(key string) (V, bool) {
+	v, ok := c.entries[key]
+	return v, ok
+}
+
+// Set stores a value under key.
+func (c *InstanceCache[V]) Set(key string, value V) {
+	c.entries[key] = value
+}
+
+// GetOrCompute returns the cached value for key, computing and storing it on
+// a miss. Errors are returned as-is and nothing is cached.
+func (c *InstanceCache[V]) GetOrCompute(key string, compute func() (V, error)) (V, error) {
+	if v, ok := c.entries[key]; ok {
+		return v, nil
+	}
+	v, err := compute()
+	if err != nil {
+		return v, err
+	}
+	c.entries[key] = v
+	return v, nil
+}
+
+// Delete removes key from the cache.
+func (c *InstanceCache[V]) Delete(key string) {
+	delete(c.entries, key)
+}
+
+// Clear removes every entry.
+func (c *InstanceCache[V]) Clear() {
+	c.entries = make(map[string]V)
+}
+
+// Len returns the number of cached entries.
+func (c *InstanceCache[V]) Len() int { return len(c.entries) }
+
+func (c *InstanceCache[V]) invalidate(scope string) {
+	if scope == InvalidateAll {
+		c.Clear()
+		return
+	}
+	for _, s := range c.scopes {
+		if s == scope {
+			c.Clear()
+			return
+		}
+	}
+}
+
+// InvalidateInstanceCaches clears every InstanceCache depending on scope, as
+// if the host had signalled it.
+func InvalidateInstanceCaches(scope string) {
+	for _, c := range instanceCaches {
+		c.invalidate(scope)
+	}
+}
+
+// invalidateCache is called by the host when state that instance caches may
+// derive from has changed.
+//
+//export invalidate_cache
+func invalidateCache(scopePtr uint32, scopeLen uint32) {
+	InvalidateInstanceCaches(ptrToString(scopePtr, scopeLen))
+}
@@ -0,0 +1,270 @@
+package sdk
+
+import (
+	"errors"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfirmSchema asks for a yes/no decision; the UI renders it as approve and
+// reject buttons.
+const ConfirmSchema = `{"type":"boolean"}`
+
+// ErrInputPending is returned by the HostCalls user input requests while the
+// user has not answered. The run is marked pending; return ctx.Finish() and
+// the runtime invokes the node again later.
+var ErrInputPending = errors.New("user input pending")
+
+// Status values of a user input request.
+const (
+	InputPending   = "pending"
+	InputAnswered  = "answered"
+	InputCancelled = "cancelled"
+	InputExpired   = "expired"
+)
+
+// UserResponse is the outcome of RequestUserInput. Value is only set when
+// Status is InputAnswered.
+type UserResponse struct {
+	Status      string
+	Value       RawValue
+	RespondedBy string
+	// Escalated is set when the request was escalated before it ended.
+	Escalated bool
+}
+
+// Answered reports whether the user submitted a value.
+func (r *UserResponse) Answered() bool { return r.Status == InputAnswered }
+
+// RequestInput registers a question for the user of the run and returns its
+// ID. schema is the JSON Schema of the expected answer; the UI builds a form
+// from it.
+func RequestInput(prompt, schema string) (string, bool) {
+	pp, pl := stringToPtr(prompt)
+	sp, sl := stringToPtr(schema)
+	packed := hostInteractionRequest(pp, pl, sp, sl)
+	if packed == -1 {
+		return "", false
+	}
+	return unpackString(packed), true
+}
+
+// RolePrefix marks an assignee as a role of the app rather than a user ID,
+// e.g. "role:finance".
+const RolePrefix = "role:"
+
+// InputOptions are the optional parts of a user input request. Chain the
+// builder methods to model an approval step:
+//
+//	opts := sdk.InputOptions{}.
+//		AssignTo("role:finance").
+//		EscalateAfter(24*time.Hour, "role:cfo").
+//		ExpireAfter(72 * time.Hour)
+type InputOptions struct {
+	// Form replaces the form the UI would build from the schema.
+	Form *FormDefinition
+	// Assignees may answer the request: user IDs or RolePrefix roles. Empty
+	// means the user of the run.
+	Assignees []string
+	// After Escalation without an answer, EscalateTo are assigned as well.
+	Escalation time.Duration
+	EscalateTo []string
+	// Expiry ends the request with InputExpired; 0 waits indefinitely.
+	Expiry time.Duration
+}
+
+// AssignTo adds assignees: user IDs or roles written as "role:<name>".
+func (o InputOptions) AssignTo(roleOrUser ...string) InputOptions {
+	o.Assignees = append(append([]string(nil), o.Assignees...), roleOrUser...)
+	return o
+}
+
+// EscalateAfter assigns to, in addition to the assignees, when nobody
+// answered within d.
+func (o InputOptions) EscalateAfter(d time.Duration, to ...string) InputOptions {
+	o.Escalation = d
+	o.EscalateTo = append(append([]string(nil), o.EscalateTo...), to...)
+	return o
+}
+
+// ExpireAfter gives up on the request after d.
+func (o InputOptions) ExpireAfter(d time.Duration) InputOptions {
+	o.Expiry = d
+	return o
+}
+
+// WithForm attaches a structured form.
+func (o InputOptions) WithForm(form *FormDefinition) InputOptions {
+	o.Form = form
+	return o
+}
+
+func (o *InputOptions) IsZero() bool {
+	return o.Form == nil && len(o.Assignees) == 0 && o.Escalation == 0 && len(o.EscalateTo) == 0 && o.Expiry == 0
+}
+
+func (o *InputOptions) ToJSON() string {
+	var parts []string
+	if o.Form != nil {
+		parts = append(parts, `"form":`+o.Form.ToJSON())
+	}
+	if len(o.Assignees) > 0 {
+		parts = append(parts, `"assignees":`+jsonStringArray(o.Assignees))
+	}
+	if o.Escalation > 0 {
+		parts = append(parts, `"escalate_after_ms":`+strconv.FormatInt(o.Escalation.Milliseconds(), 10))
+	}
+	if len(o.EscalateTo) > 0 {
+		parts = append(parts, `"escalate_to":`+jsonStringArray(o.EscalateTo))
+	}
+	if o.Expiry > 0 {
+		parts = append(parts, `"expire_after_ms":`+strconv.FormatInt(o.Expiry.Milliseconds(), 10))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// RequestInputWith is RequestInput with options.
+func RequestInputWith(prompt, schema string, opts InputOptions) (string, bool) {
+	if opts.IsZero() {
+		return RequestInput(prompt, schema)
+	}
+	pp, pl := stringToPtr(prompt)
+	sp, sl := stringToPtr(schema)
+	op, ol := stringToPtr(opts.ToJSON())
+	packed := hostInteractionRequestWith(pp, pl, sp, sl, op, ol)
+	if packed == -1 {
+		return "", false
+	}
+	return unpackString(packed), true
+}
+
+// InputResponse returns the current state of a request made with
+// RequestInput.
+func InputResponse(id string) (UserResponse, bool) {
+	p, l := stringToPtr(id)
+	packed := hostInteractionResponse(p, l)
+	if packed == -1 {
+		return UserResponse{}, false
+	}
+	f := jsonObjectFields(unpackString(packed))
+	if f == nil {
+		return UserResponse{}, false
+	}
+	resp := UserResponse{
+		Status:      jsonUnquote(f["status"]),
+		RespondedBy: jsonUnquote(f["user_id"]),
+		Escalated:   f["escalated"] == "true",
+	}
+	if resp.Status == InputAnswered {
+		resp.Value = NewRawValue(f["value"])
+	}
+	return resp, true
+}
+
+// RequestUserInput asks the user a question and suspends the node until it
+// is answered. The first call shows prompt with a form built from schema,
+// marks the result pending and returns ErrInputPending; the node must then
+// return ctx.Finish(). The runtime invokes the node again later, and the same
+// call returns the answer once the user responded, cancelled or the request
+// expired:
+//
+//	resp, err := ctx.Host().RequestUserInput("Approve the refund?", sdk.ConfirmSchema)
+//	if errors.Is(err, sdk.ErrInputPending) {
+//		return ctx.Finish()
+//	} else if err != nil {
+//		return err
+//	}
+//
+// Requests are told apart by prompt and schema, so a node may ask several
+// questions in turn. Answers that do not match schema are asked again.
+func (h HostCalls) RequestUserInput(prompt, schema string) (UserResponse, error) {
+	return h.RequestUserInputWith(prompt, schema, InputOptions{})
+}
+
+// RequestUserInputWith is RequestUserInput with options.
+func (h HostCalls) RequestUserInputWith(prompt, schema string, opts InputOptions) (UserResponse, error) {
+	c := h.c
+	if !c.host(PermInteraction) {
+		return UserResponse{}, dryRunSkipped("RequestUserInput")
+	}
+	key := c.inputKey(prompt, schema)
+	if id := c.CacheGet(key); id != "" {
+		resp, ok := InputResponse(id)
+		switch {
+		case !ok:
+			c.Warn("user input request " + id + " is unknown to the host, asking again")
+		case resp.Status == InputPending:
+			c.SetPending(true)
+			return UserResponse{}, ErrInputPending
+		case resp.Answered() && schema != "" && len(ValidateSchema(schema, resp.Value)) > 0:
+			c.Warn("answer to user input request " + id + " does not match the schema, asking again")
+		default:
+			c.CacheDelete(key)
+			return resp, nil
+		}
+	}
+	id, ok := RequestInputWith(prompt, schema, opts)
+	if !ok {
+		return UserResponse{}, hostFailure("RequestUserInput", ErrCodeInternal)
+	}
+	c.CacheSet(key, id)
+	c.StreamJSON(`{"type":"user_input","id":` + jsonString(id) + `,"prompt":` + jsonString(prompt) + `}`)
+	c.SetPending(true)
+	return UserResponse{}, ErrInputPending
+}
+
+// RequestForm shows form to the user and suspends like RequestUserInput.
+// The answer is an object keyed by field name, validated against
+// form.Schema(). To assign the form, pass form.Schema() and
+// InputOptions{}.WithForm(form) with assignees to RequestUserInputWith.
+func (h HostCalls) RequestForm(prompt string, form *FormDefinition) (UserResponse, error) {
+	return h.RequestUserInputWith(prompt, form.Schema(), InputOptions{Form: form})
+}
+
+// Confirm asks the user to approve or reject and suspends like
+// RequestUserInput. approved is false when the user rejected, cancelled or
+// let the request expire.
+func (h HostCalls) Confirm(prompt string) (approved bool, err error) {
+	resp, err := h.RequestUserInput(prompt, ConfirmSchema)
+	return err == nil && resp.Answered() && resp.Value.Raw() == "true", err
+}
+
+// Deprecated: Use c.Host().RequestUserInput, which reports why the request
+// failed. ok is false both while the answer is pending and when the host
+// could not take the request; the latter also fails the run.
+func (c *Context) RequestUserInput(prompt, schema string) (UserResponse, bool) {
+	return c.RequestUserInputWith(prompt, schema, InputOptions{})
+}
+
+// Deprecated: Use c.Host().RequestUserInputWith, which reports why the
+// request failed.
+func (c *Context) RequestUserInputWith(prompt, schema string, opts InputOptions) (UserResponse, bool) {
+	resp, err := c.Host().RequestUserInputWith(prompt, schema, opts)
+	var herr *HostError
+	if errors.As(err, &herr) && c.trace == nil {
+		c.SetError("could not request user input")
+	}
+	return resp, err == nil
+}
+
+// Deprecated: Use c.Host().RequestForm, which reports why the request failed.
+func (c *Context) RequestForm(prompt string, form *FormDefinition) (UserResponse, bool) {
+	return c.RequestUserInputWith(prompt, form.Schema(), InputOptions{Form: form})
+}
+
+// Deprecated: Use c.Host().Confirm, which reports why the request failed.
+func (c *Context) Confirm(prompt string) (approved, done bool) {
+	resp, done := c.RequestUserInput(prompt, ConfirmSchema)
+	return done && resp.Answered() && resp.Value.Raw() == "true", done
+}
+
+// inputKey scopes a pending request to the run, the node and the question.
+func (c *Context) inputKey(prompt, schema string) string {
+	h := fnv.New64a()
+	h.Write([]byte(prompt))
+	h.Write([]byte{0})
+	h.Write([]byte(schema))
+	return "flowlike.input:" + c.input.RunID + ":" + c.input.NodeID + ":" + strconv.FormatUint(h.Sum64(), 16)
+}
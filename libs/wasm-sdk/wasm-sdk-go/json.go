@@ -0,0 +1,261 @@
+package sdk
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// jsonScanner walks a JSON document without decoding it, which keeps TinyGo
+// binaries small. It is used to pick apart host responses.
+type jsonScanner struct {
+	s   string
+	idx int
+}
+
+func (j *jsonScanner) skipWhitespace() {
+	for j.idx < len(j.s) {
+		switch j.s[j.idx] {
+		case ' ', '\t', '\n', '\r':
+			j.idx++
+		default:
+			return
+		}
+	}
+}
+
+// skipString advances past a string literal starting at the current quote.
+func (j *jsonScanner) skipString() {
+	j.idx++
+	for j.idx < len(j.s) && j.s[j.idx] != '"' {
+		if j.s[j.idx] == '\\' {
+			j.idx++
+		}
+		j.idx++
+	}
+	if j.idx < len(j.s) {
+		j.idx++
+	}
+}
+
+// readValue returns the next value as raw JSON text.
+func (j *jsonScanner) readValue() string {
+	j.skipWhitespace()
+	if j.idx >= len(j.s) {
+		return ""
+	}
+	start := j.idx
+	switch j.s[j.idx] {
+	case '"':
+		j.skipString()
+	case '{', '[':
+		depth := 0
+		for j.idx < len(j.s) {
+			c := j.s[j.idx]
+			if c == '"' {
+				j.skipString()
+				continue
+			}
+			j.idx++
+			if c == '{' || c == '[' {
+				depth++
+			} else if c == '}' || c == ']' {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+	default:
+		for j.idx < len(j.s) {
+			c := j.s[j.idx]
+			if c == ',' || c == '}' || c == ']' || c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+				break
+			}
+			j.idx++
+		}
+	}
+	return j.s[start:j.idx]
+}
+
+// jsonObjectFields splits a JSON object into its raw member values.
+// It returns nil when s is not an object.
+func jsonObjectFields(s string) map[string]string {
+	j := jsonScanner{s: s}
+	j.skipWhitespace()
+	if j.idx >= len(s) || s[j.idx] != '{' {
+		return nil
+	}
+	j.idx++
+	fields := make(map[string]string)
+	for j.idx < len(s) {
+		j.skipWhitespace()
+		if j.idx >= len(s) || s[j.idx] == '}' {
+			break
+		}
+		if s[j.idx] == ',' {
+			j.idx++
+			continue
+		}
+		key := jsonUnquote(j.readValue())
+		j.skipWhitespace()
+		if j.idx < len(s) && s[j.idx] == ':' {
+			j.idx++
+		}
+		fields[key] = j.readValue()
+	}
+	return fields
+}
+
+// jsonArrayItems splits a JSON array into its raw element values.
+// It returns nil when s is not an array.
+func jsonArrayItems(s string) []string {
+	j := jsonScanner{s: s}
+	j.skipWhitespace()
+	if j.idx >= len(s) || s[j.idx] != '[' {
+		return nil
+	}
+	j.idx++
+	items := []string{}
+	for j.idx < len(s) {
+		j.skipWhitespace()
+		if j.idx >= len(s) || s[j.idx] == ']' {
+			break
+		}
+		if s[j.idx] == ',' {
+			j.idx++
+			continue
+		}
+		items = append(items, j.readValue())
+	}
+	return items
+}
+
+// jsonUnquote decodes a JSON string literal. Non-string input is returned as-is.
+func jsonUnquote(raw string) string {
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return raw
+	}
+	raw = raw[1 : len(raw)-1]
+	if strings.IndexByte(raw, '\\') < 0 {
+		return raw
+	}
+	var b strings.Builder
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if c != '\\' || i+1 >= len(raw) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch raw[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 < len(raw) {
+				if r, err := strconv.ParseUint(raw[i+1:i+5], 16, 32); err == nil {
+					i += 4
+					if r >= 0xD800 && r < 0xDC00 && i+6 < len(raw) && raw[i+1] == '\\' && raw[i+2] == 'u' {
+						if lo, err := strconv.ParseUint(raw[i+3:i+7], 16, 32); err == nil && lo >= 0xDC00 && lo < 0xE000 {
+							r = (r-0xD800)<<10 + (lo - 0xDC00) + 0x10000
+							i += 6
+						}
+					}
+					var buf [4]byte
+					n := utf8.EncodeRune(buf[:], rune(r))
+					b.Write(buf[:n])
+					continue
+				}
+			}
+			b.WriteString(`\u`)
+		default:
+			b.WriteByte(raw[i])
+		}
+	}
+	return b.String()
+}
+
+// jsonStringArray encodes a slice of strings as a JSON array.
+func jsonStringArray(items []string) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, item := range items {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(jsonString(item))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// parseStringArrayJSON decodes a JSON array of strings.
+func parseStringArrayJSON(s string) []string {
+	items := jsonArrayItems(s)
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = jsonUnquote(item)
+	}
+	return out
+}
+
+// parseStringMapJSON decodes a JSON object of strings; it returns nil for an
+// empty or invalid object.
+func parseStringMapJSON(s string) map[string]string {
+	fields := jsonObjectFields(s)
+	if len(fields) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(fields))
+	for k, v := range fields {
+		out[k] = jsonUnquote(v)
+	}
+	return out
+}
+
+// jsonStringMap encodes a map of strings as a JSON object with sorted keys.
+func jsonStringMap(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(jsonString(k))
+		b.WriteByte(':')
+		b.WriteString(jsonString(m[k]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// JSONObjectFields splits a raw JSON object into its raw member values, for use
+// in node implementations. It returns nil when raw is not an object.
+func JSONObjectFields(raw string) map[string]string {
+	return jsonObjectFields(raw)
+}
+
+// JSONArrayItems splits a raw JSON array into its raw element values, for use
+// in node implementations. It returns nil when raw is not an array.
+func JSONArrayItems(raw string) []string {
+	return jsonArrayItems(raw)
+}
+
+// JSONUnquote decodes a raw JSON string literal, the inverse of JSONString.
+func JSONUnquote(raw string) string {
+	return jsonUnquote(raw)
+}
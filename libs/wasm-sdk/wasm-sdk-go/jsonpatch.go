@@ -0,0 +1,441 @@
+package sdk
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrPatchSyntax = errors.New("json patch: invalid operation")
+	ErrPatchPath   = errors.New("json patch: path not found")
+	ErrPatchTest   = errors.New("json patch: test failed")
+)
+
+// PatchError reports the operation that made ApplyPatch fail. It unwraps to
+// one of the ErrPatch* sentinels.
+type PatchError struct {
+	Index int
+	Op    PatchOp
+	Err   error
+}
+
+func (e *PatchError) Error() string {
+	return e.Err.Error() + " (operation " + strconv.Itoa(e.Index) + ": " + e.Op.Op + " " + e.Op.Path + ")"
+}
+
+func (e *PatchError) Unwrap() error { return e.Err }
+
+// PatchOp is a single RFC 6902 JSON Patch operation. Op is one of "add",
+// "remove", "replace", "move", "copy" and "test"; From is used by move and
+// copy, Value by add, replace and test. Paths are JSON Pointers (RFC 6901).
+type PatchOp struct {
+	Op    string
+	Path  string
+	From  string
+	Value RawValue
+}
+
+func (o PatchOp) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"op":`)
+	b.WriteString(jsonString(o.Op))
+	b.WriteString(`,"path":`)
+	b.WriteString(jsonString(o.Path))
+	switch o.Op {
+	case "move", "copy":
+		b.WriteString(`,"from":`)
+		b.WriteString(jsonString(o.From))
+	case "add", "replace", "test":
+		b.WriteString(`,"value":`)
+		if o.Value.Kind() == KindInvalid {
+			b.WriteString("null")
+		} else {
+			b.WriteString(o.Value.Raw())
+		}
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// Patch is an RFC 6902 JSON Patch document.
+type Patch []PatchOp
+
+func (p Patch) ToJSON() string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, op := range p {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(op.ToJSON())
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// ParsePatch decodes a JSON Patch document.
+func ParsePatch(raw string) (Patch, error) {
+	if NewRawValue(raw).Kind() != KindArray {
+		return nil, ErrPatchSyntax
+	}
+	items := jsonArrayItems(raw)
+	patch := make(Patch, 0, len(items))
+	for i, item := range items {
+		fields := jsonObjectFields(item)
+		op := PatchOp{
+			Op:   jsonUnquote(fields["op"]),
+			Path: jsonUnquote(fields["path"]),
+			From: jsonUnquote(fields["from"]),
+		}
+		value, hasValue := fields["value"]
+		op.Value = NewRawValue(value)
+		if err := op.check(hasValue); err != nil {
+			return nil, &PatchError{Index: i, Op: op, Err: err}
+		}
+		patch = append(patch, op)
+	}
+	return patch, nil
+}
+
+func (o PatchOp) check(hasValue bool) error {
+	switch o.Op {
+	case "add", "replace", "test":
+		if !hasValue {
+			return ErrPatchSyntax
+		}
+	case "move", "copy":
+		if _, err := splitPointer(o.From); err != nil {
+			return err
+		}
+	case "remove":
+	default:
+		return ErrPatchSyntax
+	}
+	_, err := splitPointer(o.Path)
+	return err
+}
+
+// ApplyPatch applies patch to doc and returns the result. Operations apply in
+// order; if one fails, the whole patch fails and doc is unchanged.
+func ApplyPatch(doc RawValue, patch Patch) (RawValue, error) {
+	root := parseTree(doc.Raw())
+	if root == nil {
+		return RawValue{}, ErrPatchSyntax
+	}
+	for i, op := range patch {
+		next, err := applyOp(root, op)
+		if err != nil {
+			return RawValue{}, &PatchError{Index: i, Op: op, Err: err}
+		}
+		root = next
+	}
+	return root.rawValue(), nil
+}
+
+func applyOp(root *jsonTree, op PatchOp) (*jsonTree, error) {
+	path, err := splitPointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch op.Op {
+	case "add", "replace", "test":
+		value := parseTree(op.Value.Raw())
+		if value == nil {
+			return nil, ErrPatchSyntax
+		}
+		switch op.Op {
+		case "add":
+			return addAt(root, path, value)
+		case "replace":
+			return replaceAt(root, path, value)
+		default:
+			current, ok := root.lookup(path)
+			if !ok || !current.equal(value) {
+				return nil, ErrPatchTest
+			}
+			return root, nil
+		}
+	case "remove":
+		return removeAt(root, path)
+	case "move", "copy":
+		from, err := splitPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, ok := root.lookup(from)
+		if !ok {
+			return nil, ErrPatchPath
+		}
+		if op.Op == "copy" {
+			return addAt(root, path, value.clone())
+		}
+		if op.Path == op.From {
+			return root, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, ErrPatchPath
+		}
+		if root, err = removeAt(root, from); err != nil {
+			return nil, err
+		}
+		return addAt(root, path, value)
+	}
+	return nil, ErrPatchSyntax
+}
+
+// splitPointer decodes a JSON Pointer into its reference tokens.
+func splitPointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, ErrPatchSyntax
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		if strings.IndexByte(t, '~') >= 0 {
+			tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+		}
+	}
+	return tokens, nil
+}
+
+// escapePointer encodes a single reference token.
+func escapePointer(token string) string {
+	if strings.IndexAny(token, "~/") < 0 {
+		return token
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// arrayIndex parses an array reference token. "-" is accepted as the end of
+// the array when allowEnd is set.
+func arrayIndex(token string, n int, allowEnd bool) (int, bool) {
+	if token == "-" {
+		return n, allowEnd
+	}
+	if token == "" || len(token) > 1 && token[0] == '0' {
+		return 0, false
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > n || i == n && !allowEnd {
+		return 0, false
+	}
+	return i, true
+}
+
+func (t *jsonTree) lookup(path []string) (*jsonTree, bool) {
+	for _, token := range path {
+		switch t.kind {
+		case KindObject:
+			child, ok := t.get(token)
+			if !ok {
+				return nil, false
+			}
+			t = child
+		case KindArray:
+			i, ok := arrayIndex(token, len(t.items), false)
+			if !ok {
+				return nil, false
+			}
+			t = t.items[i]
+		default:
+			return nil, false
+		}
+	}
+	return t, true
+}
+
+func addAt(root *jsonTree, path []string, value *jsonTree) (*jsonTree, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, ok := root.lookup(path[:len(path)-1])
+	if !ok {
+		return nil, ErrPatchPath
+	}
+	last := path[len(path)-1]
+	switch parent.kind {
+	case KindObject:
+		parent.set(last, value)
+	case KindArray:
+		i, ok := arrayIndex(last, len(parent.items), true)
+		if !ok {
+			return nil, ErrPatchPath
+		}
+		parent.items = append(parent.items, nil)
+		copy(parent.items[i+1:], parent.items[i:])
+		parent.items[i] = value
+	default:
+		return nil, ErrPatchPath
+	}
+	return root, nil
+}
+
+// replaceAt swaps an existing value in place, keeping its position.
+func replaceAt(root *jsonTree, path []string, value *jsonTree) (*jsonTree, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	if _, ok := root.lookup(path); !ok {
+		return nil, ErrPatchPath
+	}
+	parent, _ := root.lookup(path[:len(path)-1])
+	last := path[len(path)-1]
+	if parent.kind == KindArray {
+		i, _ := arrayIndex(last, len(parent.items), false)
+		parent.items[i] = value
+	} else {
+		parent.set(last, value)
+	}
+	return root, nil
+}
+
+func removeAt(root *jsonTree, path []string) (*jsonTree, error) {
+	if len(path) == 0 {
+		return nil, ErrPatchPath
+	}
+	parent, ok := root.lookup(path[:len(path)-1])
+	if !ok {
+		return nil, ErrPatchPath
+	}
+	last := path[len(path)-1]
+	switch parent.kind {
+	case KindObject:
+		if !parent.remove(last) {
+			return nil, ErrPatchPath
+		}
+	case KindArray:
+		i, ok := arrayIndex(last, len(parent.items), false)
+		if !ok {
+			return nil, ErrPatchPath
+		}
+		parent.items = append(parent.items[:i], parent.items[i+1:]...)
+	default:
+		return nil, ErrPatchPath
+	}
+	return root, nil
+}
+
+// DiffPatch returns a JSON Patch that turns from into to. Objects are
+// compared member by member and arrays element by element, so an element
+// inserted at the front of an array shows up as a run of replaces rather than
+// a single add. The result is empty when the documents are equal.
+func DiffPatch(from, to RawValue) Patch {
+	a, b := parseTree(from.Raw()), parseTree(to.Raw())
+	if b == nil {
+		return nil
+	}
+	if a == nil {
+		return Patch{{Op: "add", Path: "", Value: b.rawValue()}}
+	}
+	var patch Patch
+	diffTrees("", a, b, &patch)
+	return patch
+}
+
+func diffTrees(path string, a, b *jsonTree, patch *Patch) {
+	if a.equal(b) {
+		return
+	}
+	switch {
+	case a.kind == KindObject && b.kind == KindObject:
+		for _, k := range a.keys {
+			if _, ok := b.get(k); !ok {
+				*patch = append(*patch, PatchOp{Op: "remove", Path: path + "/" + escapePointer(k)})
+			}
+		}
+		for _, k := range b.keys {
+			p := path + "/" + escapePointer(k)
+			if av, ok := a.get(k); ok {
+				diffTrees(p, av, b.vals[k], patch)
+			} else {
+				*patch = append(*patch, PatchOp{Op: "add", Path: p, Value: b.vals[k].rawValue()})
+			}
+		}
+	case a.kind == KindArray && b.kind == KindArray:
+		n := len(a.items)
+		if len(b.items) < n {
+			n = len(b.items)
+		}
+		for i := 0; i < n; i++ {
+			diffTrees(path+"/"+strconv.Itoa(i), a.items[i], b.items[i], patch)
+		}
+		for i := len(a.items) - 1; i >= n; i-- {
+			*patch = append(*patch, PatchOp{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+		}
+		for i := n; i < len(b.items); i++ {
+			*patch = append(*patch, PatchOp{Op: "add", Path: path + "/" + strconv.Itoa(i), Value: b.items[i].rawValue()})
+		}
+	default:
+		*patch = append(*patch, PatchOp{Op: "replace", Path: path, Value: b.rawValue()})
+	}
+}
+
+// ApplyMergePatch applies an RFC 7386 JSON Merge Patch: members of patch
+// replace those of doc recursively, and null members delete them. A patch
+// that is not an object replaces doc entirely.
+func ApplyMergePatch(doc, patch RawValue) (RawValue, error) {
+	p := parseTree(patch.Raw())
+	if p == nil {
+		return RawValue{}, ErrPatchSyntax
+	}
+	return mergePatch(parseTree(doc.Raw()), p).rawValue(), nil
+}
+
+func mergePatch(target, patch *jsonTree) *jsonTree {
+	if patch.kind != KindObject {
+		return patch.clone()
+	}
+	if target == nil || target.kind != KindObject {
+		target = &jsonTree{kind: KindObject, vals: map[string]*jsonTree{}}
+	}
+	for _, k := range patch.keys {
+		v := patch.vals[k]
+		if v.kind == KindNull {
+			target.remove(k)
+			continue
+		}
+		current, _ := target.get(k)
+		target.set(k, mergePatch(current, v))
+	}
+	return target
+}
+
+// DiffMergePatch returns a JSON Merge Patch that turns from into to. Merge
+// patches cannot set a member to null, so such members of to are dropped by
+// the round trip; use DiffPatch when that matters.
+func DiffMergePatch(from, to RawValue) RawValue {
+	a, b := parseTree(from.Raw()), parseTree(to.Raw())
+	if b == nil {
+		return RawValue{}
+	}
+	if a == nil {
+		return b.rawValue()
+	}
+	return diffMerge(a, b).rawValue()
+}
+
+func diffMerge(a, b *jsonTree) *jsonTree {
+	if a.kind != KindObject || b.kind != KindObject {
+		return b
+	}
+	out := &jsonTree{kind: KindObject, vals: map[string]*jsonTree{}}
+	for _, k := range a.keys {
+		if _, ok := b.get(k); !ok {
+			out.set(k, &jsonTree{kind: KindNull, raw: "null"})
+		}
+	}
+	for _, k := range b.keys {
+		bv := b.vals[k]
+		av, ok := a.get(k)
+		switch {
+		case !ok:
+			out.set(k, bv)
+		case !av.equal(bv):
+			out.set(k, diffMerge(av, bv))
+		}
+	}
+	return out
+}
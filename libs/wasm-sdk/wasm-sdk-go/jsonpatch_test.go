@@ -0,0 +1,128 @@
+package sdk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		name, doc, patch, want string
+		err                    error
+	}{
+		{"add member", `{"foo":"bar"}`, `[{"op":"add","path":"/baz","value":"qux"}]`, `{"foo":"bar","baz":"qux"}`, nil},
+		{"add array element", `{"foo":["bar","baz"]}`, `[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`, nil},
+		{"append", `{"a":[1]}`, `[{"op":"add","path":"/a/-","value":2}]`, `{"a":[1,2]}`, nil},
+		{"remove", `{"baz":"qux","foo":"bar"}`, `[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`, nil},
+		{"replace", `{"baz":"qux"}`, `[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo"}`, nil},
+		{"move", `{"foo":{"waldo":"fred"},"qux":{}}`, `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, `{"foo":{},"qux":{"thud":"fred"}}`, nil},
+		{"copy", `{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"}]`, `{"a":{"b":1},"c":{"b":1}}`, nil},
+		{"test", `{"a":[1,{"b":"c"}]}`, `[{"op":"test","path":"/a/1","value":{"b":"c"}}]`, `{"a":[1,{"b":"c"}]}`, nil},
+		{"escaped", `{"a/b":1,"m~n":2}`, `[{"op":"remove","path":"/a~1b"},{"op":"replace","path":"/m~0n","value":3}]`, `{"m~n":3}`, nil},
+		{"whole document", `{"a":1}`, `[{"op":"replace","path":"","value":[1]}]`, `[1]`, nil},
+		{"missing member", `{"a":1}`, `[{"op":"remove","path":"/b"}]`, "", ErrPatchPath},
+		{"missing parent", `{"a":1}`, `[{"op":"add","path":"/b/c","value":1}]`, "", ErrPatchPath},
+		{"index out of range", `[1,2]`, `[{"op":"add","path":"/3","value":1}]`, "", ErrPatchPath},
+		{"leading zero index", `[1,2]`, `[{"op":"remove","path":"/01"}]`, "", ErrPatchPath},
+		{"test fails", `{"a":1}`, `[{"op":"test","path":"/a","value":2}]`, "", ErrPatchTest},
+		{"atomic", `{"a":1}`, `[{"op":"add","path":"/b","value":2},{"op":"remove","path":"/c"}]`, "", ErrPatchPath},
+		{"invalid document", `{"a":`, `[]`, "", ErrPatchSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := ParsePatch(tt.patch)
+			if err != nil {
+				t.Fatalf("ParsePatch: %v", err)
+			}
+			got, err := ApplyPatch(NewRawValue(tt.doc), patch)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ApplyPatch error = %v, want %v", err, tt.err)
+			}
+			if err == nil && got.Raw() != tt.want {
+				t.Errorf("ApplyPatch = %s, want %s", got.Raw(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePatchErrors(t *testing.T) {
+	for _, raw := range []string{
+		`{"op":"add"}`,
+		`[{"op":"frobnicate","path":"/a"}]`,
+		`[{"op":"add","path":"/a"}]`,
+		`[{"op":"remove","path":"a"}]`,
+		`[{"op":"copy","from":"x","path":"/a"}]`,
+	} {
+		if _, err := ParsePatch(raw); !errors.Is(err, ErrPatchSyntax) {
+			t.Errorf("ParsePatch(%s) error = %v, want ErrPatchSyntax", raw, err)
+		}
+	}
+	_, err := ParsePatch(`[{"op":"remove","path":"/a"},{"op":"add","path":"/b"}]`)
+	var pe *PatchError
+	if !errors.As(err, &pe) || pe.Index != 1 {
+		t.Errorf("ParsePatch error = %v, want PatchError at index 1", err)
+	}
+}
+
+func TestPatchRoundTrip(t *testing.T) {
+	docs := []string{
+		`{}`,
+		`{"a":1,"b":[1,2,3],"c":{"d":"e"}}`,
+		`{"a":2,"b":[1,3],"c":{"d":"f","g":null},"h":true}`,
+		`{"b":[0,1,2,3,4],"c":"flat"}`,
+		`[1,"two",{"three":3}]`,
+		`"scalar"`,
+	}
+	for _, from := range docs {
+		for _, to := range docs {
+			patch := DiffPatch(NewRawValue(from), NewRawValue(to))
+			parsed, err := ParsePatch(patch.ToJSON())
+			if err != nil {
+				t.Fatalf("ParsePatch(%s): %v", patch.ToJSON(), err)
+			}
+			got, err := ApplyPatch(NewRawValue(from), parsed)
+			if err != nil {
+				t.Fatalf("%s -> %s: ApplyPatch(%s): %v", from, to, patch.ToJSON(), err)
+			}
+			if want := parseTree(to); !parseTree(got.Raw()).equal(want) {
+				t.Errorf("%s -> %s: patch %s gives %s", from, to, patch.ToJSON(), got.Raw())
+			}
+			if from == to && len(patch) != 0 {
+				t.Errorf("DiffPatch of equal documents = %s", patch.ToJSON())
+			}
+		}
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct{ doc, patch, want string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+	}
+	for _, tt := range tests {
+		got, err := ApplyMergePatch(NewRawValue(tt.doc), NewRawValue(tt.patch))
+		if err != nil || got.Raw() != tt.want {
+			t.Errorf("ApplyMergePatch(%s, %s) = %s, %v, want %s", tt.doc, tt.patch, got.Raw(), err, tt.want)
+		}
+	}
+	if _, err := ApplyMergePatch(NewRawValue(`{}`), NewRawValue(``)); !errors.Is(err, ErrPatchSyntax) {
+		t.Errorf("invalid merge patch error = %v", err)
+	}
+
+	from, to := `{"a":1,"b":{"c":2,"d":3},"e":[1]}`, `{"a":1,"b":{"c":4},"e":[2],"f":"new"}`
+	patch := DiffMergePatch(NewRawValue(from), NewRawValue(to))
+	if want := `{"b":{"d":null,"c":4},"e":[2],"f":"new"}`; patch.Raw() != want {
+		t.Errorf("DiffMergePatch = %s, want %s", patch.Raw(), want)
+	}
+	got, _ := ApplyMergePatch(NewRawValue(from), patch)
+	if !parseTree(got.Raw()).equal(parseTree(to)) {
+		t.Errorf("merge patch round trip = %s, want %s", got.Raw(), to)
+	}
+}
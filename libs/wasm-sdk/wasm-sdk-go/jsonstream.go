@@ -0,0 +1,254 @@
+package sdk
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrJSONStream is returned by ArrayStream when the data is not a well-formed
+// JSON array.
+var ErrJSONStream = errors.New("json stream: malformed array")
+
+// ArrayStream iterates over the elements of a JSON array read from an
+// io.Reader, holding only the current element in memory. It lets nodes work
+// through arrays far larger than the WASM heap allows to decode at once:
+//
+//	s, err := ctx.Host().StreamStorageArray(path)
+//	if err != nil {
+//		return err
+//	}
+//	defer s.Close()
+//	for s.Next() {
+//		item := s.Value()
+//		...
+//	}
+//	if err := s.Err(); err != nil { ... }
+type ArrayStream struct {
+	r       io.Reader
+	buf     []byte
+	pos     int
+	eof     bool
+	started bool
+	done    bool
+	index   int
+	cur     RawValue
+	err     error
+}
+
+// NewArrayStream streams the elements of the JSON array in r.
+func NewArrayStream(r io.Reader) *ArrayStream {
+	return &ArrayStream{r: r, index: -1}
+}
+
+// Deprecated: Use c.Host().StreamInputArray, which reports a missing input.
+func (c *Context) StreamInputArray(name string) (*ArrayStream, bool) {
+	s, err := c.Host().StreamInputArray(name)
+	return s, err == nil
+}
+
+// Deprecated: Use c.Host().StreamStorageArray, which reports why the call
+// failed.
+func (c *Context) StreamStorageArray(path string) (*ArrayStream, bool) {
+	s, err := c.Host().StreamStorageArray(path)
+	return s, err == nil
+}
+
+// StreamInputArray streams the elements of an array input. The input itself
+// is already in memory, but elements are decoded one at a time instead of
+// being split into a slice up front. It fails with a *MissingInputError when
+// the input has no value.
+func (h HostCalls) StreamInputArray(name string) (*ArrayStream, error) {
+	raw, ok := h.c.lookup(name)
+	if !ok {
+		return nil, &MissingInputError{Pin: name}
+	}
+	return NewArrayStream(strings.NewReader(raw)), nil
+}
+
+// StreamStorageArray streams the elements of a JSON array stored in flow
+// storage. Close the stream to release the file handle.
+func (h HostCalls) StreamStorageArray(path string) (*ArrayStream, error) {
+	r, err := h.OpenStorageReader(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewArrayStream(r), nil
+}
+
+// Next advances to the next element and reports whether there is one. It
+// returns false at the end of the array or on error; check Err.
+func (s *ArrayStream) Next() bool {
+	if s.done || s.err != nil {
+		return false
+	}
+	c, ok := s.skipSpace()
+	if !s.started {
+		if !ok || c != '[' {
+			return s.fail()
+		}
+		s.pos++
+		s.started = true
+		if c, ok = s.skipSpace(); ok && c == ']' {
+			s.pos++
+			s.done = true
+			return false
+		}
+	} else {
+		switch {
+		case ok && c == ']':
+			s.pos++
+			s.done = true
+			return false
+		case ok && c == ',':
+			s.pos++
+			c, ok = s.skipSpace()
+		default:
+			return s.fail()
+		}
+	}
+	if !ok {
+		return s.fail()
+	}
+	raw, ok := s.readValue()
+	if !ok {
+		return s.fail()
+	}
+	s.cur = NewRawValue(raw)
+	s.index++
+	return true
+}
+
+// Value returns the current element.
+func (s *ArrayStream) Value() RawValue { return s.cur }
+
+// Index returns the position of the current element in the array.
+func (s *ArrayStream) Index() int { return s.index }
+
+// Err returns the first read or syntax error.
+func (s *ArrayStream) Err() error { return s.err }
+
+// Each calls fn for every remaining element, stopping at the first error.
+func (s *ArrayStream) Each(fn func(i int, v RawValue) error) error {
+	for s.Next() {
+		if err := fn(s.index, s.cur); err != nil {
+			return err
+		}
+	}
+	return s.err
+}
+
+// Close closes the underlying reader if it is an io.Closer.
+func (s *ArrayStream) Close() error {
+	s.done = true
+	s.buf = nil
+	if c, ok := s.r.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (s *ArrayStream) fail() bool {
+	if s.err == nil {
+		s.err = ErrJSONStream
+	}
+	return false
+}
+
+// fill reads more data, discarding everything before pos first so the buffer
+// only ever holds the element being decoded. It returns false at EOF.
+func (s *ArrayStream) fill() bool {
+	if s.eof {
+		return false
+	}
+	if s.pos > 0 {
+		n := copy(s.buf, s.buf[s.pos:])
+		s.buf = s.buf[:n]
+		s.pos = 0
+	}
+	if cap(s.buf)-len(s.buf) < storageChunkSize/2 {
+		grown := make([]byte, len(s.buf), 2*cap(s.buf)+storageChunkSize)
+		copy(grown, s.buf)
+		s.buf = grown
+	}
+	for {
+		n, err := s.r.Read(s.buf[len(s.buf):cap(s.buf)])
+		s.buf = s.buf[:len(s.buf)+n]
+		if err == io.EOF {
+			s.eof = true
+			return n > 0
+		}
+		if err != nil {
+			s.err = err
+			s.eof = true
+			return n > 0
+		}
+		if n > 0 {
+			return true
+		}
+	}
+}
+
+// skipSpace moves past whitespace and returns the next byte.
+func (s *ArrayStream) skipSpace() (byte, bool) {
+	for {
+		for s.pos < len(s.buf) {
+			switch c := s.buf[s.pos]; c {
+			case ' ', '\t', '\n', '\r':
+				s.pos++
+			default:
+				return c, true
+			}
+		}
+		if !s.fill() {
+			return 0, false
+		}
+	}
+}
+
+// readValue consumes the value starting at pos and returns its text.
+func (s *ArrayStream) readValue() (string, bool) {
+	depth, inString, escaped := 0, false, false
+	scalar := s.buf[s.pos] != '{' && s.buf[s.pos] != '[' && s.buf[s.pos] != '"'
+	for j := 0; ; j++ {
+		if s.pos+j >= len(s.buf) {
+			if !s.fill() {
+				return "", false
+			}
+		}
+		c := s.buf[s.pos+j]
+		switch {
+		case scalar:
+			if c == ',' || c == ']' || c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+				return s.take(j), true
+			}
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+				if depth == 0 {
+					return s.take(j + 1), true
+				}
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth == 0 {
+				return s.take(j + 1), true
+			}
+		}
+	}
+}
+
+func (s *ArrayStream) take(n int) string {
+	v := string(s.buf[s.pos : s.pos+n])
+	s.pos += n
+	return v
+}
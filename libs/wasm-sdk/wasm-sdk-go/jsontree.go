@@ -0,0 +1,205 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// jsonTree is a mutable, order-preserving decoding of a JSON document used by
+// the structural helpers (patch, merge). Scalars keep their raw text so
+// numbers round-trip exactly.
+type jsonTree struct {
+	kind  Kind
+	raw   string
+	keys  []string
+	vals  map[string]*jsonTree
+	items []*jsonTree
+}
+
+// parseTree decodes raw into a tree. It returns nil for invalid or empty
+// input.
+func parseTree(raw string) *jsonTree {
+	v := NewRawValue(raw)
+	switch k := v.Kind(); k {
+	case KindInvalid:
+		return nil
+	case KindObject:
+		t := &jsonTree{kind: k, vals: map[string]*jsonTree{}}
+		keys, vals := jsonObjectMembers(v.raw)
+		for i, key := range keys {
+			child := parseTree(vals[i])
+			if child == nil {
+				return nil
+			}
+			t.set(key, child)
+		}
+		return t
+	case KindArray:
+		t := &jsonTree{kind: k}
+		for _, item := range jsonArrayItems(v.raw) {
+			child := parseTree(item)
+			if child == nil {
+				return nil
+			}
+			t.items = append(t.items, child)
+		}
+		return t
+	default:
+		return &jsonTree{kind: k, raw: v.raw}
+	}
+}
+
+// jsonObjectMembers splits an object like jsonObjectFields but keeps member
+// order. Duplicate keys are reported as they appear.
+func jsonObjectMembers(s string) (keys, vals []string) {
+	j := jsonScanner{s: s}
+	j.skipWhitespace()
+	if j.idx >= len(s) || s[j.idx] != '{' {
+		return nil, nil
+	}
+	j.idx++
+	for j.idx < len(s) {
+		j.skipWhitespace()
+		if j.idx >= len(s) || s[j.idx] == '}' {
+			break
+		}
+		if s[j.idx] == ',' {
+			j.idx++
+			continue
+		}
+		key := jsonUnquote(j.readValue())
+		j.skipWhitespace()
+		if j.idx < len(s) && s[j.idx] == ':' {
+			j.idx++
+		}
+		keys = append(keys, key)
+		vals = append(vals, j.readValue())
+	}
+	return keys, vals
+}
+
+func (t *jsonTree) get(key string) (*jsonTree, bool) {
+	v, ok := t.vals[key]
+	return v, ok
+}
+
+// set adds or replaces an object member, keeping the position of an existing
+// key.
+func (t *jsonTree) set(key string, v *jsonTree) {
+	if _, ok := t.vals[key]; !ok {
+		t.keys = append(t.keys, key)
+	}
+	t.vals[key] = v
+}
+
+func (t *jsonTree) remove(key string) bool {
+	if _, ok := t.vals[key]; !ok {
+		return false
+	}
+	delete(t.vals, key)
+	for i, k := range t.keys {
+		if k == key {
+			t.keys = append(t.keys[:i], t.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (t *jsonTree) clone() *jsonTree {
+	c := &jsonTree{kind: t.kind, raw: t.raw}
+	if t.kind == KindObject {
+		c.vals = make(map[string]*jsonTree, len(t.vals))
+		c.keys = append([]string(nil), t.keys...)
+		for k, v := range t.vals {
+			c.vals[k] = v.clone()
+		}
+	}
+	for _, item := range t.items {
+		c.items = append(c.items, item.clone())
+	}
+	return c
+}
+
+// equal compares by JSON value: object member order is ignored, strings are
+// compared decoded and numbers numerically.
+func (t *jsonTree) equal(o *jsonTree) bool {
+	if t.kind != o.kind {
+		return false
+	}
+	switch t.kind {
+	case KindObject:
+		if len(t.keys) != len(o.keys) {
+			return false
+		}
+		for k, v := range t.vals {
+			ov, ok := o.vals[k]
+			if !ok || !v.equal(ov) {
+				return false
+			}
+		}
+		return true
+	case KindArray:
+		if len(t.items) != len(o.items) {
+			return false
+		}
+		for i := range t.items {
+			if !t.items[i].equal(o.items[i]) {
+				return false
+			}
+		}
+		return true
+	case KindString:
+		return t.raw == o.raw || jsonUnquote(t.raw) == jsonUnquote(o.raw)
+	case KindNumber:
+		if t.raw == o.raw {
+			return true
+		}
+		a, errA := strconv.ParseFloat(t.raw, 64)
+		b, errB := strconv.ParseFloat(o.raw, 64)
+		return errA == nil && errB == nil && a == b
+	default:
+		return t.raw == o.raw
+	}
+}
+
+func (t *jsonTree) encode(b *strings.Builder) {
+	switch t.kind {
+	case KindObject:
+		b.WriteByte('{')
+		for i, k := range t.keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(jsonString(k))
+			b.WriteByte(':')
+			t.vals[k].encode(b)
+		}
+		b.WriteByte('}')
+	case KindArray:
+		b.WriteByte('[')
+		for i, item := range t.items {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			item.encode(b)
+		}
+		b.WriteByte(']')
+	default:
+		b.WriteString(t.raw)
+	}
+}
+
+func (t *jsonTree) String() string {
+	var b strings.Builder
+	t.encode(&b)
+	return b.String()
+}
+
+// rawValue re-encodes the tree as a RawValue.
+func (t *jsonTree) rawValue() RawValue {
+	if t == nil {
+		return RawValue{}
+	}
+	return NewRawValue(t.String())
+}
@@ -0,0 +1,182 @@
+package sdk
+
+import "strconv"
+
+// KVScope namespaces the durable key-value store. Keys in different scopes
+// never collide.
+type KVScope string
+
+const (
+	KVApp   KVScope = "app"   // shared by every board of the app
+	KVBoard KVScope = "board" // shared by the nodes of one board
+	KVNode  KVScope = "node"  // private to this node instance
+	KVUser  KVScope = "user"  // per user, across the app
+)
+
+// KVAbsent as the expected version makes KVPutIf create a key only if it
+// does not exist yet.
+const KVAbsent int64 = 0
+
+// KVEntry is a stored value. Version starts at 1 and increases with every
+// write, for use with KVPutIf.
+type KVEntry struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Version int64  `json:"version"`
+}
+
+// KVGet reads a key from the durable store. Unlike the cache, entries are
+// persisted and never evicted. ok is false when the key does not exist.
+// Requires the "kv" permission.
+func KVGet(scope KVScope, key string) (KVEntry, bool) {
+	sp, sl := stringToPtr(string(scope))
+	kp, kl := stringToPtr(key)
+	packed := hostKVGet(sp, sl, kp, kl)
+	if packed == -1 {
+		return KVEntry{}, false
+	}
+	return parseKVEntryJSON(unpackString(packed)), true
+}
+
+// KVPut stores value under key and returns the new version.
+func KVPut(scope KVScope, key, value string) (int64, bool) {
+	return kvPut(scope, key, value, -1)
+}
+
+// KVPutIf stores value only if the key is currently at version (KVAbsent:
+// does not exist), and returns the new version. ok is false when another
+// writer got there first; re-read the entry and retry.
+func KVPutIf(scope KVScope, key, value string, version int64) (int64, bool) {
+	return kvPut(scope, key, value, version)
+}
+
+func kvPut(scope KVScope, key, value string, ifVersion int64) (int64, bool) {
+	sp, sl := stringToPtr(string(scope))
+	kp, kl := stringToPtr(key)
+	vp, vl := stringToPtr(value)
+	v := hostKVPut(sp, sl, kp, kl, vp, vl, ifVersion)
+	return v, v > 0
+}
+
+// KVDelete removes a key. Deleting a missing key succeeds.
+func KVDelete(scope KVScope, key string) bool {
+	sp, sl := stringToPtr(string(scope))
+	kp, kl := stringToPtr(key)
+	return hostKVDelete(sp, sl, kp, kl) != 0
+}
+
+// KVList returns up to limit entries whose keys start with prefix, in key
+// order, starting after cursor ("" for the first page). next is "" on the
+// last page.
+func KVList(scope KVScope, prefix string, limit int, cursor string) (entries []KVEntry, next string, ok bool) {
+	sp, sl := stringToPtr(string(scope))
+	pp, pl := stringToPtr(prefix)
+	cp, cl := stringToPtr(cursor)
+	packed := hostKVList(sp, sl, pp, pl, int32(limit), cp, cl)
+	if packed == -1 {
+		return nil, "", false
+	}
+	f := jsonObjectFields(unpackString(packed))
+	items := jsonArrayItems(f["entries"])
+	entries = make([]KVEntry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, parseKVEntryJSON(item))
+	}
+	return entries, jsonUnquote(f["cursor"]), true
+}
+
+// Deprecated: Use c.Host().KVGet, which reports why the call failed.
+func (c *Context) KVGet(scope KVScope, key string) (KVEntry, bool) {
+	e, err := c.Host().KVGet(scope, key)
+	return e, err == nil
+}
+
+// Deprecated: Use c.Host().KVPut, which reports why the call failed.
+func (c *Context) KVPut(scope KVScope, key, value string) (int64, bool) {
+	version, err := c.Host().KVPut(scope, key, value)
+	return version, err == nil
+}
+
+// Deprecated: Use c.Host().KVPutIf, which reports why the call failed.
+func (c *Context) KVPutIf(scope KVScope, key, value string, version int64) (int64, bool) {
+	next, err := c.Host().KVPutIf(scope, key, value, version)
+	return next, err == nil
+}
+
+// Deprecated: Use c.Host().KVDelete, which reports why the call failed.
+func (c *Context) KVDelete(scope KVScope, key string) bool {
+	return c.Host().KVDelete(scope, key) == nil
+}
+
+// Deprecated: Use c.Host().KVList, which reports why the call failed.
+func (c *Context) KVList(scope KVScope, prefix string, limit int, cursor string) ([]KVEntry, string, bool) {
+	entries, next, err := c.Host().KVList(scope, prefix, limit, cursor)
+	return entries, next, err == nil
+}
+
+// KVGet reads a key, failing with ErrNotFound when it does not exist.
+func (h HostCalls) KVGet(scope KVScope, key string) (KVEntry, error) {
+	if !h.c.host(PermKV) {
+		return KVEntry{}, dryRunSkipped("KVGet")
+	}
+	e, ok := KVGet(scope, key)
+	if !ok {
+		return KVEntry{}, hostFailure("KVGet", ErrCodeNotFound)
+	}
+	return e, nil
+}
+
+func (h HostCalls) KVPut(scope KVScope, key, value string) (int64, error) {
+	if !h.c.host(PermKV) {
+		return 0, dryRunSkipped("KVPut")
+	}
+	version, ok := KVPut(scope, key, value)
+	if !ok {
+		return 0, hostFailure("KVPut", ErrCodeInternal)
+	}
+	return version, nil
+}
+
+// KVPutIf writes only at the expected version, failing with ErrConflict when
+// the stored version differs.
+func (h HostCalls) KVPutIf(scope KVScope, key, value string, version int64) (int64, error) {
+	if !h.c.host(PermKV) {
+		return 0, dryRunSkipped("KVPutIf")
+	}
+	next, ok := KVPutIf(scope, key, value, version)
+	if !ok {
+		return 0, hostFailure("KVPutIf", ErrCodeConflict)
+	}
+	return next, nil
+}
+
+func (h HostCalls) KVDelete(scope KVScope, key string) error {
+	if !h.c.host(PermKV) {
+		return dryRunSkipped("KVDelete")
+	}
+	if !KVDelete(scope, key) {
+		return hostFailure("KVDelete", ErrCodeInternal)
+	}
+	return nil
+}
+
+func (h HostCalls) KVList(scope KVScope, prefix string, limit int, cursor string) ([]KVEntry, string, error) {
+	if !h.c.host(PermKV) {
+		return nil, "", dryRunSkipped("KVList")
+	}
+	entries, next, ok := KVList(scope, prefix, limit, cursor)
+	if !ok {
+		return nil, "", hostFailure("KVList", ErrCodeInternal)
+	}
+	return entries, next, nil
+}
+
+func parseKVEntryJSON(s string) KVEntry {
+	f := jsonObjectFields(s)
+	version, _ := strconv.ParseInt(f["version"], 10, 64)
+	return KVEntry{
+		Key:     jsonUnquote(f["key"]),
+		Value:   jsonUnquote(f["value"]),
+		Version: version,
+	}
+}
@@ -0,0 +1,99 @@
+package sdk
+
+// Initializer is implemented by handlers that need one-time setup per module
+// instance, such as building lookup tables or compiling templates. The
+// registry calls Init before the first run of any node.
+type Initializer interface {
+	Init() error
+}
+
+// Shutdowner is implemented by handlers that hold state to flush (buffers,
+// open writers) when the host recycles the module instance.
+type Shutdowner interface {
+	Shutdown()
+}
+
+// OnInit adds a module-level setup hook. Hooks run once per instance, in the
+// order added and before any handler's Init; the first error aborts setup and
+// every later run fails with it.
+func (r *Registry) OnInit(fn func() error) *Registry {
+	r.initHooks = append(r.initHooks, fn)
+	return r
+}
+
+// OnShutdown adds a module-level teardown hook. Hooks run after every
+// handler's Shutdown, in reverse order of registration.
+func (r *Registry) OnShutdown(fn func()) *Registry {
+	r.shutdownHooks = append(r.shutdownHooks, fn)
+	return r
+}
+
+// Init runs the setup hooks and handler initializers once. Later calls return
+// the first result. Execute calls it lazily, so modules also work on hosts
+// that never call the on_init export.
+func (r *Registry) Init() error {
+	if r.initDone {
+		return r.initErr
+	}
+	r.initDone = true
+	for _, fn := range r.initHooks {
+		if r.initErr = fn(); r.initErr != nil {
+			return r.initErr
+		}
+	}
+	for _, h := range r.handlers {
+		if i, ok := h.(Initializer); ok {
+			if r.initErr = i.Init(); r.initErr != nil {
+				return r.initErr
+			}
+		}
+	}
+	return nil
+}
+
+// Shutdown runs handler Shutdown methods and teardown hooks, then resets the
+// registry so a reused instance initializes again on its next run.
+func (r *Registry) Shutdown() {
+	if !r.initDone {
+		return
+	}
+	for i := len(r.handlers) - 1; i >= 0; i-- {
+		if s, ok := r.handlers[i].(Shutdowner); ok {
+			s.Shutdown()
+		}
+	}
+	for i := len(r.shutdownHooks) - 1; i >= 0; i-- {
+		r.shutdownHooks[i]()
+	}
+	r.initDone = false
+	r.initErr = nil
+}
+
+// InitInstance implements the on_init export. It returns 0 on success and 1
+// if setup failed, after logging the error.
+func (r *Registry) InitInstance() int32 {
+	if err := r.Init(); err != nil {
+		LogError("on_init failed: " + err.Error())
+		return 1
+	}
+	return 0
+}
+
+// ShutdownInstance implements the on_shutdown export.
+func (r *Registry) ShutdownInstance() int32 {
+	r.Shutdown()
+	return 0
+}
+
+func (a errorHandlerAdapter) Init() error {
+	if i, ok := a.h.(Initializer); ok {
+		return i.Init()
+	}
+	return nil
+}
+
+func (a errorHandlerAdapter) Shutdown() {
+	if s, ok := a.h.(Shutdowner); ok {
+		s.Shutdown()
+	}
+}
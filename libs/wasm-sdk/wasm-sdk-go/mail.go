@@ -0,0 +1,236 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MailAddress is a sender or recipient.
+type MailAddress struct {
+	Name    string `json:"name,omitempty"`
+	Address string `json:"address"`
+}
+
+// String formats the address as `Name <address>`, or the bare address.
+func (a MailAddress) String() string {
+	if a.Name == "" {
+		return a.Address
+	}
+	return a.Name + " <" + a.Address + ">"
+}
+
+// MailAttachment describes an attachment of a MailMessage. Its content is
+// not loaded; save it to flow storage with SaveMailAttachment.
+type MailAttachment struct {
+	ID          string `json:"id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Inline      bool   `json:"inline"`
+}
+
+// MailMessage is a message in a connected mailbox. Listings fill in the
+// envelope, Snippet and Attachments; Text and HTML are only set by
+// FetchMail. Date is in Unix milliseconds.
+type MailMessage struct {
+	ID          string           `json:"id"`
+	ThreadID    string           `json:"thread_id,omitempty"`
+	Folder      string           `json:"folder"`
+	From        MailAddress      `json:"from"`
+	To          []MailAddress    `json:"to"`
+	Cc          []MailAddress    `json:"cc,omitempty"`
+	Subject     string           `json:"subject"`
+	Date        int64            `json:"date"`
+	Snippet     string           `json:"snippet,omitempty"`
+	Text        string           `json:"text,omitempty"`
+	HTML        string           `json:"html,omitempty"`
+	Seen        bool             `json:"seen"`
+	Attachments []MailAttachment `json:"attachments,omitempty"`
+}
+
+// MailQuery filters a mailbox listing. Zero fields do not filter; Folder
+// defaults to the inbox and Limit to the host's page size.
+type MailQuery struct {
+	Folder     string `json:"folder,omitempty"`
+	From       string `json:"from,omitempty"`
+	Subject    string `json:"subject,omitempty"`
+	UnseenOnly bool   `json:"unseen_only,omitempty"`
+	Since      int64  `json:"since,omitempty"` // Unix milliseconds
+	Limit      int    `json:"limit,omitempty"`
+}
+
+func (q *MailQuery) ToJSON() string {
+	var b strings.Builder
+	b.WriteByte('{')
+	field := func(name, raw string) {
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		b.WriteString(jsonString(name))
+		b.WriteByte(':')
+		b.WriteString(raw)
+	}
+	if q.Folder != "" {
+		field("folder", jsonString(q.Folder))
+	}
+	if q.From != "" {
+		field("from", jsonString(q.From))
+	}
+	if q.Subject != "" {
+		field("subject", jsonString(q.Subject))
+	}
+	if q.UnseenOnly {
+		field("unseen_only", "true")
+	}
+	if q.Since != 0 {
+		field("since", strconv.FormatInt(q.Since, 10))
+	}
+	if q.Limit != 0 {
+		field("limit", strconv.Itoa(q.Limit))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// ListMail returns the messages of a connected mailbox matching q, newest
+// first. mailbox is the connection name from the platform's mail
+// integration. ok is false when the mailbox is not connected or the server
+// failed. Requires the "mail" permission.
+func ListMail(mailbox string, q MailQuery) ([]MailMessage, bool) {
+	mp, ml := stringToPtr(mailbox)
+	qp, ql := stringToPtr(q.ToJSON())
+	packed := hostMailList(mp, ml, qp, ql)
+	if packed == -1 {
+		return nil, false
+	}
+	items := jsonArrayItems(unpackString(packed))
+	msgs := make([]MailMessage, 0, len(items))
+	for _, item := range items {
+		if m, ok := parseMailMessageJSON(item); ok {
+			msgs = append(msgs, m)
+		}
+	}
+	return msgs, true
+}
+
+// FetchMail returns a message with its text and HTML bodies.
+func FetchMail(mailbox, id string) (MailMessage, bool) {
+	mp, ml := stringToPtr(mailbox)
+	ip, il := stringToPtr(id)
+	packed := hostMailFetch(mp, ml, ip, il)
+	if packed == -1 {
+		return MailMessage{}, false
+	}
+	return parseMailMessageJSON(unpackString(packed))
+}
+
+// SaveMailAttachment copies an attachment of message id into flow storage
+// at path.
+func SaveMailAttachment(mailbox, id, attachmentID, path string) bool {
+	mp, ml := stringToPtr(mailbox)
+	ip, il := stringToPtr(id)
+	ap, al := stringToPtr(attachmentID)
+	lp, ll := stringToPtr(path)
+	return hostMailSaveAttachment(mp, ml, ip, il, ap, al, lp, ll) != 0
+}
+
+// Deprecated: Use c.Host().ListMail, which reports why the call failed.
+func (c *Context) ListMail(mailbox string, q MailQuery) ([]MailMessage, bool) {
+	messages, err := c.Host().ListMail(mailbox, q)
+	return messages, err == nil
+}
+
+// Deprecated: Use c.Host().FetchMail, which reports why the call failed.
+func (c *Context) FetchMail(mailbox, id string) (MailMessage, bool) {
+	m, err := c.Host().FetchMail(mailbox, id)
+	return m, err == nil
+}
+
+// Deprecated: Use c.Host().SaveMailAttachment, which reports why the call
+// failed.
+func (c *Context) SaveMailAttachment(mailbox, id, attachmentID, path string) bool {
+	return c.Host().SaveMailAttachment(mailbox, id, attachmentID, path) == nil
+}
+
+func (h HostCalls) ListMail(mailbox string, q MailQuery) ([]MailMessage, error) {
+	if !h.c.host(PermMail) {
+		return nil, dryRunSkipped("ListMail")
+	}
+	messages, ok := ListMail(mailbox, q)
+	if !ok {
+		return nil, hostFailure("ListMail", ErrCodeUpstream)
+	}
+	return messages, nil
+}
+
+// FetchMail fails with ErrNotFound when the message does not exist.
+func (h HostCalls) FetchMail(mailbox, id string) (MailMessage, error) {
+	if !h.c.host(PermMail) {
+		return MailMessage{}, dryRunSkipped("FetchMail")
+	}
+	m, ok := FetchMail(mailbox, id)
+	if !ok {
+		return MailMessage{}, hostFailure("FetchMail", ErrCodeNotFound)
+	}
+	return m, nil
+}
+
+func (h HostCalls) SaveMailAttachment(mailbox, id, attachmentID, path string) error {
+	if !h.c.host(PermMail) {
+		return dryRunSkipped("SaveMailAttachment")
+	}
+	if !SaveMailAttachment(mailbox, id, attachmentID, path) {
+		return hostFailure("SaveMailAttachment", ErrCodeNotFound)
+	}
+	return nil
+}
+
+func parseMailMessageJSON(s string) (MailMessage, bool) {
+	f := jsonObjectFields(s)
+	if f == nil {
+		return MailMessage{}, false
+	}
+	date, _ := strconv.ParseInt(f["date"], 10, 64)
+	m := MailMessage{
+		ID:       jsonUnquote(f["id"]),
+		ThreadID: jsonUnquote(f["thread_id"]),
+		Folder:   jsonUnquote(f["folder"]),
+		From:     parseMailAddressJSON(f["from"]),
+		To:       parseMailAddressesJSON(f["to"]),
+		Cc:       parseMailAddressesJSON(f["cc"]),
+		Subject:  jsonUnquote(f["subject"]),
+		Date:     date,
+		Snippet:  jsonUnquote(f["snippet"]),
+		Text:     jsonUnquote(f["text"]),
+		HTML:     jsonUnquote(f["html"]),
+		Seen:     f["seen"] == "true",
+	}
+	for _, item := range jsonArrayItems(f["attachments"]) {
+		a := jsonObjectFields(item)
+		if a == nil {
+			continue
+		}
+		size, _ := strconv.ParseInt(a["size"], 10, 64)
+		m.Attachments = append(m.Attachments, MailAttachment{
+			ID:          jsonUnquote(a["id"]),
+			Filename:    jsonUnquote(a["filename"]),
+			ContentType: jsonUnquote(a["content_type"]),
+			Size:        size,
+			Inline:      a["inline"] == "true",
+		})
+	}
+	return m, true
+}
+
+func parseMailAddressJSON(s string) MailAddress {
+	f := jsonObjectFields(s)
+	return MailAddress{Name: jsonUnquote(f["name"]), Address: jsonUnquote(f["address"])}
+}
+
+func parseMailAddressesJSON(s string) []MailAddress {
+	var out []MailAddress
+	for _, item := range jsonArrayItems(s) {
+		out = append(out, parseMailAddressJSON(item))
+	}
+	return out
+}
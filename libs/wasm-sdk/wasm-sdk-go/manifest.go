@@ -0,0 +1,121 @@
+package sdk
+
+import "strings"
+
+// Build metadata, meant to be injected at build time, e.g.
+//
+//	tinygo build -ldflags "-X github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go.BuildCommit=$(git rev-parse HEAD)" ...
+//
+// Registry.SetManifest uses them for fields the manifest leaves empty.
+var (
+	BuildVersion string
+	BuildCommit  string
+	BuildTime    string
+)
+
+// SDKVersion is the version of this SDK, recorded in every manifest.
+const SDKVersion = "0.1.0"
+
+// Manifest describes who built a module and from what. It is served by the
+// get_manifest export; `flowlike-gen sign` embeds it, together with a hash of
+// the module, in a signed custom section so hosts can verify provenance.
+type Manifest struct {
+	Module     string `json:"module"`
+	Version    string `json:"version"`
+	Author     string `json:"author,omitempty"`
+	Homepage   string `json:"homepage,omitempty"`
+	Commit     string `json:"commit,omitempty"`
+	BuiltAt    string `json:"built_at,omitempty"`
+	SDKVersion string `json:"sdk_version"`
+	// Changelogs collects the changelog of every registered node that has
+	// one; Registry.Manifest fills it.
+	Changelogs []NodeChangelog `json:"changelogs,omitempty"`
+}
+
+// NodeChangelog is the changelog of one node of a module.
+type NodeChangelog struct {
+	Node    string           `json:"node"`
+	Entries []ChangelogEntry `json:"entries"`
+}
+
+func (m *Manifest) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"module":`)
+	b.WriteString(jsonString(m.Module))
+	b.WriteString(`,"version":`)
+	b.WriteString(jsonString(m.Version))
+	if m.Author != "" {
+		b.WriteString(`,"author":`)
+		b.WriteString(jsonString(m.Author))
+	}
+	if m.Homepage != "" {
+		b.WriteString(`,"homepage":`)
+		b.WriteString(jsonString(m.Homepage))
+	}
+	if m.Commit != "" {
+		b.WriteString(`,"commit":`)
+		b.WriteString(jsonString(m.Commit))
+	}
+	if m.BuiltAt != "" {
+		b.WriteString(`,"built_at":`)
+		b.WriteString(jsonString(m.BuiltAt))
+	}
+	b.WriteString(`,"sdk_version":`)
+	b.WriteString(jsonString(m.SDKVersion))
+	if len(m.Changelogs) > 0 {
+		b.WriteString(`,"changelogs":[`)
+		for i, c := range m.Changelogs {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(`{"node":`)
+			b.WriteString(jsonString(c.Node))
+			b.WriteString(`,"entries":`)
+			b.WriteString(changelogJSON(c.Entries))
+			b.WriteByte('}')
+		}
+		b.WriteByte(']')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// SetManifest sets the module manifest, filling Version, Commit and BuiltAt
+// from the Build* variables when left empty.
+func (r *Registry) SetManifest(m Manifest) *Registry {
+	if m.Version == "" {
+		m.Version = BuildVersion
+	}
+	if m.Commit == "" {
+		m.Commit = BuildCommit
+	}
+	if m.BuiltAt == "" {
+		m.BuiltAt = BuildTime
+	}
+	m.SDKVersion = SDKVersion
+	r.manifest = &m
+	return r
+}
+
+// Manifest returns the module manifest with the changelogs of the
+// registered nodes. Without SetManifest it is derived from the Build*
+// variables alone.
+func (r *Registry) Manifest() Manifest {
+	m := Manifest{Version: BuildVersion, Commit: BuildCommit, BuiltAt: BuildTime, SDKVersion: SDKVersion}
+	if r.manifest != nil {
+		m = *r.manifest
+	}
+	m.Changelogs = nil
+	for i := range r.defs {
+		if len(r.defs[i].Changelog) > 0 {
+			m.Changelogs = append(m.Changelogs, NodeChangelog{Node: r.defs[i].Name, Entries: r.defs[i].Changelog})
+		}
+	}
+	return m
+}
+
+// GetManifest implements the get_manifest export.
+func (r *Registry) GetManifest() int64 {
+	m := r.Manifest()
+	return PackResult(m.ToJSON())
+}
@@ -24,6 +24,14 @@ func stringToPtr(s string) (uint32, uint32) {
 	return uint32(uintptr(unsafe.Pointer(&b[0]))), uint32(len(b))
 }
 
+// bytesPtr returns the pointer to a byte slice's backing array.
+func bytesPtr(b []byte) uint32 {
+	if len(b) == 0 {
+		return 0
+	}
+	return uint32(uintptr(unsafe.Pointer(&b[0])))
+}
+
 // ptrToString reads a string from a wasm pointer and length.
 func ptrToString(ptr uint32, length uint32) string {
 	if ptr == 0 || length == 0 {
@@ -0,0 +1,169 @@
+// Package modelcache serves repeated chat completions from a cache instead of
+// calling the model again. Entries are keyed by a hash of the model bit, the
+// messages and every request parameter, so any change to the prompt, the
+// temperature, the token limit or the tools is a miss:
+//
+//	var answers = modelcache.Cache{Namespace: "faq", TTL: 24 * time.Hour}
+//
+//	resp, ok := answers.ChatComplete(ctx, bit, req)
+//
+// Only cache nodes whose answers may be reused: low-temperature extraction,
+// classification or summarization of the same input. Sampling at a high
+// temperature is usually meant to produce different answers.
+package modelcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// DefaultMaxEntryBytes bounds a cached response when MaxEntryBytes is 0.
+const DefaultMaxEntryBytes = 256 * 1024
+
+// Store selects where entries are kept.
+type Store int
+
+const (
+	// StoreCache keeps entries in the host cache (the default). It is fast
+	// and the host may evict entries at any time.
+	StoreCache Store = iota
+	// StoreKV keeps entries in the durable key-value store, so they survive
+	// restarts and are shared by every instance in Scope.
+	StoreKV
+)
+
+// Cache configures a model call cache. The zero value caches in the host
+// cache without expiry.
+type Cache struct {
+	// Namespace prefixes every key; empty uses "modelcache". Change it to
+	// drop all entries at once, e.g. after a prompt template change.
+	Namespace string
+	// Store selects the backing store.
+	Store Store
+	// Scope is the KV scope used with StoreKV; empty uses sdk.KVApp.
+	Scope sdk.KVScope
+	// TTL is how long an entry is served; 0 keeps it until evicted.
+	TTL time.Duration
+	// MaxEntryBytes skips caching responses whose encoding is larger; 0
+	// uses DefaultMaxEntryBytes.
+	MaxEntryBytes int
+}
+
+// Key returns the cache key of a request: Namespace and the hex SHA-256 of
+// the bit and the encoded request.
+func (c *Cache) Key(bitJSON string, req sdk.ChatRequest) string {
+	ns := c.Namespace
+	if ns == "" {
+		ns = "modelcache"
+	}
+	sum := sha256.Sum256([]byte(bitJSON + "\x00" + req.ToJSON()))
+	return ns + ":" + hex.EncodeToString(sum[:])
+}
+
+// ChatComplete returns the cached response for req or, on a miss, calls the
+// model and caches a successful response. ok is false if the model call
+// failed.
+func (c *Cache) ChatComplete(ctx *sdk.Context, bitJSON string, req sdk.ChatRequest) (sdk.ChatResponse, bool) {
+	key := c.Key(bitJSON, req)
+	if resp, hit := c.get(ctx, key); hit {
+		ctx.Debug("modelcache: hit " + key)
+		return resp, true
+	}
+	resp, err := ctx.Host().ChatComplete(bitJSON, req)
+	if err != nil {
+		return resp, false
+	}
+	c.put(ctx, key, resp)
+	return resp, true
+}
+
+// Get returns the cached response for req, if there is a live entry.
+func (c *Cache) Get(ctx *sdk.Context, bitJSON string, req sdk.ChatRequest) (sdk.ChatResponse, bool) {
+	return c.get(ctx, c.Key(bitJSON, req))
+}
+
+// Put caches resp as the response to req. It reports false when the entry
+// was too large or could not be stored.
+func (c *Cache) Put(ctx *sdk.Context, bitJSON string, req sdk.ChatRequest, resp sdk.ChatResponse) bool {
+	return c.put(ctx, c.Key(bitJSON, req), resp)
+}
+
+// Invalidate removes the entry for req.
+func (c *Cache) Invalidate(ctx *sdk.Context, bitJSON string, req sdk.ChatRequest) {
+	c.delete(ctx, c.Key(bitJSON, req))
+}
+
+// Entries are stored as {"expires":<unix ms, 0 for never>,"response":{...}}.
+func (c *Cache) get(ctx *sdk.Context, key string) (sdk.ChatResponse, bool) {
+	var raw string
+	if c.Store == StoreKV {
+		entry, err := ctx.Host().KVGet(c.scope(), key)
+		if err != nil {
+			return sdk.ChatResponse{}, false
+		}
+		raw = entry.Value
+	} else {
+		raw = ctx.CacheGet(key)
+	}
+	if raw == "" {
+		return sdk.ChatResponse{}, false
+	}
+	entry := sdk.NewRawValue(raw)
+	expires, _ := entry.Field("expires")
+	if ms, err := strconv.ParseInt(expires.Raw(), 10, 64); err == nil && ms > 0 && ctx.TimeNow() >= ms {
+		c.delete(ctx, key)
+		return sdk.ChatResponse{}, false
+	}
+	body, ok := entry.Field("response")
+	if !ok {
+		return sdk.ChatResponse{}, false
+	}
+	return sdk.ParseChatResponse(body.Raw())
+}
+
+func (c *Cache) put(ctx *sdk.Context, key string, resp sdk.ChatResponse) bool {
+	var expires int64
+	if c.TTL > 0 {
+		expires = ctx.TimeNow() + c.TTL.Milliseconds()
+	}
+	var b strings.Builder
+	b.WriteString(`{"expires":`)
+	b.WriteString(strconv.FormatInt(expires, 10))
+	b.WriteString(`,"response":`)
+	b.WriteString(resp.ToJSON())
+	b.WriteByte('}')
+	limit := c.MaxEntryBytes
+	if limit <= 0 {
+		limit = DefaultMaxEntryBytes
+	}
+	if b.Len() > limit {
+		ctx.Debug("modelcache: response of " + strconv.Itoa(b.Len()) + " bytes not cached")
+		return false
+	}
+	if c.Store == StoreKV {
+		_, err := ctx.Host().KVPut(c.scope(), key, b.String())
+		return err == nil
+	}
+	ctx.CacheSet(key, b.String())
+	return true
+}
+
+func (c *Cache) delete(ctx *sdk.Context, key string) {
+	if c.Store == StoreKV {
+		ctx.Host().KVDelete(c.scope(), key)
+		return
+	}
+	ctx.CacheDelete(key)
+}
+
+func (c *Cache) scope() sdk.KVScope {
+	if c.Scope == "" {
+		return sdk.KVApp
+	}
+	return c.Scope
+}
@@ -0,0 +1,244 @@
+// Package ndjson reads and writes newline-delimited JSON (JSON Lines), one
+// value per line, streaming over flow storage so log and ETL nodes can
+// exchange datasets far larger than the WASM heap.
+//
+// Readers skip blank lines and collect malformed records instead of failing
+// the whole file; set Reader.Strict to stop at the first bad record.
+package ndjson
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+var (
+	ErrRecord      = errors.New("ndjson: invalid record")
+	ErrLineTooLong = errors.New("ndjson: line too long")
+)
+
+// DefaultMaxLineSize bounds the memory a single record may take.
+const DefaultMaxLineSize = 16 << 20
+
+// maxRawLen is how much of a bad record RecordError keeps.
+const maxRawLen = 128
+
+// RecordError describes a line that could not be read as JSON. It unwraps to
+// ErrRecord or ErrLineTooLong.
+type RecordError struct {
+	Line int
+	Raw  string
+	Err  error
+}
+
+func (e *RecordError) Error() string {
+	return e.Err.Error() + " on line " + strconv.Itoa(e.Line)
+}
+
+func (e *RecordError) Unwrap() error { return e.Err }
+
+// Reader iterates over the records of an NDJSON stream.
+type Reader struct {
+	// Strict stops reading at the first malformed record, reporting it via
+	// Err instead of collecting it in Errors.
+	Strict bool
+	// MaxLineSize limits the length of a record; longer lines are reported as
+	// ErrLineTooLong. Zero means DefaultMaxLineSize.
+	MaxLineSize int
+
+	r      *bufio.Reader
+	closer io.Closer
+	line   int
+	cur    sdk.RawValue
+	errs   []*RecordError
+	err    error
+	done   bool
+}
+
+// NewReader reads records from r.
+func NewReader(r io.Reader) *Reader {
+	rd := &Reader{r: bufio.NewReaderSize(r, 64*1024)}
+	if c, ok := r.(io.Closer); ok {
+		rd.closer = c
+	}
+	return rd
+}
+
+// Open streams an NDJSON file from flow storage. It requires the "storage"
+// permission.
+func Open(ctx *sdk.Context, path string) (*Reader, bool) {
+	r, err := ctx.Host().OpenStorageReader(path)
+	if err != nil {
+		return nil, false
+	}
+	return NewReader(r), true
+}
+
+// Next advances to the next valid record and reports whether there is one.
+func (r *Reader) Next() bool {
+	for !r.done && r.err == nil {
+		line, long, err := r.readLine()
+		if err != nil {
+			if err != io.EOF {
+				r.err = err
+			}
+			r.done = true
+			if line == "" && !long {
+				return false
+			}
+		}
+		r.line++
+		if long {
+			r.record(&RecordError{Line: r.line, Err: ErrLineTooLong})
+			continue
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !validJSON(line) {
+			raw := line
+			if len(raw) > maxRawLen {
+				raw = raw[:maxRawLen] + "…"
+			}
+			r.record(&RecordError{Line: r.line, Raw: raw, Err: ErrRecord})
+			continue
+		}
+		r.cur = sdk.NewRawValue(line)
+		return true
+	}
+	return false
+}
+
+// readLine returns the next line. Lines longer than MaxLineSize are
+// discarded and reported with long set.
+func (r *Reader) readLine() (line string, long bool, err error) {
+	max := r.MaxLineSize
+	if max <= 0 {
+		max = DefaultMaxLineSize
+	}
+	var b []byte
+	for {
+		var chunk []byte
+		chunk, err = r.r.ReadSlice('\n')
+		if len(b)+len(chunk) > max {
+			for err == bufio.ErrBufferFull {
+				_, err = r.r.ReadSlice('\n')
+			}
+			return "", true, err
+		}
+		b = append(b, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		return string(b), false, err
+	}
+}
+
+func (r *Reader) record(e *RecordError) {
+	if r.Strict {
+		r.err = e
+		return
+	}
+	r.errs = append(r.errs, e)
+}
+
+// Value returns the current record.
+func (r *Reader) Value() sdk.RawValue { return r.cur }
+
+// Line returns the 1-based line number of the current record.
+func (r *Reader) Line() int { return r.line }
+
+// Errors returns the malformed records skipped so far.
+func (r *Reader) Errors() []*RecordError { return r.errs }
+
+// Err returns the read error that stopped iteration, or in strict mode the
+// first malformed record.
+func (r *Reader) Err() error { return r.err }
+
+// Close closes the underlying reader if it is an io.Closer.
+func (r *Reader) Close() error {
+	r.done = true
+	if r.closer != nil {
+		return r.closer.Close()
+	}
+	return nil
+}
+
+// Writer writes one JSON value per line.
+type Writer struct {
+	w      *bufio.Writer
+	closer io.Closer
+	count  int
+}
+
+// NewWriter writes records to w.
+func NewWriter(w io.Writer) *Writer {
+	wr := &Writer{w: bufio.NewWriterSize(w, 64*1024)}
+	if c, ok := w.(io.Closer); ok {
+		wr.closer = c
+	}
+	return wr
+}
+
+// Create streams an NDJSON file into flow storage; the file appears once
+// Close succeeds. It requires the "storage" permission.
+func Create(ctx *sdk.Context, path string) (*Writer, bool) {
+	w, err := ctx.Host().CreateStorageWriter(path)
+	if err != nil {
+		return nil, false
+	}
+	return NewWriter(w), true
+}
+
+// WriteRaw writes an encoded JSON value as one record. Line breaks in
+// pretty-printed JSON are whitespace and are replaced; values that are not
+// valid JSON are rejected with ErrRecord.
+func (w *Writer) WriteRaw(raw string) error {
+	raw = strings.TrimSpace(raw)
+	if !validJSON(raw) {
+		return &RecordError{Line: w.count + 1, Err: ErrRecord}
+	}
+	if strings.ContainsAny(raw, "\r\n") {
+		raw = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(raw)
+	}
+	if _, err := w.w.WriteString(raw); err != nil {
+		return err
+	}
+	if err := w.w.WriteByte('\n'); err != nil {
+		return err
+	}
+	w.count++
+	return nil
+}
+
+// WriteValue writes a RawValue as one record.
+func (w *Writer) WriteValue(v sdk.RawValue) error {
+	return w.WriteRaw(v.Raw())
+}
+
+// Write writes any SDK type with a ToJSON method as one record.
+func (w *Writer) Write(v interface{ ToJSON() string }) error {
+	return w.WriteRaw(v.ToJSON())
+}
+
+// Count returns the number of records written.
+func (w *Writer) Count() int { return w.count }
+
+// Flush writes buffered records to the underlying writer.
+func (w *Writer) Flush() error { return w.w.Flush() }
+
+// Close flushes and closes the underlying writer if it is an io.Closer.
+func (w *Writer) Close() error {
+	err := w.w.Flush()
+	if w.closer != nil {
+		if cerr := w.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package ndjson
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReader(t *testing.T) {
+	input := "{\"a\":1}\n\n  [1, 2]  \r\nnot json\n\"str\"\n{\"a\":\n" + strings.Repeat("x", 40) + "\nnull"
+	r := NewReader(strings.NewReader(input))
+	r.MaxLineSize = 32
+	var got []string
+	var lines []int
+	for r.Next() {
+		got = append(got, r.Value().Raw())
+		lines = append(lines, r.Line())
+	}
+	if r.Err() != nil {
+		t.Fatalf("Err = %v", r.Err())
+	}
+	want := []string{`{"a":1}`, `[1, 2]`, `"str"`, `null`}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("records = %q, want %q", got, want)
+	}
+	if wantLines := []int{1, 3, 5, 8}; len(lines) != 4 || lines[0] != wantLines[0] || lines[1] != wantLines[1] || lines[2] != wantLines[2] || lines[3] != wantLines[3] {
+		t.Errorf("lines = %v, want %v", lines, wantLines)
+	}
+
+	errs := r.Errors()
+	if len(errs) != 3 {
+		t.Fatalf("Errors = %v, want 3", errs)
+	}
+	tests := []struct {
+		line int
+		raw  string
+		err  error
+	}{
+		{4, "not json", ErrRecord},
+		{6, `{"a":`, ErrRecord},
+		{7, "", ErrLineTooLong},
+	}
+	for i, tt := range tests {
+		if e := errs[i]; e.Line != tt.line || e.Raw != tt.raw || !errors.Is(e, tt.err) {
+			t.Errorf("Errors[%d] = %+v, want line %d %q %v", i, e, tt.line, tt.raw, tt.err)
+		}
+	}
+}
+
+func TestReaderStrict(t *testing.T) {
+	r := NewReader(strings.NewReader("1\n{bad}\n2\n"))
+	r.Strict = true
+	n := 0
+	for r.Next() {
+		n++
+	}
+	var re *RecordError
+	if n != 1 || !errors.As(r.Err(), &re) || re.Line != 2 || len(r.Errors()) != 0 {
+		t.Errorf("strict read: %d records, Err = %v", n, r.Err())
+	}
+}
+
+func TestValidJSON(t *testing.T) {
+	valid := []string{`0`, `-1.5e+3`, `"a\"é"`, `true`, `[]`, `{}`, `{"a":[1,{"b":null}]}`, ` [ 1 , 2 ] `}
+	invalid := []string{``, `01`, `1.`, `.5`, `+1`, `"unterminated`, `[1,]`, `{"a"}`, `{a:1}`, `[1] 2`, `tru`, `nul`,
+		strings.Repeat("[", 1000) + strings.Repeat("]", 1000)}
+	for _, s := range valid {
+		if !validJSON(s) {
+			t.Errorf("validJSON(%q) = false", s)
+		}
+	}
+	for _, s := range invalid {
+		if validJSON(s) {
+			t.Errorf("validJSON(%q) = true", s)
+		}
+	}
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	var b strings.Builder
+	w := NewWriter(&b)
+	records := []string{"{\n  \"a\": 1\n}", `[1,2]`, ` "x" `, `null`}
+	for _, rec := range records {
+		if err := w.WriteRaw(rec); err != nil {
+			t.Fatalf("WriteRaw(%q): %v", rec, err)
+		}
+	}
+	if err := w.WriteRaw("{oops"); !errors.Is(err, ErrRecord) {
+		t.Errorf("WriteRaw(invalid) error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w.Count() != 4 {
+		t.Errorf("Count = %d", w.Count())
+	}
+	want := "{   \"a\": 1 }\n[1,2]\n\"x\"\nnull\n"
+	if b.String() != want {
+		t.Errorf("output = %q, want %q", b.String(), want)
+	}
+
+	r := NewReader(strings.NewReader(b.String()))
+	n := 0
+	for r.Next() {
+		n++
+	}
+	if n != 4 || len(r.Errors()) != 0 {
+		t.Errorf("read back %d records, errors %v", n, r.Errors())
+	}
+}
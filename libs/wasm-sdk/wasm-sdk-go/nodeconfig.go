@@ -0,0 +1,84 @@
+package sdk
+
+import "strings"
+
+// SetConfig gives the node a configuration form, filled in per node instance
+// in the board editor instead of through input pins. SecretRefField and
+// OAuthProviderRefField render pickers bound to the platform's credentials,
+// so boards store references instead of keys:
+//
+//	def.SetConfig(sdk.NewForm("Acme").
+//		AddField(sdk.SecretRefField("api_key", "API key").Required()).
+//		AddField(sdk.OAuthProviderRefField("account", "Account", "google")))
+//
+// Read the values with Context.GetConfig.
+func (n *NodeDefinition) SetConfig(form *FormDefinition) *NodeDefinition {
+	n.Config = form
+	return n
+}
+
+// NodeConfig returns the node instance's configuration with every credential
+// reference resolved by the host. It fails with ErrNotFound while the node
+// is not configured and with ErrPermissionDenied when the board may not use
+// a referenced credential.
+func (h HostCalls) NodeConfig() (RawValue, error) {
+	if !h.c.host("") {
+		return RawValue{}, dryRunSkipped("NodeConfig")
+	}
+	config := GetNodeConfig()
+	if config == "" {
+		return RawValue{}, hostFailure("NodeConfig", ErrCodeNotFound)
+	}
+	return NewRawValue(config), nil
+}
+
+// GetConfig reads the node instance's configuration (see SetConfig) into
+// dst. Secret and OAuth references arrive resolved to the secret's value and
+// an access token; the board itself only stores the references. The resolved
+// values are masked in the messages of Debug, Info, Warn and Error and in
+// the error of the run's result, but nodes must still keep them out of
+// outputs and streamed text.
+func (c *Context) GetConfig(dst ConfigDecoder) error {
+	config, err := c.Host().NodeConfig()
+	if err != nil {
+		return err
+	}
+	if c.def != nil {
+		c.registerSecrets(c.def.Config, config)
+	}
+	return dst.DecodeConfig(config)
+}
+
+// registerSecrets remembers the resolved values of the secret and OAuth
+// fields of form in config, so redact masks them.
+func (c *Context) registerSecrets(form *FormDefinition, config RawValue) {
+	if form == nil {
+		return
+	}
+	for i := range form.Fields {
+		f := &form.Fields[i]
+		if !f.isRef() {
+			continue
+		}
+		if v, ok := config.Field(f.Name); ok && v.String() != "" && !c.hasSecret(v.String()) {
+			c.secrets = append(c.secrets, v.String())
+		}
+	}
+}
+
+func (c *Context) hasSecret(s string) bool {
+	for _, known := range c.secrets {
+		if known == s {
+			return true
+		}
+	}
+	return false
+}
+
+// redact masks resolved credentials in a log message.
+func (c *Context) redact(msg string) string {
+	for _, s := range c.secrets {
+		msg = strings.ReplaceAll(msg, s, "[redacted]")
+	}
+	return msg
+}
@@ -0,0 +1,33 @@
+package sdk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegisterSecrets(t *testing.T) {
+	form := &FormDefinition{Fields: []FormField{
+		TextField("region", "Region"),
+		SecretRefField("api_key", "API key"),
+		OAuthProviderRefField("account", "Account", "google"),
+	}}
+	config := NewRawValue(`{"region":"eu","api_key":"sk-123","account":"ya29.tok"}`)
+	c := NewContext(ExecutionInput{})
+	for i := 0; i < 3; i++ {
+		c.registerSecrets(form, config)
+	}
+	if len(c.secrets) != 2 {
+		t.Fatalf("secrets = %q, want the two resolved refs once", c.secrets)
+	}
+	if got := c.redact("eu key sk-123 token ya29.tok"); got != "eu key [redacted] token [redacted]" {
+		t.Errorf("redact = %q", got)
+	}
+
+	res := c.Result(errors.New("401 for key sk-123"))
+	if res.Error == nil || *res.Error != "401 for key [redacted]" {
+		t.Errorf("result error = %v", res.Error)
+	}
+	if res.ErrorInfo == nil || res.ErrorInfo.Message != "401 for key [redacted]" {
+		t.Errorf("result error info = %+v", res.ErrorInfo)
+	}
+}
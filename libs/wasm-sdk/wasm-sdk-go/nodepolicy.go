@@ -0,0 +1,186 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keys of a ConcurrencyLimit: the limit applies separately to each value
+// of the key.
+const (
+	ConcurrencyPerNode  = ""      // all runs of the node in the app
+	ConcurrencyPerBoard = "board" // runs on the same board
+	ConcurrencyPerUser  = "user"  // runs of the same user
+)
+
+// ConcurrencyPerInput keys a ConcurrencyLimit by the value of an input pin,
+// e.g. the account ID of an external system that rejects parallel writes.
+func ConcurrencyPerInput(pin string) string { return "input:" + pin }
+
+// ConcurrencyLimit tells the runtime how many runs of a node may execute at
+// the same time; further runs wait for a slot in arrival order.
+type ConcurrencyLimit struct {
+	Max int    `json:"max"`
+	Key string `json:"key,omitempty"`
+}
+
+func (l *ConcurrencyLimit) ToJSON() string {
+	s := `{"max":` + strconv.Itoa(l.Max)
+	if l.Key != "" {
+		s += `,"key":` + jsonString(l.Key)
+	}
+	return s + "}"
+}
+
+// SetConcurrency limits the node to max parallel runs per value of key
+// (ConcurrencyPerNode, ConcurrencyPerBoard, ConcurrencyPerUser or
+// ConcurrencyPerInput); max 1 serializes them:
+//
+//	def.SetConcurrency(1, sdk.ConcurrencyPerInput("account_id"))
+//
+// Without a limit, or after SetConcurrency(0, ""), the runtime runs as many
+// as it schedules.
+func (n *NodeDefinition) SetConcurrency(max int, key string) *NodeDefinition {
+	if max < 1 {
+		n.Concurrency = nil
+		return n
+	}
+	n.Concurrency = &ConcurrencyLimit{Max: max, Key: key}
+	return n
+}
+
+// Conditions a RetryPolicy retries on besides the error codes of failed
+// runs (ErrorInfo.Code, e.g. ErrCodeUpstream or ErrCodeDeadlineExceeded).
+const (
+	RetryOnNetwork     = "network"      // a host call could not reach the remote side
+	RetryOnRateLimited = "rate_limited" // a host call got HTTP 429 or a provider rate limit
+)
+
+// RetryPolicy tells the runtime to run a failed node again. Attempts are
+// spaced by Backoff, doubled after each one and jittered by the host.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	RetryOn     []string
+}
+
+func (p *RetryPolicy) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"max_attempts":`)
+	b.WriteString(strconv.Itoa(p.MaxAttempts))
+	b.WriteString(`,"backoff_ms":`)
+	b.WriteString(strconv.FormatInt(p.Backoff.Milliseconds(), 10))
+	b.WriteString(`,"retry_on":`)
+	b.WriteString(jsonStringArray(p.RetryOn))
+	b.WriteByte('}')
+	return b.String()
+}
+
+// SetRetryPolicy makes the host retry failed runs up to maxAttempts runs in
+// total when the failure matches retryOn: RetryOnNetwork,
+// RetryOnRateLimited or error codes of the run. Without retryOn it retries
+// network failures and rate limits:
+//
+//	def.SetRetryPolicy(4, time.Second, sdk.RetryOnRateLimited, sdk.ErrCodeUpstream)
+//
+// Only make nodes retryable whose side effects are safe to repeat; a run
+// reads its attempt number with Context.Attempt. maxAttempts below 2
+// removes the policy.
+func (n *NodeDefinition) SetRetryPolicy(maxAttempts int, backoff time.Duration, retryOn ...string) *NodeDefinition {
+	if maxAttempts < 2 {
+		n.Retry = nil
+		return n
+	}
+	if len(retryOn) == 0 {
+		retryOn = []string{RetryOnNetwork, RetryOnRateLimited}
+	}
+	n.Retry = &RetryPolicy{MaxAttempts: maxAttempts, Backoff: backoff, RetryOn: retryOn}
+	return n
+}
+
+// Attempt returns which attempt of a retried run this is, starting at 1.
+// Runs of nodes without a RetryPolicy are always attempt 1.
+func (c *Context) Attempt() int {
+	if c.input.Attempt < 1 {
+		return 1
+	}
+	return c.input.Attempt
+}
+
+// SetTimeout bounds each run of the node to seconds. The host caps it at the
+// platform limit and fails runs that exceed it with ErrCodeDeadlineExceeded;
+// Context.Timeout returns the effective value and Context.Deadline the end of
+// the current run. seconds below 1 removes the bound, leaving the platform
+// default.
+func (n *NodeDefinition) SetTimeout(seconds int) *NodeDefinition {
+	if seconds < 1 {
+		seconds = 0
+	}
+	n.TimeoutSecs = seconds
+	return n
+}
+
+// Timeout returns the run's effective timeout, the definition's SetTimeout
+// capped by the platform limit, or 0 when the run is unbounded. Nodes that
+// work in batches can size them from it:
+//
+//	if t := ctx.Timeout(); t > 0 && t < time.Minute {
+//		batchSize = 100
+//	}
+func (c *Context) Timeout() time.Duration {
+	return time.Duration(c.input.TimeoutMs) * time.Millisecond
+}
+
+// CPU classes of ResourceHints.
+const (
+	CPULight    = "light"    // mostly waits on host calls; packed densely
+	CPUStandard = "standard" // the default
+	CPUHeavy    = "heavy"    // parsing, compression or other sustained compute
+)
+
+// ResourceHints tell server deployments what a run of the node needs, so
+// heavy nodes get larger instances and light ones share them. Desktop runs
+// ignore them.
+type ResourceHints struct {
+	MemoryMB int    `json:"memory_mb,omitempty"`
+	CPUClass string `json:"cpu_class,omitempty"`
+}
+
+func (r *ResourceHints) ToJSON() string {
+	var b strings.Builder
+	b.WriteByte('{')
+	if r.MemoryMB > 0 {
+		b.WriteString(`"memory_mb":`)
+		b.WriteString(strconv.Itoa(r.MemoryMB))
+	}
+	if r.CPUClass != "" {
+		if r.MemoryMB > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(`"cpu_class":`)
+		b.WriteString(jsonString(r.CPUClass))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// SetResourceHints declares the peak memory in MB a run needs and its
+// CPUClass (CPULight, CPUStandard or CPUHeavy):
+//
+//	def.SetResourceHints(512, sdk.CPUHeavy)
+//
+// Zero memory and an empty class leave the platform defaults; both together
+// remove the hints. They are hints: the scheduler may place runs elsewhere
+// when no matching instance is free.
+func (n *NodeDefinition) SetResourceHints(memoryMB int, cpuClass string) *NodeDefinition {
+	if memoryMB < 0 {
+		memoryMB = 0
+	}
+	if memoryMB == 0 && cpuClass == "" {
+		n.Resources = nil
+		return n
+	}
+	n.Resources = &ResourceHints{MemoryMB: memoryMB, CPUClass: cpuClass}
+	return n
+}
@@ -0,0 +1,86 @@
+package sdk
+
+import "strings"
+
+// VerifyOutputs enables output completeness checking: when a successful run
+// finishes, every declared non-Exec output must have been set or explicitly
+// skipped with SkipOutput, otherwise the run fails listing the missing pins.
+// It requires a bound definition (see BindDefinition) and is implied by Strict.
+func (c *Context) VerifyOutputs(enabled bool) *Context {
+	c.verifyOut = enabled
+	return c
+}
+
+// SkipOutput marks an output as intentionally left unset on this code path,
+// so output verification does not report it.
+func (c *Context) SkipOutput(name string) *Context {
+	if c.skipped == nil {
+		c.skipped = make(map[string]bool)
+	}
+	c.skipped[name] = true
+	return c
+}
+
+// UnsetOutputs lists declared non-Exec outputs that have been neither set nor
+// skipped so far. It returns nil when no definition is bound.
+func (c *Context) UnsetOutputs() []string {
+	if c.def == nil {
+		return nil
+	}
+	var missing []string
+	for i := range c.def.Pins {
+		p := &c.def.Pins[i]
+		if p.PinType != "Output" || p.DataType == DataTypeExec || c.skipped[p.Name] {
+			continue
+		}
+		if _, ok := c.outputs[p.Name]; ok {
+			continue
+		}
+		if _, ok := c.result.Outputs[p.Name]; !ok {
+			missing = append(missing, p.Name)
+		}
+	}
+	return missing
+}
+
+// Outputs returns a snapshot of the outputs set so far, keyed by pin name.
+// Change them with SetOutput and ClearOutput; editing the map has no effect.
+func (c *Context) Outputs() map[string]RawValue {
+	out := make(map[string]RawValue, len(c.outputs)+len(c.result.Outputs))
+	for k, v := range c.result.Outputs {
+		out[k] = NewRawValue(v)
+	}
+	for k, v := range c.outputs {
+		out[k] = NewRawValue(v)
+	}
+	return out
+}
+
+// HasOutput reports whether the output has been set.
+func (c *Context) HasOutput(name string) bool {
+	if _, ok := c.outputs[name]; ok {
+		return true
+	}
+	_, ok := c.result.Outputs[name]
+	return ok
+}
+
+// ClearOutput removes a previously set output so it is not sent to the host.
+func (c *Context) ClearOutput(name string) {
+	delete(c.outputs, name)
+	delete(c.result.Outputs, name)
+}
+
+func (c *Context) enforceOutputs() {
+	if c.result.Error != nil || c.result.Pending {
+		return
+	}
+	missing := c.UnsetOutputs()
+	if len(missing) == 0 {
+		return
+	}
+	msg := "outputs never set: " + strings.Join(missing, ", ")
+	c.result.ActivateExec = []string{}
+	c.result.ErrorInfo = &ErrorInfo{Code: ErrCodeInternal, Message: msg}
+	c.result.Error = &msg
+}
@@ -0,0 +1,156 @@
+package sdk
+
+import "strings"
+
+// PackManifest presents the nodes of a module as one pack: the store lists
+// them together, and settings and permissions declared here are configured
+// and granted once for the whole pack instead of per node. It is served by
+// the get_pack export.
+type PackManifest struct {
+	Name        string
+	Version     string
+	Description string
+	// Config is the pack-level settings form an admin fills in once, e.g.
+	// the base URL of the API every node talks to. Its schema is served as
+	// config_schema so hosts can validate what the admin saves.
+	Config *FormDefinition
+	// Permissions are added to every node of the pack.
+	Permissions []string
+	// Nodes names the pack's nodes; Registry.Pack fills it.
+	Nodes []string
+}
+
+func (p *PackManifest) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"name":`)
+	b.WriteString(jsonString(p.Name))
+	b.WriteString(`,"version":`)
+	b.WriteString(jsonString(p.Version))
+	if p.Description != "" {
+		b.WriteString(`,"description":`)
+		b.WriteString(jsonString(p.Description))
+	}
+	if p.Config != nil {
+		b.WriteString(`,"config":`)
+		b.WriteString(p.Config.ToJSON())
+		b.WriteString(`,"config_schema":`)
+		b.WriteString(p.Config.Schema())
+	}
+	if len(p.Permissions) > 0 {
+		b.WriteString(`,"permissions":`)
+		b.WriteString(jsonStringArray(p.Permissions))
+	}
+	b.WriteString(`,"nodes":`)
+	b.WriteString(jsonStringArray(p.Nodes))
+	b.WriteByte('}')
+	return b.String()
+}
+
+// SetPack declares the module a pack and adds the pack's permissions to
+// every registered node, including nodes registered later:
+//
+//	registry.SetPack(sdk.PackManifest{
+//		Name:        "Acme CRM",
+//		Config:      sdk.NewForm("Acme CRM").AddField(sdk.TextField("base_url", "API base URL").Required()),
+//		Permissions: []string{sdk.PermHTTP},
+//	})
+//
+// An empty Name or Version is taken from the module manifest.
+func (r *Registry) SetPack(p PackManifest) *Registry {
+	r.pack = &p
+	for i := range r.defs {
+		r.applyPack(&r.defs[i])
+	}
+	return r
+}
+
+func (r *Registry) applyPack(def *NodeDefinition) {
+	if r.pack == nil {
+		return
+	}
+	for _, perm := range r.pack.Permissions {
+		if !def.HasPermission(perm) {
+			def.AddPermission(perm)
+		}
+	}
+}
+
+// Pack returns the pack manifest with the names of the registered nodes.
+// Without SetPack it is derived from the module manifest.
+func (r *Registry) Pack() PackManifest {
+	var p PackManifest
+	if r.pack != nil {
+		p = *r.pack
+	}
+	m := r.Manifest()
+	if p.Name == "" {
+		p.Name = m.Module
+	}
+	if p.Version == "" {
+		p.Version = m.Version
+	}
+	p.Nodes = make([]string, len(r.defs))
+	for i := range r.defs {
+		p.Nodes[i] = r.defs[i].Name
+	}
+	return p
+}
+
+// ConfigDecoder is implemented by the types nodes read their settings into.
+// DecodeConfig receives the saved settings as a JSON object:
+//
+//	type acmeConfig struct{ BaseURL string }
+//
+//	func (c *acmeConfig) DecodeConfig(v sdk.RawValue) error {
+//		url, ok := v.Field("base_url")
+//		if !ok {
+//			return sdk.NewError(sdk.ErrCodeInvalidInput, "base_url is not set")
+//		}
+//		c.BaseURL = url.String()
+//		return nil
+//	}
+type ConfigDecoder interface {
+	DecodeConfig(config RawValue) error
+}
+
+// PackConfig returns the pack settings an admin saved for the PackManifest's
+// Config form, shared by every node of the pack, with credential references
+// resolved like NodeConfig's. It fails with ErrNotFound while the pack is not
+// configured.
+func (h HostCalls) PackConfig() (RawValue, error) {
+	if !h.c.host("") {
+		return RawValue{}, dryRunSkipped("PackConfig")
+	}
+	config := GetPackConfig()
+	if config == "" {
+		return RawValue{}, hostFailure("PackConfig", ErrCodeNotFound)
+	}
+	return NewRawValue(config), nil
+}
+
+// GetPackConfig reads the pack settings into dst, so every node of a pack
+// uses the same admin-configured credentials and settings instead of each
+// node on a board carrying its own API key pin:
+//
+//	var cfg acmeConfig
+//	if err := ctx.GetPackConfig(&cfg); err != nil {
+//		return err
+//	}
+//
+// Settings change rarely; nodes that decode them on every run can keep them
+// in an InstanceCache with InvalidateConfig instead. As with GetConfig, the
+// resolved secret and OAuth values are masked in log messages.
+func (c *Context) GetPackConfig(dst ConfigDecoder) error {
+	config, err := c.Host().PackConfig()
+	if err != nil {
+		return err
+	}
+	c.registerSecrets(c.packForm, config)
+	return dst.DecodeConfig(config)
+}
+
+// GetPack implements the get_pack export.
+func (r *Registry) GetPack() int64 {
+	p := r.Pack()
+	return PackResult(p.ToJSON())
+}
@@ -0,0 +1,113 @@
+package sdk
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// StackFrame is one call in the stack of a recovered panic.
+type StackFrame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// PanicError is the failure a Registry reports when a handler panics. The
+// run fails with ErrCodeInternal instead of trapping the module instance.
+// This relies on the build supporting recover(): TinyGo builds with
+// -panic trap (the size profile of flowlike-gen build) still trap the
+// instance, and the host backtrace is then the only trace.
+type PanicError struct {
+	Value string
+	// Stack is empty when the toolchain cannot walk the stack, as with
+	// TinyGo builds without debug info.
+	Stack []StackFrame
+}
+
+func (e *PanicError) Error() string { return "panic: " + e.Value }
+
+func (e *PanicError) errorInfo() ErrorInfo {
+	return ErrorInfo{Code: ErrCodeInternal, Message: e.Error()}
+}
+
+// Trace formats the panic and its stack like a Go traceback.
+func (e *PanicError) Trace() string {
+	var b strings.Builder
+	b.WriteString(e.Error())
+	if len(e.Stack) == 0 {
+		b.WriteString("\n\t(no stack; build with `flowlike-gen build -profile debug` or symbolize the host backtrace with `flowlike-gen symbolize`)")
+	}
+	for _, f := range e.Stack {
+		b.WriteString("\n")
+		b.WriteString(f.Function)
+		if f.File != "" {
+			b.WriteString("\n\t")
+			b.WriteString(f.File)
+			b.WriteByte(':')
+			b.WriteString(strconv.Itoa(f.Line))
+		}
+	}
+	return b.String()
+}
+
+// recoveredPanic builds a PanicError for r. Call it from the deferred
+// function that recovered, while the panicking frames are still on the stack.
+func recoveredPanic(r any) *PanicError {
+	e := &PanicError{Value: panicValue(r)}
+	pcs := make([]uintptr, 32)
+	// Skip runtime.Callers, recoveredPanic and the deferred function.
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		// Frames up to the panic belong to the runtime's panic machinery.
+		if f.Function != "" && !strings.HasPrefix(f.Function, "runtime.") {
+			e.Stack = append(e.Stack, StackFrame{Function: f.Function, File: f.File, Line: f.Line})
+		}
+		if !more {
+			break
+		}
+	}
+	return e
+}
+
+// panicValue renders r the way the Go runtime prints panic values: scalars
+// by value, wrapped in their type name when it is a named type, and other
+// values by type name alone.
+func panicValue(r any) string {
+	switch v := r.(type) {
+	case error:
+		return v.Error()
+	case string:
+		return v
+	case interface{ String() string }:
+		return v.String()
+	}
+	v := reflect.ValueOf(r)
+	if !v.IsValid() {
+		return "nil"
+	}
+	var s string
+	switch v.Kind() {
+	case reflect.Bool:
+		s = strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s = strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32:
+		s = strconv.FormatFloat(v.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		s = strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.String:
+		s = strconv.Quote(v.String())
+	default:
+		return "(" + v.Type().String() + ")"
+	}
+	if t := v.Type(); t.PkgPath() != "" {
+		return t.String() + "(" + s + ")"
+	}
+	return s
+}
@@ -0,0 +1,34 @@
+package sdk
+
+import (
+	"errors"
+	"testing"
+)
+
+type panicCode int
+
+type panicPoint struct{ X, Y int }
+
+func TestPanicValue(t *testing.T) {
+	tests := []struct {
+		r    any
+		want string
+	}{
+		{"boom", "boom"},
+		{errors.New("bad input"), "bad input"},
+		{42, "42"},
+		{uint8(7), "7"},
+		{-1.5, "-1.5"},
+		{float32(0.25), "0.25"},
+		{true, "true"},
+		{panicCode(3), "sdk.panicCode(3)"},
+		{panicPoint{1, 2}, "(sdk.panicPoint)"},
+		{&panicPoint{}, "(*sdk.panicPoint)"},
+		{[]int{1}, "([]int)"},
+	}
+	for _, tt := range tests {
+		if got := panicValue(tt.r); got != tt.want {
+			t.Errorf("panicValue(%#v) = %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package sdk
+
+// Permissions a node declares with NodeDefinition.AddPermission. The runtime
+// only grants a module the capabilities its nodes declare.
+const (
+	PermStreaming     = "streaming"
+	PermHTTP          = "http"
+	PermStorage       = "storage"
+	PermStorageWatch  = "storage_watch"
+	PermOAuth         = "oauth"
+	PermModels        = "models"
+	PermVector        = "vector"
+	PermNotifications = "notifications"
+	PermClipboard     = "clipboard"
+	PermOpenFile      = "open_file"
+	PermExec          = "exec"
+	PermTransfer      = "transfer"
+	PermBucket        = "bucket"
+	PermMail          = "mail"
+	PermCalendar      = "calendar"
+	PermContacts      = "contacts"
+	PermQueue         = "queue"
+	PermKV            = "kv"
+	PermSearch        = "search"
+	PermInteraction   = "interaction"
+	PermMembers       = "members"
+)
+
+// HasPermission reports whether the definition declares perm.
+func (n *NodeDefinition) HasPermission(perm string) bool {
+	for _, p := range n.Permissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,440 @@
+// Package privacy finds and redacts personal data in free text: email
+// addresses, phone numbers, payment card numbers, IBANs, IPv4 addresses and
+// national identifiers (US Social Security and UK National Insurance numbers).
+//
+// Pattern detection runs entirely inside the module and favors precision:
+// card numbers must pass the Luhn check, IBANs their checksum, and bare digit
+// runs only count as phone numbers when they look like one. Names, places and
+// other free-form entities need a model; set Detector.Model, e.g. to HostNER,
+// to merge the host's named-entity recognition into the results.
+package privacy
+
+import (
+	"sort"
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+	"github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go/validate"
+)
+
+// Kind names a category of personal data.
+type Kind string
+
+const (
+	Email      Kind = "EMAIL"
+	Phone      Kind = "PHONE"
+	CreditCard Kind = "CREDIT_CARD"
+	IBAN       Kind = "IBAN"
+	IPAddress  Kind = "IP_ADDRESS"
+	SSN        Kind = "SSN"
+	NINO       Kind = "NINO"
+
+	// Kinds reported by typical NER models; see HostNER.
+	Person       Kind = "PERSON"
+	Location     Kind = "LOCATION"
+	Organization Kind = "ORGANIZATION"
+)
+
+// PatternKinds lists the kinds detected without a model.
+var PatternKinds = []Kind{Email, Phone, CreditCard, IBAN, IPAddress, SSN, NINO}
+
+// Finding is a piece of personal data in the scanned text. Start and End are
+// byte offsets; Score is 1 for pattern matches and the model's confidence for
+// model findings.
+type Finding struct {
+	Kind  Kind
+	Start int
+	End   int
+	Text  string
+	Score float64
+}
+
+// Detector configures detection. The zero value detects all pattern kinds.
+type Detector struct {
+	// Kinds restricts detection to the listed kinds; nil means every pattern
+	// kind plus whatever the model reports.
+	Kinds []Kind
+	// Model, if set, contributes additional findings, typically from NER.
+	Model func(text string) []Finding
+	// MinScore drops model findings with a lower confidence.
+	MinScore float64
+}
+
+// Detect scans text with the default detector.
+func Detect(text string) []Finding {
+	return (&Detector{}).Detect(text)
+}
+
+// Detect returns the findings in text ordered by position. Overlapping
+// matches are resolved in favor of the longer one.
+func (d *Detector) Detect(text string) []Finding {
+	var found []Finding
+	if d.wants(Email) {
+		found = append(found, findEmails(text)...)
+	}
+	if d.wants(IBAN) {
+		found = append(found, findIBANs(text)...)
+	}
+	if d.wants(NINO) {
+		found = append(found, findNINOs(text)...)
+	}
+	for _, f := range findNumbers(text) {
+		if d.wants(f.Kind) {
+			found = append(found, f)
+		}
+	}
+	if d.Model != nil {
+		for _, f := range d.Model(text) {
+			if f.Score < d.MinScore || !d.wants(f.Kind) || f.Start < 0 || f.End > len(text) || f.Start >= f.End {
+				continue
+			}
+			f.Text = text[f.Start:f.End]
+			found = append(found, f)
+		}
+	}
+	return resolve(found)
+}
+
+// Contains reports whether text holds any personal data the detector finds.
+func (d *Detector) Contains(text string) bool {
+	return len(d.Detect(text)) > 0
+}
+
+func (d *Detector) wants(k Kind) bool {
+	if d.Kinds == nil {
+		return true
+	}
+	for _, want := range d.Kinds {
+		if want == k {
+			return true
+		}
+	}
+	return false
+}
+
+// HostNER returns a Detector.Model backed by the host's named-entity
+// recognition (see sdk.HostCalls.DetectEntities). bitJSON selects the model
+// bit; "" uses the host default. Labels are upper-cased into Kinds, so a
+// "person" entity becomes Person. It requires the "models" permission and
+// yields no findings without it.
+func HostNER(ctx *sdk.Context, bitJSON string) func(text string) []Finding {
+	return func(text string) []Finding {
+		entities, _ := ctx.Host().DetectEntities(bitJSON, text)
+		out := make([]Finding, 0, len(entities))
+		for _, e := range entities {
+			out = append(out, Finding{
+				Kind:  Kind(strings.ToUpper(e.Label)),
+				Start: e.Start,
+				End:   e.End,
+				Score: e.Score,
+			})
+		}
+		return out
+	}
+}
+
+// resolve keeps the longest of overlapping findings and orders the rest by
+// position.
+func resolve(found []Finding) []Finding {
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].End-found[i].Start > found[j].End-found[j].Start
+	})
+	kept := found[:0:0]
+	for _, f := range found {
+		overlaps := false
+		for _, k := range kept {
+			if f.Start < k.End && k.Start < f.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			kept = append(kept, f)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Start < kept[j].Start })
+	return kept
+}
+
+func match(kind Kind, text string, start, end int) Finding {
+	return Finding{Kind: kind, Start: start, End: end, Text: text[start:end], Score: 1}
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+func isUpper(c byte) bool { return c >= 'A' && c <= 'Z' }
+
+func isAlnum(c byte) bool {
+	return isDigit(c) || isUpper(c) || c >= 'a' && c <= 'z'
+}
+
+// boundary reports whether position i in text is not inside a word.
+func boundary(text string, i int) bool {
+	return i <= 0 || i >= len(text) || !isAlnum(text[i]) && text[i] < 0x80
+}
+
+// --- Email ---
+
+func findEmails(text string) []Finding {
+	var out []Finding
+	for i := 0; i < len(text); i++ {
+		if text[i] != '@' {
+			continue
+		}
+		l := i
+		for l > 0 && (isAlnum(text[l-1]) || strings.IndexByte("._%+-'", text[l-1]) >= 0) {
+			l--
+		}
+		for l < i && (text[l] == '.' || text[l] == '\'') {
+			l++
+		}
+		r := i + 1
+		for r < len(text) && (isAlnum(text[r]) || text[r] == '.' || text[r] == '-') {
+			r++
+		}
+		for r > i+1 && (text[r-1] == '.' || text[r-1] == '-') {
+			r--
+		}
+		if l < i && validate.IsEmail(text[l:r]) {
+			out = append(out, match(Email, text, l, r))
+			i = r - 1
+		}
+	}
+	return out
+}
+
+// --- IBAN ---
+
+func findIBANs(text string) []Finding {
+	var out []Finding
+	for i := 0; i+4 <= len(text); i++ {
+		if !boundary(text, i-1) || !isUpper(text[i]) || !isUpper(text[i+1]) || !isDigit(text[i+2]) || !isDigit(text[i+3]) {
+			continue
+		}
+		// Collect up to 34 alphanumerics, allowing single spaces between
+		// groups, then take the longest prefix with a valid checksum.
+		var ends []int
+		n := 0
+		for j := i; j < len(text) && n < 34; j++ {
+			c := text[j]
+			if isAlnum(c) {
+				n++
+				ends = append(ends, j+1)
+			} else if c != ' ' || j+1 >= len(text) || !isAlnum(text[j+1]) || text[j-1] == ' ' {
+				break
+			}
+		}
+		for k := len(ends) - 1; k >= 14; k-- {
+			if end := ends[k]; boundary(text, end) && validate.IsIBAN(text[i:end]) {
+				out = append(out, match(IBAN, text, i, end))
+				i = end - 1
+				break
+			}
+		}
+	}
+	return out
+}
+
+// --- UK National Insurance number ---
+
+func findNINOs(text string) []Finding {
+	var out []Finding
+	for i := 0; i+9 <= len(text); i++ {
+		if !boundary(text, i-1) || !isUpper(text[i]) || !isUpper(text[i+1]) {
+			continue
+		}
+		if strings.IndexByte("DFIQUV", text[i]) >= 0 || strings.IndexByte("DFIQUVO", text[i+1]) >= 0 {
+			continue
+		}
+		switch text[i : i+2] {
+		case "BG", "GB", "NK", "KN", "TN", "NT", "ZZ":
+			continue
+		}
+		j, digits := i+2, 0
+		for j < len(text) && digits < 6 {
+			if isDigit(text[j]) {
+				digits++
+			} else if text[j] != ' ' || digits%2 != 0 {
+				break
+			}
+			j++
+		}
+		if j < len(text) && text[j] == ' ' {
+			j++
+		}
+		if digits == 6 && j < len(text) && text[j] >= 'A' && text[j] <= 'D' && boundary(text, j+1) {
+			out = append(out, match(NINO, text, i, j+1))
+			i = j
+		}
+	}
+	return out
+}
+
+// --- Digit runs: phone, card, SSN, IP ---
+
+const numberSeparators = " -.()/"
+
+// findNumbers splits text into runs of digits joined by separators and
+// classifies each run. A run that matches nothing as a whole is retried as
+// its space-separated parts, so adjacent numbers are still found.
+func findNumbers(text string) []Finding {
+	var out []Finding
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		startsNumber := isDigit(c) || (c == '+' || c == '(') && i+1 < len(text) && isDigit(text[i+1])
+		if !startsNumber || !boundary(text, i-1) || i > 0 && (text[i-1] == '.' || text[i-1] == '-') {
+			continue
+		}
+		end := i + 1
+		for j := i + 1; j < len(text); j++ {
+			if isDigit(text[j]) {
+				end = j + 1
+				continue
+			}
+			if strings.IndexByte(numberSeparators, text[j]) < 0 {
+				break
+			}
+			// Allow at most two separators in a row, e.g. ") ".
+			if j+1 < len(text) && isDigit(text[j+1]) {
+				continue
+			}
+			if j+2 < len(text) && strings.IndexByte(numberSeparators, text[j+1]) >= 0 && isDigit(text[j+2]) {
+				j++
+				continue
+			}
+			break
+		}
+		if !boundary(text, end) {
+			i = end
+			continue
+		}
+		out = append(out, classifyRun(text, i, end)...)
+		i = end
+	}
+	return out
+}
+
+func classifyRun(text string, start, end int) []Finding {
+	if k, ok := classify(text[start:end]); ok {
+		return []Finding{match(k, text, start, end)}
+	}
+	// Split into space-separated chunks and match the longest groups from
+	// the left.
+	var chunks [][2]int
+	for i := start; i < end; {
+		j := i
+		for j < end && text[j] != ' ' {
+			j++
+		}
+		if j > i {
+			chunks = append(chunks, [2]int{i, j})
+		}
+		i = j + 1
+	}
+	if len(chunks) < 2 {
+		return nil
+	}
+	var out []Finding
+	for a := 0; a < len(chunks); a++ {
+		for b := len(chunks) - 1; b >= a; b-- {
+			if a == 0 && b == len(chunks)-1 {
+				continue
+			}
+			s, e := chunks[a][0], chunks[b][1]
+			if k, ok := classify(text[s:e]); ok {
+				out = append(out, match(k, text, s, e))
+				a = b
+				break
+			}
+		}
+	}
+	return out
+}
+
+func classify(run string) (Kind, bool) {
+	var digits []byte
+	var seps string
+	for i := 0; i < len(run); i++ {
+		if isDigit(run[i]) {
+			digits = append(digits, run[i])
+		} else if run[i] != '+' {
+			seps += run[i : i+1]
+		}
+	}
+	switch {
+	case isIPv4(run):
+		return IPAddress, true
+	case isSSN(run):
+		return SSN, true
+	case isDigit(run[0]) && len(digits) >= 13 && len(digits) <= 19 && strings.Trim(seps, " -") == "" &&
+		digits[0] >= '3' && digits[0] <= '6' && luhn(digits):
+		return CreditCard, true
+	case isPhone(run, digits, seps):
+		return Phone, true
+	}
+	return "", false
+}
+
+func isIPv4(run string) bool {
+	parts := strings.Split(run, ".")
+	if len(parts) != 4 {
+		return false
+	}
+	for _, p := range parts {
+		if len(p) == 0 || len(p) > 3 || len(p) > 1 && p[0] == '0' {
+			return false
+		}
+		v := 0
+		for i := 0; i < len(p); i++ {
+			if !isDigit(p[i]) {
+				return false
+			}
+			v = v*10 + int(p[i]-'0')
+		}
+		if v > 255 {
+			return false
+		}
+	}
+	return true
+}
+
+// isSSN matches AAA-GG-SSSS, excluding ranges the SSA never assigns.
+func isSSN(run string) bool {
+	if len(run) != 11 || run[3] != '-' || run[6] != '-' {
+		return false
+	}
+	for i, c := range []byte(run) {
+		if i != 3 && i != 6 && !isDigit(c) {
+			return false
+		}
+	}
+	area, group, serial := run[:3], run[4:6], run[7:]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+// isPhone accepts 8–15 digits written in an international form ("+49 …",
+// "0049 …", "(555) …"), or at least 10 digits grouped by separators. Dates,
+// version numbers and plain integers are left alone.
+func isPhone(run string, digits []byte, seps string) bool {
+	if len(digits) < 8 || len(digits) > 15 {
+		return false
+	}
+	if run[0] == '+' || run[0] == '(' || strings.HasPrefix(run, "00") {
+		return true
+	}
+	return len(digits) >= 10 && seps != "" && strings.Trim(seps, ".") != ""
+}
+
+func luhn(digits []byte) bool {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-i)%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
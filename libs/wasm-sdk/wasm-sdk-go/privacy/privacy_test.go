@@ -0,0 +1,110 @@
+package privacy
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text string
+		kind Kind
+		want string
+	}{
+		{"mail jane.doe@example.com today", Email, "jane.doe@example.com"},
+		{"card 4242 4242 4242 4242 ok", CreditCard, "4242 4242 4242 4242"},
+		{"iban DE89 3704 0044 0532 0130 00.", IBAN, "DE89 3704 0044 0532 0130 00"},
+		{"from 192.168.1.20 at noon", IPAddress, "192.168.1.20"},
+		{"ssn 123-45-6789", SSN, "123-45-6789"},
+		{"nino AB 12 34 56 C", NINO, "AB 12 34 56 C"},
+		{"call +49 151 12345678", Phone, "+49 151 12345678"},
+		{"call (415) 555-2671 now", Phone, "(415) 555-2671"},
+	}
+	for _, tt := range tests {
+		found := Detect(tt.text)
+		if len(found) != 1 || found[0].Kind != tt.kind || found[0].Text != tt.want {
+			t.Errorf("Detect(%q) = %+v, want one %s %q", tt.text, found, tt.kind, tt.want)
+			continue
+		}
+		if f := found[0]; tt.text[f.Start:f.End] != f.Text || f.Score != 1 {
+			t.Errorf("Detect(%q) offsets or score wrong: %+v", tt.text, f)
+		}
+	}
+}
+
+func TestDetectNegatives(t *testing.T) {
+	// A failed checksum must not produce its kind, though the digits may
+	// still be reported as something else.
+	for text, kind := range map[string]Kind{
+		"card 4242 4242 4242 4241":         CreditCard,
+		"iban DE88 3704 0044 0532 0130 00": IBAN,
+	} {
+		for _, f := range Detect(text) {
+			if f.Kind == kind {
+				t.Errorf("Detect(%q) found %s %q", text, kind, f.Text)
+			}
+		}
+	}
+	for _, text := range []string{
+		"",
+		"version 1.2.3.4.5",
+		"ip 256.1.1.1",
+		"ssn 666-45-6789",
+		"order 12345 shipped on 2024-01-15",
+		"pi is 3.14159265",
+	} {
+		if found := Detect(text); len(found) != 0 {
+			t.Errorf("Detect(%q) = %+v, want none", text, found)
+		}
+	}
+}
+
+func TestDetectorKinds(t *testing.T) {
+	text := "jane@example.com, 123-45-6789"
+	d := &Detector{Kinds: []Kind{SSN}}
+	if found := d.Detect(text); len(found) != 1 || found[0].Kind != SSN {
+		t.Errorf("Detect with Kinds = %+v", found)
+	}
+	model := func(string) []Finding {
+		return []Finding{
+			{Kind: Person, Start: 0, End: 4, Score: 0.9},
+			{Kind: Location, Start: 5, End: 9, Score: 0.2},
+			{Kind: Person, Start: 20, End: 99, Score: 1},
+		}
+	}
+	d = &Detector{Model: model, MinScore: 0.5}
+	found := d.Detect("Jane from Oslo")
+	if len(found) != 1 || found[0].Kind != Person || found[0].Text != "Jane" {
+		t.Errorf("Detect with model = %+v", found)
+	}
+	if (&Detector{Kinds: []Kind{Email}}).Contains("123-45-6789") {
+		t.Error("Contains reported an SSN with Kinds = [EMAIL]")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	const text = "Mail jane@example.com or call +49 151 12345678."
+	tests := []struct {
+		r    Redactor
+		want string
+	}{
+		{Redactor{}, "Mail [EMAIL] or call [PHONE]."},
+		{Redactor{Style: Mask}, "Mail ****@*******.*** or call +** *** ********."},
+		{Redactor{Style: Partial}, "Mail j***@example.com or call +** *** ****5678."},
+		{Redactor{Style: Mask, MaskChar: '#', Styles: map[Kind]Style{Email: Label}}, "Mail [EMAIL] or call +## ### ########."},
+	}
+	for _, tt := range tests {
+		got, found := tt.r.Redact(text)
+		if got != tt.want || len(found) != 2 {
+			t.Errorf("Redact(style %d) = %q, %d findings, want %q", tt.r.Style, got, len(found), tt.want)
+		}
+	}
+	if got := Redact("nothing to see"); got != "nothing to see" {
+		t.Errorf("Redact without findings = %q", got)
+	}
+
+	h := &Redactor{Style: Hash, Salt: "s"}
+	a, _ := h.Redact("jane@example.com")
+	b, _ := h.Redact("jane@example.com")
+	c, _ := (&Redactor{Style: Hash, Salt: "t"}).Redact("jane@example.com")
+	if a != b || a == c || len(a) != len("[EMAIL:12345678]") {
+		t.Errorf("Hash pseudonyms = %q, %q, %q", a, b, c)
+	}
+}
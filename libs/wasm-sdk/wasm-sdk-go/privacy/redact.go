@@ -0,0 +1,145 @@
+package privacy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// Style selects how a finding is replaced.
+type Style uint8
+
+const (
+	// Label replaces the finding with its kind: "[EMAIL]".
+	Label Style = iota
+	// Mask replaces letters and digits with the mask character and keeps
+	// punctuation: "***-**-****".
+	Mask
+	// Partial masks all but a hint: the first letter and domain of an email
+	// ("j***@example.com") or the last four characters of numbers
+	// ("**** **** **** 4242"). Other kinds are masked.
+	Partial
+	// Hash replaces the finding with a stable pseudonym ("[EMAIL:5d41402a]"),
+	// so records can still be joined without revealing the value.
+	Hash
+)
+
+// Redactor replaces the personal data its Detector finds. The zero value
+// labels every pattern finding.
+type Redactor struct {
+	Detector
+	// Style is the default replacement style.
+	Style Style
+	// Styles overrides Style per kind.
+	Styles map[Kind]Style
+	// MaskChar is used by Mask and Partial; the default is '*'.
+	MaskChar rune
+	// Salt is mixed into Hash pseudonyms. Use a per-app secret so hashes of
+	// low-entropy values such as phone numbers cannot be reversed by brute force.
+	Salt string
+}
+
+// Redact replaces all personal data in text with labels.
+func Redact(text string) string {
+	out, _ := (&Redactor{}).Redact(text)
+	return out
+}
+
+// Redact returns text with every finding replaced, along with the findings.
+func (r *Redactor) Redact(text string) (string, []Finding) {
+	found := r.Detect(text)
+	if len(found) == 0 {
+		return text, nil
+	}
+	var b strings.Builder
+	last := 0
+	for _, f := range found {
+		b.WriteString(text[last:f.Start])
+		b.WriteString(r.Replacement(f))
+		last = f.End
+	}
+	b.WriteString(text[last:])
+	return b.String(), found
+}
+
+// Replacement returns the text that replaces f.
+func (r *Redactor) Replacement(f Finding) string {
+	style, ok := r.Styles[f.Kind]
+	if !ok {
+		style = r.Style
+	}
+	switch style {
+	case Mask:
+		return r.mask(f.Text, 0)
+	case Partial:
+		switch f.Kind {
+		case Email:
+			at := strings.LastIndexByte(f.Text, '@')
+			_, size := utf8.DecodeRuneInString(f.Text)
+			if at <= size {
+				return r.mask(f.Text[:at], 0) + f.Text[at:]
+			}
+			return f.Text[:size] + r.mask(f.Text[size:at], 0) + f.Text[at:]
+		case Phone, CreditCard, IBAN, SSN:
+			return r.mask(f.Text, 4)
+		}
+		return r.mask(f.Text, 0)
+	case Hash:
+		sum := sha256.Sum256([]byte(r.Salt + f.Text))
+		return "[" + string(f.Kind) + ":" + hex.EncodeToString(sum[:4]) + "]"
+	}
+	return "[" + string(f.Kind) + "]"
+}
+
+// mask replaces letters and digits with the mask character, leaving the last
+// keep of them visible.
+func (r *Redactor) mask(s string, keep int) string {
+	ch := r.MaskChar
+	if ch == 0 {
+		ch = '*'
+	}
+	total := 0
+	for _, c := range s {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			total++
+		}
+	}
+	var b strings.Builder
+	seen := 0
+	for _, c := range s {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			seen++
+			if seen <= total-keep {
+				b.WriteRune(ch)
+				continue
+			}
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// RedactOutputs redacts every string output set so far on ctx, typically as
+// the last step of a node before Finish. A nil Redactor uses labels. It
+// returns the number of findings replaced. Non-string outputs are left as is.
+func RedactOutputs(ctx *sdk.Context, r *Redactor) int {
+	if r == nil {
+		r = &Redactor{}
+	}
+	n := 0
+	for name, v := range ctx.Outputs() {
+		if v.Kind() != sdk.KindString {
+			continue
+		}
+		out, found := r.Redact(v.String())
+		if len(found) > 0 {
+			ctx.SetOutput(name, sdk.JSONString(out))
+			n += len(found)
+		}
+	}
+	return n
+}
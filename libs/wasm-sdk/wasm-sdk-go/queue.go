@@ -0,0 +1,148 @@
+package sdk
+
+import "strconv"
+
+// QueueMessage is a message received from a platform-managed queue.
+// Delivery is at least once: a message that is not acknowledged before its
+// visibility timeout runs out is delivered again, with Attempts increased,
+// so consumers must tolerate duplicates.
+type QueueMessage struct {
+	ID          string            `json:"id"`
+	Body        string            `json:"body"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	Receipt     string            `json:"receipt"`
+	Attempts    int               `json:"attempts"`
+	PublishedAt int64             `json:"published_at"` // Unix milliseconds
+}
+
+// QueuePublish appends body to a queue or topic of the current app and
+// returns the message ID. attributes may be nil. Requires the "queue"
+// permission.
+func QueuePublish(queue, body string, attributes map[string]string) (string, bool) {
+	qp, ql := stringToPtr(queue)
+	bp, bl := stringToPtr(body)
+	attrs := "{}"
+	if len(attributes) > 0 {
+		attrs = jsonStringMap(attributes)
+	}
+	ap, al := stringToPtr(attrs)
+	packed := hostQueuePublish(qp, ql, bp, bl, ap, al)
+	if packed == -1 {
+		return "", false
+	}
+	return unpackString(packed), true
+}
+
+// QueuePoll receives up to max messages and hides them from other consumers
+// for visibilityMs milliseconds (0 uses the queue default). It returns an
+// empty slice when the queue is empty.
+func QueuePoll(queue string, max int, visibilityMs int64) ([]QueueMessage, bool) {
+	qp, ql := stringToPtr(queue)
+	packed := hostQueuePoll(qp, ql, int32(max), visibilityMs)
+	if packed == -1 {
+		return nil, false
+	}
+	items := jsonArrayItems(unpackString(packed))
+	msgs := make([]QueueMessage, 0, len(items))
+	for _, item := range items {
+		f := jsonObjectFields(item)
+		if f == nil {
+			continue
+		}
+		attempts, _ := strconv.Atoi(f["attempts"])
+		published, _ := strconv.ParseInt(f["published_at"], 10, 64)
+		m := QueueMessage{
+			ID:          jsonUnquote(f["id"]),
+			Body:        jsonUnquote(f["body"]),
+			Receipt:     jsonUnquote(f["receipt"]),
+			Attempts:    attempts,
+			PublishedAt: published,
+			Attributes:  parseStringMapJSON(f["attributes"]),
+		}
+		msgs = append(msgs, m)
+	}
+	return msgs, true
+}
+
+// QueueAck deletes a processed message by its receipt. ok is false when the
+// receipt expired, in which case the message may already be redelivered.
+func QueueAck(queue, receipt string) bool {
+	qp, ql := stringToPtr(queue)
+	rp, rl := stringToPtr(receipt)
+	return hostQueueAck(qp, ql, rp, rl) != 0
+}
+
+// QueueExtend changes the remaining visibility timeout of a received
+// message: a longer timeout keeps working on it, 0 releases it for
+// redelivery right away.
+func QueueExtend(queue, receipt string, visibilityMs int64) bool {
+	qp, ql := stringToPtr(queue)
+	rp, rl := stringToPtr(receipt)
+	return hostQueueExtend(qp, ql, rp, rl, visibilityMs) != 0
+}
+
+// Deprecated: Use c.Host().QueuePublish, which reports why the call failed.
+func (c *Context) QueuePublish(queue, body string, attributes map[string]string) (string, bool) {
+	id, err := c.Host().QueuePublish(queue, body, attributes)
+	return id, err == nil
+}
+
+// Deprecated: Use c.Host().QueuePoll, which reports why the call failed.
+func (c *Context) QueuePoll(queue string, max int, visibilityMs int64) ([]QueueMessage, bool) {
+	messages, err := c.Host().QueuePoll(queue, max, visibilityMs)
+	return messages, err == nil
+}
+
+// Deprecated: Use c.Host().QueueAck, which reports why the call failed.
+func (c *Context) QueueAck(queue, receipt string) bool {
+	return c.Host().QueueAck(queue, receipt) == nil
+}
+
+// Deprecated: Use c.Host().QueueExtend, which reports why the call failed.
+func (c *Context) QueueExtend(queue, receipt string, visibilityMs int64) bool {
+	return c.Host().QueueExtend(queue, receipt, visibilityMs) == nil
+}
+
+func (h HostCalls) QueuePublish(queue, body string, attributes map[string]string) (string, error) {
+	if !h.c.host(PermQueue) {
+		return "", dryRunSkipped("QueuePublish")
+	}
+	id, ok := QueuePublish(queue, body, attributes)
+	if !ok {
+		return "", hostFailure("QueuePublish", ErrCodeInternal)
+	}
+	return id, nil
+}
+
+func (h HostCalls) QueuePoll(queue string, max int, visibilityMs int64) ([]QueueMessage, error) {
+	if !h.c.host(PermQueue) {
+		return nil, dryRunSkipped("QueuePoll")
+	}
+	messages, ok := QueuePoll(queue, max, visibilityMs)
+	if !ok {
+		return nil, hostFailure("QueuePoll", ErrCodeInternal)
+	}
+	return messages, nil
+}
+
+// QueueAck fails with ErrNotFound when the receipt expired, i.e. the message
+// may already have been redelivered.
+func (h HostCalls) QueueAck(queue, receipt string) error {
+	if !h.c.host(PermQueue) {
+		return dryRunSkipped("QueueAck")
+	}
+	if !QueueAck(queue, receipt) {
+		return hostFailure("QueueAck", ErrCodeNotFound)
+	}
+	return nil
+}
+
+func (h HostCalls) QueueExtend(queue, receipt string, visibilityMs int64) error {
+	if !h.c.host(PermQueue) {
+		return dryRunSkipped("QueueExtend")
+	}
+	if !QueueExtend(queue, receipt, visibilityMs) {
+		return hostFailure("QueueExtend", ErrCodeNotFound)
+	}
+	return nil
+}
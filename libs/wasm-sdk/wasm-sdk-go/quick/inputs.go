@@ -0,0 +1,123 @@
+package quick
+
+import (
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// Inputs generates the inputs of one run from the definition's input pins.
+// Each pin independently gets its default value, a value generated from its
+// schema (or from its data type when it has none), an edge case for its type,
+// or no value at all. Edge cases are only used for pins without a schema,
+// since a schema states which values the node accepts. The same seed always
+// yields the same inputs.
+func Inputs(def *sdk.NodeDefinition, seed int64) map[string]string {
+	g := rng(uint64(seed))
+	inputs := make(map[string]string)
+	for i := range def.Pins {
+		p := &def.Pins[i]
+		if p.PinType != "Input" || p.DataType == sdk.DataTypeExec {
+			continue
+		}
+		roll := g.intn(20)
+		switch {
+		case roll == 0:
+			continue
+		case roll <= 3 && p.DefaultValue != nil:
+			inputs[p.Name] = *p.DefaultValue
+			continue
+		case roll <= 6 && p.Schema == nil:
+			edges := edgeCases(p)
+			inputs[p.Name] = edges[g.intn(len(edges))]
+			continue
+		}
+		schema := pinSchema(p)
+		if v, err := sdk.GenerateFromSchema(schema, int64(g.next())); err == nil {
+			inputs[p.Name] = v
+		} else if p.DefaultValue != nil {
+			inputs[p.Name] = *p.DefaultValue
+		}
+	}
+	return inputs
+}
+
+// pinSchema is the pin's JSON Schema, or one derived from its data and value
+// type.
+func pinSchema(p *sdk.PinDefinition) string {
+	if p.Schema != nil {
+		return *p.Schema
+	}
+	item := typeSchema(p.DataType)
+	if p.ValueType != nil {
+		switch *p.ValueType {
+		case "Array":
+			return `{"type":"array","items":` + item + `}`
+		case "HashSet":
+			return `{"type":"array","uniqueItems":true,"items":` + item + `}`
+		case "HashMap":
+			return `{"type":"object","additionalProperties":` + item + `}`
+		}
+	}
+	return item
+}
+
+func typeSchema(dataType string) string {
+	switch dataType {
+	case sdk.DataTypeString, sdk.DataTypePathBuf:
+		return `{"type":"string","maxLength":64}`
+	case sdk.DataTypeI64:
+		return `{"type":"integer","minimum":-1000000,"maximum":1000000}`
+	case sdk.DataTypeF64:
+		return `{"type":"number","minimum":-1000000,"maximum":1000000}`
+	case sdk.DataTypeBool:
+		return `{"type":"boolean"}`
+	case sdk.DataTypeDate:
+		return `{"type":"string","format":"date-time"}`
+	case sdk.DataTypeBytes:
+		return `{"type":"array","maxItems":32,"items":{"type":"integer","minimum":0,"maximum":255}}`
+	}
+	return `true`
+}
+
+// edgeCases returns values at the boundaries of the pin's type, as raw JSON.
+func edgeCases(p *sdk.PinDefinition) []string {
+	if p.ValueType != nil {
+		switch *p.ValueType {
+		case "Array", "HashSet":
+			return []string{`[]`, `null`}
+		case "HashMap":
+			return []string{`{}`, `null`}
+		}
+	}
+	switch p.DataType {
+	case sdk.DataTypeString, sdk.DataTypePathBuf:
+		return []string{`""`, `" "`, `"\u0000"`, `"ünïcödé 🙂 \"quoted\"\n"`, `"` + strings.Repeat("x", 10000) + `"`}
+	case sdk.DataTypeI64:
+		return []string{`0`, `-1`, `9223372036854775807`, `-9223372036854775808`}
+	case sdk.DataTypeF64:
+		return []string{`0`, `-1`, `0.1`, `1.7976931348623157e308`, `-1.7976931348623157e308`, `5e-324`}
+	case sdk.DataTypeBool:
+		return []string{`true`, `false`}
+	case sdk.DataTypeDate:
+		return []string{`"1970-01-01T00:00:00Z"`, `"9999-12-31T23:59:59Z"`, `""`}
+	case sdk.DataTypeBytes:
+		return []string{`[]`, `""`}
+	}
+	return []string{`null`, `{}`, `[]`, `""`, `0`}
+}
+
+// rng is splitmix64, matching the SDK's deterministic mode.
+type rng uint64
+
+func (r *rng) next() uint64 {
+	*r += 0x9e3779b97f4a7c15
+	z := uint64(*r)
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (r *rng) intn(n int) int {
+	return int(r.next() % uint64(n))
+}
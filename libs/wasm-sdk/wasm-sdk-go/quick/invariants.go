@@ -0,0 +1,120 @@
+package quick
+
+import (
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// Defaults returns NoPanic, DeclaredPins and ValidOutputs.
+func Defaults() []Invariant {
+	return []Invariant{NoPanic, DeclaredPins, ValidOutputs}
+}
+
+// NoPanic fails when the handler panics.
+var NoPanic = Invariant{Name: "no_panic", Check: func(r *Run) string {
+	for _, issue := range r.Issues {
+		if issue.Kind == sdk.IssuePanic {
+			return issue.Detail
+		}
+	}
+	return ""
+}}
+
+// DeclaredPins fails when the handler reads, sets or activates a pin it does
+// not declare, or uses a capability without its permission.
+var DeclaredPins = Invariant{Name: "declared_pins", Check: func(r *Run) string {
+	var details []string
+	for _, issue := range r.Issues {
+		if issue.Kind != sdk.IssuePanic {
+			details = append(details, issue.Detail)
+		}
+	}
+	return strings.Join(details, "; ")
+}}
+
+// ValidOutputs fails when an output does not match its pin: its JSON Schema
+// if the pin has one, otherwise its data and value type.
+var ValidOutputs = Invariant{Name: "valid_outputs", Check: func(r *Run) string {
+	var details []string
+	for i := range r.Def.Pins {
+		p := &r.Def.Pins[i]
+		raw, ok := r.Result.Outputs[p.Name]
+		if !ok || p.PinType != "Output" || p.DataType == sdk.DataTypeExec {
+			continue
+		}
+		value := sdk.NewRawValue(raw)
+		if value.Kind() == sdk.KindInvalid {
+			details = append(details, p.Name+": not valid JSON")
+			continue
+		}
+		if p.Schema != nil {
+			for _, v := range sdk.ValidateSchema(*p.Schema, value) {
+				details = append(details, p.Name+v.Path+": "+v.Message)
+			}
+			continue
+		}
+		if msg := checkType(p, value); msg != "" {
+			details = append(details, p.Name+": "+msg)
+		}
+	}
+	return strings.Join(details, "; ")
+}}
+
+// NoError fails when a run finishes with an error. It is not part of
+// Defaults: rejecting invalid input is usually correct behaviour, so use it
+// for nodes that must accept everything their pins allow.
+var NoError = Invariant{Name: "no_error", Check: func(r *Run) string {
+	if r.Result.Error != nil {
+		return *r.Result.Error
+	}
+	return ""
+}}
+
+func checkType(p *sdk.PinDefinition, value sdk.RawValue) string {
+	if p.ValueType != nil {
+		switch *p.ValueType {
+		case "Array", "HashSet":
+			if value.Kind() != sdk.KindArray {
+				return "expected an array, got " + value.Kind().String()
+			}
+			for _, item := range value.Items() {
+				if msg := checkScalar(p.DataType, item); msg != "" {
+					return msg
+				}
+			}
+			return ""
+		case "HashMap":
+			if value.Kind() != sdk.KindObject {
+				return "expected an object, got " + value.Kind().String()
+			}
+			for _, item := range value.Fields() {
+				if msg := checkScalar(p.DataType, item); msg != "" {
+					return msg
+				}
+			}
+			return ""
+		}
+	}
+	return checkScalar(p.DataType, value)
+}
+
+func checkScalar(dataType string, value sdk.RawValue) string {
+	ok := true
+	switch dataType {
+	case sdk.DataTypeString, sdk.DataTypeDate, sdk.DataTypePathBuf:
+		ok = value.Kind() == sdk.KindString
+	case sdk.DataTypeI64:
+		_, ok = value.Int()
+	case sdk.DataTypeF64:
+		ok = value.Kind() == sdk.KindNumber
+	case sdk.DataTypeBool:
+		ok = value.Kind() == sdk.KindBool
+	case sdk.DataTypeBytes:
+		ok = value.Kind() == sdk.KindString || value.Bytes() != nil
+	}
+	if !ok {
+		return "expected " + dataType + ", got " + value.Raw()
+	}
+	return ""
+}
@@ -0,0 +1,175 @@
+// Package quick property-tests node handlers. Check runs a handler many times
+// with random inputs generated from its definition — pin defaults, data
+// types, JSON Schemas and their enums, plus edge cases such as empty strings,
+// extreme numbers and missing inputs — and reports runs that break an
+// invariant: a panic, undeclared pins or permissions, or outputs that do not
+// match their pin.
+//
+// Runs are dry runs (see sdk.CheckRun): host calls made through the Context
+// are not executed, so handlers are exercised without side effects. Export
+// the results next to check_nodes:
+//
+//	//export quick_check
+//	func quickCheck() int64 {
+//		return quick.Export(quick.Config{Runs: 200}, &MyNode{}, &OtherNode{})
+//	}
+//
+// Every failure carries the seed of its run; quick.Inputs(def, seed)
+// rebuilds the exact inputs to reproduce it.
+package quick
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// DefaultRuns is the number of runs per handler when Config.Runs is zero.
+const DefaultRuns = 100
+
+// Config tunes Check.
+type Config struct {
+	// Runs per handler; zero means DefaultRuns.
+	Runs int
+	// Seed of the first run. Run i uses a seed derived from Seed and i.
+	Seed int64
+	// Invariants to assert; nil means Defaults().
+	Invariants []Invariant
+}
+
+// Run is one execution of a handler under test.
+type Run struct {
+	Def    *sdk.NodeDefinition
+	Seed   int64
+	Inputs map[string]string
+	Result sdk.ExecutionResult
+	Issues []sdk.CheckIssue
+}
+
+// Invariant is a property every run must satisfy. Check returns "" when the
+// run satisfies it and a description of the violation otherwise.
+type Invariant struct {
+	Name  string
+	Check func(r *Run) string
+}
+
+// Failure is the first run that violated an invariant.
+type Failure struct {
+	Node      string
+	Invariant string
+	Message   string
+	Seed      int64
+	Inputs    map[string]string
+}
+
+func (f *Failure) String() string {
+	return f.Node + ": " + f.Invariant + ": " + f.Message + " (seed " + strconv.FormatInt(f.Seed, 10) + ")"
+}
+
+func (f *Failure) ToJSON() string {
+	names := make([]string, 0, len(f.Inputs))
+	for name := range f.Inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(`{"node":`)
+	b.WriteString(sdk.JSONString(f.Node))
+	b.WriteString(`,"invariant":`)
+	b.WriteString(sdk.JSONString(f.Invariant))
+	b.WriteString(`,"message":`)
+	b.WriteString(sdk.JSONString(f.Message))
+	b.WriteString(`,"seed":`)
+	b.WriteString(strconv.FormatInt(f.Seed, 10))
+	b.WriteString(`,"inputs":{`)
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(sdk.JSONString(name))
+		b.WriteByte(':')
+		b.WriteString(f.Inputs[name])
+	}
+	b.WriteString(`}}`)
+	return b.String()
+}
+
+// Check runs h cfg.Runs times and returns the first failure of each
+// invariant, or nil if every run passed.
+func Check(h sdk.NodeHandler, cfg Config) []Failure {
+	runs := cfg.Runs
+	if runs <= 0 {
+		runs = DefaultRuns
+	}
+	invariants := cfg.Invariants
+	if invariants == nil {
+		invariants = Defaults()
+	}
+	def := h.Define()
+	failed := make(map[string]bool)
+	var failures []Failure
+	for i := 0; i < runs && len(failed) < len(invariants); i++ {
+		seed := runSeed(cfg.Seed, i)
+		inputs := Inputs(&def, seed)
+		result, issues := sdk.CheckRun(h, sdk.ExecutionInput{
+			Inputs:        copyInputs(inputs),
+			RunID:         "quick-" + strconv.Itoa(i),
+			LogLevel:      sdk.LogLevelError,
+			Deterministic: &sdk.DeterministicInput{Seed: seed},
+		})
+		r := Run{Def: &def, Seed: seed, Inputs: inputs, Result: result, Issues: issues}
+		for _, inv := range invariants {
+			if failed[inv.Name] {
+				continue
+			}
+			if msg := inv.Check(&r); msg != "" {
+				failed[inv.Name] = true
+				failures = append(failures, Failure{
+					Node: def.Name, Invariant: inv.Name, Message: msg, Seed: seed, Inputs: inputs,
+				})
+			}
+		}
+	}
+	return failures
+}
+
+// CheckAll runs Check for every handler.
+func CheckAll(cfg Config, handlers ...sdk.NodeHandler) []Failure {
+	var failures []Failure
+	for _, h := range handlers {
+		failures = append(failures, Check(h, cfg)...)
+	}
+	return failures
+}
+
+// Export serializes CheckAll's failures as a JSON array and returns a packed
+// i64 for a quick_check export.
+func Export(cfg Config, handlers ...sdk.NodeHandler) int64 {
+	failures := CheckAll(cfg, handlers...)
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := range failures {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(failures[i].ToJSON())
+	}
+	b.WriteByte(']')
+	return sdk.PackResult(b.String())
+}
+
+// runSeed spreads run indices over the seed space so neighbouring runs do
+// not share structure.
+func runSeed(base int64, i int) int64 {
+	return base + int64(i)*-7046029254386353131 // 0x9e3779b97f4a7c15
+}
+
+func copyInputs(in map[string]string) map[string]string {
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,230 @@
+package sdk
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Kind is the JSON type of a RawValue.
+type Kind uint8
+
+const (
+	KindInvalid Kind = iota
+	KindNull
+	KindBool
+	KindNumber
+	KindString
+	KindArray
+	KindObject
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNull:
+		return "null"
+	case KindBool:
+		return "bool"
+	case KindNumber:
+		return "number"
+	case KindString:
+		return "string"
+	case KindArray:
+		return "array"
+	case KindObject:
+		return "object"
+	}
+	return "invalid"
+}
+
+// RawValue is an undecoded JSON value, typically a pin input. Decoding work is
+// done on first use and cached, so repeated accessors do not re-parse the
+// input. Copies of a RawValue share the cache.
+type RawValue struct {
+	raw   string
+	cache *rawCache
+}
+
+type rawCache struct {
+	str    *string
+	items  []RawValue
+	fields map[string]RawValue
+}
+
+// NewRawValue wraps a JSON document. Surrounding whitespace is ignored.
+func NewRawValue(raw string) RawValue {
+	return RawValue{raw: strings.TrimSpace(raw), cache: &rawCache{}}
+}
+
+// GetRaw returns an input without decoding it.
+func (c *Context) GetRaw(name string) (RawValue, bool) {
+	v, ok := c.lookup(name)
+	if !ok {
+		return RawValue{}, false
+	}
+	return NewRawValue(v), true
+}
+
+// Kind reports the JSON type by looking at the first byte only.
+func (v RawValue) Kind() Kind {
+	if v.raw == "" {
+		return KindInvalid
+	}
+	switch v.raw[0] {
+	case 'n':
+		return KindNull
+	case 't', 'f':
+		return KindBool
+	case '"':
+		return KindString
+	case '[':
+		return KindArray
+	case '{':
+		return KindObject
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return KindNumber
+	}
+	return KindInvalid
+}
+
+// Raw returns the JSON text of the value.
+func (v RawValue) Raw() string { return v.raw }
+
+// IsNull reports whether the value is JSON null or absent.
+func (v RawValue) IsNull() bool { return v.raw == "" || v.raw == "null" }
+
+// String returns the decoded text of a string value and the JSON text of any
+// other kind.
+func (v RawValue) String() string {
+	if v.Kind() != KindString {
+		return v.raw
+	}
+	if v.cache == nil {
+		return jsonUnquote(v.raw)
+	}
+	if v.cache.str == nil {
+		s := jsonUnquote(v.raw)
+		v.cache.str = &s
+	}
+	return *v.cache.str
+}
+
+// Bytes returns the binary content of the value: the elements of an array of
+// byte values (how the runtime serializes byte buffers), or the UTF-8 bytes of
+// a string. Other kinds, and arrays with non-byte elements, yield nil.
+func (v RawValue) Bytes() []byte {
+	switch v.Kind() {
+	case KindString:
+		return []byte(v.String())
+	case KindArray:
+		items := v.Items()
+		out := make([]byte, len(items))
+		for i, item := range items {
+			n, err := strconv.ParseUint(item.raw, 10, 8)
+			if err != nil {
+				return nil
+			}
+			out[i] = byte(n)
+		}
+		return out
+	}
+	return nil
+}
+
+// Int decodes an integral number. Floats without a fractional part are accepted.
+func (v RawValue) Int() (int64, bool) {
+	if v.Kind() != KindNumber {
+		return 0, false
+	}
+	if n, err := strconv.ParseInt(v.raw, 10, 64); err == nil {
+		return n, true
+	}
+	f, err := strconv.ParseFloat(v.raw, 64)
+	if err != nil || f != math.Trunc(f) || f < math.MinInt64 || f > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// Float decodes a number.
+func (v RawValue) Float() (float64, bool) {
+	if v.Kind() != KindNumber {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(v.raw, 64)
+	return f, err == nil
+}
+
+// Bool decodes a boolean.
+func (v RawValue) Bool() (bool, bool) {
+	switch v.raw {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+	return false, false
+}
+
+// Items returns the elements of an array, or nil for other kinds.
+func (v RawValue) Items() []RawValue {
+	if v.Kind() != KindArray {
+		return nil
+	}
+	if v.cache != nil && v.cache.items != nil {
+		return v.cache.items
+	}
+	raw := jsonArrayItems(v.raw)
+	items := make([]RawValue, len(raw))
+	for i, r := range raw {
+		items[i] = NewRawValue(r)
+	}
+	if v.cache != nil {
+		v.cache.items = items
+	}
+	return items
+}
+
+// Len returns the number of array elements or object members.
+func (v RawValue) Len() int {
+	switch v.Kind() {
+	case KindArray:
+		return len(v.Items())
+	case KindObject:
+		return len(v.Fields())
+	}
+	return 0
+}
+
+// Fields returns the members of an object, or nil for other kinds.
+func (v RawValue) Fields() map[string]RawValue {
+	if v.Kind() != KindObject {
+		return nil
+	}
+	if v.cache != nil && v.cache.fields != nil {
+		return v.cache.fields
+	}
+	fields := make(map[string]RawValue)
+	for k, r := range jsonObjectFields(v.raw) {
+		fields[k] = NewRawValue(r)
+	}
+	if v.cache != nil {
+		v.cache.fields = fields
+	}
+	return fields
+}
+
+// Field returns an object member.
+func (v RawValue) Field(name string) (RawValue, bool) {
+	f, ok := v.Fields()[name]
+	return f, ok
+}
+
+// Index returns an array element.
+func (v RawValue) Index(i int) (RawValue, bool) {
+	items := v.Items()
+	if i < 0 || i >= len(items) {
+		return RawValue{}, false
+	}
+	return items[i], true
+}
@@ -0,0 +1,209 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultRecordDir is the folder, inside the node's storage, that model call
+// records are written to when RecorderOptions.Dir is empty.
+const DefaultRecordDir = "model-calls"
+
+// RecorderOptions configures RecordModelCalls.
+type RecorderOptions struct {
+	// Dir is the folder inside node-scoped storage; empty uses
+	// DefaultRecordDir.
+	Dir string
+	// Redact rewrites every prompt, reply, tool argument and embedded text
+	// before it is recorded, e.g. privacy.Redact. Calls sent to the model
+	// are not changed.
+	Redact func(string) string
+	// MetadataOnly drops prompts and replies and keeps timing, token counts
+	// and outcome.
+	MetadataOnly bool
+	// NoStorage skips the storage artifacts; records are still traced and
+	// kept for ModelCalls.
+	NoStorage bool
+}
+
+// ModelCallRecord is one model call seen by the recorder. Request and
+// Response are JSON, already redacted, and empty with MetadataOnly.
+type ModelCallRecord struct {
+	Seq              int    `json:"seq"`
+	Kind             string `json:"kind"` // "chat", "chat_json" or "embed"
+	Model            string `json:"model"`
+	StartedAt        int64  `json:"started_at"`
+	DurationMs       int64  `json:"duration_ms"`
+	OK               bool   `json:"ok"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	Request          string `json:"request,omitempty"`
+	Response         string `json:"response,omitempty"`
+	Path             string `json:"-"`
+}
+
+func (r *ModelCallRecord) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"seq":`)
+	b.WriteString(strconv.Itoa(r.Seq))
+	b.WriteString(`,"kind":`)
+	b.WriteString(jsonString(r.Kind))
+	b.WriteString(`,"model":`)
+	b.WriteString(jsonString(r.Model))
+	b.WriteString(`,"started_at":`)
+	b.WriteString(strconv.FormatInt(r.StartedAt, 10))
+	b.WriteString(`,"duration_ms":`)
+	b.WriteString(strconv.FormatInt(r.DurationMs, 10))
+	b.WriteString(`,"ok":`)
+	b.WriteString(strconv.FormatBool(r.OK))
+	b.WriteString(`,"prompt_tokens":`)
+	b.WriteString(strconv.Itoa(r.PromptTokens))
+	b.WriteString(`,"completion_tokens":`)
+	b.WriteString(strconv.Itoa(r.CompletionTokens))
+	if r.Request != "" {
+		b.WriteString(`,"request":`)
+		b.WriteString(r.Request)
+	}
+	if r.Response != "" {
+		b.WriteString(`,"response":`)
+		b.WriteString(r.Response)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// modelRecorder is the per-run state behind RecordModelCalls.
+type modelRecorder struct {
+	opts    RecorderOptions
+	records []ModelCallRecord
+}
+
+// RecordModelCalls turns on recording of every chat and embedding call made
+// through this context for the rest of the run. Each call is written as a
+// JSON artifact to <node storage>/<Dir>/<run id>-<seq>.json and summarized
+// in a trace log line, so LLM nodes can be debugged and audited after the
+// fact. Prompts and replies pass through opts.Redact first.
+func (c *Context) RecordModelCalls(opts RecorderOptions) {
+	if opts.Dir == "" {
+		opts.Dir = DefaultRecordDir
+	}
+	c.rec = &modelRecorder{opts: opts}
+}
+
+// ModelCalls returns the calls recorded so far in this run.
+func (c *Context) ModelCalls() []ModelCallRecord {
+	if c.rec == nil {
+		return nil
+	}
+	return c.rec.records
+}
+
+func (c *Context) chatComplete(kind, bitJSON string, req ChatRequest, call func() (ChatResponse, bool)) (ChatResponse, bool) {
+	if c.rec == nil {
+		return call()
+	}
+	start := TimeNow()
+	resp, ok := call()
+	rec := ModelCallRecord{Kind: kind, Model: modelID(bitJSON), StartedAt: start, OK: ok}
+	rec.DurationMs = TimeNow() - start
+	rec.PromptTokens, rec.CompletionTokens = resp.PromptTokens, resp.CompletionTokens
+	if !c.rec.opts.MetadataOnly {
+		redacted := req
+		redacted.Messages = make([]ChatMessage, len(req.Messages))
+		for i, m := range req.Messages {
+			m.Content = c.rec.redact(m.Content)
+			m.ToolCalls = c.rec.redactCalls(m.ToolCalls)
+			redacted.Messages[i] = m
+		}
+		rec.Request = redacted.ToJSON()
+		if ok {
+			out := ChatResponse{
+				Content:          c.rec.redact(resp.Content),
+				FinishReason:     resp.FinishReason,
+				PromptTokens:     resp.PromptTokens,
+				CompletionTokens: resp.CompletionTokens,
+				ToolCalls:        c.rec.redactCalls(resp.ToolCalls),
+			}
+			rec.Response = out.ToJSON()
+		}
+	}
+	c.record(rec)
+	return resp, ok
+}
+
+// embedText embeds textsJSON and records the call. The failure reason is
+// taken before recording, whose storage writes would replace it.
+func (c *Context) embedText(bitJSON, textsJSON string) (string, error) {
+	var start int64
+	if c.rec != nil {
+		start = TimeNow()
+	}
+	out := EmbedText(bitJSON, textsJSON)
+	var err error
+	if out == "" {
+		err = hostFailure("EmbedText", ErrCodeUpstream)
+	}
+	if c.rec == nil {
+		return out, err
+	}
+	rec := ModelCallRecord{Kind: "embed", Model: modelID(bitJSON), StartedAt: start, OK: out != ""}
+	rec.DurationMs = TimeNow() - start
+	if !c.rec.opts.MetadataOnly {
+		texts := parseStringArrayJSON(textsJSON)
+		for i := range texts {
+			texts[i] = c.rec.redact(texts[i])
+		}
+		// Vectors are bulky and reveal little, so only their count is kept.
+		rec.Request = `{"texts":` + jsonStringArray(texts) + `}`
+		if rec.OK {
+			rec.Response = `{"vectors":` + strconv.Itoa(len(jsonArrayItems(out))) + `}`
+		}
+	}
+	c.record(rec)
+	return out, err
+}
+
+func (c *Context) record(rec ModelCallRecord) {
+	rec.Seq = len(c.rec.records) + 1
+	if !c.rec.opts.NoStorage {
+		dir := StorageDir(true)
+		if dir != "" {
+			path, err := JoinStoragePath(dir, c.rec.opts.Dir, c.input.RunID+"-"+strconv.Itoa(rec.Seq)+".json")
+			if err != nil {
+				c.Warn("model call recorder: " + err.Error())
+			} else if StorageWrite(path, rec.ToJSON()) {
+				rec.Path = path
+			} else {
+				c.Warn("model call recorder: could not write " + path)
+			}
+		}
+	}
+	c.rec.records = append(c.rec.records, rec)
+	LogTrace("model call " + strconv.Itoa(rec.Seq) + " " + rec.Kind + " " + rec.Model +
+		" ok=" + strconv.FormatBool(rec.OK) + " " + strconv.FormatInt(rec.DurationMs, 10) + "ms" +
+		" tokens=" + strconv.Itoa(rec.PromptTokens) + "/" + strconv.Itoa(rec.CompletionTokens))
+}
+
+func (r *modelRecorder) redact(s string) string {
+	if r.opts.Redact == nil || s == "" {
+		return s
+	}
+	return r.opts.Redact(s)
+}
+
+func (r *modelRecorder) redactCalls(calls []ToolCall) []ToolCall {
+	if r.opts.Redact == nil || len(calls) == 0 {
+		return calls
+	}
+	out := make([]ToolCall, len(calls))
+	for i, call := range calls {
+		call.Arguments = r.redact(call.Arguments)
+		out[i] = call
+	}
+	return out
+}
+
+// modelID returns the id of a raw Bit JSON, or "" when it has none.
+func modelID(bitJSON string) string {
+	return jsonUnquote(jsonObjectFields(bitJSON)["id"])
+}
@@ -0,0 +1,174 @@
+package sdk
+
+import "strings"
+
+// NodeHandler is implemented by each node in a module. Define describes the
+// node's pins and metadata, Run executes it for a single invocation.
+type NodeHandler interface {
+	Define() NodeDefinition
+	Run(ctx *Context) ExecutionResult
+}
+
+// Registry dispatches the get_nodes and run exports to a set of NodeHandlers,
+// letting one WASM binary ship a whole node pack.
+type Registry struct {
+	handlers []NodeHandler
+	defs     []NodeDefinition
+	byName   map[string]int
+	strict   bool
+	verify   bool
+	validate bool
+	manifest *Manifest
+	pack     *PackManifest
+
+	initHooks     []func() error
+	shutdownHooks []func()
+	initDone      bool
+	initErr       error
+}
+
+// NewRegistry creates a registry with the given handlers already registered.
+func NewRegistry(handlers ...NodeHandler) *Registry {
+	r := &Registry{byName: make(map[string]int)}
+	for _, h := range handlers {
+		r.Register(h)
+	}
+	return r
+}
+
+// Register adds a handler. A handler whose definition reuses an existing node
+// name replaces the earlier one.
+func (r *Registry) Register(h NodeHandler) *Registry {
+	def := h.Define()
+	r.applyPack(&def)
+	if i, ok := r.byName[def.Name]; ok {
+		r.handlers[i] = h
+		r.defs[i] = def
+		return r
+	}
+	r.byName[def.Name] = len(r.handlers)
+	r.handlers = append(r.handlers, h)
+	r.defs = append(r.defs, def)
+	return r
+}
+
+// SetStrict runs every node in strict mode (see Context.Strict). Enable it in
+// development builds to surface wiring bugs early.
+func (r *Registry) SetStrict(strict bool) *Registry {
+	r.strict = strict
+	return r
+}
+
+// SetVerifyOutputs enables output completeness checking (see
+// Context.VerifyOutputs) for every node in the registry.
+func (r *Registry) SetVerifyOutputs(verify bool) *Registry {
+	r.verify = verify
+	return r
+}
+
+// SetValidateInputs checks every input against its pin's JSON Schema before
+// a node runs (see Context.ValidateInputs); runs with violations fail with an
+// invalid_input error naming the pin and path instead of reaching the handler.
+func (r *Registry) SetValidateInputs(validate bool) *Registry {
+	r.validate = validate
+	return r
+}
+
+// Definitions returns the definitions of all registered nodes in registration order.
+func (r *Registry) Definitions() []NodeDefinition {
+	return r.defs
+}
+
+// Handler looks up the handler registered under the given node name.
+func (r *Registry) Handler(name string) (NodeHandler, bool) {
+	i, ok := r.byName[name]
+	if !ok {
+		return nil, false
+	}
+	return r.handlers[i], true
+}
+
+// DefinitionsJSON serializes all definitions as a JSON array.
+func (r *Registry) DefinitionsJSON() string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := range r.defs {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(r.defs[i].ToJSON())
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// GetNodes implements the get_nodes export.
+func (r *Registry) GetNodes() int64 {
+	return PackResult(r.DefinitionsJSON())
+}
+
+// GetNode implements the single-node get_node export that hosts without
+// get_nodes support call. It returns the first registered definition, so
+// such hosts see only that node of a pack.
+func (r *Registry) GetNode() int64 {
+	if len(r.defs) == 0 {
+		return PackResult("{}")
+	}
+	return SerializeDefinition(r.defs[0])
+}
+
+// Run implements the run export, routing the call to the handler matching the
+// input's node name. Modules with a single node accept an empty node name.
+func (r *Registry) Run(ptr uint32, length uint32) int64 {
+	input := ParseInput(ptr, length)
+	return SerializeResult(r.Execute(input))
+}
+
+// Execute runs the matching handler for an already parsed input. A handler
+// that panics fails the run with a *PanicError whose trace is logged.
+func (r *Registry) Execute(input ExecutionInput) ExecutionResult {
+	i, ok := r.byName[input.NodeName]
+	if !ok && input.NodeName == "" && len(r.handlers) == 1 {
+		i, ok = 0, true
+	}
+	if !ok {
+		return FailResult("unknown node: " + input.NodeName)
+	}
+	if err := r.Init(); err != nil {
+		info := errorInfoFor(err)
+		info.Message = "module init failed: " + info.Message
+		res := FailResult(info.Message)
+		res.ErrorInfo = &info
+		return res
+	}
+	ctx := NewContext(input)
+	ctx.BindDefinition(&r.defs[i])
+	if r.pack != nil {
+		ctx.packForm = r.pack.Config
+	}
+	if r.strict {
+		ctx.Strict()
+	}
+	if r.verify {
+		ctx.VerifyOutputs(true)
+	}
+	if r.validate {
+		if err := ctx.ValidateInputs(); err != nil {
+			return ctx.Result(err)
+		}
+	}
+	return runHandler(r.handlers[i], ctx)
+}
+
+// runHandler runs h, turning a panic into a failed run: the trace is logged
+// with LogError and the result carries a PanicError.
+func runHandler(h NodeHandler, ctx *Context) (result ExecutionResult) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err := recoveredPanic(rec)
+			LogError(err.Trace())
+			result = ctx.Result(err)
+		}
+	}()
+	return h.Run(ctx)
+}
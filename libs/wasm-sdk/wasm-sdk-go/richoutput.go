@@ -0,0 +1,259 @@
+package sdk
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RichOutput is a visual result the frontend renders in place of plain
+// streamed text: a Table, Chart, CodeBlock or FileCard.
+type RichOutput interface {
+	// EventType is the stream event type the UI dispatches on.
+	EventType() string
+	ToJSON() string
+}
+
+// Emit streams a rich output to the UI. Like StreamText it does nothing when
+// the run is not streaming.
+func (c *Context) Emit(out RichOutput) {
+	if c.StreamEnabled() && c.host(PermStreaming) {
+		StreamEmit(out.EventType(), out.ToJSON())
+	}
+}
+
+// Column types of a Table. They control alignment, sorting and formatting
+// in the UI.
+const (
+	ColumnString = "string"
+	ColumnNumber = "number"
+	ColumnBool   = "boolean"
+	ColumnDate   = "date" // RFC 3339 or YYYY-MM-DD
+	ColumnURL    = "url"
+)
+
+// TableColumn is a column header of a Table.
+type TableColumn struct {
+	Name string
+	Type string // a Column* constant; "" is ColumnString
+}
+
+// Table is a rendered table. Cells are given as text and encoded per column
+// type: number and boolean cells that parse are sent as JSON numbers and
+// booleans, everything else as strings.
+type Table struct {
+	Title   string
+	Columns []TableColumn
+	Rows    [][]string
+}
+
+// NewTable creates a table with string columns of the given names.
+func NewTable(title string, columns ...string) *Table {
+	t := &Table{Title: title, Columns: make([]TableColumn, len(columns))}
+	for i, name := range columns {
+		t.Columns[i] = TableColumn{Name: name, Type: ColumnString}
+	}
+	return t
+}
+
+// SetColumnType changes the type of the named column.
+func (t *Table) SetColumnType(name, typ string) *Table {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			t.Columns[i].Type = typ
+		}
+	}
+	return t
+}
+
+// AddRow appends a row; missing cells are empty, extra cells are dropped.
+func (t *Table) AddRow(cells ...string) *Table {
+	t.Rows = append(t.Rows, cells)
+	return t
+}
+
+func (t *Table) EventType() string { return "table" }
+
+func (t *Table) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"title":`)
+	b.WriteString(jsonString(t.Title))
+	b.WriteString(`,"columns":[`)
+	for i, col := range t.Columns {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		typ := col.Type
+		if typ == "" {
+			typ = ColumnString
+		}
+		b.WriteString(`{"name":`)
+		b.WriteString(jsonString(col.Name))
+		b.WriteString(`,"type":`)
+		b.WriteString(jsonString(typ))
+		b.WriteByte('}')
+	}
+	b.WriteString(`],"rows":[`)
+	for i, row := range t.Rows {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('[')
+		for j, col := range t.Columns {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+			cell := ""
+			if j < len(row) {
+				cell = row[j]
+			}
+			b.WriteString(tableCellJSON(col.Type, cell))
+		}
+		b.WriteByte(']')
+	}
+	b.WriteString(`]}`)
+	return b.String()
+}
+
+func tableCellJSON(typ, cell string) string {
+	switch typ {
+	case ColumnNumber:
+		if cell == "" {
+			return "null"
+		}
+		if f, err := strconv.ParseFloat(cell, 64); err == nil {
+			return chartNumber(f)
+		}
+	case ColumnBool:
+		if v, err := strconv.ParseBool(cell); err == nil {
+			return strconv.FormatBool(v)
+		}
+	}
+	return jsonString(cell)
+}
+
+// Chart kinds.
+const (
+	ChartLine = "line"
+	ChartBar  = "bar"
+	ChartArea = "area"
+	ChartPie  = "pie"
+)
+
+// ChartSeries is one named data series. Values align with Chart.Labels.
+type ChartSeries struct {
+	Name   string
+	Values []float64
+}
+
+// Chart is a rendered chart. Labels are the x-axis categories (or pie
+// slices); a pie chart uses only its first series.
+type Chart struct {
+	Kind   string // a Chart* constant; "" is ChartLine
+	Title  string
+	XLabel string
+	YLabel string
+	Labels []string
+	Series []ChartSeries
+}
+
+func (c *Chart) EventType() string { return "chart" }
+
+func (c *Chart) ToJSON() string {
+	kind := c.Kind
+	if kind == "" {
+		kind = ChartLine
+	}
+	var b strings.Builder
+	b.WriteString(`{"kind":`)
+	b.WriteString(jsonString(kind))
+	b.WriteString(`,"title":`)
+	b.WriteString(jsonString(c.Title))
+	if c.XLabel != "" {
+		b.WriteString(`,"x_label":`)
+		b.WriteString(jsonString(c.XLabel))
+	}
+	if c.YLabel != "" {
+		b.WriteString(`,"y_label":`)
+		b.WriteString(jsonString(c.YLabel))
+	}
+	b.WriteString(`,"labels":`)
+	b.WriteString(jsonStringArray(c.Labels))
+	b.WriteString(`,"series":[`)
+	for i, s := range c.Series {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(`{"name":`)
+		b.WriteString(jsonString(s.Name))
+		b.WriteString(`,"values":[`)
+		for j, v := range s.Values {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(chartNumber(v))
+		}
+		b.WriteString(`]}`)
+	}
+	b.WriteString(`]}`)
+	return b.String()
+}
+
+// chartNumber encodes v as JSON; NaN and infinities, which JSON cannot
+// represent, become null (a gap in the chart).
+func chartNumber(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "null"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// CodeBlock is source code rendered with syntax highlighting and a copy
+// button. Language is a highlighter name such as "go", "sql" or "json".
+type CodeBlock struct {
+	Language string
+	Code     string
+	Filename string
+}
+
+func (c *CodeBlock) EventType() string { return "code" }
+
+func (c *CodeBlock) ToJSON() string {
+	s := `{"language":` + jsonString(c.Language) + `,"code":` + jsonString(c.Code)
+	if c.Filename != "" {
+		s += `,"filename":` + jsonString(c.Filename)
+	}
+	return s + `}`
+}
+
+// FileCard is a downloadable file. Path is a flow storage path the UI
+// resolves to a download; URL is used as is when Path is empty.
+type FileCard struct {
+	Name     string
+	MimeType string
+	Size     int64
+	Path     string
+	URL      string
+}
+
+func (f *FileCard) EventType() string { return "file" }
+
+func (f *FileCard) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"name":`)
+	b.WriteString(jsonString(f.Name))
+	b.WriteString(`,"mime_type":`)
+	b.WriteString(jsonString(f.MimeType))
+	b.WriteString(`,"size":`)
+	b.WriteString(strconv.FormatInt(f.Size, 10))
+	if f.Path != "" {
+		b.WriteString(`,"path":`)
+		b.WriteString(jsonString(f.Path))
+	}
+	if f.URL != "" {
+		b.WriteString(`,"url":`)
+		b.WriteString(jsonString(f.URL))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
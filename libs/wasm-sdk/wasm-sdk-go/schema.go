@@ -0,0 +1,393 @@
+package sdk
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SchemaViolation is one way a value fails its JSON Schema. Path is a JSON
+// Pointer to the offending part of the value, "" for the value itself.
+type SchemaViolation struct {
+	Path    string
+	Message string
+}
+
+func (v SchemaViolation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// SchemaError reports an input that does not match the JSON Schema attached
+// to its pin.
+type SchemaError struct {
+	Pin        string
+	Violations []SchemaViolation
+}
+
+func (e *SchemaError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	return "input " + strconv.Quote(e.Pin) + " does not match its schema: " + strings.Join(parts, "; ")
+}
+
+func (e *SchemaError) errorInfo() ErrorInfo {
+	return ErrorInfo{Code: ErrCodeInvalidInput, Message: e.Error(), Pin: e.Pin}
+}
+
+// ValidateInput checks an input against the JSON Schema attached to its pin
+// with WithSchema. It returns nil when the pin has no schema or no definition
+// is bound, a *MissingInputError when the input has no value, and a
+// *SchemaError listing every violation otherwise.
+func (c *Context) ValidateInput(name string) error {
+	schema := c.inputSchema(name)
+	if schema == "" {
+		return nil
+	}
+	raw, ok := c.lookup(name)
+	if !ok {
+		return &MissingInputError{Pin: name}
+	}
+	if violations := ValidateSchema(schema, NewRawValue(raw)); len(violations) > 0 {
+		return &SchemaError{Pin: name, Violations: violations}
+	}
+	return nil
+}
+
+// ValidateInputs runs ValidateInput for every input pin that has a schema
+// and a value, joining the errors. Registry.SetValidateInputs calls it before
+// each run.
+func (c *Context) ValidateInputs() error {
+	if c.def == nil {
+		return nil
+	}
+	var errs []error
+	for i := range c.def.Pins {
+		p := &c.def.Pins[i]
+		if p.PinType != "Input" || p.Schema == nil {
+			continue
+		}
+		if _, ok := c.input.Inputs[p.Name]; !ok {
+			continue
+		}
+		if err := c.ValidateInput(p.Name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Context) inputSchema(name string) string {
+	if c.def == nil {
+		return ""
+	}
+	for i := range c.def.Pins {
+		p := &c.def.Pins[i]
+		if p.PinType == "Input" && p.Name == name && p.Schema != nil {
+			return *p.Schema
+		}
+	}
+	return ""
+}
+
+// ValidateSchema checks value against a JSON Schema and returns every
+// violation found. It supports the subset produced by schema generators such
+// as schemars: type, enum, const, properties, required,
+// additionalProperties, items, prefixItems, min/maxItems, uniqueItems,
+// min/maxLength, minimum, maximum, exclusiveMinimum/Maximum, multipleOf,
+// allOf, anyOf, oneOf, not and local $ref ("#/definitions/…", "#/$defs/…").
+// Other keywords, including pattern and format, are ignored. An unparsable
+// schema accepts everything.
+func ValidateSchema(schema string, value RawValue) []SchemaViolation {
+	root := NewRawValue(schema)
+	if root.Kind() != KindObject && root.Kind() != KindBool {
+		return nil
+	}
+	v := schemaValidator{root: root}
+	v.check(root, value, "", 0)
+	return v.out
+}
+
+// maxSchemaDepth bounds $ref recursion on self-referencing schemas.
+const maxSchemaDepth = 64
+
+type schemaValidator struct {
+	root RawValue
+	out  []SchemaViolation
+}
+
+func (v *schemaValidator) fail(path, msg string) {
+	v.out = append(v.out, SchemaViolation{Path: path, Message: msg})
+}
+
+// valid reports whether value matches schema without recording violations.
+func (v *schemaValidator) valid(schema, value RawValue, depth int) bool {
+	sub := schemaValidator{root: v.root}
+	sub.check(schema, value, "", depth)
+	return len(sub.out) == 0
+}
+
+func (v *schemaValidator) check(schema, value RawValue, path string, depth int) {
+	if b, ok := schema.Bool(); ok {
+		if !b {
+			v.fail(path, "no value is allowed here")
+		}
+		return
+	}
+	if depth > maxSchemaDepth {
+		return
+	}
+	s := schema.Fields()
+	if ref, ok := s["$ref"]; ok {
+		if target, ok := v.resolve(ref.String()); ok {
+			v.check(target, value, path, depth+1)
+		}
+	}
+	if t, ok := s["type"]; ok && !matchesType(t, value) {
+		v.fail(path, "expected "+typeNames(t)+", got "+jsonTypeName(value))
+		return
+	}
+	if enum, ok := s["enum"]; ok {
+		found := false
+		for _, e := range enum.Items() {
+			if jsonEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(path, "value is not one of "+enum.Raw())
+		}
+	}
+	if c, ok := s["const"]; ok && !jsonEqual(c, value) {
+		v.fail(path, "value must be "+c.Raw())
+	}
+	switch value.Kind() {
+	case KindObject:
+		v.checkObject(s, value, path, depth)
+	case KindArray:
+		v.checkArray(s, value, path, depth)
+	case KindString:
+		n := utf8.RuneCountInString(value.String())
+		if min, ok := schemaInt(s, "minLength"); ok && n < min {
+			v.fail(path, "must be at least "+strconv.Itoa(min)+" characters long")
+		}
+		if max, ok := schemaInt(s, "maxLength"); ok && n > max {
+			v.fail(path, "must be at most "+strconv.Itoa(max)+" characters long")
+		}
+	case KindNumber:
+		v.checkNumber(s, value, path)
+	}
+	if all, ok := s["allOf"]; ok {
+		for _, sub := range all.Items() {
+			v.check(sub, value, path, depth+1)
+		}
+	}
+	if anyOf, ok := s["anyOf"]; ok {
+		matched := false
+		for _, sub := range anyOf.Items() {
+			if v.valid(sub, value, depth+1) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "does not match any of the allowed schemas")
+		}
+	}
+	if oneOf, ok := s["oneOf"]; ok {
+		n := 0
+		for _, sub := range oneOf.Items() {
+			if v.valid(sub, value, depth+1) {
+				n++
+			}
+		}
+		if n != 1 {
+			v.fail(path, "must match exactly one schema, matched "+strconv.Itoa(n))
+		}
+	}
+	if not, ok := s["not"]; ok && v.valid(not, value, depth+1) {
+		v.fail(path, "must not match the excluded schema")
+	}
+}
+
+func (v *schemaValidator) checkObject(s map[string]RawValue, value RawValue, path string, depth int) {
+	fields := value.Fields()
+	if req, ok := s["required"]; ok {
+		for _, r := range req.Items() {
+			name := r.String()
+			if _, ok := fields[name]; !ok {
+				v.fail(path, "missing required property "+strconv.Quote(name))
+			}
+		}
+	}
+	props := s["properties"].Fields()
+	extra, hasExtra := s["additionalProperties"]
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p := path + "/" + escapePointer(k)
+		if sub, ok := props[k]; ok {
+			v.check(sub, fields[k], p, depth+1)
+		} else if hasExtra {
+			if b, ok := extra.Bool(); ok && !b {
+				v.fail(p, "unexpected property")
+			} else if !ok {
+				v.check(extra, fields[k], p, depth+1)
+			}
+		}
+	}
+}
+
+func (v *schemaValidator) checkArray(s map[string]RawValue, value RawValue, path string, depth int) {
+	items := value.Items()
+	if min, ok := schemaInt(s, "minItems"); ok && len(items) < min {
+		v.fail(path, "must have at least "+strconv.Itoa(min)+" items")
+	}
+	if max, ok := schemaInt(s, "maxItems"); ok && len(items) > max {
+		v.fail(path, "must have at most "+strconv.Itoa(max)+" items")
+	}
+	start := 0
+	if prefix, ok := s["prefixItems"]; ok {
+		for i, sub := range prefix.Items() {
+			if i < len(items) {
+				v.check(sub, items[i], path+"/"+strconv.Itoa(i), depth+1)
+				start = i + 1
+			}
+		}
+	}
+	if sub, ok := s["items"]; ok {
+		// Draft 4–7 tuple form: an array of schemas.
+		if sub.Kind() == KindArray {
+			for i, tuple := range sub.Items() {
+				if i < len(items) {
+					v.check(tuple, items[i], path+"/"+strconv.Itoa(i), depth+1)
+				}
+			}
+		} else {
+			for i := start; i < len(items); i++ {
+				v.check(sub, items[i], path+"/"+strconv.Itoa(i), depth+1)
+			}
+		}
+	}
+	if u, ok := s["uniqueItems"]; ok && u.Raw() == "true" {
+		for i := 1; i < len(items); i++ {
+			for j := 0; j < i; j++ {
+				if jsonEqual(items[i], items[j]) {
+					v.fail(path+"/"+strconv.Itoa(i), "duplicates item "+strconv.Itoa(j))
+					break
+				}
+			}
+		}
+	}
+}
+
+func (v *schemaValidator) checkNumber(s map[string]RawValue, value RawValue, path string) {
+	f, _ := value.Float()
+	if min, ok := s["minimum"].Float(); ok && f < min {
+		v.fail(path, "must be >= "+s["minimum"].Raw())
+	}
+	if max, ok := s["maximum"].Float(); ok && f > max {
+		v.fail(path, "must be <= "+s["maximum"].Raw())
+	}
+	if min, ok := s["exclusiveMinimum"].Float(); ok && f <= min {
+		v.fail(path, "must be > "+s["exclusiveMinimum"].Raw())
+	}
+	if max, ok := s["exclusiveMaximum"].Float(); ok && f >= max {
+		v.fail(path, "must be < "+s["exclusiveMaximum"].Raw())
+	}
+	if m, ok := s["multipleOf"].Float(); ok && m > 0 {
+		if q := f / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.fail(path, "must be a multiple of "+s["multipleOf"].Raw())
+		}
+	}
+}
+
+// resolve follows a local $ref such as "#/definitions/Address".
+func (v *schemaValidator) resolve(ref string) (RawValue, bool) {
+	if ref == "#" {
+		return v.root, true
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return RawValue{}, false
+	}
+	tokens, err := splitPointer(ref[1:])
+	if err != nil {
+		return RawValue{}, false
+	}
+	cur := v.root
+	for _, t := range tokens {
+		next, ok := cur.Field(t)
+		if !ok {
+			return RawValue{}, false
+		}
+		cur = next
+	}
+	return cur, true
+}
+
+func schemaInt(s map[string]RawValue, key string) (int, bool) {
+	n, ok := s[key].Int()
+	return int(n), ok
+}
+
+func matchesType(t, value RawValue) bool {
+	if t.Kind() == KindArray {
+		for _, item := range t.Items() {
+			if matchesTypeName(item.String(), value) {
+				return true
+			}
+		}
+		return false
+	}
+	return matchesTypeName(t.String(), value)
+}
+
+func matchesTypeName(name string, value RawValue) bool {
+	switch name {
+	case "integer":
+		_, ok := value.Int()
+		return ok
+	case "number":
+		return value.Kind() == KindNumber
+	case "boolean":
+		return value.Kind() == KindBool
+	case "null", "string", "array", "object":
+		return jsonTypeName(value) == name
+	}
+	return true
+}
+
+func typeNames(t RawValue) string {
+	if t.Kind() != KindArray {
+		return t.String()
+	}
+	names := make([]string, 0, t.Len())
+	for _, item := range t.Items() {
+		names = append(names, item.String())
+	}
+	return strings.Join(names, " or ")
+}
+
+func jsonTypeName(value RawValue) string {
+	if value.Kind() == KindBool {
+		return "boolean"
+	}
+	return value.Kind().String()
+}
+
+// jsonEqual compares two values structurally.
+func jsonEqual(a, b RawValue) bool {
+	ta, tb := parseTree(a.Raw()), parseTree(b.Raw())
+	return ta != nil && tb != nil && ta.equal(tb)
+}
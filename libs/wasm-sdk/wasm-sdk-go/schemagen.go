@@ -0,0 +1,457 @@
+package sdk
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrSchemaUnsatisfied is returned by GenerateFromSchema when no generated
+// value passed validation, typically because the schema contradicts itself.
+var ErrSchemaUnsatisfied = errors.New("schema: no valid value generated")
+
+// generateAttempts bounds how often GenerateFromSchema retries a value that
+// failed validation, e.g. because a "not" or "oneOf" excluded it.
+const generateAttempts = 16
+
+// maxGenerateDepth is the nesting depth after which optional properties and
+// array items are omitted, so recursive schemas produce finite values.
+const maxGenerateDepth = 6
+
+// GenerateFromSchema returns a random JSON value that matches a JSON Schema,
+// for board tests and property tests of pin handling. The same schema and
+// seed always produce the same value. It understands the keywords checked by
+// ValidateSchema plus the string formats date, date-time, time, email, uri
+// and uuid; every result is checked with ValidateSchema before it is
+// returned.
+func GenerateFromSchema(schema string, seed int64) (string, error) {
+	root := NewRawValue(schema)
+	if root.Kind() != KindObject && root.Kind() != KindBool {
+		return "", ErrSchemaUnsatisfied
+	}
+	g := schemaGenerator{root: root, rng: &deterministicState{rng: uint64(seed)}}
+	for i := 0; i < generateAttempts; i++ {
+		out := g.value(root, 0)
+		if len(ValidateSchema(schema, NewRawValue(out))) == 0 {
+			return out, nil
+		}
+	}
+	return "", ErrSchemaUnsatisfied
+}
+
+type schemaGenerator struct {
+	root RawValue
+	rng  *deterministicState
+}
+
+func (g *schemaGenerator) intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return int(uint64(g.rng.next()) % uint64(n))
+}
+
+func (g *schemaGenerator) float() float64 {
+	return float64(uint64(g.rng.next())>>11) / (1 << 53)
+}
+
+func (g *schemaGenerator) value(schema RawValue, depth int) string {
+	if b, ok := schema.Bool(); ok {
+		if !b {
+			return "null"
+		}
+		return g.anyScalar()
+	}
+	if depth > maxSchemaDepth {
+		return "null"
+	}
+	s := schema.Fields()
+	if ref, ok := s["$ref"]; ok {
+		v := schemaValidator{root: g.root}
+		if target, ok := v.resolve(ref.String()); ok {
+			return g.value(target, depth+1)
+		}
+	}
+	if c, ok := s["const"]; ok {
+		return c.Raw()
+	}
+	if enum, ok := s["enum"]; ok {
+		if items := enum.Items(); len(items) > 0 {
+			return items[g.intn(len(items))].Raw()
+		}
+	}
+	if all, ok := s["allOf"]; ok {
+		return g.value(mergeSchemas(s, "allOf", all.Items()...), depth)
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		if alts, ok := s[key]; ok {
+			if items := alts.Items(); len(items) > 0 {
+				return g.value(mergeSchemas(s, key, items[g.intn(len(items))]), depth)
+			}
+		}
+	}
+	switch g.typeOf(s) {
+	case "object":
+		return g.object(s, depth)
+	case "array":
+		return g.array(s, depth)
+	case "string":
+		return jsonString(g.str(s))
+	case "integer":
+		return g.integer(s)
+	case "number":
+		return g.number(s)
+	case "boolean":
+		return strconv.FormatBool(g.intn(2) == 0)
+	case "null":
+		return "null"
+	}
+	return g.anyScalar()
+}
+
+// typeOf picks the type to generate, inferring it from the keywords present
+// when "type" is missing.
+func (g *schemaGenerator) typeOf(s map[string]RawValue) string {
+	if t, ok := s["type"]; ok {
+		if t.Kind() != KindArray {
+			return t.String()
+		}
+		if items := t.Items(); len(items) > 0 {
+			return items[g.intn(len(items))].String()
+		}
+	}
+	has := func(keys ...string) bool {
+		for _, k := range keys {
+			if _, ok := s[k]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has("properties", "required", "additionalProperties"):
+		return "object"
+	case has("items", "prefixItems", "minItems", "maxItems"):
+		return "array"
+	case has("minLength", "maxLength", "format", "pattern"):
+		return "string"
+	case has("minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"):
+		return "number"
+	}
+	return ""
+}
+
+func (g *schemaGenerator) anyScalar() string {
+	switch g.intn(4) {
+	case 0:
+		return strconv.Itoa(g.intn(1000))
+	case 1:
+		return strconv.FormatBool(g.intn(2) == 0)
+	case 2:
+		return "null"
+	}
+	return jsonString(g.word(3, 10))
+}
+
+func (g *schemaGenerator) object(s map[string]RawValue, depth int) string {
+	required := map[string]bool{}
+	for _, r := range s["required"].Items() {
+		required[r.String()] = true
+	}
+	props := s["properties"].Fields()
+	names := make([]string, 0, len(props)+len(required))
+	for name := range props {
+		names = append(names, name)
+	}
+	for name := range required {
+		if _, ok := props[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteByte('{')
+	n := 0
+	add := func(name, value string) {
+		if n > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(jsonString(name))
+		b.WriteByte(':')
+		b.WriteString(value)
+		n++
+	}
+	for _, name := range names {
+		if !required[name] && (depth >= maxGenerateDepth || g.intn(4) == 0) {
+			continue
+		}
+		sub, ok := props[name]
+		if !ok {
+			sub = s["additionalProperties"]
+			if _, isBool := sub.Bool(); isBool || sub.Kind() != KindObject {
+				sub = NewRawValue("true")
+			}
+		}
+		add(name, g.value(sub, depth+1))
+	}
+	if extra, ok := s["additionalProperties"]; ok && extra.Kind() == KindObject && depth < maxGenerateDepth {
+		for i, count := 0, g.intn(3); i < count; i++ {
+			name := "extra_" + strconv.Itoa(i+1)
+			if _, taken := props[name]; !taken && !required[name] {
+				add(name, g.value(extra, depth+1))
+			}
+		}
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func (g *schemaGenerator) array(s map[string]RawValue, depth int) string {
+	var tuple []RawValue
+	if prefix, ok := s["prefixItems"]; ok {
+		tuple = prefix.Items()
+	} else if items := s["items"]; items.Kind() == KindArray {
+		tuple = items.Items()
+	}
+	item, hasItem := s["items"]
+	if hasItem && item.Kind() == KindArray {
+		hasItem = false
+	}
+	min, _ := schemaInt(s, "minItems")
+	if min < len(tuple) {
+		min = len(tuple)
+	}
+	max, hasMax := schemaInt(s, "maxItems")
+	if !hasMax || max > min+4 {
+		max = min + 4
+	}
+	if !hasItem {
+		max = len(tuple)
+		if min > max {
+			max = min
+		}
+	}
+	n := min
+	if depth < maxGenerateDepth && max > min {
+		n += g.intn(max - min + 1)
+	}
+	unique := s["uniqueItems"].Raw() == "true"
+	values := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		sub := NewRawValue("true")
+		if i < len(tuple) {
+			sub = tuple[i]
+		} else if hasItem {
+			sub = item
+		}
+		v := g.value(sub, depth+1)
+		for try := 0; unique && try < generateAttempts && containsJSON(values, v); try++ {
+			v = g.value(sub, depth+1)
+		}
+		values = append(values, v)
+	}
+	return "[" + strings.Join(values, ",") + "]"
+}
+
+func containsJSON(values []string, v string) bool {
+	for _, existing := range values {
+		if jsonEqual(NewRawValue(existing), NewRawValue(v)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *schemaGenerator) str(s map[string]RawValue) string {
+	switch s["format"].String() {
+	case "date":
+		return g.time().Format("2006-01-02")
+	case "date-time":
+		return g.time().Format(time.RFC3339)
+	case "time":
+		return g.time().Format("15:04:05")
+	case "email":
+		return g.word(3, 8) + "@example.com"
+	case "uri", "url":
+		return "https://example.com/" + g.word(3, 8)
+	case "uuid":
+		return g.uuid()
+	}
+	min, _ := schemaInt(s, "minLength")
+	max, hasMax := schemaInt(s, "maxLength")
+	if !hasMax || max > min+12 {
+		max = min + 12
+	}
+	if min == 0 && max > 0 {
+		min = 1
+	}
+	return g.word(min, max)
+}
+
+func (g *schemaGenerator) word(min, max int) string {
+	n := min
+	if max > min {
+		n += g.intn(max - min + 1)
+	}
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + g.intn(26))
+	}
+	return string(b)
+}
+
+func (g *schemaGenerator) time() time.Time {
+	// 2000-01-01 to 2030-01-01.
+	return time.Unix(946684800+int64(g.intn(946771200)), 0).UTC()
+}
+
+func (g *schemaGenerator) uuid() string {
+	const hex = "0123456789abcdef"
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(g.intn(256))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	out := make([]byte, 0, 36)
+	for i, c := range b {
+		if i == 4 || i == 6 || i == 8 || i == 10 {
+			out = append(out, '-')
+		}
+		out = append(out, hex[c>>4], hex[c&0x0f])
+	}
+	return string(out)
+}
+
+// numberBounds returns the range a number must fall in, defaulting to
+// [0, 1000] around whichever side is given.
+func numberBounds(s map[string]RawValue) (lo, hi float64, exLo, exHi bool) {
+	lo, hasLo := s["minimum"].Float()
+	if v, ok := s["exclusiveMinimum"].Float(); ok && (!hasLo || v >= lo) {
+		lo, hasLo, exLo = v, true, true
+	}
+	hi, hasHi := s["maximum"].Float()
+	if v, ok := s["exclusiveMaximum"].Float(); ok && (!hasHi || v <= hi) {
+		hi, hasHi, exHi = v, true, true
+	}
+	switch {
+	case !hasLo && !hasHi:
+		lo, hi = 0, 1000
+	case !hasLo:
+		lo = hi - 1000
+	case !hasHi:
+		hi = lo + 1000
+	}
+	return lo, hi, exLo, exHi
+}
+
+func (g *schemaGenerator) integer(s map[string]RawValue) string {
+	lo, hi, exLo, exHi := numberBounds(s)
+	min, max := int64(math.Ceil(lo)), int64(math.Floor(hi))
+	if exLo && float64(min) == lo {
+		min++
+	}
+	if exHi && float64(max) == hi {
+		max--
+	}
+	if m, ok := s["multipleOf"].Float(); ok && m > 0 {
+		k := g.multiple(lo, hi, exLo, exHi, m)
+		return strconv.FormatInt(int64(math.Round(k*m)), 10)
+	}
+	if max < min {
+		return strconv.FormatInt(min, 10)
+	}
+	return strconv.FormatInt(min+int64(uint64(g.rng.next())%uint64(max-min+1)), 10)
+}
+
+func (g *schemaGenerator) number(s map[string]RawValue) string {
+	lo, hi, exLo, exHi := numberBounds(s)
+	if m, ok := s["multipleOf"].Float(); ok && m > 0 {
+		k := g.multiple(lo, hi, exLo, exHi, m)
+		return strconv.FormatFloat(k*m, 'f', -1, 64)
+	}
+	// Two decimals keep values readable; fall back to the midpoint when
+	// rounding lands on an excluded bound.
+	f := math.Round((lo+g.float()*(hi-lo))*100) / 100
+	if f < lo || f > hi || (exLo && f == lo) || (exHi && f == hi) {
+		f = lo + (hi-lo)/2
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// multiple picks an integer k such that k*m lies within the bounds.
+func (g *schemaGenerator) multiple(lo, hi float64, exLo, exHi bool, m float64) float64 {
+	kLo, kHi := math.Ceil(lo/m), math.Floor(hi/m)
+	if exLo && kLo*m == lo {
+		kLo++
+	}
+	if exHi && kHi*m == hi {
+		kHi--
+	}
+	if kHi < kLo {
+		return kLo
+	}
+	return kLo + float64(g.intn(int(kHi-kLo)+1))
+}
+
+// mergeSchemas combines s, minus the combinator key, with subs so that the
+// result can be generated in one pass. Properties and required lists are
+// united; any other keyword takes the value of the last schema naming it.
+func mergeSchemas(s map[string]RawValue, drop string, subs ...RawValue) RawValue {
+	merged := map[string]string{}
+	props := map[string]string{}
+	var required []string
+	add := func(fields map[string]RawValue) {
+		for k, v := range fields {
+			switch k {
+			case drop:
+			case "properties":
+				for name, p := range v.Fields() {
+					props[name] = p.Raw()
+				}
+			case "required":
+				for _, r := range v.Items() {
+					required = append(required, r.String())
+				}
+			default:
+				merged[k] = v.Raw()
+			}
+		}
+	}
+	add(s)
+	for _, sub := range subs {
+		add(sub.Fields())
+	}
+	if len(props) > 0 {
+		merged["properties"] = rawObject(props)
+	}
+	if len(required) > 0 {
+		merged["required"] = jsonStringArray(required)
+	}
+	return NewRawValue(rawObject(merged))
+}
+
+// rawObject serializes fields whose values are already raw JSON, in key
+// order.
+func rawObject(fields map[string]string) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(jsonString(k))
+		b.WriteByte(':')
+		b.WriteString(fields[k])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
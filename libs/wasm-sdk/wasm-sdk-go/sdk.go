@@ -7,10 +7,65 @@
 //   - types.go:   JSON-serializable types (NodeDefinition, PinDefinition, etc.)
 //   - host.go:    Raw host import declarations and Go wrapper functions
 //   - context.go: Context struct with high-level helpers
+//   - hostcalls.go: Context.Host, host calls that return (value, error)
+//   - hosterr.go: HostError, the host's code and message for a failed call
+//   - fluent.go:  chainable Out/Activate/Done finishing on Context
+//   - errors.go:  NodeError, error-returning handlers and Context.Result
+//   - coerce.go:  typed input getters with coercion and mismatch errors
+//   - rawvalue.go: RawValue for undecoded inputs with kind detection
+//   - decimal.go: fixed-point Decimal for money math and currency formatting
+//   - strict.go:  strict mode that fails runs on wiring problems
+//   - schema.go:  JSON Schema validation of inputs against pin schemas
+//   - outputs.go: output inspection and completeness verification
+//   - check.go:   dry-run consistency checks of handlers against definitions
+//   - permissions.go: permission name constants
+//   - catalog.go: store tags, keywords, author, license and changelog of a definition
+//   - nodepolicy.go: execution declarations of a definition (concurrency, retries, timeouts, resources)
+//   - manifest.go: module manifest, build provenance and node changelogs
+//   - nodeconfig.go: per-instance config forms with resolved credential references
+//   - pack.go:    PackManifest, pack settings and permissions, GetPackConfig
+//   - httpallow.go: per-node HTTP allowlists
+//   - httpdecode.go: response decompression and charset conversion to UTF-8
+//   - audit.go:   Audit, entries in the platform's tamper-evident audit trail
+//   - app.go:     app metadata and member listing
+//   - mail.go:    typed mailbox listing, fetching and attachment export
+//   - calendar.go: calendar events and contacts of connected OAuth providers
+//   - queue.go:   at-least-once queue publish/poll/ack between boards
+//   - kv.go:      durable, namespaced key-value store with conditional writes
+//   - search.go:  full-text indexing and search with highlighting
+//   - sql.go:     SQL queries over CSV, Parquet and JSON files, in row batches
+//   - interaction.go: RequestUserInput and Confirm, human-in-the-loop prompts
+//   - forms.go:   FormDefinition builder for human tasks and config forms
+//   - richoutput.go: tables, charts, code blocks and file cards streamed to the UI
+//   - attachments.go: typed uploads of chat-triggered runs (images, PDFs, audio)
+//   - embedbatch.go: EmbedBatched, embedding in host-sized batches with retries
+//   - structured.go: schema-validated structured output from chat models
+//   - guardrails.go: moderation and output filters applied by GuardOutputs
+//   - recorder.go: opt-in recording of model calls to node storage and traces
+//   - cookiejar.go: opt-in cookie jar persisted in the node cache for HTTP sessions
 //   - memory.go:  alloc/dealloc exports and memory helpers
+//   - storageio.go: streaming StorageReader/StorageWriter over flow storage
+//   - storagepath.go: JoinStoragePath, traversal-safe storage paths, and FlowPath
+//   - storagemeta.go: StorageObject metadata, conditional WriteIf, content hashes
+//   - storagewatch.go: WatchStorage, triggering nodes on storage changes
+//   - jsonstream.go: ArrayStream, element-at-a-time decoding of large JSON arrays
+//   - json.go:    minimal JSON scanning helpers for host responses
+//   - jsontree.go: order-preserving mutable JSON tree for structural helpers
+//   - jsonpatch.go: JSON Patch (RFC 6902) and Merge Patch (RFC 7386) apply/diff
+//   - transform.go: Merge strategies and Pick/Omit/Rename/Flatten reshaping
+//   - registry.go: NodeHandler interface and Registry for multi-node modules
+//   - panic.go:   PanicError, the traced failure of a panicking handler
+//   - deterministic.go: seeded clock and random values for reproducible runs
+//   - schemagen.go: random values matching a JSON Schema (GenerateFromSchema)
+//   - assert.go:  assertion results of board test nodes (Expect*, ctx.Assert)
+//   - dryrun.go:  IsDryRun and SideEffect guards for board previews
+//   - lifecycle.go: per-instance Init/Shutdown hooks behind on_init/on_shutdown
+//   - instancecache.go: InstanceCache for warm state with host invalidation
 //   - sdk.go:     (this file) ParseInput, SerializeDefinition, SerializeResult
 package sdk
 
+import "strconv"
+
 // ParseInput deserializes an ExecutionInput from wasm memory at the given pointer.
 func ParseInput(ptr uint32, length uint32) ExecutionInput {
 	jsonStr := ptrToString(ptr, length)
@@ -173,6 +228,14 @@ func parseExecutionInputJSON(s string) ExecutionInput {
 			if len(v) == 1 && v[0] >= '0' && v[0] <= '9' {
 				input.LogLevel = v[0] - '0'
 			}
+		case "deterministic":
+			input.Deterministic = parseDeterministicJSON(readValue())
+		case "dry_run":
+			input.DryRun = readValue() == "true"
+		case "attempt":
+			input.Attempt, _ = strconv.Atoi(readValue())
+		case "timeout_ms":
+			input.TimeoutMs, _ = strconv.ParseInt(readValue(), 10, 64)
 		case "inputs":
 			skipWhitespace()
 			if idx < len(s) && s[idx] == '{' {
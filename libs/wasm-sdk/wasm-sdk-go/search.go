@@ -0,0 +1,197 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SearchDocument is a document in a full-text index. Title and Text are
+// indexed; Metadata is raw JSON stored with the document and usable in
+// SearchOptions.Filter.
+type SearchDocument struct {
+	ID       string `json:"id"`
+	Title    string `json:"title,omitempty"`
+	Text     string `json:"text"`
+	Metadata string `json:"metadata,omitempty"`
+}
+
+func (d *SearchDocument) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"id":`)
+	b.WriteString(jsonString(d.ID))
+	if d.Title != "" {
+		b.WriteString(`,"title":`)
+		b.WriteString(jsonString(d.Title))
+	}
+	b.WriteString(`,"text":`)
+	b.WriteString(jsonString(d.Text))
+	if d.Metadata != "" {
+		b.WriteString(`,"metadata":`)
+		b.WriteString(d.Metadata)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// SearchOptions tunes a full-text query. The zero value returns the host's
+// default page of hits without highlights.
+type SearchOptions struct {
+	Limit  int `json:"limit,omitempty"`
+	Offset int `json:"offset,omitempty"`
+	// Filter is a raw JSON object of metadata fields that hits must equal,
+	// e.g. `{"lang":"en"}`.
+	Filter string `json:"filter,omitempty"`
+	// Fuzzy tolerates small typos in query terms.
+	Fuzzy bool `json:"fuzzy,omitempty"`
+	// Highlight returns the matching passages of each hit with the terms
+	// wrapped in HighlightPre and HighlightPost ("<mark>" and "</mark>" if
+	// empty).
+	Highlight     bool   `json:"highlight,omitempty"`
+	HighlightPre  string `json:"highlight_pre,omitempty"`
+	HighlightPost string `json:"highlight_post,omitempty"`
+}
+
+func (o *SearchOptions) ToJSON() string {
+	var b strings.Builder
+	b.WriteByte('{')
+	field := func(name, raw string) {
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		b.WriteString(jsonString(name))
+		b.WriteByte(':')
+		b.WriteString(raw)
+	}
+	if o.Limit != 0 {
+		field("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset != 0 {
+		field("offset", strconv.Itoa(o.Offset))
+	}
+	if o.Filter != "" {
+		field("filter", o.Filter)
+	}
+	if o.Fuzzy {
+		field("fuzzy", "true")
+	}
+	if o.Highlight {
+		field("highlight", "true")
+		if o.HighlightPre != "" {
+			field("highlight_pre", jsonString(o.HighlightPre))
+		}
+		if o.HighlightPost != "" {
+			field("highlight_post", jsonString(o.HighlightPost))
+		}
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// SearchHit is a document matching a query, best first.
+type SearchHit struct {
+	SearchDocument
+	Score      float64  `json:"score"`
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+// SearchResult is a page of hits. Total counts all matches, not just the
+// returned page.
+type SearchResult struct {
+	Hits  []SearchHit `json:"hits"`
+	Total int         `json:"total"`
+}
+
+// IndexDocument adds or replaces a document (by ID) in a full-text
+// collection of the current app. Requires the "search" permission.
+func IndexDocument(collection string, doc SearchDocument) bool {
+	cp, cl := stringToPtr(collection)
+	dp, dl := stringToPtr(doc.ToJSON())
+	return hostSearchIndex(cp, cl, dp, dl) != 0
+}
+
+// DeleteDocument removes a document from a full-text collection.
+func DeleteDocument(collection, id string) bool {
+	cp, cl := stringToPtr(collection)
+	ip, il := stringToPtr(id)
+	return hostSearchDelete(cp, cl, ip, il) != 0
+}
+
+// Search runs a full-text query against a collection. query supports
+// quoted phrases, and "-term" to exclude a term.
+func Search(collection, query string, opts SearchOptions) (SearchResult, bool) {
+	cp, cl := stringToPtr(collection)
+	qp, ql := stringToPtr(query)
+	op, ol := stringToPtr(opts.ToJSON())
+	packed := hostSearchQuery(cp, cl, qp, ql, op, ol)
+	if packed == -1 {
+		return SearchResult{}, false
+	}
+	f := jsonObjectFields(unpackString(packed))
+	total, _ := strconv.Atoi(f["total"])
+	res := SearchResult{Total: total}
+	for _, item := range jsonArrayItems(f["hits"]) {
+		h := jsonObjectFields(item)
+		if h == nil {
+			continue
+		}
+		score, _ := strconv.ParseFloat(h["score"], 64)
+		res.Hits = append(res.Hits, SearchHit{
+			SearchDocument: SearchDocument{
+				ID:       jsonUnquote(h["id"]),
+				Title:    jsonUnquote(h["title"]),
+				Text:     jsonUnquote(h["text"]),
+				Metadata: h["metadata"],
+			},
+			Score:      score,
+			Highlights: parseStringArrayJSON(h["highlights"]),
+		})
+	}
+	return res, true
+}
+
+// Deprecated: Use c.Host().IndexDocument, which reports why the call failed.
+func (c *Context) IndexDocument(collection string, doc SearchDocument) bool {
+	return c.Host().IndexDocument(collection, doc) == nil
+}
+
+// Deprecated: Use c.Host().DeleteDocument, which reports why the call failed.
+func (c *Context) DeleteDocument(collection, id string) bool {
+	return c.Host().DeleteDocument(collection, id) == nil
+}
+
+// Deprecated: Use c.Host().Search, which reports why the call failed.
+func (c *Context) Search(collection, query string, opts SearchOptions) (SearchResult, bool) {
+	res, err := c.Host().Search(collection, query, opts)
+	return res, err == nil
+}
+
+func (h HostCalls) IndexDocument(collection string, doc SearchDocument) error {
+	if !h.c.host(PermSearch) {
+		return dryRunSkipped("IndexDocument")
+	}
+	if !IndexDocument(collection, doc) {
+		return hostFailure("IndexDocument", ErrCodeInternal)
+	}
+	return nil
+}
+
+func (h HostCalls) DeleteDocument(collection, id string) error {
+	if !h.c.host(PermSearch) {
+		return dryRunSkipped("DeleteDocument")
+	}
+	if !DeleteDocument(collection, id) {
+		return hostFailure("DeleteDocument", ErrCodeInternal)
+	}
+	return nil
+}
+
+func (h HostCalls) Search(collection, query string, opts SearchOptions) (SearchResult, error) {
+	if !h.c.host(PermSearch) {
+		return SearchResult{}, dryRunSkipped("Search")
+	}
+	res, ok := Search(collection, query, opts)
+	if !ok {
+		return SearchResult{}, hostFailure("Search", ErrCodeInternal)
+	}
+	return res, nil
+}
@@ -0,0 +1,107 @@
+package search
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultRRFK is the rank constant of reciprocal-rank fusion. 60 is the value
+// from the original paper and works well without tuning.
+const DefaultRRFK = 60
+
+// Ranked is one entry of a ranked list to fuse: an ID and the score the
+// source assigned (higher is better).
+type Ranked struct {
+	ID    string
+	Score float64
+}
+
+// Fused is an entry of a fused ranking. Ranks holds the 1-based rank of the ID
+// in each input list, 0 where the list did not contain it.
+type Fused struct {
+	ID    string
+	Score float64
+	Ranks []int
+}
+
+// RRF merges ranked lists with reciprocal-rank fusion: each list contributes
+// weight/(k+rank) for every ID it contains. Scores of the sources are
+// ignored, which makes RRF robust against incomparable scales. weights may be
+// nil for equal weights; k <= 0 uses DefaultRRFK.
+func RRF(k float64, weights []float64, lists ...[]Ranked) []Fused {
+	if k <= 0 {
+		k = DefaultRRFK
+	}
+	return fuse(lists, func(list, rank int) float64 {
+		return weightOf(weights, list) / (k + float64(rank))
+	})
+}
+
+// Weighted merges ranked lists by a weighted sum of their min-max normalized
+// scores, so the best hit of each list scores 1 and the worst 0. It keeps
+// score gaps that RRF discards, but one list with a single strong outlier can
+// dominate. weights may be nil for equal weights.
+func Weighted(weights []float64, lists ...[]Ranked) []Fused {
+	norm := make([][]float64, len(lists))
+	for i, list := range lists {
+		scores := make([]float64, len(list))
+		for j, r := range list {
+			scores[j] = r.Score
+		}
+		norm[i] = MinMax(scores)
+	}
+	return fuse(lists, func(list, rank int) float64 {
+		return weightOf(weights, list) * norm[list][rank-1]
+	})
+}
+
+// MinMax rescales scores to [0, 1]. When all scores are equal they all
+// become 1.
+func MinMax(scores []float64) []float64 {
+	out := make([]float64, len(scores))
+	if len(scores) == 0 {
+		return out
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, s := range scores {
+		lo = math.Min(lo, s)
+		hi = math.Max(hi, s)
+	}
+	for i, s := range scores {
+		if hi == lo {
+			out[i] = 1
+		} else {
+			out[i] = (s - lo) / (hi - lo)
+		}
+	}
+	return out
+}
+
+func fuse(lists [][]Ranked, contribution func(list, rank int) float64) []Fused {
+	index := make(map[string]int)
+	var out []Fused
+	for li, list := range lists {
+		for ri, r := range list {
+			i, ok := index[r.ID]
+			if !ok {
+				i = len(out)
+				index[r.ID] = i
+				out = append(out, Fused{ID: r.ID, Ranks: make([]int, len(lists))})
+			}
+			if out[i].Ranks[li] != 0 {
+				continue // duplicate within one list: keep the better rank
+			}
+			out[i].Ranks[li] = ri + 1
+			out[i].Score += contribution(li, ri+1)
+		}
+	}
+	sort.SliceStable(out, func(a, b int) bool { return out[a].Score > out[b].Score })
+	return out
+}
+
+func weightOf(weights []float64, i int) float64 {
+	if i < len(weights) {
+		return weights[i]
+	}
+	return 1
+}
@@ -0,0 +1,144 @@
+// Package search combines the host's vector and full-text search into one
+// hybrid retrieval step. Keyword search finds exact terms, names and codes
+// that embeddings blur; vector search finds paraphrases that share no words
+// with the query. Fusing both ranks is a strong default for RAG nodes:
+//
+//	h := search.Hybrid{Collection: "docs", EmbeddingBit: bit}
+//	hits, ok := h.Search(ctx, question)
+//
+// Documents must be stored under the same IDs in both indexes, e.g. with
+// ctx.Host().VectorUpsert and ctx.Host().IndexDocument.
+package search
+
+import (
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// Fusion selects how the vector and keyword rankings are merged.
+type Fusion int
+
+const (
+	// FusionRRF uses reciprocal-rank fusion (the default).
+	FusionRRF Fusion = iota
+	// FusionWeighted sums min-max normalized scores.
+	FusionWeighted
+)
+
+// Hit is a document returned by Hybrid.Search. VectorRank and KeywordRank
+// are 1-based ranks in each source, 0 if the source did not return it.
+type Hit struct {
+	ID          string
+	Text        string
+	Metadata    string
+	Score       float64
+	VectorRank  int
+	KeywordRank int
+	Highlights  []string
+}
+
+// Hybrid configures a hybrid query. Only Collection is required; set
+// EmbeddingBit or pass the query vector to SearchVector.
+type Hybrid struct {
+	// Collection is the vector collection.
+	Collection string
+	// Index is the full-text collection; empty uses Collection.
+	Index string
+	// EmbeddingBit is the raw Bit JSON used to embed the query.
+	EmbeddingBit string
+	// K is the number of hits to return (default 10).
+	K int
+	// Candidates is how many hits to fetch from each source (default 4*K).
+	Candidates int
+	// Fusion selects the merge strategy (default FusionRRF).
+	Fusion Fusion
+	// RRFK is the RRF rank constant (default DefaultRRFK).
+	RRFK float64
+	// VectorWeight in [0, 1] balances vector against keyword results; the
+	// keyword weight is 1-VectorWeight. 0 means 0.5.
+	VectorWeight float64
+	// Options are passed to the keyword search; Limit is overridden by
+	// Candidates.
+	Options sdk.SearchOptions
+}
+
+// Search embeds query with EmbeddingBit and runs SearchVector. ok is false if
+// the embedding failed; a failing keyword search only logs a warning.
+func (h Hybrid) Search(ctx *sdk.Context, query string) ([]Hit, bool) {
+	embedded, err := ctx.Host().EmbedText(h.EmbeddingBit, "["+sdk.JSONString(query)+"]")
+	vectors := sdk.JSONArrayItems(embedded)
+	if err != nil || len(vectors) != 1 {
+		return nil, false
+	}
+	return h.SearchVector(ctx, query, vectors[0]), true
+}
+
+// SearchVector runs both searches with a precomputed query vector (raw JSON
+// array of numbers) and fuses the results.
+func (h Hybrid) SearchVector(ctx *sdk.Context, query, vectorJSON string) []Hit {
+	k := h.K
+	if k <= 0 {
+		k = 10
+	}
+	candidates := h.Candidates
+	if candidates <= 0 {
+		candidates = 4 * k
+	}
+	index := h.Index
+	if index == "" {
+		index = h.Collection
+	}
+
+	byID := make(map[string]*Hit)
+	matches, err := ctx.Host().VectorSearch(h.Collection, vectorJSON, candidates)
+	if err != nil {
+		ctx.Warn("hybrid search: vector search in " + h.Collection + " failed, using keyword results only: " + err.Error())
+	}
+	vec := make([]Ranked, len(matches))
+	for i, m := range matches {
+		vec[i] = Ranked{ID: m.ID, Score: m.Score}
+		if byID[m.ID] == nil {
+			byID[m.ID] = &Hit{ID: m.ID, Text: m.Text, Metadata: m.Metadata}
+		}
+	}
+
+	opts := h.Options
+	opts.Limit = candidates
+	var kw []Ranked
+	if res, err := ctx.Host().Search(index, query, opts); err == nil {
+		kw = make([]Ranked, len(res.Hits))
+		for i, r := range res.Hits {
+			kw[i] = Ranked{ID: r.ID, Score: r.Score}
+			hit := byID[r.ID]
+			if hit == nil {
+				hit = &Hit{ID: r.ID, Text: r.Text, Metadata: r.Metadata}
+				byID[r.ID] = hit
+			}
+			hit.Highlights = r.Highlights
+		}
+	} else {
+		ctx.Warn("hybrid search: keyword search in " + index + " failed, using vector results only: " + err.Error())
+	}
+
+	w := h.VectorWeight
+	if w <= 0 || w > 1 {
+		w = 0.5
+	}
+	weights := []float64{w, 1 - w}
+	var fused []Fused
+	if h.Fusion == FusionWeighted {
+		fused = Weighted(weights, vec, kw)
+	} else {
+		fused = RRF(h.RRFK, weights, vec, kw)
+	}
+	if len(fused) > k {
+		fused = fused[:k]
+	}
+	hits := make([]Hit, len(fused))
+	for i, f := range fused {
+		hit := *byID[f.ID]
+		hit.Score = f.Score
+		hit.VectorRank, hit.KeywordRank = f.Ranks[0], f.Ranks[1]
+		hits[i] = hit
+	}
+	return hits
+}
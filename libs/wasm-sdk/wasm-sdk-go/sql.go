@@ -0,0 +1,209 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Formats of the files an SQLQuery reads. An empty SQLTable.Format is
+// detected from the file extension.
+const (
+	SQLFormatCSV     = "csv"
+	SQLFormatParquet = "parquet"
+	SQLFormatJSON    = "json"
+	SQLFormatNDJSON  = "ndjson"
+)
+
+// SQLTable exposes a structured file in flow storage as a table.
+type SQLTable struct {
+	// Name is the table name used in the query.
+	Name string
+	// Path is the storage path of the file; a trailing "/" reads every
+	// file of the directory as one table.
+	Path   string
+	Format string
+}
+
+// SQLQuery is a read-only SQL query the host runs over storage files. The
+// host engine speaks a SQLite-compatible dialect.
+type SQLQuery struct {
+	SQL    string
+	Tables []SQLTable
+	// Params are bound to the query's ? placeholders in order and encoded
+	// like Context.Out values.
+	Params []any
+	// BatchSize is the number of rows the host returns per batch; 0 uses
+	// its default.
+	BatchSize int
+}
+
+func (q SQLQuery) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"sql":`)
+	b.WriteString(jsonString(q.SQL))
+	b.WriteString(`,"tables":[`)
+	for i, t := range q.Tables {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(`{"name":`)
+		b.WriteString(jsonString(t.Name))
+		b.WriteString(`,"path":`)
+		b.WriteString(jsonString(t.Path))
+		if t.Format != "" {
+			b.WriteString(`,"format":`)
+			b.WriteString(jsonString(t.Format))
+		}
+		b.WriteByte('}')
+	}
+	b.WriteString(`],"params":[`)
+	for i, p := range q.Params {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(encodeValue(p))
+	}
+	b.WriteByte(']')
+	if q.BatchSize > 0 {
+		b.WriteString(`,"batch_size":`)
+		b.WriteString(strconv.Itoa(q.BatchSize))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// SQLColumn is a result column with the engine's type name, e.g. "INTEGER".
+type SQLColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// SQLRows iterates the result of a query. The host keeps the result and
+// hands it over one batch at a time, so only the current batch is in
+// module memory:
+//
+//	rows, err := ctx.Host().Query(sdk.SQLQuery{
+//		SQL:    "SELECT region, SUM(amount) FROM sales GROUP BY region",
+//		Tables: []sdk.SQLTable{{Name: "sales", Path: salesPath}},
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer rows.Close()
+//	for rows.Next() {
+//		row := rows.Row()
+//		...
+//	}
+//	if err := rows.Err(); err != nil { ... }
+type SQLRows struct {
+	cursor  string
+	columns []SQLColumn
+	batch   []string
+	pos     int
+	done    bool
+	row     []RawValue
+	err     error
+}
+
+// SQLQueryRun starts q and returns its columns and first batch. ok is false
+// when the query is invalid or a file cannot be read; see LastHostError.
+func SQLQueryRun(q SQLQuery) (*SQLRows, bool) {
+	p, l := stringToPtr(q.ToJSON())
+	packed := hostSQLQuery(p, l)
+	if packed == 0 || packed == -1 {
+		return nil, false
+	}
+	f := jsonObjectFields(unpackString(packed))
+	r := &SQLRows{cursor: jsonUnquote(f["cursor"])}
+	for _, item := range jsonArrayItems(f["columns"]) {
+		c := jsonObjectFields(item)
+		r.columns = append(r.columns, SQLColumn{Name: jsonUnquote(c["name"]), Type: jsonUnquote(c["type"])})
+	}
+	r.setBatch(f)
+	return r, true
+}
+
+func (r *SQLRows) setBatch(f map[string]string) {
+	r.batch = jsonArrayItems(f["rows"])
+	r.pos = 0
+	r.done = f["done"] == "true"
+}
+
+// Columns returns the result columns in row order.
+func (r *SQLRows) Columns() []SQLColumn { return r.columns }
+
+// Next advances to the next row, fetching the next batch from the host when
+// the current one is used up. It returns false after the last row or on
+// error; check Err.
+func (r *SQLRows) Next() bool {
+	for r.pos >= len(r.batch) {
+		if r.done || r.err != nil {
+			r.row = nil
+			return false
+		}
+		cp, cl := stringToPtr(r.cursor)
+		packed := hostSQLNext(cp, cl)
+		if packed == 0 || packed == -1 {
+			r.err = hostFailure("Query", ErrCodeInternal)
+			r.row = nil
+			return false
+		}
+		r.setBatch(jsonObjectFields(unpackString(packed)))
+	}
+	items := jsonArrayItems(r.batch[r.pos])
+	r.pos++
+	r.row = make([]RawValue, len(items))
+	for i, item := range items {
+		r.row[i] = NewRawValue(item)
+	}
+	return true
+}
+
+// Row returns the current row, one value per column.
+func (r *SQLRows) Row() []RawValue { return r.row }
+
+// Map returns the current row keyed by column name.
+func (r *SQLRows) Map() map[string]RawValue {
+	m := make(map[string]RawValue, len(r.columns))
+	for i, c := range r.columns {
+		if i < len(r.row) {
+			m[c.Name] = r.row[i]
+		}
+	}
+	return m
+}
+
+func (r *SQLRows) Err() error { return r.err }
+
+// Close releases the result on the host. Results read to the end are
+// released already; closing them again is a no-op.
+func (r *SQLRows) Close() error {
+	if r.cursor == "" {
+		return nil
+	}
+	cp, cl := stringToPtr(r.cursor)
+	r.cursor = ""
+	if r.done {
+		return nil
+	}
+	r.done = true
+	if hostSQLClose(cp, cl) == 0 {
+		return hostFailure("Query", ErrCodeInternal)
+	}
+	return nil
+}
+
+// Query runs q over storage files on the host, which reads and aggregates
+// them itself, so a SUM over a large CSV or Parquet file only moves the
+// result rows into the module. Invalid queries fail with ErrCodeInvalidInput
+// and missing files with ErrNotFound.
+func (h HostCalls) Query(q SQLQuery) (*SQLRows, error) {
+	if !h.c.host(PermStorage) {
+		return nil, dryRunSkipped("Query")
+	}
+	rows, ok := SQLQueryRun(q)
+	if !ok {
+		return nil, hostFailure("Query", ErrCodeInvalidInput)
+	}
+	return rows, nil
+}
@@ -0,0 +1,138 @@
+package sdk
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrStorageClosed is returned when reading from or writing to a closed stream.
+var ErrStorageClosed = errors.New("storage stream closed")
+
+// ErrStorageWrite is returned when the host rejected a chunk written to storage.
+var ErrStorageWrite = errors.New("storage write failed")
+
+// storageChunkSize is how many bytes a StorageReader requests per host call.
+// It bounds the memory a streaming node needs independently of the file size.
+const storageChunkSize = 64 * 1024
+
+// StorageReader streams a file from flow storage in fixed-size chunks.
+// It implements io.ReadCloser, so it can be wrapped in bufio.Reader,
+// encoding/csv and similar readers.
+type StorageReader struct {
+	handle int32
+	buf    string
+	eof    bool
+	err    error
+	closed bool
+}
+
+// OpenStorageReader opens a file in flow storage for streaming reads.
+// ok is false if the file does not exist or access was denied.
+func OpenStorageReader(path string) (*StorageReader, bool) {
+	p, l := stringToPtr(path)
+	h := hostStorageOpenReader(p, l)
+	if h < 0 {
+		return nil, false
+	}
+	return &StorageReader{handle: h}, true
+}
+
+// Read returns io.EOF once the host sends an empty chunk. A chunk the host
+// fails to read ends the stream with a *HostError instead, so a truncated
+// file is not mistaken for a complete one.
+func (r *StorageReader) Read(p []byte) (int, error) {
+	if r.closed {
+		return 0, ErrStorageClosed
+	}
+	if len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		if r.eof {
+			return 0, io.EOF
+		}
+		packed := hostStorageReadChunk(r.handle, storageChunkSize)
+		if packed == -1 {
+			r.err = hostFailure("StorageRead", ErrCodeInternal)
+			return 0, r.err
+		}
+		r.buf = unpackString(packed)
+		if len(r.buf) == 0 {
+			r.eof = true
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// Close releases the host-side handle. It is safe to call more than once.
+func (r *StorageReader) Close() error {
+	if !r.closed {
+		r.closed = true
+		hostStorageCloseReader(r.handle)
+	}
+	return nil
+}
+
+// StorageWriter streams data into a file in flow storage. The file only
+// becomes visible once Close succeeds; Abort discards the data instead. It
+// implements io.WriteCloser.
+type StorageWriter struct {
+	handle int32
+	closed bool
+}
+
+// CreateStorageWriter opens a file in flow storage for streaming writes,
+// replacing any existing content on Close.
+func CreateStorageWriter(path string) (*StorageWriter, bool) {
+	p, l := stringToPtr(path)
+	h := hostStorageOpenWriter(p, l)
+	if h < 0 {
+		return nil, false
+	}
+	return &StorageWriter{handle: h}, true
+}
+
+func (w *StorageWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, ErrStorageClosed
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if hostStorageWriteChunk(w.handle, bytesPtr(p), uint32(len(p))) == 0 {
+		return 0, ErrStorageWrite
+	}
+	return len(p), nil
+}
+
+// WriteString implements io.StringWriter.
+func (w *StorageWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Close commits the written data. Calling Close again is a no-op.
+func (w *StorageWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if hostStorageCloseWriter(w.handle) == 0 {
+		return ErrStorageWrite
+	}
+	return nil
+}
+
+// Abort discards the written data and releases the handle, leaving any
+// existing file untouched. Use it instead of Close when producing the data
+// failed. Calling Abort after Close, or again, is a no-op.
+func (w *StorageWriter) Abort() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	hostStorageAbortWriter(w.handle)
+	return nil
+}
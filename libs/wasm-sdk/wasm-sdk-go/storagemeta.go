@@ -0,0 +1,183 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// StorageObject describes a file in flow storage. ETag changes whenever the
+// content does; pass it to WriteIf to write only if nobody else wrote the
+// file in between.
+type StorageObject struct {
+	Path       string `json:"path"`
+	Size       int64  `json:"size"`
+	ETag       string `json:"etag"`
+	ModifiedAt int64  `json:"modified_at"` // Unix milliseconds
+}
+
+// StorageStat returns the metadata of the file at path. ok is false when it
+// does not exist.
+func StorageStat(path string) (StorageObject, bool) {
+	p, l := stringToPtr(path)
+	packed := hostStorageStat(p, l)
+	if packed == 0 || packed == -1 {
+		return StorageObject{}, false
+	}
+	return parseStorageObject(unpackString(packed)), true
+}
+
+// StorageWriteIf replaces the file at path with data only if its ETag is
+// still etag, or, with etag "", only if the file does not exist. It returns
+// the new ETag; ok is false on a mismatch or failure.
+func StorageWriteIf(path, data, etag string) (string, bool) {
+	pp, pl := stringToPtr(path)
+	dp, dl := stringToPtr(data)
+	ep, el := stringToPtr(etag)
+	packed := hostStorageWriteIf(pp, pl, dp, dl, ep, el)
+	if packed == 0 || packed == -1 {
+		return "", false
+	}
+	return unpackString(packed), true
+}
+
+// StorageWriteVerified writes data like StorageWrite and returns the
+// lowercase hex SHA-256 the host computed over the stored content, after
+// checking it against the data it received. It returns "" on failure.
+func StorageWriteVerified(path, data string) string {
+	pp, pl := stringToPtr(path)
+	dp, dl := stringToPtr(data)
+	packed := hostStorageWriteVerified(pp, pl, dp, dl)
+	if packed == 0 || packed == -1 {
+		return ""
+	}
+	return unpackString(packed)
+}
+
+// StorageHash returns the lowercase hex SHA-256 of the file at path, hashed
+// by the host, or "" when it cannot be read.
+func StorageHash(path string) string {
+	p, l := stringToPtr(path)
+	packed := hostStorageHash(p, l)
+	if packed == 0 || packed == -1 {
+		return ""
+	}
+	return unpackString(packed)
+}
+
+// parseStorageObject reads a stat result or list entry. Hosts list entries
+// as objects or, without metadata, as bare paths.
+func parseStorageObject(s string) StorageObject {
+	if len(s) > 0 && s[0] == '"' {
+		return StorageObject{Path: jsonUnquote(s)}
+	}
+	f := jsonObjectFields(s)
+	size, _ := strconv.ParseInt(f["size"], 10, 64)
+	modified, _ := strconv.ParseInt(f["modified_at"], 10, 64)
+	path := f["path"]
+	if len(path) > 0 && path[0] == '{' {
+		path = jsonObjectFields(path)["path"]
+	}
+	return StorageObject{
+		Path:       jsonUnquote(path),
+		Size:       size,
+		ETag:       jsonUnquote(f["etag"]),
+		ModifiedAt: modified,
+	}
+}
+
+// StorageStat returns the size, ETag and modification time of the file at
+// path, failing with ErrNotFound when it does not exist.
+func (h HostCalls) StorageStat(path string) (StorageObject, error) {
+	if !h.c.host(PermStorage) {
+		return StorageObject{}, dryRunSkipped("StorageStat")
+	}
+	obj, ok := StorageStat(path)
+	if !ok {
+		return StorageObject{}, hostFailure("StorageStat", ErrCodeNotFound)
+	}
+	return obj, nil
+}
+
+// StorageListObjects lists the files under dir with their metadata. It is
+// StorageList decoded; entries the host lists without metadata only have
+// Path set.
+func (h HostCalls) StorageListObjects(dir string) ([]StorageObject, error) {
+	list, err := h.StorageList(FlowPath(dir))
+	if err != nil {
+		return nil, err
+	}
+	items := jsonArrayItems(list)
+	objects := make([]StorageObject, 0, len(items))
+	for _, item := range items {
+		objects = append(objects, parseStorageObject(item))
+	}
+	return objects, nil
+}
+
+// WriteIf replaces the file at path only if its ETag is still etag (from
+// StorageStat, StorageListObjects or an earlier WriteIf), or with etag ""
+// only if the file does not exist yet, and returns the new ETag. When
+// another run wrote the file in between it fails with ErrConflict, so
+// read-modify-write cycles can re-read and retry instead of overwriting:
+//
+//	obj, err := ctx.Host().StorageStat(path)
+//	...
+//	_, err = ctx.Host().WriteIf(path, updated, obj.ETag)
+//	if errors.Is(err, sdk.ErrConflict) {
+//		// another run wrote the file: read it again and retry
+//	}
+func (h HostCalls) WriteIf(path, data, etag string) (string, error) {
+	if !h.c.host(PermStorage) {
+		return "", dryRunSkipped("WriteIf")
+	}
+	next, ok := StorageWriteIf(path, data, etag)
+	if !ok {
+		return "", hostFailure("WriteIf", ErrCodeConflict)
+	}
+	return next, nil
+}
+
+// StorageWriteVerified writes data to path and returns its SHA-256 (lowercase
+// hex). The host hashes what it stored and fails the call with ErrIntegrity
+// when that differs from what it received. Keep the hash to check the file
+// later with VerifyIntegrity, or as a dedupe key: equal hashes mean equal
+// content.
+func (h HostCalls) StorageWriteVerified(path, data string) (string, error) {
+	if !h.c.host(PermStorage) {
+		return "", dryRunSkipped("StorageWriteVerified")
+	}
+	hash := StorageWriteVerified(path, data)
+	if hash == "" {
+		return "", hostFailure("StorageWriteVerified", ErrCodeInternal)
+	}
+	return hash, nil
+}
+
+// StorageHash returns the SHA-256 (lowercase hex) of the file at path,
+// computed by the host without reading the file into the module.
+func (h HostCalls) StorageHash(path string) (string, error) {
+	if !h.c.host(PermStorage) {
+		return "", dryRunSkipped("StorageHash")
+	}
+	hash := StorageHash(path)
+	if hash == "" {
+		return "", hostFailure("StorageHash", ErrCodeNotFound)
+	}
+	return hash, nil
+}
+
+// VerifyIntegrity checks that the file at path still hashes to
+// expectedHash, a SHA-256 from StorageWriteVerified or StorageHash; hex case
+// and a "sha256:" prefix are ignored. A mismatch fails with ErrIntegrity.
+func (h HostCalls) VerifyIntegrity(path, expectedHash string) error {
+	hash, err := h.StorageHash(path)
+	if err != nil {
+		return err
+	}
+	expected := strings.TrimPrefix(strings.ToLower(expectedHash), "sha256:")
+	if strings.ToLower(hash) != expected {
+		return &HostError{Op: "VerifyIntegrity", Code: ErrCodeIntegrity,
+			Message: path + " hashes to " + hash + ", expected " + expected}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package sdk
+
+import "strings"
+
+// JoinStoragePath appends segments to dir, a directory returned by the
+// storage or cache dir calls, and returns the path the storage calls take.
+// A segment may hold several "/"-separated names, e.g. "exports/2024"; an
+// empty name, "." or "..", a backslash or a control character fails with an
+// invalid_input NodeError, so input-derived names cannot leave dir:
+//
+//	path, err := sdk.JoinStoragePath(dir, "exports", fileName)
+func JoinStoragePath(dir string, segments ...string) (string, error) {
+	if dir == "" {
+		return "", NewError(ErrCodeInvalidInput, "storage path without a directory")
+	}
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(dir, "/"))
+	for _, segment := range segments {
+		for _, name := range strings.Split(segment, "/") {
+			if err := checkPathName(name, segment); err != nil {
+				return "", err
+			}
+			b.WriteByte('/')
+			b.WriteString(name)
+		}
+	}
+	return b.String(), nil
+}
+
+func checkPathName(name, segment string) error {
+	if name == "" || name == "." || name == ".." {
+		return NewError(ErrCodeInvalidInput, "invalid storage path segment "+jsonString(segment))
+	}
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; c < 0x20 || c == 0x7f || c == '\\' {
+			return NewError(ErrCodeInvalidInput, "invalid character in storage path segment "+jsonString(segment))
+		}
+	}
+	return nil
+}
+
+// FlowPath returns the FlowPath JSON of a storage path, as taken by
+// StorageList and OpenFile.
+func FlowPath(path string) string {
+	return `{"path":` + jsonString(path) + `}`
+}
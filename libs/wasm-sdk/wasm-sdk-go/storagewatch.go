@@ -0,0 +1,132 @@
+package sdk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// DefaultStorageChangesPin is the input the host delivers storage changes
+// on when it triggers a watching node.
+const DefaultStorageChangesPin = "storage_changes"
+
+// StorageChangeKind is what happened to a file under a watched prefix.
+type StorageChangeKind string
+
+const (
+	StorageCreated StorageChangeKind = "created"
+	StorageUpdated StorageChangeKind = "updated"
+	StorageDeleted StorageChangeKind = "deleted"
+)
+
+// StorageChangeSchema is the JSON Schema of one change as delivered on
+// DefaultStorageChangesPin.
+const StorageChangeSchema = `{"type":"object","properties":{` +
+	`"kind":{"type":"string","enum":["created","updated","deleted"]},` +
+	`"path":{"type":"string"},"size":{"type":"integer"},` +
+	`"changed_at":{"type":"integer"}},"required":["kind","path"]}`
+
+// StorageChange is a change to a file under a watched prefix. Size is the
+// file's size after the change, 0 for deletions.
+type StorageChange struct {
+	Kind      StorageChangeKind `json:"kind"`
+	Path      string            `json:"path"`
+	Size      int64             `json:"size"`
+	ChangedAt int64             `json:"changed_at"` // Unix milliseconds
+}
+
+// StorageChangesPin declares the input a watching node is triggered with.
+func StorageChangesPin() PinDefinition {
+	return InputPin(DefaultStorageChangesPin, "Storage Changes", "Files created, updated or deleted under the watched prefix", DataTypeStruct).
+		WithValueType("Array").
+		WithSchema(StorageChangeSchema)
+}
+
+// WatchStorage subscribes the calling node to changes of the given kinds
+// (all kinds when empty) under prefix, a storage path as returned by the
+// dir calls and JoinStoragePath. The host then triggers the node with the
+// affected files on DefaultStorageChangesPin, batching changes that land
+// close together. Watching the same prefix again returns the existing
+// subscription. Requires the "storage_watch" permission.
+func WatchStorage(prefix string, kinds []StorageChangeKind) (string, bool) {
+	pp, pl := stringToPtr(prefix)
+	kp, kl := stringToPtr(storageKindsJSON(kinds))
+	packed := hostStorageWatch(pp, pl, kp, kl)
+	if packed == -1 {
+		return "", false
+	}
+	return unpackString(packed), true
+}
+
+// UnwatchStorage ends a subscription made with WatchStorage.
+func UnwatchStorage(id string) bool {
+	p, l := stringToPtr(id)
+	return hostStorageUnwatch(p, l) != 0
+}
+
+func storageKindsJSON(kinds []StorageChangeKind) string {
+	quoted := make([]string, len(kinds))
+	for i, k := range kinds {
+		quoted[i] = jsonString(string(k))
+	}
+	return "[" + strings.Join(quoted, ",") + "]"
+}
+
+// WatchStorage subscribes the node to changes under prefix and returns the
+// subscription ID. Event-source nodes call it when set up and handle the
+// triggered runs through Context.StorageChanges:
+//
+//	dir, _ := ctx.Host().StorageDir()
+//	imports, _ := sdk.JoinStoragePath(dir, "imports")
+//	id, err := ctx.Host().WatchStorage(imports, sdk.StorageCreated)
+func (h HostCalls) WatchStorage(prefix string, kinds ...StorageChangeKind) (string, error) {
+	if !h.c.host(PermStorageWatch) {
+		return "", dryRunSkipped("WatchStorage")
+	}
+	id, ok := WatchStorage(prefix, kinds)
+	if !ok {
+		return "", hostFailure("WatchStorage", ErrCodePermissionDenied)
+	}
+	return id, nil
+}
+
+// UnwatchStorage ends a subscription, failing with ErrNotFound when it
+// does not exist.
+func (h HostCalls) UnwatchStorage(id string) error {
+	if !h.c.host(PermStorageWatch) {
+		return dryRunSkipped("UnwatchStorage")
+	}
+	if !UnwatchStorage(id) {
+		return hostFailure("UnwatchStorage", ErrCodeNotFound)
+	}
+	return nil
+}
+
+// StorageChanges reads the changes a storage watch triggered the run with,
+// from DefaultStorageChangesPin. ok is false when the run was not triggered
+// by a watch, e.g. the run that sets the watch up.
+func (c *Context) StorageChanges() ([]StorageChange, bool) {
+	raw, ok := c.lookup(DefaultStorageChangesPin)
+	if !ok {
+		return nil, false
+	}
+	return parseStorageChangesJSON(raw), true
+}
+
+func parseStorageChangesJSON(s string) []StorageChange {
+	var out []StorageChange
+	for _, item := range jsonArrayItems(s) {
+		f := jsonObjectFields(item)
+		if f == nil {
+			continue
+		}
+		size, _ := strconv.ParseInt(f["size"], 10, 64)
+		changed, _ := strconv.ParseInt(f["changed_at"], 10, 64)
+		out = append(out, StorageChange{
+			Kind:      StorageChangeKind(jsonUnquote(f["kind"])),
+			Path:      jsonUnquote(f["path"]),
+			Size:      size,
+			ChangedAt: changed,
+		})
+	}
+	return out
+}
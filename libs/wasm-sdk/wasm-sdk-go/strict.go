@@ -0,0 +1,60 @@
+package sdk
+
+import "strings"
+
+// Strict switches the run's context into strict mode for good and returns
+// it. It is the same Context, not a strict copy, so every code path holding
+// it is affected; Registry.SetStrict relies on this. In strict mode the
+// problems the lenient getters normally paper over fail the run instead:
+//
+//   - a Get* call for an input that has no value (the default was used)
+//   - a Get* call for a value that could not be converted
+//   - a declared non-Exec output that is neither set nor skipped when a
+//     successful run finishes (see VerifyOutputs)
+//
+// The failure message lists every problem found. Runs that already failed or
+// are pending are left untouched.
+func (c *Context) Strict() *Context {
+	c.strict = true
+	return c
+}
+
+// IsStrict reports whether strict mode is enabled.
+func (c *Context) IsStrict() bool { return c.strict }
+
+func (c *Context) noteMissing(name string) {
+	if c.strict {
+		c.problems = append(c.problems, (&MissingInputError{Pin: name}).Error())
+	}
+}
+
+// noteProblem records a conversion error. Outside strict mode it is logged
+// as a warning since the getter silently falls back to its default.
+func (c *Context) noteProblem(err error) {
+	if c.strict {
+		c.problems = append(c.problems, err.Error())
+		return
+	}
+	c.Warn(err.Error())
+}
+
+func (c *Context) enforceStrict() {
+	if c.result.Error != nil || c.result.Pending {
+		return
+	}
+	problems := c.problems
+	for _, name := range c.UnsetOutputs() {
+		problems = append(problems, "output \""+name+"\" was never set")
+	}
+	if len(problems) == 0 {
+		return
+	}
+	code := ErrCodeInternal
+	if len(c.problems) > 0 {
+		code = ErrCodeInvalidInput
+	}
+	msg := "strict mode: " + strings.Join(problems, "; ")
+	c.result.ActivateExec = []string{}
+	c.result.ErrorInfo = &ErrorInfo{Code: code, Message: msg}
+	c.result.Error = &msg
+}
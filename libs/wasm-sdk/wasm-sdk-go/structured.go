@@ -0,0 +1,116 @@
+package sdk
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrChatFailed is returned when the host could not run a chat completion,
+// e.g. because the model bit is unavailable.
+var ErrChatFailed = errors.New("chat completion failed")
+
+// StructuredAttempts is how often ChatCompleteStructured asks the model
+// before giving up on output that does not match the schema.
+var StructuredAttempts = 3
+
+// StructuredOutputError reports a model that kept answering with output that
+// is not valid JSON or does not match the requested schema. Raw is the last
+// answer; Violations is empty when it was not JSON at all.
+type StructuredOutputError struct {
+	Attempts   int
+	Raw        string
+	Violations []SchemaViolation
+}
+
+func (e *StructuredOutputError) Error() string {
+	msg := "model output did not match the schema after " + strconv.Itoa(e.Attempts) + " attempts"
+	if len(e.Violations) == 0 {
+		return msg + ": not valid JSON"
+	}
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	return msg + ": " + strings.Join(parts, "; ")
+}
+
+func (e *StructuredOutputError) errorInfo() ErrorInfo {
+	return ErrorInfo{Code: ErrCodeUpstream, Message: e.Error()}
+}
+
+// ChatCompleteJSON runs a chat completion asking the model for JSON matching
+// schema. Hosts use the provider's native structured output mode where there
+// is one; the reply is not validated. Most nodes want
+// Context.ChatCompleteStructured instead.
+func ChatCompleteJSON(bitJSON string, req ChatRequest, schema string) (ChatResponse, bool) {
+	bp, bl := stringToPtr(bitJSON)
+	rp, rl := stringToPtr(req.ToJSON())
+	sp, sl := stringToPtr(schema)
+	return parseChatResponseJSON(unpackString(hostChatCompleteJSON(bp, bl, rp, rl, sp, sl)))
+}
+
+// ChatCompleteStructured asks the model for data matching a JSON Schema and
+// returns it validated. Replies that are not JSON or violate the schema are
+// sent back to the model with the list of problems, up to StructuredAttempts
+// times in total; a *StructuredOutputError is returned if it never complies.
+// Markdown code fences around the JSON are tolerated.
+func (c *Context) ChatCompleteStructured(bitJSON string, req ChatRequest, schema string) (RawValue, error) {
+	if !c.host(PermModels) {
+		return RawValue{}, ErrChatFailed
+	}
+	attempts := StructuredAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	messages := append([]ChatMessage(nil), req.Messages...)
+	var last StructuredOutputError
+	for i := 1; i <= attempts; i++ {
+		req.Messages = messages
+		resp, ok := c.chatComplete("chat_json", bitJSON, req, func() (ChatResponse, bool) {
+			return ChatCompleteJSON(bitJSON, req, schema)
+		})
+		if !ok {
+			return RawValue{}, ErrChatFailed
+		}
+		raw := extractJSON(resp.Content)
+		last = StructuredOutputError{Attempts: i, Raw: resp.Content}
+		var feedback string
+		if parseTree(raw) == nil {
+			feedback = "Your reply was not valid JSON."
+		} else {
+			value := NewRawValue(raw)
+			last.Violations = ValidateSchema(schema, value)
+			if len(last.Violations) == 0 {
+				return value, nil
+			}
+			parts := make([]string, len(last.Violations))
+			for j, v := range last.Violations {
+				parts[j] = "- " + v.String()
+			}
+			feedback = "Your reply does not match the JSON Schema:\n" + strings.Join(parts, "\n")
+		}
+		if i < attempts {
+			c.Debug("structured output attempt " + strconv.Itoa(i) + " rejected, retrying")
+		}
+		messages = append(messages,
+			ChatMessage{Role: RoleAssistant, Content: resp.Content},
+			ChatMessage{Role: RoleUser, Content: feedback + "\nReply with only the corrected JSON."},
+		)
+	}
+	return RawValue{}, &last
+}
+
+// extractJSON strips surrounding whitespace and a Markdown code fence.
+func extractJSON(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```")
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[i+1:] // drop the language tag
+	}
+	s = strings.TrimSpace(s)
+	return strings.TrimSpace(strings.TrimSuffix(s, "```"))
+}
@@ -0,0 +1,123 @@
+// Package textsplit splits long text into overlapping chunks suitable for
+// embedding. It prefers to break at paragraph, line, sentence and word
+// boundaries, in that order, and only cuts inside a word as a last resort.
+package textsplit
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Options controls chunking. Sizes are measured in runes.
+type Options struct {
+	// ChunkSize is the maximum length of a chunk. Defaults to 1000.
+	ChunkSize int
+	// Overlap is how much trailing text of a chunk is repeated at the start
+	// of the next one. Defaults to 0 and is clamped below ChunkSize.
+	Overlap int
+	// Separators are tried in order; defaults to DefaultSeparators. Empty
+	// separators are ignored.
+	Separators []string
+}
+
+// DefaultSeparators break text at paragraphs, lines, sentences and words.
+var DefaultSeparators = []string{"\n\n", "\n", ". ", " "}
+
+// Split divides text into chunks of at most opts.ChunkSize runes.
+func Split(text string, opts Options) []string {
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = 1000
+	}
+	if opts.Overlap < 0 || opts.Overlap >= opts.ChunkSize {
+		opts.Overlap = 0
+	}
+	separators := opts.Separators
+	if separators == nil {
+		separators = DefaultSeparators
+	}
+	var nonEmpty []string
+	for _, sep := range separators {
+		if sep != "" {
+			nonEmpty = append(nonEmpty, sep)
+		}
+	}
+
+	pieces := splitRecursive(text, nonEmpty, opts.ChunkSize)
+	return merge(pieces, opts.ChunkSize, opts.Overlap)
+}
+
+// splitRecursive breaks text into pieces no longer than size, keeping each
+// separator attached to the piece it ends.
+func splitRecursive(text string, separators []string, size int) []string {
+	if utf8.RuneCountInString(text) <= size {
+		return []string{text}
+	}
+	if len(separators) == 0 {
+		return hardSplit(text, size)
+	}
+	sep := separators[0]
+	var out []string
+	for len(text) > 0 {
+		i := strings.Index(text, sep)
+		var piece string
+		if i < 0 {
+			piece, text = text, ""
+		} else {
+			piece, text = text[:i+len(sep)], text[i+len(sep):]
+		}
+		if utf8.RuneCountInString(piece) > size {
+			out = append(out, splitRecursive(piece, separators[1:], size)...)
+		} else {
+			out = append(out, piece)
+		}
+	}
+	return out
+}
+
+// merge packs consecutive pieces into chunks, carrying overlap runes over.
+func merge(pieces []string, size, overlap int) []string {
+	var chunks []string
+	current := ""
+	currentLen := 0
+	for _, p := range pieces {
+		pLen := utf8.RuneCountInString(p)
+		if currentLen > 0 && currentLen+pLen > size {
+			chunks = append(chunks, current)
+			current = tail(current, overlap)
+			currentLen = utf8.RuneCountInString(current)
+			if currentLen+pLen > size {
+				current, currentLen = "", 0
+			}
+		}
+		current += p
+		currentLen += pLen
+	}
+	if currentLen > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+func hardSplit(text string, size int) []string {
+	var out []string
+	runes := []rune(text)
+	for len(runes) > size {
+		out = append(out, string(runes[:size]))
+		runes = runes[size:]
+	}
+	if len(runes) > 0 {
+		out = append(out, string(runes))
+	}
+	return out
+}
+
+func tail(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[len(runes)-n:])
+}
@@ -0,0 +1,61 @@
+package textsplit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		opts Options
+		want []string
+	}{
+		{"empty", "", Options{ChunkSize: 10}, nil},
+		{"fits", "short text", Options{ChunkSize: 10}, []string{"short text"}},
+		{"paragraphs", "first para\n\nsecond para", Options{ChunkSize: 15},
+			[]string{"first para\n\n", "second para"}},
+		{"words", "one two three four", Options{ChunkSize: 9},
+			[]string{"one two ", "three ", "four"}},
+		{"hard split", "abcdefghij", Options{ChunkSize: 4},
+			[]string{"abcd", "efgh", "ij"}},
+		{"runes", "äöüäöü", Options{ChunkSize: 4}, []string{"äöüä", "öü"}},
+		{"overlap", "aa bb cc dd", Options{ChunkSize: 6, Overlap: 3},
+			[]string{"aa bb ", "bb cc ", "cc dd"}},
+		{"overlap clamped", "aa bb cc", Options{ChunkSize: 3, Overlap: 5},
+			[]string{"aa ", "bb ", "cc"}},
+		{"custom separators", "a;b;c", Options{ChunkSize: 2, Separators: []string{";"}},
+			[]string{"a;", "b;", "c"}},
+		{"empty separators skipped", "a;b;c", Options{ChunkSize: 2, Separators: []string{"", ";", ""}},
+			[]string{"a;", "b;", "c"}},
+		{"only empty separators", "abcde", Options{ChunkSize: 2, Separators: []string{""}},
+			[]string{"ab", "cd", "e"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Split(tt.text, tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitRoundTrip(t *testing.T) {
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 40) +
+		"\n\n" + strings.Repeat("Pack my box with five dozen liquor jugs.\n", 30)
+	for _, size := range []int{7, 50, 200, 5000} {
+		chunks := Split(text, Options{ChunkSize: size})
+		for _, c := range chunks {
+			if n := utf8.RuneCountInString(c); n > size {
+				t.Fatalf("size %d: chunk of %d runes", size, n)
+			}
+		}
+		if got := strings.Join(chunks, ""); got != text {
+			t.Fatalf("size %d: chunks do not join back to the input", size)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package timex
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNoBusinessDays is returned when a Calendar's weekend and holidays leave
+// no business day to move to.
+var ErrNoBusinessDays = errors.New("timex: calendar has no business days")
+
+// Calendar defines which days are business days. The zero value treats
+// Saturday and Sunday as the weekend and has no holidays.
+type Calendar struct {
+	// Weekend lists non-working weekdays. Nil means Saturday and Sunday.
+	Weekend []time.Weekday
+	// Holidays are non-working dates in "2006-01-02" form.
+	Holidays []string
+}
+
+func (c *Calendar) isWeekend(d time.Weekday) bool {
+	if c.Weekend == nil {
+		return d == time.Saturday || d == time.Sunday
+	}
+	for _, w := range c.Weekend {
+		if w == d {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBusinessDay reports whether t falls on a working day, judged by its date
+// in t's own location.
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	if c.isWeekend(t.Weekday()) {
+		return false
+	}
+	date := t.Format("2006-01-02")
+	for _, h := range c.Holidays {
+		if h == date {
+			return false
+		}
+	}
+	return true
+}
+
+// AddBusinessDays moves t forward (or backward for negative n) by n business
+// days, keeping the time of day. Adding zero rolls a non-business day forward
+// to the next business day. It fails with ErrNoBusinessDays when the calendar
+// leaves no business day to reach.
+func (c *Calendar) AddBusinessDays(t time.Time, n int) (time.Time, error) {
+	start, step := t, 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	if n == 0 {
+		n, step = 1, 1
+		t = t.AddDate(0, 0, -1)
+	}
+	// Each week has a weekday off the weekend unless all seven are, so a
+	// business day is at most one week past every holiday.
+	limit := 7 * (len(c.Holidays) + 1)
+	for gap := 0; n > 0; gap++ {
+		if gap == limit {
+			return start, ErrNoBusinessDays
+		}
+		t = t.AddDate(0, 0, step)
+		if c.IsBusinessDay(t) {
+			n--
+			gap = -1
+		}
+	}
+	return t, nil
+}
+
+// BusinessDaysBetween counts business days after from up to and including
+// to. It is negative when to is before from. Dates are taken in from's
+// location.
+func (c *Calendar) BusinessDaysBetween(from, to time.Time) int {
+	to = to.In(from.Location())
+	sign := 1
+	if to.Before(from) {
+		from, to, sign = to, from, -1
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, from.Location())
+	n := 0
+	for d := from.AddDate(0, 0, 1); !d.After(to); d = d.AddDate(0, 0, 1) {
+		if c.IsBusinessDay(d) {
+			n++
+		}
+	}
+	return sign * n
+}
@@ -0,0 +1,90 @@
+package timex
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	cal := &Calendar{Holidays: []string{"2024-12-25", "2024-12-26"}}
+	tests := []struct {
+		from string
+		n    int
+		want string
+	}{
+		{"2024-03-01 09:00", 0, "2024-03-01 09:00"}, // Friday
+		{"2024-03-02 09:00", 0, "2024-03-04 09:00"}, // Saturday rolls to Monday
+		{"2024-03-01 09:00", 1, "2024-03-04 09:00"},
+		{"2024-03-01 09:00", 5, "2024-03-08 09:00"},
+		{"2024-03-04 09:00", -1, "2024-03-01 09:00"},
+		{"2024-03-03 09:00", -5, "2024-02-26 09:00"},
+		{"2024-12-24 17:30", 1, "2024-12-27 17:30"},
+		{"2024-12-27 17:30", -1, "2024-12-24 17:30"},
+		{"2024-12-25 08:00", 0, "2024-12-27 08:00"},
+	}
+	for _, tt := range tests {
+		got, err := cal.AddBusinessDays(day(tt.from), tt.n)
+		if err != nil || !got.Equal(day(tt.want)) {
+			t.Errorf("AddBusinessDays(%s, %d) = %v, %v, want %s", tt.from, tt.n, got, err, tt.want)
+		}
+	}
+
+	friOnly := &Calendar{Weekend: []time.Weekday{time.Saturday, time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday}}
+	if got, err := friOnly.AddBusinessDays(day("2024-03-01 09:00"), 2); err != nil || !got.Equal(day("2024-03-15 09:00")) {
+		t.Errorf("Friday-only calendar = %v, %v", got, err)
+	}
+}
+
+func TestAddBusinessDaysNoBusinessDays(t *testing.T) {
+	all := []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+	from := day("2024-03-01 09:00")
+	for _, n := range []int{0, 1, -1} {
+		got, err := (&Calendar{Weekend: all}).AddBusinessDays(from, n)
+		if !errors.Is(err, ErrNoBusinessDays) || !got.Equal(from) {
+			t.Errorf("all-weekend AddBusinessDays(%d) = %v, %v, want ErrNoBusinessDays", n, got, err)
+		}
+	}
+	covered := &Calendar{
+		Weekend:  []time.Weekday{time.Saturday, time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday},
+		Holidays: []string{"2024-03-01"},
+	}
+	if got, err := covered.AddBusinessDays(from, 1); err != nil || !got.Equal(day("2024-03-08 09:00")) {
+		t.Errorf("holiday on the only workday = %v, %v", got, err)
+	}
+}
+
+func TestBusinessDaysBetween(t *testing.T) {
+	cal := &Calendar{Holidays: []string{"2024-03-06"}}
+	tests := []struct {
+		from, to string
+		want     int
+	}{
+		{"2024-03-01 09:00", "2024-03-01 18:00", 0},
+		{"2024-03-01 09:00", "2024-03-04 09:00", 1},
+		{"2024-03-01 09:00", "2024-03-08 09:00", 4},
+		{"2024-03-08 09:00", "2024-03-01 09:00", -4},
+		{"2024-03-02 09:00", "2024-03-03 09:00", 0},
+	}
+	for _, tt := range tests {
+		if got := cal.BusinessDaysBetween(day(tt.from), day(tt.to)); got != tt.want {
+			t.Errorf("BusinessDaysBetween(%s, %s) = %d, want %d", tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	// Monday 23:00 UTC is already Tuesday in from's zone, ten hours ahead.
+	east := time.FixedZone("UTC+10", 10*60*60)
+	from := time.Date(2024, 3, 1, 8, 0, 0, 0, east)
+	to := day("2024-03-04 23:00")
+	if got := cal.BusinessDaysBetween(from, to); got != 2 {
+		t.Errorf("BusinessDaysBetween across zones = %d, want 2", got)
+	}
+}
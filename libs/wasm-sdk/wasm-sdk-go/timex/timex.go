@@ -0,0 +1,127 @@
+// Package timex provides timezone-aware formatting and parsing, duration
+// humanization and business-day arithmetic for WASM nodes.
+//
+// The WASM sandbox has no zoneinfo database, so time.LoadLocation fails for
+// everything but "UTC". LoadLocation here fetches zone data from the host
+// instead and caches it for the lifetime of the module instance.
+package timex
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// ErrUnknownZone is returned for zone names the host cannot resolve.
+var ErrUnknownZone = errors.New("timex: unknown time zone")
+
+var locations = map[string]*time.Location{}
+
+// LoadLocation returns the location for an IANA zone name such as
+// "America/New_York". "" and "UTC" return time.UTC.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "UTC" {
+		return time.UTC, nil
+	}
+	if loc, ok := locations[name]; ok {
+		return loc, nil
+	}
+	data := sdk.TimezoneData(name)
+	if data == "" {
+		return nil, ErrUnknownZone
+	}
+	loc, err := time.LoadLocationFromTZData(name, []byte(data))
+	if err != nil {
+		return nil, err
+	}
+	locations[name] = loc
+	return loc, nil
+}
+
+// In converts t to the given zone.
+func In(t time.Time, zone string) (time.Time, error) {
+	loc, err := LoadLocation(zone)
+	if err != nil {
+		return t, err
+	}
+	return t.In(loc), nil
+}
+
+// Format renders t in the given zone using a time.Format layout.
+func Format(t time.Time, zone, layout string) (string, error) {
+	t, err := In(t, zone)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(layout), nil
+}
+
+// Parse interprets value in the given zone unless the layout carries its own
+// offset, like time.ParseInLocation.
+func Parse(layout, value, zone string) (time.Time, error) {
+	loc, err := LoadLocation(zone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation(layout, value, loc)
+}
+
+// Convert re-expresses a wall-clock value from one zone in another, e.g.
+// "2024-03-01 09:00" Europe/Berlin to America/New_York.
+func Convert(layout, value, from, to string) (string, error) {
+	t, err := Parse(layout, value, from)
+	if err != nil {
+		return "", err
+	}
+	return Format(t, to, layout)
+}
+
+var units = []struct {
+	d    time.Duration
+	name string
+}{
+	{24 * time.Hour, "day"},
+	{time.Hour, "hour"},
+	{time.Minute, "minute"},
+	{time.Second, "second"},
+}
+
+// Humanize renders d in its largest unit plus the next smaller one when that
+// is non-zero, e.g. "2 days 3 hours" or "45 seconds". Durations under a
+// second are "0 seconds".
+func Humanize(d time.Duration) string {
+	neg := d < 0
+	if neg {
+		d = -d
+	}
+	var parts []string
+	for _, u := range units {
+		if len(parts) == 2 {
+			break
+		}
+		n := int64(d / u.d)
+		if n == 0 {
+			if len(parts) > 0 {
+				break
+			}
+			continue
+		}
+		d -= time.Duration(n) * u.d
+		name := u.name
+		if n != 1 {
+			name += "s"
+		}
+		parts = append(parts, strconv.FormatInt(n, 10)+" "+name)
+	}
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+	s := strings.Join(parts, " ")
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
@@ -0,0 +1,28 @@
+package timex
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanize(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0 seconds"},
+		{500 * time.Millisecond, "0 seconds"},
+		{time.Second, "1 second"},
+		{45 * time.Second, "45 seconds"},
+		{90 * time.Second, "1 minute 30 seconds"},
+		{time.Hour + 30*time.Second, "1 hour"},
+		{51 * time.Hour, "2 days 3 hours"},
+		{48*time.Hour + 5*time.Minute, "2 days"},
+		{-90 * time.Minute, "-1 hour 30 minutes"},
+	}
+	for _, tt := range tests {
+		if got := Humanize(tt.d); got != tt.want {
+			t.Errorf("Humanize(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package sdk
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MergeStrategy controls how Merge combines two values.
+type MergeStrategy uint8
+
+const (
+	// MergeDeep merges objects recursively; for any other value, including
+	// arrays and null, b replaces a.
+	MergeDeep MergeStrategy = iota
+	// MergeShallow replaces top-level members of a with those of b.
+	MergeShallow
+	// MergeConcat merges like MergeDeep but appends b's arrays to a's.
+	MergeConcat
+	// MergeDefaults merges objects recursively but keeps a's values, only
+	// adding members a lacks; b acts as a set of defaults.
+	MergeDefaults
+)
+
+// Merge combines two JSON values. If either is invalid, the other is
+// returned. Member order follows a, with b's new members appended.
+func Merge(a, b RawValue, strategy MergeStrategy) RawValue {
+	ta, tb := parseTree(a.Raw()), parseTree(b.Raw())
+	if ta == nil {
+		return b
+	}
+	if tb == nil {
+		return a
+	}
+	return mergeTrees(ta, tb, strategy, true).rawValue()
+}
+
+func mergeTrees(a, b *jsonTree, strategy MergeStrategy, top bool) *jsonTree {
+	if a.kind == KindObject && b.kind == KindObject {
+		for _, k := range b.keys {
+			bv := b.vals[k]
+			av, ok := a.get(k)
+			switch {
+			case !ok:
+				a.set(k, bv)
+			case strategy == MergeShallow && top:
+				a.set(k, bv)
+			default:
+				a.set(k, mergeTrees(av, bv, strategy, false))
+			}
+		}
+		return a
+	}
+	switch {
+	case strategy == MergeConcat && a.kind == KindArray && b.kind == KindArray:
+		a.items = append(a.items, b.items...)
+		return a
+	case strategy == MergeDefaults:
+		return a
+	}
+	return b
+}
+
+// Paths used by Pick, Omit and Rename are dot-separated member names such as
+// "user.address.city". When a path reaches an array, the rest of the path
+// applies to every element, so "items.id" addresses the id of each item.
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// Pick returns an object holding only the given paths of v, nested as in v.
+// Missing paths are skipped. Non-objects yield an empty object.
+func Pick(v RawValue, paths ...string) RawValue {
+	t := parseTree(v.Raw())
+	out := &jsonTree{kind: KindObject, vals: map[string]*jsonTree{}}
+	if t == nil || t.kind != KindObject {
+		return out.rawValue()
+	}
+	for _, p := range paths {
+		if picked := pickTree(t, splitPath(p)); picked != nil {
+			out = mergePicked(out, picked)
+		}
+	}
+	return out.rawValue()
+}
+
+// mergePicked combines two picks of the same source; arrays are merged
+// element by element, as both come from the same array.
+func mergePicked(a, b *jsonTree) *jsonTree {
+	switch {
+	case a.kind == KindObject && b.kind == KindObject:
+		for _, k := range b.keys {
+			if av, ok := a.get(k); ok {
+				a.set(k, mergePicked(av, b.vals[k]))
+			} else {
+				a.set(k, b.vals[k])
+			}
+		}
+		return a
+	case a.kind == KindArray && b.kind == KindArray && len(a.items) == len(b.items):
+		for i := range a.items {
+			a.items[i] = mergePicked(a.items[i], b.items[i])
+		}
+		return a
+	}
+	return b
+}
+
+func pickTree(t *jsonTree, path []string) *jsonTree {
+	if len(path) == 0 {
+		return t.clone()
+	}
+	switch t.kind {
+	case KindObject:
+		child, ok := t.get(path[0])
+		if !ok {
+			return nil
+		}
+		sub := pickTree(child, path[1:])
+		if sub == nil {
+			return nil
+		}
+		out := &jsonTree{kind: KindObject, vals: map[string]*jsonTree{}}
+		out.set(path[0], sub)
+		return out
+	case KindArray:
+		out := &jsonTree{kind: KindArray}
+		for _, item := range t.items {
+			if sub := pickTree(item, path); sub != nil {
+				out.items = append(out.items, sub)
+			} else {
+				out.items = append(out.items, &jsonTree{kind: KindObject, vals: map[string]*jsonTree{}})
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// Omit returns v without the given paths.
+func Omit(v RawValue, paths ...string) RawValue {
+	t := parseTree(v.Raw())
+	if t == nil {
+		return v
+	}
+	for _, p := range paths {
+		omitTree(t, splitPath(p))
+	}
+	return t.rawValue()
+}
+
+func omitTree(t *jsonTree, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	switch t.kind {
+	case KindObject:
+		if len(path) == 1 {
+			t.remove(path[0])
+		} else if child, ok := t.get(path[0]); ok {
+			omitTree(child, path[1:])
+		}
+	case KindArray:
+		for _, item := range t.items {
+			omitTree(item, path)
+		}
+	}
+}
+
+// Rename moves values from old to new paths, given as a map of old path to
+// new path. A member renamed within the same object keeps its position;
+// moves to another object create missing parents. Renames are applied in
+// sorted order of the old paths.
+func Rename(v RawValue, mapping map[string]string) RawValue {
+	t := parseTree(v.Raw())
+	if t == nil {
+		return v
+	}
+	olds := make([]string, 0, len(mapping))
+	for old := range mapping {
+		olds = append(olds, old)
+	}
+	sort.Strings(olds)
+	for _, old := range olds {
+		renameTree(t, splitPath(old), splitPath(mapping[old]))
+	}
+	return t.rawValue()
+}
+
+func renameTree(t *jsonTree, from, to []string) {
+	if len(from) == 0 || len(to) == 0 {
+		return
+	}
+	switch t.kind {
+	case KindObject:
+		// Descend while both paths share the parent.
+		if len(from) > 1 && len(to) > 1 && from[0] == to[0] {
+			if child, ok := t.get(from[0]); ok {
+				renameTree(child, from[1:], to[1:])
+			}
+			return
+		}
+		if len(from) == 1 && len(to) == 1 {
+			t.renameKey(from[0], to[0])
+			return
+		}
+		value := takeTree(t, from)
+		if value != nil {
+			putTree(t, to, value)
+		}
+	case KindArray:
+		for _, item := range t.items {
+			renameTree(item, from, to)
+		}
+	}
+}
+
+// renameKey renames a member in place. An existing member named to is
+// replaced.
+func (t *jsonTree) renameKey(from, to string) {
+	v, ok := t.get(from)
+	if !ok || from == to {
+		return
+	}
+	t.remove(to)
+	for i, k := range t.keys {
+		if k == from {
+			t.keys[i] = to
+			break
+		}
+	}
+	delete(t.vals, from)
+	t.vals[to] = v
+}
+
+// takeTree removes and returns the value at an object path.
+func takeTree(t *jsonTree, path []string) *jsonTree {
+	for _, seg := range path[:len(path)-1] {
+		child, ok := t.get(seg)
+		if !ok || child.kind != KindObject {
+			return nil
+		}
+		t = child
+	}
+	last := path[len(path)-1]
+	v, ok := t.get(last)
+	if !ok {
+		return nil
+	}
+	t.remove(last)
+	return v
+}
+
+// putTree sets the value at an object path, creating or replacing
+// intermediate objects.
+func putTree(t *jsonTree, path []string, v *jsonTree) {
+	for _, seg := range path[:len(path)-1] {
+		child, ok := t.get(seg)
+		if !ok || child.kind != KindObject {
+			child = &jsonTree{kind: KindObject, vals: map[string]*jsonTree{}}
+			t.set(seg, child)
+		}
+		t = child
+	}
+	t.set(path[len(path)-1], v)
+}
+
+// Flatten turns nested objects and arrays into a single-level object whose
+// keys join the member names and array indexes with sep:
+// {"a":{"b":[1,2]}} becomes {"a.b.0":1,"a.b.1":2}. Empty objects and arrays
+// are kept as values. Scalars are returned unchanged.
+func Flatten(v RawValue, sep string) RawValue {
+	t := parseTree(v.Raw())
+	if t == nil || t.kind != KindObject && t.kind != KindArray {
+		return v
+	}
+	out := &jsonTree{kind: KindObject, vals: map[string]*jsonTree{}}
+	flattenTree(t, "", sep, out)
+	return out.rawValue()
+}
+
+func flattenTree(t *jsonTree, prefix, sep string, out *jsonTree) {
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + sep + k
+	}
+	switch {
+	case t.kind == KindObject && len(t.keys) > 0:
+		for _, k := range t.keys {
+			flattenTree(t.vals[k], join(k), sep, out)
+		}
+	case t.kind == KindArray && len(t.items) > 0:
+		for i, item := range t.items {
+			flattenTree(item, join(strconv.Itoa(i)), sep, out)
+		}
+	case prefix != "":
+		out.set(prefix, t)
+	}
+}
+
+// Unflatten reverses Flatten for objects: keys are split on sep into nested
+// objects. Array indexes become object members, as the original shape cannot
+// be told apart from objects with numeric keys.
+func Unflatten(v RawValue, sep string) RawValue {
+	t := parseTree(v.Raw())
+	if t == nil || t.kind != KindObject || sep == "" {
+		return v
+	}
+	out := &jsonTree{kind: KindObject, vals: map[string]*jsonTree{}}
+	for _, k := range t.keys {
+		putTree(out, strings.Split(k, sep), t.vals[k])
+	}
+	return out.rawValue()
+}
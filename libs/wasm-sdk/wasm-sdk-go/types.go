@@ -1,12 +1,18 @@
 package sdk
 
 import (
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const ABIVersion = 1
 
+// DefaultExecPin is the exec output activated by Context.Success unless a
+// node configures a different one.
+const DefaultExecPin = "exec_out"
+
 const (
 	LogLevelDebug = 0
 	LogLevelInfo  = 1
@@ -28,6 +34,16 @@ const (
 	DataTypeStruct  = "Struct"
 )
 
+const (
+	HTTPGet     = 0
+	HTTPPost    = 1
+	HTTPPut     = 2
+	HTTPDelete  = 3
+	HTTPPatch   = 4
+	HTTPHead    = 5
+	HTTPOptions = 6
+)
+
 type NodeScores struct {
 	Privacy     uint8 `json:"privacy"`
 	Security    uint8 `json:"security"`
@@ -64,8 +80,17 @@ type PinDefinition struct {
 	DefaultValue *string `json:"default_value,omitempty"`
 	ValueType    *string `json:"value_type,omitempty"`
 	Schema       *string `json:"schema,omitempty"`
+	DataClass    *string `json:"data_class,omitempty"`
 }
 
+// Data classification labels for pins, used by governance tooling to trace
+// sensitive data through boards.
+const (
+	DataClassPublic       = "public"
+	DataClassConfidential = "confidential"
+	DataClassPII          = "pii"
+)
+
 func InputPin(name, friendlyName, description, dataType string) PinDefinition {
 	return PinDefinition{
 		Name:         name,
@@ -103,6 +128,13 @@ func (p PinDefinition) WithSchema(schema string) PinDefinition {
 	return p
 }
 
+// WithDataClass labels the data carried by a pin (DataClassPII,
+// DataClassConfidential or DataClassPublic).
+func (p PinDefinition) WithDataClass(class string) PinDefinition {
+	p.DataClass = &class
+	return p
+}
+
 func (p *PinDefinition) ToJSON() string {
 	var b strings.Builder
 	b.WriteString(`{"name":`)
@@ -128,22 +160,48 @@ func (p *PinDefinition) ToJSON() string {
 		b.WriteString(`,"schema":`)
 		b.WriteString(jsonString(*p.Schema))
 	}
+	if p.DataClass != nil {
+		b.WriteString(`,"data_class":`)
+		b.WriteString(jsonString(*p.DataClass))
+	}
 	b.WriteByte('}')
 	return b.String()
 }
 
 type NodeDefinition struct {
-	Name         string         `json:"name"`
-	FriendlyName string         `json:"friendly_name"`
-	Description  string         `json:"description"`
-	Category     string         `json:"category"`
-	Icon         *string        `json:"icon,omitempty"`
+	Name         string          `json:"name"`
+	FriendlyName string          `json:"friendly_name"`
+	Description  string          `json:"description"`
+	Category     string          `json:"category"`
+	Icon         *string         `json:"icon,omitempty"`
 	Pins         []PinDefinition `json:"pins"`
-	Scores       *NodeScores    `json:"scores,omitempty"`
-	LongRunning  bool           `json:"long_running"`
-	Docs         *string        `json:"docs,omitempty"`
-	Permissions  []string       `json:"permissions,omitempty"`
-	ABIVersion   int            `json:"abi_version"`
+	Scores       *NodeScores     `json:"scores,omitempty"`
+	LongRunning  bool            `json:"long_running"`
+	Docs         *string         `json:"docs,omitempty"`
+	Permissions  []string        `json:"permissions,omitempty"`
+	ABIVersion   int             `json:"abi_version"`
+	HTTPAllow    []string        `json:"http_allowlist,omitempty"`
+	// Catalog metadata shown and searched in the node store; see catalog.go.
+	Tags     []string `json:"tags,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+	Author   string   `json:"author,omitempty"`
+	Homepage string   `json:"homepage,omitempty"`
+	License  string   `json:"license,omitempty"`
+	// Config is the per-instance configuration form; see SetConfig.
+	Config *FormDefinition `json:"config,omitempty"`
+	// Changelog lists what changed per node version; see SetChangelog.
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+	// Concurrency limits parallel runs; see SetConcurrency.
+	Concurrency *ConcurrencyLimit `json:"concurrency,omitempty"`
+	// Retry makes the host retry failed runs; see SetRetryPolicy.
+	Retry *RetryPolicy `json:"retry,omitempty"`
+	// TimeoutSecs bounds each run in seconds; see SetTimeout.
+	TimeoutSecs int `json:"timeout_secs,omitempty"`
+	// Resources guides instance placement; see SetResourceHints.
+	Resources *ResourceHints `json:"resources,omitempty"`
+	// DefaultExecPin is the exec output Context.Success activates for this
+	// node when run through a Registry. It is not serialized.
+	DefaultExecPin string `json:"-"`
 }
 
 func NewNodeDefinition() NodeDefinition {
@@ -167,6 +225,38 @@ func (n *NodeDefinition) AddPermission(perm string) *NodeDefinition {
 	return n
 }
 
+// SetDefaultExecPin names the exec output that Context.Success activates.
+func (n *NodeDefinition) SetDefaultExecPin(pin string) *NodeDefinition {
+	n.DefaultExecPin = pin
+	return n
+}
+
+// ResolveDefaultExecPin returns the exec output Success should activate:
+// DefaultExecPin if set, otherwise "exec_out" if declared, otherwise the only
+// declared exec output. It falls back to "exec_out".
+func (n *NodeDefinition) ResolveDefaultExecPin() string {
+	if n.DefaultExecPin != "" {
+		return n.DefaultExecPin
+	}
+	only := ""
+	count := 0
+	for i := range n.Pins {
+		p := &n.Pins[i]
+		if p.PinType != "Output" || p.DataType != DataTypeExec {
+			continue
+		}
+		if p.Name == DefaultExecPin {
+			return DefaultExecPin
+		}
+		only = p.Name
+		count++
+	}
+	if count == 1 {
+		return only
+	}
+	return DefaultExecPin
+}
+
 func (n *NodeDefinition) ToJSON() string {
 	var b strings.Builder
 	b.WriteString(`{"name":`)
@@ -204,6 +294,56 @@ func (n *NodeDefinition) ToJSON() string {
 		b.WriteString(`,"docs":`)
 		b.WriteString(jsonString(*n.Docs))
 	}
+	if len(n.HTTPAllow) > 0 {
+		b.WriteString(`,"http_allowlist":`)
+		b.WriteString(jsonStringArray(n.HTTPAllow))
+	}
+	if len(n.Tags) > 0 {
+		b.WriteString(`,"tags":`)
+		b.WriteString(jsonStringArray(n.Tags))
+	}
+	if len(n.Keywords) > 0 {
+		b.WriteString(`,"keywords":`)
+		b.WriteString(jsonStringArray(n.Keywords))
+	}
+	if n.Author != "" {
+		b.WriteString(`,"author":`)
+		b.WriteString(jsonString(n.Author))
+	}
+	if n.Homepage != "" {
+		b.WriteString(`,"homepage":`)
+		b.WriteString(jsonString(n.Homepage))
+	}
+	if n.License != "" {
+		b.WriteString(`,"license":`)
+		b.WriteString(jsonString(n.License))
+	}
+	if n.Config != nil {
+		b.WriteString(`,"config":`)
+		b.WriteString(n.Config.ToJSON())
+		b.WriteString(`,"config_schema":`)
+		b.WriteString(n.Config.Schema())
+	}
+	if len(n.Changelog) > 0 {
+		b.WriteString(`,"changelog":`)
+		b.WriteString(changelogJSON(n.Changelog))
+	}
+	if n.Concurrency != nil {
+		b.WriteString(`,"concurrency":`)
+		b.WriteString(n.Concurrency.ToJSON())
+	}
+	if n.Retry != nil {
+		b.WriteString(`,"retry":`)
+		b.WriteString(n.Retry.ToJSON())
+	}
+	if n.TimeoutSecs > 0 {
+		b.WriteString(`,"timeout_secs":`)
+		b.WriteString(strconv.Itoa(n.TimeoutSecs))
+	}
+	if n.Resources != nil {
+		b.WriteString(`,"resources":`)
+		b.WriteString(n.Resources.ToJSON())
+	}
 	if len(n.Permissions) > 0 {
 		b.WriteString(`,"permissions":[`)
 		for i, p := range n.Permissions {
@@ -218,6 +358,710 @@ func (n *NodeDefinition) ToJSON() string {
 	return b.String()
 }
 
+const (
+	NotifyInfo    = "info"
+	NotifySuccess = "success"
+	NotifyWarning = "warning"
+	NotifyError   = "error"
+)
+
+// NotificationAction is a button attached to a user notification. Clicking it
+// either opens URL or, when URL is empty, reports ID back to the board.
+type NotificationAction struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	URL   string `json:"url,omitempty"`
+}
+
+func (a *NotificationAction) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"id":`)
+	b.WriteString(jsonString(a.ID))
+	b.WriteString(`,"label":`)
+	b.WriteString(jsonString(a.Label))
+	if a.URL != "" {
+		b.WriteString(`,"url":`)
+		b.WriteString(jsonString(a.URL))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func notificationActionsJSON(actions []NotificationAction) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := range actions {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(actions[i].ToJSON())
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// ExecOutput is the captured result of a child process started via Exec.
+type ExecOutput struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+}
+
+func parseExecOutputJSON(s string) (ExecOutput, bool) {
+	fields := jsonObjectFields(s)
+	if fields == nil {
+		return ExecOutput{ExitCode: -1}, false
+	}
+	code, err := strconv.Atoi(fields["exit_code"])
+	if err != nil {
+		code = -1
+	}
+	return ExecOutput{
+		Stdout:   jsonUnquote(fields["stdout"]),
+		Stderr:   jsonUnquote(fields["stderr"]),
+		ExitCode: code,
+	}, true
+}
+
+// HTTPResponse is a completed HTTP exchange returned by HTTPFetch.
+// Header names are lower-cased by the host.
+type HTTPResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+// Header returns the value of a response header, matching names case-insensitively.
+func (r *HTTPResponse) Header(name string) string {
+	return r.Headers[strings.ToLower(name)]
+}
+
+// OK reports whether the status code is in the 2xx range.
+func (r *HTTPResponse) OK() bool {
+	return r.Status >= 200 && r.Status < 300
+}
+
+// HTTPRequestOptions tunes a single request sent with HTTPFetchWith. The
+// zero value keeps the host defaults. Hosts apply their egress policy on top:
+// a proxy or disabled verification that policy forbids makes the request fail
+// rather than silently using other settings.
+type HTTPRequestOptions struct {
+	// Proxy routes the request through a proxy URL, e.g.
+	// "http://proxy.internal:3128" or "socks5://10.0.0.2:1080".
+	Proxy string `json:"proxy,omitempty"`
+	// CARef names a platform-managed CA certificate to trust in addition to
+	// the system roots, for internal services with a private CA.
+	CARef string `json:"ca_ref,omitempty"`
+	// InsecureSkipVerify disables TLS certificate verification. Hosts only
+	// honor it on self-hosted deployments.
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+	// TimeoutMs bounds the whole exchange in milliseconds.
+	TimeoutMs int64 `json:"timeout_ms,omitempty"`
+	// ClientCertRef names a platform-managed client certificate presented
+	// for mutual TLS. The private key stays in the host.
+	ClientCertRef string `json:"client_cert_ref,omitempty"`
+}
+
+// HTTPCall bundles a request for helpers that take it as one value, such as
+// Context.HTTPRequestWithClientCert. Headers is a JSON object, as for
+// HTTPFetch.
+type HTTPCall struct {
+	Method  int
+	URL     string
+	Headers string
+	Body    string
+	Options HTTPRequestOptions
+}
+
+// IsZero reports whether no option is set.
+func (o *HTTPRequestOptions) IsZero() bool {
+	return *o == HTTPRequestOptions{}
+}
+
+func (o *HTTPRequestOptions) ToJSON() string {
+	var b strings.Builder
+	b.WriteByte('{')
+	sep := func() {
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+	}
+	if o.Proxy != "" {
+		b.WriteString(`"proxy":`)
+		b.WriteString(jsonString(o.Proxy))
+	}
+	if o.CARef != "" {
+		sep()
+		b.WriteString(`"ca_ref":`)
+		b.WriteString(jsonString(o.CARef))
+	}
+	if o.InsecureSkipVerify {
+		sep()
+		b.WriteString(`"insecure_skip_verify":true`)
+	}
+	if o.TimeoutMs != 0 {
+		sep()
+		b.WriteString(`"timeout_ms":`)
+		b.WriteString(strconv.FormatInt(o.TimeoutMs, 10))
+	}
+	if o.ClientCertRef != "" {
+		sep()
+		b.WriteString(`"client_cert_ref":`)
+		b.WriteString(jsonString(o.ClientCertRef))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func parseHTTPResponseJSON(s string) (HTTPResponse, bool) {
+	fields := jsonObjectFields(s)
+	if fields == nil {
+		return HTTPResponse{}, false
+	}
+	status, _ := strconv.Atoi(fields["status"])
+	resp := HTTPResponse{
+		Status:  status,
+		Headers: make(map[string]string),
+		Body:    jsonUnquote(fields["body"]),
+	}
+	for k, v := range jsonObjectFields(fields["headers"]) {
+		resp.Headers[strings.ToLower(k)] = jsonUnquote(v)
+	}
+	return resp, true
+}
+
+// Platform quota kinds for GetQuota.
+const (
+	QuotaModelTokens  = "model_tokens"
+	QuotaHTTPCalls    = "http_calls"
+	QuotaStorageBytes = "storage_bytes"
+	QuotaCredits      = "credits"
+)
+
+// Quota is the state of a budget in the current billing period. Unlimited
+// quotas have no meaningful Limit or Remaining.
+type Quota struct {
+	Kind      string
+	Limit     int64
+	Used      int64
+	Remaining int64
+	ResetsAt  int64 // Unix milliseconds; 0 if the quota does not reset
+	Unlimited bool
+}
+
+// Allows reports whether amount fits into the remaining budget.
+func (q *Quota) Allows(amount int64) bool { return q.Unlimited || q.Remaining >= amount }
+
+// UsedFraction returns Used/Limit in [0, 1], or 0 for unlimited quotas.
+func (q *Quota) UsedFraction() float64 {
+	if q.Unlimited || q.Limit <= 0 {
+		return 0
+	}
+	if q.Used >= q.Limit {
+		return 1
+	}
+	return float64(q.Used) / float64(q.Limit)
+}
+
+// RetentionPolicy tells the platform how long to keep a stored file. Files
+// written without one are kept until deleted.
+type RetentionPolicy struct {
+	// TTL deletes the file this long after it was written; 0 keeps it.
+	TTL time.Duration
+	// LegalHold protects the file from deletion, including by TTL and by
+	// users, until the hold is lifted by an administrator.
+	LegalHold bool
+}
+
+func (p *RetentionPolicy) IsZero() bool { return p.TTL <= 0 && !p.LegalHold }
+
+func (p *RetentionPolicy) ToJSON() string {
+	return `{"ttl_ms":` + strconv.FormatInt(p.TTL.Milliseconds(), 10) +
+		`,"legal_hold":` + strconv.FormatBool(p.LegalHold) + `}`
+}
+
+// Transfer protocols supported by TransferConn.
+const (
+	TransferSFTP = "sftp"
+	TransferFTPS = "ftps"
+)
+
+// TransferConn describes an SFTP or FTPS server. Credentials are never part
+// of the module: CredentialRef names a platform-managed secret holding the
+// password or private key.
+type TransferConn struct {
+	Protocol      string `json:"protocol"`
+	Host          string `json:"host"`
+	Port          int    `json:"port,omitempty"` // 0 uses 22 (SFTP) or 21 (FTPS)
+	User          string `json:"user"`
+	CredentialRef string `json:"credential_ref"`
+	// HostKey pins the server's SSH host key fingerprint ("SHA256:...") for
+	// SFTP. Empty accepts keys the host already trusts.
+	HostKey string `json:"host_key,omitempty"`
+}
+
+func (t *TransferConn) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"protocol":`)
+	b.WriteString(jsonString(t.Protocol))
+	b.WriteString(`,"host":`)
+	b.WriteString(jsonString(t.Host))
+	if t.Port != 0 {
+		b.WriteString(`,"port":`)
+		b.WriteString(strconv.Itoa(t.Port))
+	}
+	b.WriteString(`,"user":`)
+	b.WriteString(jsonString(t.User))
+	b.WriteString(`,"credential_ref":`)
+	b.WriteString(jsonString(t.CredentialRef))
+	if t.HostKey != "" {
+		b.WriteString(`,"host_key":`)
+		b.WriteString(jsonString(t.HostKey))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// RemoteFile is an entry of a remote directory listing. Modified is in Unix
+// milliseconds, 0 if the server did not report it.
+type RemoteFile struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Size     int64  `json:"size"`
+	Modified int64  `json:"modified"`
+	IsDir    bool   `json:"is_dir"`
+}
+
+func parseRemoteFilesJSON(s string) []RemoteFile {
+	items := jsonArrayItems(s)
+	out := make([]RemoteFile, 0, len(items))
+	for _, item := range items {
+		f := jsonObjectFields(item)
+		if f == nil {
+			continue
+		}
+		size, _ := strconv.ParseInt(f["size"], 10, 64)
+		modified, _ := strconv.ParseInt(f["modified"], 10, 64)
+		out = append(out, RemoteFile{
+			Name:     jsonUnquote(f["name"]),
+			Path:     jsonUnquote(f["path"]),
+			Size:     size,
+			Modified: modified,
+			IsDir:    f["is_dir"] == "true",
+		})
+	}
+	return out
+}
+
+// Bucket describes an external S3-compatible bucket, such as AWS S3, MinIO
+// or Cloudflare R2, owned by the user rather than the flow. CredentialRef
+// names a platform-managed secret holding the access key pair.
+type Bucket struct {
+	Endpoint      string `json:"endpoint,omitempty"` // empty uses AWS
+	Region        string `json:"region,omitempty"`
+	Name          string `json:"name"`
+	CredentialRef string `json:"credential_ref"`
+	PathStyle     bool   `json:"path_style,omitempty"` // bucket in the path instead of the host name
+}
+
+func (b *Bucket) ToJSON() string {
+	var s strings.Builder
+	s.WriteString(`{"name":`)
+	s.WriteString(jsonString(b.Name))
+	s.WriteString(`,"credential_ref":`)
+	s.WriteString(jsonString(b.CredentialRef))
+	if b.Endpoint != "" {
+		s.WriteString(`,"endpoint":`)
+		s.WriteString(jsonString(b.Endpoint))
+	}
+	if b.Region != "" {
+		s.WriteString(`,"region":`)
+		s.WriteString(jsonString(b.Region))
+	}
+	if b.PathStyle {
+		s.WriteString(`,"path_style":true`)
+	}
+	s.WriteByte('}')
+	return s.String()
+}
+
+// BucketObject is an entry of a bucket listing. Modified is in Unix
+// milliseconds.
+type BucketObject struct {
+	Key      string `json:"key"`
+	Size     int64  `json:"size"`
+	ETag     string `json:"etag"`
+	Modified int64  `json:"modified"`
+}
+
+func parseBucketObjectsJSON(s string) []BucketObject {
+	items := jsonArrayItems(s)
+	out := make([]BucketObject, 0, len(items))
+	for _, item := range items {
+		f := jsonObjectFields(item)
+		if f == nil {
+			continue
+		}
+		size, _ := strconv.ParseInt(f["size"], 10, 64)
+		modified, _ := strconv.ParseInt(f["modified"], 10, 64)
+		out = append(out, BucketObject{
+			Key:      jsonUnquote(f["key"]),
+			Size:     size,
+			ETag:     jsonUnquote(f["etag"]),
+			Modified: modified,
+		})
+	}
+	return out
+}
+
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// ChatMessage is a single message in a chat completion request. Assistant
+// messages that requested tools carry ToolCalls; the answer to each call is a
+// RoleTool message with the matching ToolCallID.
+type ChatMessage struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+}
+
+func (m *ChatMessage) ToJSON() string {
+	if len(m.ToolCalls) == 0 && m.ToolCallID == "" {
+		return `{"role":` + jsonString(m.Role) + `,"content":` + jsonString(m.Content) + `}`
+	}
+	var b strings.Builder
+	b.WriteString(`{"role":`)
+	b.WriteString(jsonString(m.Role))
+	b.WriteString(`,"content":`)
+	b.WriteString(jsonString(m.Content))
+	if len(m.ToolCalls) > 0 {
+		b.WriteString(`,"tool_calls":[`)
+		for i := range m.ToolCalls {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(m.ToolCalls[i].ToJSON())
+		}
+		b.WriteByte(']')
+	}
+	if m.ToolCallID != "" {
+		b.WriteString(`,"tool_call_id":`)
+		b.WriteString(jsonString(m.ToolCallID))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// ToolSpec declares a function the model may call. Parameters is the JSON
+// Schema of its arguments object.
+type ToolSpec struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Parameters  string `json:"parameters"`
+}
+
+func (t *ToolSpec) ToJSON() string {
+	params := t.Parameters
+	if params == "" {
+		params = `{"type":"object","properties":{}}`
+	}
+	return `{"name":` + jsonString(t.Name) + `,"description":` + jsonString(t.Description) +
+		`,"parameters":` + params + `}`
+}
+
+// ToolCall is a model's request to call a tool. Arguments is the raw JSON
+// arguments object as produced by the model; it may be invalid.
+type ToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+func (t *ToolCall) ToJSON() string {
+	return `{"id":` + jsonString(t.ID) + `,"name":` + jsonString(t.Name) +
+		`,"arguments":` + jsonString(t.Arguments) + `}`
+}
+
+// ChatRequest describes a chat completion. Zero Temperature and MaxTokens
+// leave the model bit's defaults in place. With Tools set the model may
+// answer with ChatResponse.ToolCalls instead of content.
+type ChatRequest struct {
+	Messages    []ChatMessage `json:"messages"`
+	Temperature float64       `json:"temperature,omitempty"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Tools       []ToolSpec    `json:"tools,omitempty"`
+}
+
+func (r *ChatRequest) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"messages":[`)
+	for i := range r.Messages {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(r.Messages[i].ToJSON())
+	}
+	b.WriteByte(']')
+	if r.Temperature != 0 {
+		b.WriteString(`,"temperature":`)
+		b.WriteString(strconv.FormatFloat(r.Temperature, 'f', -1, 64))
+	}
+	if r.MaxTokens != 0 {
+		b.WriteString(`,"max_tokens":`)
+		b.WriteString(strconv.Itoa(r.MaxTokens))
+	}
+	if len(r.Tools) > 0 {
+		b.WriteString(`,"tools":[`)
+		for i := range r.Tools {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(r.Tools[i].ToJSON())
+		}
+		b.WriteByte(']')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// ChatResponse is the assistant reply to a ChatRequest.
+type ChatResponse struct {
+	Content          string `json:"content"`
+	FinishReason     string `json:"finish_reason"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	// ToolCalls lists the tools the model wants called, in order.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+func (r *ChatResponse) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"content":`)
+	b.WriteString(jsonString(r.Content))
+	b.WriteString(`,"finish_reason":`)
+	b.WriteString(jsonString(r.FinishReason))
+	b.WriteString(`,"prompt_tokens":`)
+	b.WriteString(strconv.Itoa(r.PromptTokens))
+	b.WriteString(`,"completion_tokens":`)
+	b.WriteString(strconv.Itoa(r.CompletionTokens))
+	if len(r.ToolCalls) > 0 {
+		b.WriteString(`,"tool_calls":[`)
+		for i := range r.ToolCalls {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(r.ToolCalls[i].ToJSON())
+		}
+		b.WriteByte(']')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// ParseChatResponse decodes a ChatResponse encoded with ToJSON or returned by
+// the host.
+func ParseChatResponse(s string) (ChatResponse, bool) { return parseChatResponseJSON(s) }
+
+func parseChatResponseJSON(s string) (ChatResponse, bool) {
+	fields := jsonObjectFields(s)
+	if fields == nil {
+		return ChatResponse{}, false
+	}
+	prompt, _ := strconv.Atoi(fields["prompt_tokens"])
+	completion, _ := strconv.Atoi(fields["completion_tokens"])
+	resp := ChatResponse{
+		Content:          jsonUnquote(fields["content"]),
+		FinishReason:     jsonUnquote(fields["finish_reason"]),
+		PromptTokens:     prompt,
+		CompletionTokens: completion,
+	}
+	for _, item := range jsonArrayItems(fields["tool_calls"]) {
+		call := jsonObjectFields(item)
+		if call == nil {
+			continue
+		}
+		// Providers send arguments either as an object or as a JSON string.
+		args := call["arguments"]
+		if strings.HasPrefix(args, `"`) {
+			args = jsonUnquote(args)
+		}
+		resp.ToolCalls = append(resp.ToolCalls, ToolCall{
+			ID:        jsonUnquote(call["id"]),
+			Name:      jsonUnquote(call["name"]),
+			Arguments: args,
+		})
+	}
+	return resp, true
+}
+
+// VectorRecord is an entry stored in a vector collection. Vector and Metadata
+// hold raw JSON (an array of numbers and an optional object).
+type VectorRecord struct {
+	ID       string `json:"id"`
+	Vector   string `json:"vector"`
+	Text     string `json:"text"`
+	Metadata string `json:"metadata,omitempty"`
+}
+
+func (r *VectorRecord) ToJSON() string {
+	var b strings.Builder
+	b.WriteString(`{"id":`)
+	b.WriteString(jsonString(r.ID))
+	b.WriteString(`,"vector":`)
+	b.WriteString(r.Vector)
+	b.WriteString(`,"text":`)
+	b.WriteString(jsonString(r.Text))
+	if r.Metadata != "" {
+		b.WriteString(`,"metadata":`)
+		b.WriteString(r.Metadata)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// VectorMatch is a search hit returned by VectorSearch, ordered by descending Score.
+type VectorMatch struct {
+	ID       string  `json:"id"`
+	Score    float64 `json:"score"`
+	Text     string  `json:"text"`
+	Metadata string  `json:"metadata,omitempty"`
+}
+
+func parseVectorMatchesJSON(s string) []VectorMatch {
+	items := jsonArrayItems(s)
+	matches := make([]VectorMatch, 0, len(items))
+	for _, item := range items {
+		fields := jsonObjectFields(item)
+		score, _ := strconv.ParseFloat(fields["score"], 64)
+		matches = append(matches, VectorMatch{
+			ID:       jsonUnquote(fields["id"]),
+			Score:    score,
+			Text:     jsonUnquote(fields["text"]),
+			Metadata: fields["metadata"],
+		})
+	}
+	return matches
+}
+
+// Entity is a named entity found by DetectEntities. Start and End are byte
+// offsets into the analyzed text; Label is the model's tag, e.g. "PERSON".
+type Entity struct {
+	Label string  `json:"label"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
+}
+
+func parseEntitiesJSON(s string) []Entity {
+	items := jsonArrayItems(s)
+	entities := make([]Entity, 0, len(items))
+	for _, item := range items {
+		fields := jsonObjectFields(item)
+		start, _ := strconv.Atoi(fields["start"])
+		end, _ := strconv.Atoi(fields["end"])
+		score, _ := strconv.ParseFloat(fields["score"], 64)
+		entities = append(entities, Entity{
+			Label: jsonUnquote(fields["label"]),
+			Start: start,
+			End:   end,
+			Text:  jsonUnquote(fields["text"]),
+			Score: score,
+		})
+	}
+	return entities
+}
+
+// Model capabilities for ListModelBits and DefaultBit.
+const (
+	CapabilityChat      = "chat"
+	CapabilityEmbedding = "embedding"
+	CapabilityRerank    = "rerank"
+	CapabilityNER       = "ner"
+	CapabilityVision    = "vision"
+	CapabilityTools     = "tools"
+)
+
+// ModelBit describes a model available to the app. Pass Bit, the raw Bit
+// JSON, to ChatComplete, EmbedText, Rerank or DetectEntities. Costs are
+// hints in USD per million tokens, 0 when unknown or free (e.g. local
+// models).
+type ModelBit struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Provider        string   `json:"provider"`
+	Capabilities    []string `json:"capabilities"`
+	ContextSize     int      `json:"context_size"`
+	MaxOutputTokens int      `json:"max_output_tokens,omitempty"`
+	InputCost       float64  `json:"input_cost,omitempty"`
+	OutputCost      float64  `json:"output_cost,omitempty"`
+	Local           bool     `json:"local,omitempty"`
+	Bit             string   `json:"bit"`
+}
+
+// Has reports whether the model has capability.
+func (m *ModelBit) Has(capability string) bool {
+	for _, c := range m.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+func parseModelBitJSON(s string) (ModelBit, bool) {
+	fields := jsonObjectFields(s)
+	if fields == nil || fields["bit"] == "" {
+		return ModelBit{}, false
+	}
+	ctxSize, _ := strconv.Atoi(fields["context_size"])
+	maxOut, _ := strconv.Atoi(fields["max_output_tokens"])
+	inCost, _ := strconv.ParseFloat(fields["input_cost"], 64)
+	outCost, _ := strconv.ParseFloat(fields["output_cost"], 64)
+	return ModelBit{
+		ID:              jsonUnquote(fields["id"]),
+		Name:            jsonUnquote(fields["name"]),
+		Provider:        jsonUnquote(fields["provider"]),
+		Capabilities:    parseStringArrayJSON(fields["capabilities"]),
+		ContextSize:     ctxSize,
+		MaxOutputTokens: maxOut,
+		InputCost:       inCost,
+		OutputCost:      outCost,
+		Local:           fields["local"] == "true",
+		Bit:             fields["bit"],
+	}, true
+}
+
+// ScoredResult is a candidate ranked by Rerank. Index is its position in the
+// candidates passed in; Score is the model's relevance, higher is better.
+type ScoredResult struct {
+	Index int     `json:"index"`
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
+}
+
+func parseScoredResultsJSON(s string, candidates []string) []ScoredResult {
+	items := jsonArrayItems(s)
+	results := make([]ScoredResult, 0, len(items))
+	for _, item := range items {
+		fields := jsonObjectFields(item)
+		index, err := strconv.Atoi(fields["index"])
+		if err != nil || index < 0 || index >= len(candidates) {
+			continue
+		}
+		score, _ := strconv.ParseFloat(fields["score"], 64)
+		results = append(results, ScoredResult{Index: index, Text: candidates[index], Score: score})
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results
+}
+
 type ExecutionInput struct {
 	Inputs      map[string]string `json:"inputs"`
 	NodeID      string            `json:"node_id"`
@@ -228,6 +1072,15 @@ type ExecutionInput struct {
 	UserID      string            `json:"user_id"`
 	StreamState bool              `json:"stream_state"`
 	LogLevel    uint8             `json:"log_level"`
+	// Deterministic is set when the host requests a reproducible run.
+	Deterministic *DeterministicInput `json:"deterministic,omitempty"`
+	// DryRun is set when the user previews the board; see Context.SideEffect.
+	DryRun bool `json:"dry_run,omitempty"`
+	// Attempt numbers the runs of a retried node, starting at 1.
+	Attempt int `json:"attempt,omitempty"`
+	// TimeoutMs is the run's effective timeout: the definition's, capped by
+	// the platform limit. 0 means unbounded.
+	TimeoutMs int64 `json:"timeout_ms,omitempty"`
 }
 
 type ExecutionResult struct {
@@ -235,6 +1088,9 @@ type ExecutionResult struct {
 	Error        *string           `json:"error,omitempty"`
 	ActivateExec []string          `json:"activate_exec"`
 	Pending      bool              `json:"pending"`
+	ErrorInfo    *ErrorInfo        `json:"error_info,omitempty"`
+	// Assertions are the checks reported by an assertion node.
+	Assertions []AssertionResult `json:"assertions,omitempty"`
 }
 
 func SuccessResult() ExecutionResult {
@@ -252,16 +1108,20 @@ func FailResult(message string) ExecutionResult {
 	}
 }
 
+// Deprecated: set outputs on the Context (SetOutput or Out) and finish with
+// Success, Done or Finish instead of mutating the result.
 func (r *ExecutionResult) SetOutput(name, value string) *ExecutionResult {
 	r.Outputs[name] = value
 	return r
 }
 
+// Deprecated: use Context.ActivateExec or Context.Activate.
 func (r *ExecutionResult) ActivateExecPin(pinName string) *ExecutionResult {
 	r.ActivateExec = append(r.ActivateExec, pinName)
 	return r
 }
 
+// Deprecated: use Context.SetPending.
 func (r *ExecutionResult) SetPending(pending bool) *ExecutionResult {
 	r.Pending = pending
 	return r
@@ -297,6 +1157,20 @@ func (r *ExecutionResult) ToJSON() string {
 		b.WriteString(`,"error":`)
 		b.WriteString(jsonString(*r.Error))
 	}
+	if r.ErrorInfo != nil {
+		b.WriteString(`,"error_info":`)
+		b.WriteString(r.ErrorInfo.ToJSON())
+	}
+	if len(r.Assertions) > 0 {
+		b.WriteString(`,"assertions":[`)
+		for i := range r.Assertions {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(r.Assertions[i].ToJSON())
+		}
+		b.WriteByte(']')
+	}
 	b.WriteByte('}')
 	return b.String()
 }
@@ -0,0 +1,267 @@
+// Package units converts, parses and formats physical and digital quantities:
+// length, mass, temperature, speed, data sizes and data rates.
+//
+// Unit symbols are case-sensitive because case carries meaning ("MB" is
+// megabytes, "Mb" megabits); spelled-out names and aliases ("kilometers",
+// "lbs") are matched case-insensitively.
+package units
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrUnknownUnit  = errors.New("units: unknown unit")
+	ErrIncompatible = errors.New("units: incompatible dimensions")
+	ErrSyntax       = errors.New("units: invalid quantity")
+)
+
+// Dimension is the kind of quantity a unit measures.
+type Dimension uint8
+
+const (
+	Length Dimension = iota + 1
+	Mass
+	Temperature
+	Speed
+	DataSize
+	DataRate
+)
+
+func (d Dimension) String() string {
+	switch d {
+	case Length:
+		return "length"
+	case Mass:
+		return "mass"
+	case Temperature:
+		return "temperature"
+	case Speed:
+		return "speed"
+	case DataSize:
+		return "data size"
+	case DataRate:
+		return "data rate"
+	}
+	return "unknown"
+}
+
+// Unit converts to its dimension's base unit (m, kg, K, m/s, byte, byte/s)
+// as base = (value + Offset) * Factor.
+type Unit struct {
+	Symbol  string
+	Name    string
+	Dim     Dimension
+	Factor  float64
+	Offset  float64
+	aliases []string
+}
+
+var all = []Unit{
+	{Symbol: "nm", Name: "nanometer", Dim: Length, Factor: 1e-9},
+	{Symbol: "µm", Name: "micrometer", Dim: Length, Factor: 1e-6, aliases: []string{"um"}},
+	{Symbol: "mm", Name: "millimeter", Dim: Length, Factor: 1e-3},
+	{Symbol: "cm", Name: "centimeter", Dim: Length, Factor: 1e-2},
+	{Symbol: "m", Name: "meter", Dim: Length, Factor: 1, aliases: []string{"metre"}},
+	{Symbol: "km", Name: "kilometer", Dim: Length, Factor: 1e3, aliases: []string{"kilometre"}},
+	{Symbol: "in", Name: "inch", Dim: Length, Factor: 0.0254, aliases: []string{"inches", "\""}},
+	{Symbol: "ft", Name: "foot", Dim: Length, Factor: 0.3048, aliases: []string{"feet", "'"}},
+	{Symbol: "yd", Name: "yard", Dim: Length, Factor: 0.9144},
+	{Symbol: "mi", Name: "mile", Dim: Length, Factor: 1609.344},
+	{Symbol: "nmi", Name: "nautical mile", Dim: Length, Factor: 1852},
+
+	{Symbol: "mg", Name: "milligram", Dim: Mass, Factor: 1e-6},
+	{Symbol: "g", Name: "gram", Dim: Mass, Factor: 1e-3},
+	{Symbol: "kg", Name: "kilogram", Dim: Mass, Factor: 1},
+	{Symbol: "t", Name: "tonne", Dim: Mass, Factor: 1e3, aliases: []string{"metric ton"}},
+	{Symbol: "oz", Name: "ounce", Dim: Mass, Factor: 0.028349523125},
+	{Symbol: "lb", Name: "pound", Dim: Mass, Factor: 0.45359237, aliases: []string{"lbs"}},
+	{Symbol: "st", Name: "stone", Dim: Mass, Factor: 6.35029318},
+
+	{Symbol: "K", Name: "kelvin", Dim: Temperature, Factor: 1},
+	{Symbol: "°C", Name: "celsius", Dim: Temperature, Factor: 1, Offset: 273.15, aliases: []string{"C", "degC"}},
+	{Symbol: "°F", Name: "fahrenheit", Dim: Temperature, Factor: 5.0 / 9, Offset: 459.67, aliases: []string{"F", "degF"}},
+
+	{Symbol: "m/s", Name: "meter per second", Dim: Speed, Factor: 1},
+	{Symbol: "km/h", Name: "kilometer per hour", Dim: Speed, Factor: 1 / 3.6, aliases: []string{"kph"}},
+	{Symbol: "mph", Name: "mile per hour", Dim: Speed, Factor: 0.44704},
+	{Symbol: "kn", Name: "knot", Dim: Speed, Factor: 1852 / 3600.0, aliases: []string{"kt"}},
+	{Symbol: "ft/s", Name: "foot per second", Dim: Speed, Factor: 0.3048},
+
+	{Symbol: "bit", Name: "bit", Dim: DataSize, Factor: 1.0 / 8, aliases: []string{"b"}},
+	{Symbol: "B", Name: "byte", Dim: DataSize, Factor: 1},
+	{Symbol: "kB", Name: "kilobyte", Dim: DataSize, Factor: 1e3, aliases: []string{"KB"}},
+	{Symbol: "MB", Name: "megabyte", Dim: DataSize, Factor: 1e6},
+	{Symbol: "GB", Name: "gigabyte", Dim: DataSize, Factor: 1e9},
+	{Symbol: "TB", Name: "terabyte", Dim: DataSize, Factor: 1e12},
+	{Symbol: "PB", Name: "petabyte", Dim: DataSize, Factor: 1e15},
+	{Symbol: "KiB", Name: "kibibyte", Dim: DataSize, Factor: 1 << 10},
+	{Symbol: "MiB", Name: "mebibyte", Dim: DataSize, Factor: 1 << 20},
+	{Symbol: "GiB", Name: "gibibyte", Dim: DataSize, Factor: 1 << 30},
+	{Symbol: "TiB", Name: "tebibyte", Dim: DataSize, Factor: 1 << 40},
+	{Symbol: "PiB", Name: "pebibyte", Dim: DataSize, Factor: 1 << 50},
+	{Symbol: "kb", Name: "kilobit", Dim: DataSize, Factor: 1e3 / 8, aliases: []string{"Kb"}},
+	{Symbol: "Mb", Name: "megabit", Dim: DataSize, Factor: 1e6 / 8},
+	{Symbol: "Gb", Name: "gigabit", Dim: DataSize, Factor: 1e9 / 8},
+
+	{Symbol: "bps", Name: "bit per second", Dim: DataRate, Factor: 1.0 / 8, aliases: []string{"bit/s"}},
+	{Symbol: "kbps", Name: "kilobit per second", Dim: DataRate, Factor: 1e3 / 8, aliases: []string{"kbit/s", "Kbps"}},
+	{Symbol: "Mbps", Name: "megabit per second", Dim: DataRate, Factor: 1e6 / 8, aliases: []string{"Mbit/s"}},
+	{Symbol: "Gbps", Name: "gigabit per second", Dim: DataRate, Factor: 1e9 / 8, aliases: []string{"Gbit/s"}},
+	{Symbol: "B/s", Name: "byte per second", Dim: DataRate, Factor: 1},
+	{Symbol: "kB/s", Name: "kilobyte per second", Dim: DataRate, Factor: 1e3, aliases: []string{"KB/s"}},
+	{Symbol: "MB/s", Name: "megabyte per second", Dim: DataRate, Factor: 1e6},
+	{Symbol: "GB/s", Name: "gigabyte per second", Dim: DataRate, Factor: 1e9},
+	{Symbol: "MiB/s", Name: "mebibyte per second", Dim: DataRate, Factor: 1 << 20},
+}
+
+var bySymbol, byName map[string]int
+
+func init() {
+	bySymbol = make(map[string]int, len(all))
+	byName = make(map[string]int, len(all)*2)
+	for i, u := range all {
+		bySymbol[u.Symbol] = i
+		byName[strings.ToLower(u.Name)] = i
+		byName[strings.ToLower(u.Name)+"s"] = i
+		for _, a := range u.aliases {
+			if _, taken := bySymbol[a]; !taken {
+				bySymbol[a] = i
+			}
+			byName[strings.ToLower(a)] = i
+		}
+	}
+}
+
+// Lookup finds a unit by symbol ("km", "MiB"), alias or name ("kilometers").
+func Lookup(s string) (Unit, bool) {
+	s = strings.TrimSpace(s)
+	if i, ok := bySymbol[s]; ok {
+		return all[i], true
+	}
+	if i, ok := byName[strings.ToLower(s)]; ok {
+		return all[i], true
+	}
+	return Unit{}, false
+}
+
+// Units lists all known units of a dimension.
+func Units(dim Dimension) []Unit {
+	var out []Unit
+	for _, u := range all {
+		if u.Dim == dim {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// Convert converts v from one unit to another. Results carry float64 rounding
+// noise (100 °C → 211.99999999999994 °F); round them for display, e.g. with
+// Quantity.Format.
+func Convert(v float64, from, to string) (float64, error) {
+	f, ok := Lookup(from)
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+	t, ok := Lookup(to)
+	if !ok {
+		return 0, ErrUnknownUnit
+	}
+	return convert(v, f, t)
+}
+
+func convert(v float64, from, to Unit) (float64, error) {
+	if from.Dim != to.Dim {
+		return 0, ErrIncompatible
+	}
+	base := (v + from.Offset) * from.Factor
+	return base/to.Factor - to.Offset, nil
+}
+
+// Quantity is a value with a unit.
+type Quantity struct {
+	Value float64
+	Unit  Unit
+}
+
+// Parse reads a quantity such as "12.5 km", "5ft", "-3 °C" or "1.5GiB".
+func Parse(s string) (Quantity, error) {
+	s = strings.TrimSpace(s)
+	i := 0
+	for i < len(s) && (s[i] == '-' || s[i] == '+' || s[i] == '.' || s[i] == 'e' && i > 0 && isDigit(s[i-1]) || isDigit(s[i])) {
+		i++
+	}
+	if i == 0 {
+		return Quantity{}, ErrSyntax
+	}
+	v, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil {
+		return Quantity{}, ErrSyntax
+	}
+	sym := strings.TrimLeftFunc(s[i:], unicode.IsSpace)
+	if sym == "" {
+		return Quantity{}, ErrUnknownUnit
+	}
+	u, ok := Lookup(sym)
+	if !ok {
+		return Quantity{}, ErrUnknownUnit
+	}
+	return Quantity{Value: v, Unit: u}, nil
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// To converts q to another unit of the same dimension.
+func (q Quantity) To(unit string) (Quantity, error) {
+	u, ok := Lookup(unit)
+	if !ok {
+		return Quantity{}, ErrUnknownUnit
+	}
+	v, err := convert(q.Value, q.Unit, u)
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Value: v, Unit: u}, nil
+}
+
+// Format renders q with at most precision fractional digits, trailing zeros
+// removed, e.g. "12.5 km" or "21 °C". A negative precision keeps every digit.
+func (q Quantity) Format(precision int) string {
+	s := strconv.FormatFloat(q.Value, 'f', precision, 64)
+	if precision > 0 {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s + " " + q.Unit.Symbol
+}
+
+func (q Quantity) String() string { return q.Format(-1) }
+
+// FormatBytes renders a byte count with the largest fitting unit, using
+// powers of 1024 (KiB, MiB, …) when binary is set and powers of 1000
+// (kB, MB, …) otherwise. Values keep one fractional digit: "1.5 GiB".
+func FormatBytes(n int64, binary bool) string {
+	syms := []string{"B", "kB", "MB", "GB", "TB", "PB"}
+	step := 1000.0
+	if binary {
+		syms = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+		step = 1024
+	}
+	v := float64(n)
+	i := 0
+	for i < len(syms)-1 && (v >= step || v <= -step) {
+		v /= step
+		i++
+	}
+	u, _ := Lookup(syms[i])
+	if i == 0 {
+		return Quantity{Value: v, Unit: u}.Format(0)
+	}
+	return Quantity{Value: v, Unit: u}.Format(1)
+}
@@ -0,0 +1,110 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		v        float64
+		from, to string
+		want     float64
+		err      error
+	}{
+		{1, "km", "m", 1000, nil},
+		{1, "mi", "km", 1.609344, nil},
+		{12, "inches", "ft", 1, nil},
+		{100, "°C", "°F", 212, nil},
+		{32, "F", "C", 0, nil},
+		{0, "K", "degC", -273.15, nil},
+		{1, "lbs", "kg", 0.45359237, nil},
+		{36, "km/h", "m/s", 10, nil},
+		{1, "MiB", "KiB", 1024, nil},
+		{1, "MB", "Mb", 8, nil},
+		{100, "Mbps", "MB/s", 12.5, nil},
+		{1, "km", "kg", 0, ErrIncompatible},
+		{1, "parsec", "m", 0, ErrUnknownUnit},
+		{1, "m", "mB", 0, ErrUnknownUnit},
+	}
+	for _, tt := range tests {
+		got, err := Convert(tt.v, tt.from, tt.to)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Convert(%v, %s, %s) error = %v, want %v", tt.v, tt.from, tt.to, err, tt.err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Convert(%v, %s, %s) = %v, want %v", tt.v, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in     string
+		value  float64
+		symbol string
+		err    error
+	}{
+		{"12.5 km", 12.5, "km", nil},
+		{"5ft", 5, "ft", nil},
+		{"-3 °C", -3, "°C", nil},
+		{"1.5GiB", 1.5, "GiB", nil},
+		{"2e3 m", 2000, "m", nil},
+		{" 10 kilometers ", 10, "km", nil},
+		{"3 Mb", 3, "Mb", nil},
+		{"", 0, "", ErrSyntax},
+		{"km", 0, "", ErrSyntax},
+		{"1.2.3 m", 0, "", ErrSyntax},
+		{"12", 0, "", ErrUnknownUnit},
+		{"12 furlongs", 0, "", ErrUnknownUnit},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q) error = %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if err == nil && (q.Value != tt.value || q.Unit.Symbol != tt.symbol) {
+			t.Errorf("Parse(%q) = %v %s, want %v %s", tt.in, q.Value, q.Unit.Symbol, tt.value, tt.symbol)
+		}
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	for _, s := range []string{"12.5 km", "21 °C", "-0.25 lb", "1.5 GiB", "300 Mbps"} {
+		q, err := Parse(s)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", s, err)
+		}
+		if got := q.Format(3); got != s {
+			t.Errorf("Format(Parse(%q)) = %q", s, got)
+		}
+	}
+	q, _ := Parse("100 °C")
+	f, _ := q.To("°F")
+	if got := f.Format(2); got != "212 °F" {
+		t.Errorf("100 °C in °F = %q, want 212 °F", got)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n      int64
+		binary bool
+		want   string
+	}{
+		{0, false, "0 B"},
+		{999, false, "999 B"},
+		{1500, false, "1.5 kB"},
+		{1536, true, "1.5 KiB"},
+		{3 << 30, true, "3 GiB"},
+		{-2500000, false, "-2.5 MB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.n, tt.binary); got != tt.want {
+			t.Errorf("FormatBytes(%d, %v) = %q, want %q", tt.n, tt.binary, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package sdk
+
+import (
+	"context"
+	"io"
+	"time"
+
+	v1 "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// Context is the run of a v2 handler. It wraps the v1 Context of the run and
+// is the run's context.Context (see deadline.go).
+type Context struct {
+	c    *v1.Context
+	done doneState
+}
+
+// V1 returns the v1 Context of the run, for calls v2 does not cover.
+func (c *Context) V1() *v1.Context { return c.c }
+
+// --- Metadata ---
+
+func (c *Context) NodeID() string  { return c.c.NodeID() }
+func (c *Context) RunID() string   { return c.c.RunID() }
+func (c *Context) AppID() string   { return c.c.AppID() }
+func (c *Context) BoardID() string { return c.c.BoardID() }
+func (c *Context) UserID() string  { return c.c.UserID() }
+
+// Attempt returns which attempt of a retried run this is, starting at 1.
+func (c *Context) Attempt() int { return c.c.Attempt() }
+
+// Timeout returns the run's effective timeout, or 0 when it is unbounded.
+func (c *Context) Timeout() time.Duration { return c.c.Timeout() }
+
+// --- Inputs ---
+
+// Input returns the raw JSON of an input and whether it was provided.
+func (c *Context) Input(name string) (string, bool) { return c.c.GetInput(name) }
+
+// String reads a string input. Like Int, Float and Bool it coerces
+// compatible JSON and fails with an invalid_input error naming the pin when
+// the input is missing or of another type.
+func (c *Context) String(name string) (string, error) { return c.c.InputString(name) }
+
+func (c *Context) Int(name string) (int64, error)     { return c.c.InputI64(name) }
+func (c *Context) Float(name string) (float64, error) { return c.c.InputF64(name) }
+func (c *Context) Bool(name string) (bool, error)     { return c.c.InputBool(name) }
+
+// --- Outputs ---
+
+// Output sets an output pin. Strings, bools, integers, floats and []string
+// are JSON-encoded; RawJSON is written as is.
+func (c *Context) Output(name string, value any) { c.c.Out(name, value) }
+
+// Activate activates an exec output. Without one, a successful run activates
+// the node's default exec output.
+func (c *Context) Activate(pin string) { c.c.ActivateExec(pin) }
+
+// --- Logging and streaming ---
+
+func (c *Context) Debug(msg string) { c.c.Debug(msg) }
+func (c *Context) Info(msg string)  { c.c.Info(msg) }
+func (c *Context) Warn(msg string)  { c.c.Warn(msg) }
+func (c *Context) Error(msg string) { c.c.Error(msg) }
+
+// Stream returns a writer that streams text to the UI. Writes are dropped
+// when streaming is not enabled for the run.
+func (c *Context) Stream() io.Writer { return textStream{c.c} }
+
+// Progress streams a progress update; fraction is in [0, 1].
+func (c *Context) Progress(fraction float32, msg string) { c.c.StreamProgress(fraction, msg) }
+
+type textStream struct{ c *v1.Context }
+
+func (s textStream) Write(p []byte) (int, error) {
+	s.c.StreamText(string(p))
+	return len(p), nil
+}
+
+// --- Services ---
+
+// Storage returns the flow storage of the run.
+func (c *Context) Storage() Storage { return Storage{c.c.Host()} }
+
+// HTTP returns the HTTP client of the run. It honors the node's HTTP
+// allowlist and cookie jar like the v1 calls.
+func (c *Context) HTTP() HTTP { return HTTP{c.c.Host()} }
+
+// Models returns the model calls of the run.
+func (c *Context) Models() Models { return Models{c.c.Host()} }
+
+// Vectors returns a vector collection of the app.
+func (c *Context) Vectors(collection string) Collection {
+	return Collection{h: c.c.Host(), name: collection}
+}
+
+// KV returns the durable key-value store in scope.
+func (c *Context) KV(scope KVScope) KV { return KV{h: c.c.Host(), scope: scope} }
+
+// OAuth returns the user's connection to provider.
+func (c *Context) OAuth(provider string) OAuth {
+	return OAuth{c: c.c, provider: provider}
+}
+
+// --- Auth and quotas ---
+
+// OAuth is the user's connection to an OAuth provider.
+type OAuth struct {
+	c        *v1.Context
+	provider string
+}
+
+// Connected reports whether the user has authorized the provider.
+func (o OAuth) Connected() bool { return o.c.HasOAuthToken(o.provider) }
+
+// Token returns the access token, failing with ErrNotFound when the user
+// has not authorized the provider.
+func (o OAuth) Token(ctx context.Context) (token string, err error) {
+	h := o.c.Host()
+	err = call(ctx, h, func() (err error) {
+		token, err = h.GetOAuthToken(o.provider)
+		return err
+	})
+	return token, err
+}
+
+// Refresh forces a token refresh, e.g. after a 401, failing with
+// ErrPermissionDenied when the user must authorize again.
+func (o OAuth) Refresh(ctx context.Context) (token string, err error) {
+	h := o.c.Host()
+	err = call(ctx, h, func() (err error) {
+		token, err = h.RefreshOAuthToken(o.provider)
+		return err
+	})
+	return token, err
+}
+
+// PackConfig reads the settings shared by the nodes of the pack into dst,
+// failing with ErrNotFound while an admin has not configured the pack.
+func (c *Context) PackConfig(dst ConfigDecoder) error { return c.c.GetPackConfig(dst) }
+
+// Config reads the node instance's configuration into dst with credential
+// references resolved, failing with ErrNotFound while it is not configured.
+func (c *Context) Config(dst ConfigDecoder) error { return c.c.GetConfig(dst) }
+
+// Quota reports the remaining budget of a platform or named quota.
+func (c *Context) Quota(kind string) (Quota, error) { return c.c.Host().GetQuota(kind) }
+
+// ConsumeQuota takes amount from a named quota, failing with
+// ErrQuotaExceeded when it does not fit.
+func (c *Context) ConsumeQuota(name string, amount int64) error {
+	return c.c.Host().QuotaConsume(name, amount)
+}
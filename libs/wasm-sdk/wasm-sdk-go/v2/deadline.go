@@ -0,0 +1,176 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	v1 "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// A run's *Context is a context.Context: its deadline is the end of the
+// host's execution budget and it ends with context.Canceled when the run is
+// cancelled. Host calls that wait on storage, the network or a model take a
+// context.Context, fail with its Err once it ended and pass its deadline to
+// the host, which aborts calls still running at it.
+//
+// A module has no goroutines to end a context in the background, so a
+// context notices its end when Err or Done is called: Done returns a closed
+// channel from then on. Poll between steps:
+//
+//	if err := ctx.Err(); err != nil {
+//		return err
+//	}
+//
+// context.WithTimeout and context.WithCancel need timers and goroutines that
+// TinyGo builds with -scheduler=none lack; WithTimeout, WithDeadline and
+// WithCancel of this package derive contexts that work without them.
+// context.WithValue works on either.
+
+var _ context.Context = (*Context)(nil)
+
+// Deadline returns the end of the run's execution budget, if it has one.
+func (c *Context) Deadline() (time.Time, bool) {
+	ms, ok := c.c.Deadline()
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
+
+// Done returns a channel that is closed once the run was cancelled or its
+// budget ran out, as observed by Done or Err.
+func (c *Context) Done() <-chan struct{} {
+	c.Err()
+	return c.done.channel()
+}
+
+// Err returns context.Canceled when the run was cancelled and
+// context.DeadlineExceeded when its execution budget ran out.
+func (c *Context) Err() error {
+	if c.done.err != nil {
+		return c.done.err
+	}
+	if c.c.Cancelled() {
+		return c.done.finish(context.Canceled)
+	}
+	if ms, ok := c.c.Deadline(); ok && v1.TimeNow() >= ms {
+		return c.done.finish(context.DeadlineExceeded)
+	}
+	return nil
+}
+
+// Value returns nil; use context.WithValue to attach values.
+func (c *Context) Value(key any) any { return nil }
+
+// doneState ends a context once and closes its Done channel, which is only
+// made when asked for.
+type doneState struct {
+	ch  chan struct{}
+	err error
+}
+
+func (s *doneState) finish(err error) error {
+	if s.err == nil {
+		s.err = err
+		if s.ch != nil {
+			close(s.ch)
+		}
+	}
+	return s.err
+}
+
+func (s *doneState) channel() <-chan struct{} {
+	if s.ch == nil {
+		s.ch = make(chan struct{})
+		if s.err != nil {
+			close(s.ch)
+		}
+	}
+	return s.ch
+}
+
+// derived is a context made by WithCancel or WithDeadline.
+type derived struct {
+	parent   context.Context
+	deadline time.Time // zero: the parent's
+	done     doneState
+}
+
+func (d *derived) Deadline() (time.Time, bool) {
+	parent, ok := d.parent.Deadline()
+	if d.deadline.IsZero() || ok && parent.Before(d.deadline) {
+		return parent, ok
+	}
+	return d.deadline, true
+}
+
+func (d *derived) Done() <-chan struct{} {
+	d.Err()
+	return d.done.channel()
+}
+
+func (d *derived) Err() error {
+	if d.done.err != nil {
+		return d.done.err
+	}
+	if err := d.parent.Err(); err != nil {
+		return d.done.finish(err)
+	}
+	if !d.deadline.IsZero() && v1.TimeNow() >= d.deadline.UnixMilli() {
+		return d.done.finish(context.DeadlineExceeded)
+	}
+	return nil
+}
+
+func (d *derived) Value(key any) any { return d.parent.Value(key) }
+
+// WithCancel returns a copy of parent that also ends when cancel is called.
+func WithCancel(parent context.Context) (context.Context, context.CancelFunc) {
+	d := &derived{parent: parent}
+	return d, func() { d.done.finish(context.Canceled) }
+}
+
+// WithDeadline returns a copy of parent that also ends at deadline, on the
+// host clock.
+func WithDeadline(parent context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	d := &derived{parent: parent, deadline: deadline}
+	return d, func() { d.done.finish(context.Canceled) }
+}
+
+// WithTimeout returns WithDeadline(parent, now+timeout).
+func WithTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return WithDeadline(parent, time.UnixMilli(v1.TimeNow()).Add(timeout))
+}
+
+// call runs the host calls of fn under ctx: it fails with ctx.Err() without
+// calling the host once ctx ended, bounds the calls by ctx's deadline and
+// reports a call that failed because ctx ended meanwhile as ctx.Err(), so
+// errors.Is(err, context.DeadlineExceeded) holds.
+func call(ctx context.Context, h v1.HostCalls, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		h.SetCallDeadline(deadline.UnixMilli())
+		defer h.SetCallDeadline(0)
+	}
+	err := fn()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+	}
+	return err
+}
+
+// runError gives context errors returned by a handler their error codes.
+func runError(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return v1.NewError(v1.ErrCodeDeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return v1.NewError(v1.ErrCodeCancelled, err.Error())
+	}
+	return err
+}
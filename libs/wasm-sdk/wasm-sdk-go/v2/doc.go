@@ -0,0 +1,54 @@
+// Package sdk is version 2 of the Go SDK for Flow-Like WASM nodes.
+//
+// It keeps the runtime, definitions and registry of version 1 and replaces
+// the run-time API with idiomatic Go: handlers return an error, every host
+// call returns (value, error) with a *HostError that says why it failed,
+// files are io.Reader and io.Writer values, and optional settings are
+// structs instead of positional booleans. The Context of a run is a
+// context.Context bound to the host's execution budget, and calls that wait
+// on the host take one, so timeouts and cancellation work as usual:
+//
+//	tctx, cancel := sdk.WithTimeout(ctx, 10*time.Second)
+//	defer cancel()
+//	resp, err := ctx.HTTP().Get(tctx, url)
+//
+//	import sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go/v2"
+//
+//	type Summarize struct{}
+//
+//	func (Summarize) Define() sdk.NodeDefinition { ... }
+//
+//	func (Summarize) Run(ctx *sdk.Context) error {
+//		text, err := ctx.String("text")
+//		if err != nil {
+//			return err
+//		}
+//		bit, _ := ctx.Input("chat_bit")
+//		resp, err := ctx.Models().Chat(ctx, bit, sdk.ChatRequest{
+//			Messages: []sdk.ChatMessage{{Role: "user", Content: "Summarize:\n" + text}},
+//		})
+//		if err != nil {
+//			return err
+//		}
+//		ctx.Output("summary", resp.Content)
+//		return nil
+//	}
+//
+//	var registry = sdk.NewRegistry(Summarize{})
+//
+// Version 1 stays fully functional, and both versions can be mixed in one
+// module: sdk.Node turns a v2 handler into a v1 NodeHandler, and
+// Context.V1 reaches the v1 Context for anything v2 does not cover yet.
+// `flowlike-gen migrate` lists the v1 calls of a module with their v2
+// replacements and rewrites the common patterns.
+//
+// The package is split across multiple files:
+//   - node.go:    Handler, Node, NewRegistry and the types shared with v1
+//   - context.go: Context, inputs, outputs, logging, streaming and auth
+//   - storage.go: Storage, flow storage as files, readers and writers
+//   - paths.go:   PathBuilder, scoped storage and cache paths
+//   - http.go:    HTTP, requests with maps and byte bodies
+//   - models.go:  Models and Collection, chat, embeddings and vector search
+//   - kv.go:      KV, the durable key-value store
+//   - deadline.go: Context as a context.Context, WithTimeout, WithCancel
+package sdk
@@ -0,0 +1,81 @@
+package sdk
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	v1 "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// HTTP sends requests from a run. Responses with error statuses are
+// returned, not reported as errors; check Response.OK.
+type HTTP struct{ h v1.HostCalls }
+
+// Request is an HTTP request. Method defaults to GET.
+type Request struct {
+	Method  string
+	URL     string
+	Header  map[string]string
+	Body    []byte
+	Options HTTPRequestOptions
+}
+
+var httpMethods = map[string]int{
+	"GET":     v1.HTTPGet,
+	"POST":    v1.HTTPPost,
+	"PUT":     v1.HTTPPut,
+	"DELETE":  v1.HTTPDelete,
+	"PATCH":   v1.HTTPPatch,
+	"HEAD":    v1.HTTPHead,
+	"OPTIONS": v1.HTTPOptions,
+}
+
+// Do sends req and waits for the response, at most until ctx ends.
+func (c HTTP) Do(ctx context.Context, req Request) (Response, error) {
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = "GET"
+	}
+	m, ok := httpMethods[method]
+	if !ok {
+		return Response{}, v1.NewError(v1.ErrCodeInvalidInput, "unsupported HTTP method "+req.Method)
+	}
+	var resp Response
+	err := call(ctx, c.h, func() (err error) {
+		resp, err = c.h.HTTPFetchWith(m, req.URL, headerJSON(req.Header), string(req.Body), req.Options)
+		return err
+	})
+	return resp, err
+}
+
+func (c HTTP) Get(ctx context.Context, url string) (Response, error) {
+	return c.Do(ctx, Request{URL: url})
+}
+
+func (c HTTP) Post(ctx context.Context, url, contentType string, body []byte) (Response, error) {
+	return c.Do(ctx, Request{Method: "POST", URL: url, Header: map[string]string{"Content-Type": contentType}, Body: body})
+}
+
+func headerJSON(header map[string]string) string {
+	if len(header) == 0 {
+		return "{}"
+	}
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(v1.JSONString(name))
+		b.WriteByte(':')
+		b.WriteString(v1.JSONString(header[name]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
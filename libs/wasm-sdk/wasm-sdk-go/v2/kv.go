@@ -0,0 +1,63 @@
+package sdk
+
+import (
+	"context"
+
+	v1 "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// KV is the durable key-value store in one scope. Unlike the cache, entries
+// are never evicted.
+type KV struct {
+	h     v1.HostCalls
+	scope KVScope
+}
+
+// Get reads key, failing with ErrNotFound when it does not exist.
+func (s KV) Get(ctx context.Context, key string) (entry KVEntry, err error) {
+	err = call(ctx, s.h, func() (err error) {
+		entry, err = s.h.KVGet(s.scope, key)
+		return err
+	})
+	return entry, err
+}
+
+// Put writes key and returns its new version.
+func (s KV) Put(ctx context.Context, key, value string) (version int64, err error) {
+	err = call(ctx, s.h, func() (err error) {
+		version, err = s.h.KVPut(s.scope, key, value)
+		return err
+	})
+	return version, err
+}
+
+// PutIf writes key only if its version is still version (KVAbsent: the key
+// must not exist), failing with ErrConflict otherwise.
+func (s KV) PutIf(ctx context.Context, key, value string, version int64) (next int64, err error) {
+	err = call(ctx, s.h, func() (err error) {
+		next, err = s.h.KVPutIf(s.scope, key, value, version)
+		return err
+	})
+	return next, err
+}
+
+func (s KV) Delete(ctx context.Context, key string) error {
+	return call(ctx, s.h, func() error { return s.h.KVDelete(s.scope, key) })
+}
+
+// ListOptions pages through List. The zero value returns the host's default
+// page size from the start.
+type ListOptions struct {
+	Limit  int
+	Cursor string
+}
+
+// List returns the entries whose keys start with prefix and the cursor of
+// the next page, "" after the last one.
+func (s KV) List(ctx context.Context, prefix string, opts ListOptions) (entries []KVEntry, cursor string, err error) {
+	err = call(ctx, s.h, func() (err error) {
+		entries, cursor, err = s.h.KVList(s.scope, prefix, opts.Limit, opts.Cursor)
+		return err
+	})
+	return entries, cursor, err
+}
@@ -0,0 +1,102 @@
+package sdk
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	v1 "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// Models makes model calls. bit is the raw Bit JSON of a model, e.g.
+// ModelBit.Bit from List or Default, or an input pin's value.
+type Models struct{ h v1.HostCalls }
+
+// Chat runs a chat completion. Calls are recorded when the run records
+// model calls (v1 Context.RecordModelCalls).
+func (m Models) Chat(ctx context.Context, bit string, req ChatRequest) (resp ChatResponse, err error) {
+	err = call(ctx, m.h, func() (err error) {
+		resp, err = m.h.ChatComplete(bit, req)
+		return err
+	})
+	return resp, err
+}
+
+// Embed returns one vector per text, each a raw JSON array of numbers in the
+// order of texts.
+func (m Models) Embed(ctx context.Context, bit string, texts []string) ([]string, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	quoted := make([]string, len(texts))
+	for i, t := range texts {
+		quoted[i] = v1.JSONString(t)
+	}
+	var out string
+	err := call(ctx, m.h, func() (err error) {
+		out, err = m.h.EmbedText(bit, "["+strings.Join(quoted, ",")+"]")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	vectors := v1.JSONArrayItems(out)
+	if len(vectors) != len(texts) {
+		return nil, &HostError{Op: "Embed", Code: v1.ErrCodeUpstream,
+			Message: "got " + strconv.Itoa(len(vectors)) + " vectors for " + strconv.Itoa(len(texts)) + " texts"}
+	}
+	return vectors, nil
+}
+
+// Rerank scores candidates by relevance to query, best first.
+func (m Models) Rerank(ctx context.Context, bit, query string, candidates []string) (scored []ScoredResult, err error) {
+	err = call(ctx, m.h, func() (err error) {
+		scored, err = m.h.Rerank(bit, query, candidates)
+		return err
+	})
+	return scored, err
+}
+
+// Entities runs named-entity recognition over text.
+func (m Models) Entities(ctx context.Context, bit, text string) (entities []Entity, err error) {
+	err = call(ctx, m.h, func() (err error) {
+		entities, err = m.h.DetectEntities(bit, text)
+		return err
+	})
+	return entities, err
+}
+
+// List returns the models with capability (a v1 Capability constant; ""
+// lists all).
+func (m Models) List(capability string) ([]ModelBit, error) {
+	return m.h.ListModelBits(capability)
+}
+
+// Default returns the model the deployment prefers for capability, failing
+// with ErrNotFound when there is none.
+func (m Models) Default(capability string) (ModelBit, error) {
+	return m.h.DefaultBit(capability)
+}
+
+// Collection is a vector collection of the app.
+type Collection struct {
+	h    v1.HostCalls
+	name string
+}
+
+// Upsert inserts or replaces records.
+func (c Collection) Upsert(ctx context.Context, records ...VectorRecord) error {
+	return call(ctx, c.h, func() error {
+		return c.h.VectorUpsert(c.name, records)
+	})
+}
+
+// Search returns the k records nearest to vector, a raw JSON array of
+// numbers as returned by Models.Embed.
+func (c Collection) Search(ctx context.Context, vector string, k int) (matches []VectorMatch, err error) {
+	err = call(ctx, c.h, func() (err error) {
+		matches, err = c.h.VectorSearch(c.name, vector, k)
+		return err
+	})
+	return matches, err
+}
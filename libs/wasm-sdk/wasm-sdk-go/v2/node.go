@@ -0,0 +1,112 @@
+package sdk
+
+import (
+	v1 "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// Handler is a v2 node: Run reports failure as an error. A *HostError or
+// *NodeError keeps its code in the run result, context.DeadlineExceeded and
+// context.Canceled become deadline_exceeded and cancelled, and other errors
+// fail the run as internal errors.
+type Handler interface {
+	Define() NodeDefinition
+	Run(ctx *Context) error
+}
+
+type handlerAdapter struct{ h Handler }
+
+func (a handlerAdapter) Define() NodeDefinition { return a.h.Define() }
+
+func (a handlerAdapter) Run(ctx *v1.Context) v1.ExecutionResult {
+	return ctx.Result(runError(a.h.Run(&Context{c: ctx})))
+}
+
+// Node adapts h to a v1 NodeHandler, e.g. to register it next to v1 nodes.
+func Node(h Handler) v1.NodeHandler { return handlerAdapter{h} }
+
+type funcHandler struct {
+	def NodeDefinition
+	run func(ctx *Context) error
+}
+
+func (f funcHandler) Define() NodeDefinition { return f.def }
+func (f funcHandler) Run(ctx *Context) error { return f.run(ctx) }
+
+// NewNode builds a Handler from a definition and a run function.
+func NewNode(def NodeDefinition, run func(ctx *Context) error) Handler {
+	return funcHandler{def: def, run: run}
+}
+
+// Registry is the v1 registry; v2 handlers are registered through Node.
+type Registry = v1.Registry
+
+// NewRegistry creates a registry serving handlers.
+func NewRegistry(handlers ...Handler) *Registry {
+	r := v1.NewRegistry()
+	for _, h := range handlers {
+		r.Register(Node(h))
+	}
+	return r
+}
+
+// Definitions and data types are shared with v1.
+type (
+	NodeDefinition = v1.NodeDefinition
+	PinDefinition  = v1.PinDefinition
+)
+
+func NewNodeDefinition() NodeDefinition { return v1.NewNodeDefinition() }
+
+func InputPin(name, friendlyName, description, dataType string) PinDefinition {
+	return v1.InputPin(name, friendlyName, description, dataType)
+}
+
+func OutputPin(name, friendlyName, description, dataType string) PinDefinition {
+	return v1.OutputPin(name, friendlyName, description, dataType)
+}
+
+type (
+	RawJSON            = v1.RawJSON
+	RawValue           = v1.RawValue
+	ConfigDecoder      = v1.ConfigDecoder
+	ChatRequest        = v1.ChatRequest
+	ChatResponse       = v1.ChatResponse
+	ChatMessage        = v1.ChatMessage
+	ModelBit           = v1.ModelBit
+	Entity             = v1.Entity
+	ScoredResult       = v1.ScoredResult
+	VectorRecord       = v1.VectorRecord
+	VectorMatch        = v1.VectorMatch
+	KVScope            = v1.KVScope
+	KVEntry            = v1.KVEntry
+	Quota              = v1.Quota
+	RetentionPolicy    = v1.RetentionPolicy
+	HTTPRequestOptions = v1.HTTPRequestOptions
+	Response           = v1.HTTPResponse
+)
+
+const (
+	KVApp   = v1.KVApp
+	KVBoard = v1.KVBoard
+	KVNode  = v1.KVNode
+	KVUser  = v1.KVUser
+
+	KVAbsent = v1.KVAbsent
+)
+
+// Errors are shared with v1, so errors.Is works across both versions.
+type (
+	HostError = v1.HostError
+	NodeError = v1.NodeError
+)
+
+var (
+	ErrPermissionDenied = v1.ErrPermissionDenied
+	ErrNotFound         = v1.ErrNotFound
+	ErrQuotaExceeded    = v1.ErrQuotaExceeded
+	ErrConflict         = v1.ErrConflict
+	ErrIntegrity        = v1.ErrIntegrity
+)
+
+// NewError creates a NodeError with one of the v1 ErrCode constants.
+func NewError(code, message string) *NodeError { return v1.NewError(code, message) }
@@ -0,0 +1,84 @@
+package sdk
+
+import (
+	v1 "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// PathBuilder composes a storage path from a scope and names instead of
+// concatenating a directory with strings:
+//
+//	path, err := ctx.Storage().Node().Join("exports", fileName).Path()
+//	dir, err := ctx.Cache().User().Path()
+//
+// Builders are values; each method returns a new one. Names are validated
+// by Path (see v1 JoinStoragePath), so an input like "../../secrets" fails
+// with an invalid_input error instead of leaving the scope.
+type PathBuilder struct {
+	h        v1.HostCalls
+	cache    bool
+	scope    []DirOption
+	segments []string
+}
+
+// Node narrows the scope to this node instance.
+func (s Storage) Node() PathBuilder { return s.root().Node() }
+
+// User narrows the scope to the user of the run.
+func (s Storage) User() PathBuilder { return s.root().User() }
+
+// Join starts a path in the board's storage directory.
+func (s Storage) Join(segments ...string) PathBuilder { return s.root().Join(segments...) }
+
+func (s Storage) root() PathBuilder { return PathBuilder{h: s.h} }
+
+// Cache returns a builder for the cache directory, which the platform may
+// clear between runs.
+func (c *Context) Cache() PathBuilder { return PathBuilder{h: c.c.Host(), cache: true} }
+
+// Node narrows the scope to this node instance.
+func (p PathBuilder) Node() PathBuilder {
+	p.scope = append(p.scope[:len(p.scope):len(p.scope)], WithNodeScope())
+	return p
+}
+
+// User narrows the scope to the user of the run.
+func (p PathBuilder) User() PathBuilder {
+	p.scope = append(p.scope[:len(p.scope):len(p.scope)], WithUserScope())
+	return p
+}
+
+// Join appends names; a segment may hold several "/"-separated names.
+func (p PathBuilder) Join(segments ...string) PathBuilder {
+	// The full slice expression copies on append, so builders derived from
+	// a shared prefix stay independent.
+	p.segments = append(p.segments[:len(p.segments):len(p.segments)], segments...)
+	return p
+}
+
+// Path resolves the scope's directory with the host and returns the path
+// the storage calls take.
+func (p PathBuilder) Path() (string, error) {
+	var (
+		dir string
+		err error
+	)
+	if p.cache {
+		dir, err = p.h.CacheDirPath(p.scope...)
+	} else {
+		dir, err = p.h.StorageDir(p.scope...)
+	}
+	if err != nil {
+		return "", err
+	}
+	return v1.JoinStoragePath(dir, p.segments...)
+}
+
+// FlowPath returns Path as FlowPath JSON, the form taken by listing and
+// opening files through ctx.V1().Host().
+func (p PathBuilder) FlowPath() (string, error) {
+	path, err := p.Path()
+	if err != nil {
+		return "", err
+	}
+	return v1.FlowPath(path), nil
+}
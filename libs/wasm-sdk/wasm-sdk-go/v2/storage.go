@@ -0,0 +1,223 @@
+package sdk
+
+import (
+	"context"
+	"io"
+
+	v1 "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+// Storage is the flow storage of a run. Paths are those of the v1 storage
+// calls: a directory from Dir joined with file names.
+type Storage struct{ h v1.HostCalls }
+
+// DirOption scopes a storage or cache directory.
+type DirOption = v1.DirOption
+
+// WithNodeScope narrows a directory to this node instance.
+func WithNodeScope() DirOption { return v1.WithNodeScope() }
+
+// WithUserScope narrows a directory to the user of the run.
+func WithUserScope() DirOption { return v1.WithUserScope() }
+
+// Dir returns the board's storage directory, narrowed by WithNodeScope and
+// WithUserScope:
+//
+//	dir, err := ctx.Storage().Dir(sdk.WithNodeScope(), sdk.WithUserScope())
+func (s Storage) Dir(opts ...DirOption) (string, error) { return s.h.StorageDir(opts...) }
+
+// CacheDir returns the cache directory, narrowed like Dir. The platform may
+// clear it between runs.
+func (s Storage) CacheDir(opts ...DirOption) (string, error) { return s.h.CacheDirPath(opts...) }
+
+// UploadDir returns the directory of the files uploaded to the board.
+func (s Storage) UploadDir() (string, error) { return s.h.UploadDir() }
+
+// ReadFile returns the content of the file at path.
+func (s Storage) ReadFile(ctx context.Context, path string) ([]byte, error) {
+	var data string
+	err := call(ctx, s.h, func() (err error) {
+		data, err = s.h.StorageRead(path)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []byte(data), nil
+}
+
+// WriteOptions tunes WriteFile. The zero value keeps the file until it is
+// deleted.
+type WriteOptions struct {
+	// Retention lets the platform expire or hold the file.
+	Retention RetentionPolicy
+}
+
+// WriteFile replaces the file at path with data.
+func (s Storage) WriteFile(ctx context.Context, path string, data []byte, opts WriteOptions) error {
+	return call(ctx, s.h, func() error {
+		return s.h.StorageWriteWithPolicy(path, string(data), opts.Retention)
+	})
+}
+
+// AppendFile appends data to the file at path, creating it if needed.
+func (s Storage) AppendFile(ctx context.Context, path string, data []byte) error {
+	return call(ctx, s.h, func() error { return s.h.StorageAppend(path, string(data)) })
+}
+
+// ReadRange returns up to length bytes of the file at path starting at
+// offset; fewer near the end of the file and none past it.
+func (s Storage) ReadRange(ctx context.Context, path string, offset, length int64) ([]byte, error) {
+	var data string
+	err := call(ctx, s.h, func() (err error) {
+		data, err = s.h.ReadRange(path, offset, length)
+		return err
+	})
+	if err != nil || data == "" {
+		return nil, err
+	}
+	return []byte(data), nil
+}
+
+// Open streams the file at path. ctx bounds opening the file, not the
+// reads. Close the reader to release the handle.
+func (s Storage) Open(ctx context.Context, path string) (io.ReadCloser, error) {
+	var r *v1.StorageReader
+	err := call(ctx, s.h, func() (err error) {
+		r, err = s.h.OpenStorageReader(path)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Create streams a new file to path. ctx bounds creating the file, not the
+// writes. The file appears once Close succeeds.
+func (s Storage) Create(ctx context.Context, path string) (io.WriteCloser, error) {
+	var w *v1.StorageWriter
+	err := call(ctx, s.h, func() (err error) {
+		w, err = s.h.CreateStorageWriter(path)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// StorageChange is a change to a watched file; see Watch.
+type (
+	StorageChange     = v1.StorageChange
+	StorageChangeKind = v1.StorageChangeKind
+)
+
+const (
+	StorageCreated = v1.StorageCreated
+	StorageUpdated = v1.StorageUpdated
+	StorageDeleted = v1.StorageDeleted
+)
+
+// StorageChangesPin declares the input a watching node is triggered with.
+func StorageChangesPin() PinDefinition { return v1.StorageChangesPin() }
+
+// Watch subscribes the node to changes of kinds (all when none are given)
+// under prefix and returns the subscription ID. The host then triggers the
+// node with the affected files, which the run reads with
+// Context.StorageChanges:
+//
+//	prefix, err := ctx.Storage().Join("imports").Path()
+//	if err != nil {
+//		return err
+//	}
+//	_, err = ctx.Storage().Watch(prefix, sdk.StorageCreated)
+func (s Storage) Watch(prefix string, kinds ...StorageChangeKind) (string, error) {
+	return s.h.WatchStorage(prefix, kinds...)
+}
+
+// Unwatch ends a subscription made with Watch.
+func (s Storage) Unwatch(id string) error { return s.h.UnwatchStorage(id) }
+
+// StorageChanges returns the changes a storage watch triggered the run
+// with; ok is false for runs a watch did not trigger.
+func (c *Context) StorageChanges() ([]StorageChange, bool) { return c.c.StorageChanges() }
+
+// StorageObject describes a file: size, ETag and modification time.
+type StorageObject = v1.StorageObject
+
+// Stat returns the metadata of the file at path, failing with ErrNotFound
+// when it does not exist.
+func (s Storage) Stat(ctx context.Context, path string) (obj StorageObject, err error) {
+	err = call(ctx, s.h, func() (err error) {
+		obj, err = s.h.StorageStat(path)
+		return err
+	})
+	return obj, err
+}
+
+// List returns the files under dir with their metadata.
+func (s Storage) List(ctx context.Context, dir string) (objects []StorageObject, err error) {
+	err = call(ctx, s.h, func() (err error) {
+		objects, err = s.h.StorageListObjects(dir)
+		return err
+	})
+	return objects, err
+}
+
+// WriteIf replaces the file at path only if its ETag is still etag ("": only
+// if it does not exist) and returns the new ETag. It fails with ErrConflict
+// when another run wrote the file since etag was read.
+func (s Storage) WriteIf(ctx context.Context, path string, data []byte, etag string) (next string, err error) {
+	err = call(ctx, s.h, func() (err error) {
+		next, err = s.h.WriteIf(path, string(data), etag)
+		return err
+	})
+	return next, err
+}
+
+// WriteFileVerified writes data to path and returns the SHA-256 (lowercase
+// hex) the host computed over the stored content, failing with ErrIntegrity
+// when it does not match what was sent.
+func (s Storage) WriteFileVerified(ctx context.Context, path string, data []byte) (hash string, err error) {
+	err = call(ctx, s.h, func() (err error) {
+		hash, err = s.h.StorageWriteVerified(path, string(data))
+		return err
+	})
+	return hash, err
+}
+
+// Hash returns the SHA-256 (lowercase hex) of the file at path, e.g. as a
+// dedupe key.
+func (s Storage) Hash(ctx context.Context, path string) (hash string, err error) {
+	err = call(ctx, s.h, func() (err error) {
+		hash, err = s.h.StorageHash(path)
+		return err
+	})
+	return hash, err
+}
+
+// VerifyIntegrity fails with ErrIntegrity when the file at path no longer
+// hashes to expectedHash.
+func (s Storage) VerifyIntegrity(ctx context.Context, path, expectedHash string) error {
+	return call(ctx, s.h, func() error { return s.h.VerifyIntegrity(path, expectedHash) })
+}
+
+// SQL over storage files; see Query.
+type (
+	SQLQuery  = v1.SQLQuery
+	SQLTable  = v1.SQLTable
+	SQLColumn = v1.SQLColumn
+	SQLRows   = v1.SQLRows
+)
+
+// Query runs a read-only SQL query over structured files (CSV, Parquet,
+// JSON) on the host and returns the rows in batches, so aggregating a large
+// dataset only moves the result into the module. Close the rows when done.
+func (s Storage) Query(ctx context.Context, q SQLQuery) (rows *SQLRows, err error) {
+	err = call(ctx, s.h, func() (err error) {
+		rows, err = s.h.Query(q)
+		return err
+	})
+	return rows, err
+}
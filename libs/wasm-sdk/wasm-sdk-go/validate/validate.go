@@ -0,0 +1,317 @@
+// Package validate checks and normalizes common data formats — email
+// addresses, E.164 phone numbers, IBANs, URLs and UUIDs — so data-quality
+// nodes across packs agree on what "valid" means.
+//
+// Each normalizer returns the canonical form of its input or an error that
+// wraps one of the Err* sentinels; the Is* helpers only report validity.
+package validate
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrEmail = errors.New("validate: invalid email address")
+	ErrPhone = errors.New("validate: invalid phone number")
+	ErrIBAN  = errors.New("validate: invalid IBAN")
+	ErrURL   = errors.New("validate: invalid URL")
+	ErrUUID  = errors.New("validate: invalid UUID")
+)
+
+// Error describes why a value was rejected. It unwraps to the matching Err*
+// sentinel.
+type Error struct {
+	Kind   error
+	Reason string
+}
+
+func (e *Error) Error() string { return e.Kind.Error() + ": " + e.Reason }
+func (e *Error) Unwrap() error { return e.Kind }
+
+func invalid(kind error, reason string) error { return &Error{Kind: kind, Reason: reason} }
+
+// --- Email ---
+
+// Email trims the address and lower-cases its domain. The local part keeps
+// its case, as mail servers may treat it case-sensitively.
+func Email(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	at := strings.LastIndexByte(s, '@')
+	if at <= 0 || at == len(s)-1 {
+		return "", invalid(ErrEmail, "missing local part or domain")
+	}
+	local, domain := s[:at], strings.ToLower(s[at+1:])
+	if len(local) > 64 || len(s) > 254 {
+		return "", invalid(ErrEmail, "too long")
+	}
+	if local[0] == '.' || local[len(local)-1] == '.' || strings.Contains(local, "..") {
+		return "", invalid(ErrEmail, "misplaced dot in local part")
+	}
+	for i := 0; i < len(local); i++ {
+		c := local[i]
+		if !isAlnum(c) && !strings.ContainsRune(".!#$%&'*+/=?^_`{|}~-", rune(c)) && c < 0x80 {
+			return "", invalid(ErrEmail, "invalid character in local part")
+		}
+	}
+	if err := checkDomain(domain); err != nil {
+		return "", invalid(ErrEmail, err.Error())
+	}
+	return local + "@" + domain, nil
+}
+
+// IsEmail reports whether s is a plausible email address.
+func IsEmail(s string) bool {
+	_, err := Email(s)
+	return err == nil
+}
+
+func checkDomain(domain string) error {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return errors.New("domain needs a dot")
+	}
+	for _, l := range labels {
+		if l == "" || len(l) > 63 || l[0] == '-' || l[len(l)-1] == '-' {
+			return errors.New("bad domain label")
+		}
+		for i := 0; i < len(l); i++ {
+			if c := l[i]; !isAlnum(c) && c != '-' && c < 0x80 {
+				return errors.New("invalid character in domain")
+			}
+		}
+	}
+	return nil
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// --- Phone ---
+
+// CallingCodes maps ISO 3166-1 alpha-2 country codes to international
+// calling codes, used to complete national numbers. Extend it as needed.
+var CallingCodes = map[string]string{
+	"US": "1", "CA": "1", "GB": "44", "DE": "49", "FR": "33", "ES": "34",
+	"IT": "39", "NL": "31", "BE": "32", "AT": "43", "CH": "41", "SE": "46",
+	"NO": "47", "DK": "45", "FI": "358", "PL": "48", "CZ": "420", "PT": "351",
+	"IE": "353", "AU": "61", "NZ": "64", "JP": "81", "CN": "86", "IN": "91",
+	"BR": "55", "MX": "52", "ZA": "27", "SG": "65", "KR": "82", "TR": "90",
+}
+
+// Phone normalizes a phone number to E.164 ("+4915112345678"). Spaces,
+// dashes, dots and parentheses are ignored; "00" is read as an international
+// prefix. Numbers without one are completed with the calling code of
+// defaultCountry (ISO alpha-2), dropping a national trunk "0". It checks the
+// shape of the number, not whether it is assigned.
+func Phone(s, defaultCountry string) (string, error) {
+	var digits strings.Builder
+	international := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isDigit(c):
+			digits.WriteByte(c)
+		case c == '+' && digits.Len() == 0 && !international:
+			international = true
+		case c == ' ' || c == '-' || c == '.' || c == '(' || c == ')' || c == '/':
+		default:
+			return "", invalid(ErrPhone, "unexpected character "+string(c))
+		}
+	}
+	num := digits.String()
+	if !international && strings.HasPrefix(num, "00") {
+		international, num = true, num[2:]
+	}
+	if !international {
+		code, ok := CallingCodes[strings.ToUpper(defaultCountry)]
+		if !ok {
+			return "", invalid(ErrPhone, "no country code and unknown default country")
+		}
+		if code != "1" {
+			num = strings.TrimPrefix(num, "0")
+		}
+		num = code + num
+	}
+	if len(num) < 8 || len(num) > 15 || num[0] == '0' {
+		return "", invalid(ErrPhone, "not a valid E.164 length")
+	}
+	return "+" + num, nil
+}
+
+// IsE164 reports whether s is already in E.164 form.
+func IsE164(s string) bool {
+	if len(s) < 9 || len(s) > 16 || s[0] != '+' || s[1] == '0' {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// --- IBAN ---
+
+// ibanLengths holds the IBAN length per country (SWIFT IBAN registry).
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16,
+	"BG": 22, "BH": 22, "BR": 29, "CH": 21, "CR": 22, "CY": 28, "CZ": 24,
+	"DE": 22, "DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18,
+	"FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27,
+	"GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IS": 26, "IT": 27,
+	"JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LI": 21, "LT": 20, "LU": 20,
+	"LV": 21, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MR": 27, "MT": 31,
+	"MU": 30, "NL": 18, "NO": 15, "PK": 24, "PL": 28, "PS": 29, "PT": 25,
+	"QA": 29, "RO": 24, "RS": 22, "SA": 24, "SE": 24, "SI": 19, "SK": 24,
+	"SM": 27, "TN": 24, "TR": 26, "UA": 29, "VG": 24, "XK": 20,
+}
+
+// IBAN removes spaces, upper-cases the value and verifies the country length
+// and the mod-97 checksum. The result is in electronic form ("DE89370400440532013000").
+func IBAN(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '-':
+		case c >= 'a' && c <= 'z':
+			b.WriteByte(c - 'a' + 'A')
+		case c >= 'A' && c <= 'Z' || isDigit(c):
+			b.WriteByte(c)
+		default:
+			return "", invalid(ErrIBAN, "unexpected character "+string(c))
+		}
+	}
+	iban := b.String()
+	if len(iban) < 5 {
+		return "", invalid(ErrIBAN, "too short")
+	}
+	want, ok := ibanLengths[iban[:2]]
+	if !ok {
+		return "", invalid(ErrIBAN, "unknown country "+iban[:2])
+	}
+	if len(iban) != want {
+		return "", invalid(ErrIBAN, "wrong length for "+iban[:2])
+	}
+	if !isDigit(iban[2]) || !isDigit(iban[3]) {
+		return "", invalid(ErrIBAN, "check digits must be numeric")
+	}
+	rearranged := iban[4:] + iban[:4]
+	rem := 0
+	for i := 0; i < len(rearranged); i++ {
+		c := rearranged[i]
+		if isDigit(c) {
+			rem = (rem*10 + int(c-'0')) % 97
+		} else {
+			v := int(c-'A') + 10
+			rem = (rem*100 + v) % 97
+		}
+	}
+	if rem != 1 {
+		return "", invalid(ErrIBAN, "checksum mismatch")
+	}
+	return iban, nil
+}
+
+// FormatIBAN renders a valid IBAN in print form, in groups of four.
+func FormatIBAN(s string) (string, error) {
+	iban, err := IBAN(s)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for i := 0; i < len(iban); i += 4 {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		end := i + 4
+		if end > len(iban) {
+			end = len(iban)
+		}
+		b.WriteString(iban[i:end])
+	}
+	return b.String(), nil
+}
+
+// IsIBAN reports whether s is a valid IBAN.
+func IsIBAN(s string) bool {
+	_, err := IBAN(s)
+	return err == nil
+}
+
+// --- URL ---
+
+// URL requires an absolute http or https URL with a host and returns it with
+// a lower-cased scheme and host, default ports removed and an empty path
+// written as "/".
+func URL(s string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return "", invalid(ErrURL, "unparsable")
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", invalid(ErrURL, "scheme must be http or https")
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return "", invalid(ErrURL, "missing host")
+	}
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String(), nil
+}
+
+// IsURL reports whether s is an absolute http or https URL.
+func IsURL(s string) bool {
+	_, err := URL(s)
+	return err == nil
+}
+
+// --- UUID ---
+
+// UUID accepts hyphenated, bare, braced and "urn:uuid:" forms and returns the
+// lower-case hyphenated form.
+func UUID(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.ToLower(s), "urn:uuid:")
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "{"), "}")
+	hex := strings.ReplaceAll(s, "-", "")
+	if len(hex) != 32 || (len(s) != 32 && len(s) != 36) {
+		return "", invalid(ErrUUID, "wrong length")
+	}
+	if len(s) == 36 && (s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-') {
+		return "", invalid(ErrUUID, "misplaced hyphens")
+	}
+	for i := 0; i < len(hex); i++ {
+		c := hex[i]
+		if !isDigit(c) && (c < 'a' || c > 'f') {
+			return "", invalid(ErrUUID, "non-hex character")
+		}
+	}
+	return hex[:8] + "-" + hex[8:12] + "-" + hex[12:16] + "-" + hex[16:20] + "-" + hex[20:], nil
+}
+
+// IsUUID reports whether s is a UUID in any accepted form.
+func IsUUID(s string) bool {
+	_, err := UUID(s)
+	return err == nil
+}
@@ -0,0 +1,115 @@
+package validate
+
+import (
+	"errors"
+	"testing"
+)
+
+type normalizeCase struct {
+	in   string
+	want string
+	err  error
+}
+
+func runCases(t *testing.T, name string, fn func(string) (string, error), cases []normalizeCase) {
+	t.Helper()
+	for _, tt := range cases {
+		got, err := fn(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s(%q) error = %v, want %v", name, tt.in, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s(%q) = %q, want %q", name, tt.in, got, tt.want)
+		}
+		if err == nil {
+			// Normalized values normalize to themselves.
+			if again, err := fn(got); err != nil || again != got {
+				t.Errorf("%s(%q) = %q, %v; not idempotent", name, got, again, err)
+			}
+		}
+	}
+}
+
+func TestEmail(t *testing.T) {
+	runCases(t, "Email", Email, []normalizeCase{
+		{" Jane.Doe@Example.COM ", "Jane.Doe@example.com", nil},
+		{"a+tag@sub.example.org", "a+tag@sub.example.org", nil},
+		{"", "", ErrEmail},
+		{"@example.com", "", ErrEmail},
+		{"jane@", "", ErrEmail},
+		{"jane@localhost", "", ErrEmail},
+		{".jane@example.com", "", ErrEmail},
+		{"ja..ne@example.com", "", ErrEmail},
+		{"ja ne@example.com", "", ErrEmail},
+		{"jane@-example.com", "", ErrEmail},
+		{"jane@exa_mple.com", "", ErrEmail},
+	})
+}
+
+func TestPhone(t *testing.T) {
+	de := func(s string) (string, error) { return Phone(s, "DE") }
+	runCases(t, "Phone", de, []normalizeCase{
+		{"+49 151 1234 5678", "+4915112345678", nil},
+		{"0151 / 1234-5678", "+4915112345678", nil},
+		{"0049 (0)30 123456", "+49030123456", nil},
+		{"+1 (415) 555-2671", "+14155552671", nil},
+		{"12ab", "", ErrPhone},
+		{"+49 12", "", ErrPhone},
+		{"++49 151 12345678", "", ErrPhone},
+	})
+	if got, err := Phone("415 555 2671", "us"); err != nil || got != "+14155552671" {
+		t.Errorf("Phone(US national) = %q, %v", got, err)
+	}
+	if _, err := Phone("0151 12345678", "XX"); !errors.Is(err, ErrPhone) {
+		t.Errorf("unknown default country error = %v", err)
+	}
+	for s, want := range map[string]bool{"+4915112345678": true, "4915112345678": false, "+0123456789": false, "+49151x": false} {
+		if IsE164(s) != want {
+			t.Errorf("IsE164(%q) = %v", s, !want)
+		}
+	}
+}
+
+func TestIBAN(t *testing.T) {
+	runCases(t, "IBAN", IBAN, []normalizeCase{
+		{"DE89 3704 0044 0532 0130 00", "DE89370400440532013000", nil},
+		{"gb82-west-1234-5698-7654-32", "GB82WEST12345698765432", nil},
+		{"NO9386011117947", "NO9386011117947", nil},
+		{"DE88370400440532013000", "", ErrIBAN},
+		{"DE8937040044053201300", "", ErrIBAN},
+		{"XX89370400440532013000", "", ErrIBAN},
+		{"DEAB370400440532013000", "", ErrIBAN},
+		{"DE89_370400440532013000", "", ErrIBAN},
+		{"DE8", "", ErrIBAN},
+	})
+	if got, _ := FormatIBAN("de89370400440532013000"); got != "DE89 3704 0044 0532 0130 00" {
+		t.Errorf("FormatIBAN = %q", got)
+	}
+}
+
+func TestURL(t *testing.T) {
+	runCases(t, "URL", URL, []normalizeCase{
+		{"HTTPS://Example.COM", "https://example.com/", nil},
+		{"http://example.com:80/a?b=1#c", "http://example.com/a?b=1#c", nil},
+		{"https://example.com:8443/x", "https://example.com:8443/x", nil},
+		{"http://[::1]:8080/", "http://[::1]:8080/", nil},
+		{"ftp://example.com", "", ErrURL},
+		{"example.com", "", ErrURL},
+		{"https://", "", ErrURL},
+		{"http://exa mple.com", "", ErrURL},
+	})
+}
+
+func TestUUID(t *testing.T) {
+	const want = "123e4567-e89b-12d3-a456-426614174000"
+	runCases(t, "UUID", UUID, []normalizeCase{
+		{"123E4567-E89B-12D3-A456-426614174000", want, nil},
+		{"123e4567e89b12d3a456426614174000", want, nil},
+		{"{123e4567-e89b-12d3-a456-426614174000}", want, nil},
+		{"urn:uuid:123e4567-e89b-12d3-a456-426614174000", want, nil},
+		{"123e4567-e89b-12d3-a456-42661417400", "", ErrUUID},
+		{"123e4567e-89b-12d3-a456-426614174000", "", ErrUUID},
+		{"123e4567-e89b-12d3-a456-42661417400g", "", ErrUUID},
+	})
+}
@@ -0,0 +1,111 @@
+// Package githubrepos is a complete OAuth-backed integration node. It lists
+// the repositories of the signed-in GitHub user and shows the pattern most
+// SaaS integrations follow:
+//
+//   - read the user's access token with GetOAuthToken
+//   - follow the provider's pagination (here the "Link" response header)
+//   - on 401, refresh the token once and retry the same page
+//   - stream progress while pages are collected
+//
+// Register githubrepos.Node{} in main.go to ship it with your pack.
+package githubrepos
+
+import (
+	"strconv"
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+const provider = "github"
+
+type Node struct{}
+
+func (Node) Define() sdk.NodeDefinition {
+	def := sdk.NewNodeDefinition()
+	def.Name = "github_list_repos_go"
+	def.FriendlyName = "List GitHub Repositories (Go)"
+	def.Description = "Lists the repositories of the connected GitHub account"
+	def.Category = "Integrations/GitHub"
+	def.AllowHTTP("api.github.com")
+	def.AddPermission("oauth")
+	def.AddPermission("streaming")
+
+	def.AddPin(sdk.InputPin("exec", "Execute", "Trigger execution", "Exec"))
+	def.AddPin(sdk.InputPin("max_pages", "Max Pages", "Stop after this many pages (0 = all)", "I64").WithDefault("0"))
+
+	def.AddPin(sdk.OutputPin("exec_out", "Done", "Fires once all pages were fetched", "Exec"))
+	def.AddPin(sdk.OutputPin("repos", "Repositories", "Repository objects as returned by the API", "Struct").
+		WithValueType("Array"))
+	def.AddPin(sdk.OutputPin("count", "Count", "Number of repositories", "I64"))
+
+	return def
+}
+
+func (Node) Run(ctx *sdk.Context) sdk.ExecutionResult {
+	if !ctx.HasOAuthToken(provider) {
+		return ctx.Fail("GitHub is not connected: authorize the GitHub provider first")
+	}
+	token, err := ctx.Host().GetOAuthToken(provider)
+	if err != nil {
+		return ctx.Fail(err.Error())
+	}
+	maxPages := ctx.GetI64("max_pages", 0)
+
+	var repos []string
+	url := "https://api.github.com/user/repos?per_page=100"
+	for page := int64(1); url != ""; page++ {
+		if maxPages > 0 && page > maxPages {
+			break
+		}
+
+		resp, err := fetchPage(ctx, url, token)
+		if err == nil && resp.Status == 401 {
+			// Access tokens expire; refresh once and retry the same page.
+			ctx.Debug("Access token rejected, refreshing")
+			if token, err = ctx.Host().RefreshOAuthToken(provider); err != nil {
+				return ctx.Fail("GitHub authorization expired: reconnect the GitHub provider")
+			}
+			resp, err = fetchPage(ctx, url, token)
+		}
+		if err != nil {
+			return ctx.Fail("request to GitHub failed: " + err.Error())
+		}
+		if !resp.OK() {
+			return ctx.Fail("GitHub returned HTTP " + strconv.Itoa(resp.Status) + ": " + resp.Body)
+		}
+
+		items := sdk.JSONArrayItems(resp.Body)
+		repos = append(repos, items...)
+		ctx.StreamText("Fetched page " + strconv.FormatInt(page, 10) + " (" + strconv.Itoa(len(repos)) + " repositories)")
+
+		url = nextLink(resp.Header("Link"))
+	}
+
+	ctx.SetOutput("repos", "["+strings.Join(repos, ",")+"]")
+	ctx.SetOutput("count", strconv.Itoa(len(repos)))
+	return ctx.Success()
+}
+
+func fetchPage(ctx *sdk.Context, url, token string) (sdk.HTTPResponse, error) {
+	headers := `{"Authorization":` + sdk.JSONString("Bearer "+token) +
+		`,"Accept":"application/vnd.github+json","User-Agent":"flow-like-wasm-node"}`
+	return ctx.Host().HTTPFetch(sdk.HTTPGet, url, headers, "")
+}
+
+// nextLink extracts the rel="next" URL from an RFC 8288 Link header, e.g.
+// `<https://api.github.com/user/repos?page=2>; rel="next", <...>; rel="last"`.
+func nextLink(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		for _, param := range segments[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(segments[0]), "<>")
+			}
+		}
+	}
+	return ""
+}
@@ -1,19 +1,18 @@
-// HTTP Request Node - Demonstrates declaring HTTP permissions
+// Package httpget is an HTTP request node that demonstrates declaring the
+// "http" permission so the runtime grants the module outbound network access.
 //
-// This example shows how to declare the "http" permission so the runtime
-// grants your WASM node access to make outbound HTTP requests.
-// Copy this pattern into your main.go when you need network access.
-
-package main
+// Register httpget.Node{} in main.go to ship it with your pack.
+package httpget
 
 import (
-	"encoding/json"
+	"errors"
 
 	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
 )
 
-// buildHTTPGetDefinition creates the node definition with the "http" permission.
-func buildHTTPGetDefinition() sdk.NodeDefinition {
+type Node struct{}
+
+func (Node) Define() sdk.NodeDefinition {
 	def := sdk.NewNodeDefinition()
 	def.Name = "http_get_request_go"
 	def.FriendlyName = "HTTP GET Request (Go)"
@@ -32,8 +31,7 @@ func buildHTTPGetDefinition() sdk.NodeDefinition {
 	return def
 }
 
-// runHTTPGet executes the HTTP GET request.
-func runHTTPGet(ctx *sdk.Context) sdk.ExecutionResult {
+func (Node) Run(ctx *sdk.Context) sdk.ExecutionResult {
 	url := ctx.GetString("url", "https://httpbin.org/get")
 	headers := ctx.GetString("headers_json", "{}")
 
@@ -41,26 +39,18 @@ func runHTTPGet(ctx *sdk.Context) sdk.ExecutionResult {
 
 	// Method 0 = GET.  The host checks the "http" capability before
 	// executing the request.
-	ok := sdk.HTTPRequest(0, url, headers, "")
+	err := ctx.Host().HTTPRequest(0, url, headers, "")
 
-	if ok {
+	switch {
+	case err == nil:
 		ctx.Info("HTTP capability granted — request dispatched")
-	} else {
-		ctx.Error("HTTP capability denied — is the 'http' permission declared?")
+		ctx.SetOutput("success", "true")
+	case errors.Is(err, sdk.ErrPermissionDenied):
+		ctx.Error("HTTP capability denied — is the 'http' permission declared? " + err.Error())
+		ctx.SetOutput("success", "false")
+	default:
+		ctx.Error(err.Error())
+		ctx.SetOutput("success", "false")
 	}
-
-	ctx.SetOutput("success", func() string {
-		if ok {
-			return "true"
-		}
-		return "false"
-	}())
 	return ctx.Success()
 }
-
-// Example: serialise the definition (for reference — wire this up in your main get_node/run exports).
-func exampleSerialize() int64 {
-	def := buildHTTPGetDefinition()
-	b, _ := json.Marshal(def)
-	return sdk.PackString(string(b))
-}
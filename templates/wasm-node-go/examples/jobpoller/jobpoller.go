@@ -0,0 +1,114 @@
+// Package jobpoller demonstrates a long-running node that waits for an
+// external job without blocking the runtime.
+//
+// On the first invocation the node submits a (simulated) job and stores a
+// checkpoint in the cache. It then returns a pending result, which tells the
+// runtime to invoke the node again later. Every resumed invocation loads the
+// checkpoint, polls the job once and either stays pending or finishes.
+//
+// Register jobpoller.Node{} in main.go to ship it with your pack.
+package jobpoller
+
+import (
+	"strconv"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+type Node struct{}
+
+func (Node) Define() sdk.NodeDefinition {
+	def := sdk.NewNodeDefinition()
+	def.Name = "external_job_poller_go"
+	def.FriendlyName = "External Job Poller (Go)"
+	def.Description = "Submits a job to an external system and waits for it to complete"
+	def.Category = "Custom/WASM/Jobs"
+	def.LongRunning = true
+	def.AddPermission("streaming")
+
+	def.AddPin(sdk.InputPin("exec", "Execute", "Trigger execution", "Exec"))
+	def.AddPin(sdk.InputPin("steps", "Steps", "Polls until the simulated job completes", "I64").WithDefault("5"))
+
+	def.AddPin(sdk.OutputPin("exec_out", "Done", "Fires once the job has completed", "Exec"))
+	def.AddPin(sdk.OutputPin("job_id", "Job ID", "Identifier of the submitted job", "String"))
+	def.AddPin(sdk.OutputPin("polls", "Polls", "Number of polls it took", "I64"))
+
+	return def
+}
+
+// checkpoint is the state persisted between invocations of the same run.
+type checkpoint struct {
+	jobID string
+	polls int64
+}
+
+// checkpointKey scopes the checkpoint to this node instance and run, so
+// concurrent runs of the same board never see each other's state.
+func checkpointKey(ctx *sdk.Context) string {
+	return "jobpoller:" + ctx.RunID() + ":" + ctx.NodeID()
+}
+
+func loadCheckpoint(ctx *sdk.Context) (checkpoint, bool) {
+	key := checkpointKey(ctx)
+	if !ctx.CacheHas(key + ":job") {
+		return checkpoint{}, false
+	}
+	polls, _ := strconv.ParseInt(ctx.CacheGet(key+":polls"), 10, 64)
+	return checkpoint{jobID: ctx.CacheGet(key + ":job"), polls: polls}, true
+}
+
+func saveCheckpoint(ctx *sdk.Context, cp checkpoint) {
+	key := checkpointKey(ctx)
+	ctx.CacheSet(key+":job", cp.jobID)
+	ctx.CacheSet(key+":polls", strconv.FormatInt(cp.polls, 10))
+}
+
+func clearCheckpoint(ctx *sdk.Context) {
+	key := checkpointKey(ctx)
+	ctx.CacheDelete(key + ":job")
+	ctx.CacheDelete(key + ":polls")
+}
+
+// submitJob stands in for the call that starts work on the external system.
+func submitJob(ctx *sdk.Context) string {
+	return "job-" + strconv.FormatInt(ctx.Random()&0xffffff, 16)
+}
+
+// pollJob stands in for a status request; the simulated job finishes after
+// the configured number of polls.
+func pollJob(cp checkpoint, steps int64) (done bool, progress float32) {
+	if cp.polls >= steps {
+		return true, 1
+	}
+	return false, float32(cp.polls) / float32(steps)
+}
+
+func (Node) Run(ctx *sdk.Context) sdk.ExecutionResult {
+	steps := ctx.GetI64("steps", 5)
+	if steps < 1 {
+		steps = 1
+	}
+
+	cp, resumed := loadCheckpoint(ctx)
+	if !resumed {
+		cp = checkpoint{jobID: submitJob(ctx)}
+		ctx.Info("Submitted job " + cp.jobID)
+	}
+
+	cp.polls++
+	done, progress := pollJob(cp, steps)
+	ctx.StreamProgress(progress, "Waiting for "+cp.jobID)
+
+	if !done {
+		// Persist before yielding: the next invocation starts from a fresh
+		// module state and only sees what was checkpointed.
+		saveCheckpoint(ctx, cp)
+		ctx.SetPending(true)
+		return ctx.Finish()
+	}
+
+	clearCheckpoint(ctx)
+	ctx.SetOutput("job_id", sdk.JSONString(cp.jobID))
+	ctx.SetOutput("polls", strconv.FormatInt(cp.polls, 10))
+	return ctx.Success()
+}
@@ -0,0 +1,140 @@
+// Package rag is a retrieval-augmented generation pipeline in a single node:
+//
+//  1. chunk the document with textsplit
+//  2. embed the chunks with EmbedText and store them with VectorUpsert
+//  3. embed the question and retrieve the closest chunks with VectorSearch
+//  4. answer the question with ChatComplete, grounded in those chunks
+//
+// Real boards usually split indexing (1–2) and answering (3–4) into separate
+// nodes; they are combined here so the whole flow reads top to bottom.
+//
+// Register rag.Node{} in main.go to ship it with your pack.
+package rag
+
+import (
+	"strconv"
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+	"github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go/textsplit"
+)
+
+type Node struct{}
+
+func (Node) Define() sdk.NodeDefinition {
+	def := sdk.NewNodeDefinition()
+	def.Name = "rag_answer_go"
+	def.FriendlyName = "Answer From Document (Go)"
+	def.Description = "Indexes a document and answers a question using the most relevant passages"
+	def.Category = "AI/RAG"
+	def.AddPermission("models")
+	def.AddPermission("vector")
+	def.AddPermission("streaming")
+
+	def.AddPin(sdk.InputPin("exec", "Execute", "Trigger execution", "Exec"))
+	def.AddPin(sdk.InputPin("document", "Document", "Text to index", "String").WithDefault(`""`))
+	def.AddPin(sdk.InputPin("question", "Question", "Question to answer", "String").WithDefault(`""`))
+	def.AddPin(sdk.InputPin("embedding_bit", "Embedding Model", "Bit used to embed chunks and the question", "Struct"))
+	def.AddPin(sdk.InputPin("chat_bit", "Chat Model", "Bit used to write the answer", "Struct"))
+	def.AddPin(sdk.InputPin("collection", "Collection", "Vector collection to store chunks in", "String").
+		WithDefault(`"rag_example"`))
+	def.AddPin(sdk.InputPin("top_k", "Top K", "Number of passages passed to the model", "I64").WithDefault("4"))
+
+	def.AddPin(sdk.OutputPin("exec_out", "Done", "Fires once the answer is ready", "Exec"))
+	def.AddPin(sdk.OutputPin("answer", "Answer", "Model answer", "String"))
+	def.AddPin(sdk.OutputPin("sources", "Sources", "Passages the answer is based on", "String").
+		WithValueType("Array"))
+
+	return def
+}
+
+func (Node) Run(ctx *sdk.Context) sdk.ExecutionResult {
+	document := ctx.GetString("document", "")
+	question := ctx.GetString("question", "")
+	collection := ctx.GetString("collection", "rag_example")
+	topK := int(ctx.GetI64("top_k", 4))
+	embeddingBit, ok := ctx.GetInput("embedding_bit")
+	if !ok {
+		return ctx.Fail("no embedding model connected")
+	}
+	chatBit, ok := ctx.GetInput("chat_bit")
+	if !ok {
+		return ctx.Fail("no chat model connected")
+	}
+	if strings.TrimSpace(question) == "" {
+		return ctx.Fail("question is empty")
+	}
+
+	// 1. Chunk.
+	chunks := textsplit.Split(document, textsplit.Options{ChunkSize: 800, Overlap: 100})
+	ctx.StreamProgress(0.1, "Split document into "+strconv.Itoa(len(chunks))+" chunks")
+
+	// 2. Embed and index. Vectors come back in the same order as the texts.
+	if len(chunks) > 0 {
+		embedded, err := ctx.Host().EmbedText(embeddingBit, jsonStrings(chunks))
+		if err != nil {
+			return ctx.Fail("embedding failed: " + err.Error())
+		}
+		vectors := sdk.JSONArrayItems(embedded)
+		if len(vectors) != len(chunks) {
+			return ctx.Fail("embedding failed: expected " + strconv.Itoa(len(chunks)) +
+				" vectors, got " + strconv.Itoa(len(vectors)))
+		}
+		records := make([]sdk.VectorRecord, len(chunks))
+		for i, chunk := range chunks {
+			records[i] = sdk.VectorRecord{
+				ID:     ctx.RunID() + "-" + strconv.Itoa(i),
+				Vector: vectors[i],
+				Text:   chunk,
+			}
+		}
+		if err := ctx.Host().VectorUpsert(collection, records); err != nil {
+			return ctx.Fail("could not store chunks in collection " + collection + ": " + err.Error())
+		}
+	}
+	ctx.StreamProgress(0.5, "Indexed document")
+
+	// 3. Retrieve.
+	embedded, err := ctx.Host().EmbedText(embeddingBit, jsonStrings([]string{question}))
+	questionVectors := sdk.JSONArrayItems(embedded)
+	if err != nil || len(questionVectors) != 1 {
+		return ctx.Fail("embedding the question failed")
+	}
+	matches, err := ctx.Host().VectorSearch(collection, questionVectors[0], topK)
+	if err != nil {
+		return ctx.Fail("retrieval failed: " + err.Error())
+	}
+	ctx.StreamProgress(0.7, "Retrieved "+strconv.Itoa(len(matches))+" passages")
+
+	// 4. Answer.
+	var passages strings.Builder
+	sources := make([]string, len(matches))
+	for i, m := range matches {
+		passages.WriteString("[" + strconv.Itoa(i+1) + "] " + m.Text + "\n\n")
+		sources[i] = m.Text
+	}
+	resp, err := ctx.Host().ChatComplete(chatBit, sdk.ChatRequest{
+		Messages: []sdk.ChatMessage{
+			{Role: sdk.RoleSystem, Content: "Answer the question using only the numbered passages. " +
+				"Cite passages like [1]. If the passages do not contain the answer, say so."},
+			{Role: sdk.RoleUser, Content: "Passages:\n\n" + passages.String() + "Question: " + question},
+		},
+		Temperature: 0.2,
+	})
+	if err != nil {
+		return ctx.Fail("chat completion failed: " + err.Error())
+	}
+	ctx.StreamProgress(1, "Answered")
+
+	ctx.SetOutput("answer", sdk.JSONString(resp.Content))
+	ctx.SetOutput("sources", jsonStrings(sources))
+	return ctx.Success()
+}
+
+func jsonStrings(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = sdk.JSONString(item)
+	}
+	return "[" + strings.Join(quoted, ",") + "]"
+}
@@ -0,0 +1,58 @@
+// Package router shows a node with several exec outputs, of which exactly one
+// fires per run.
+//
+// ctx.Success() always activates "exec_out", which is right for nodes with a
+// single happy path. Branching nodes instead call ctx.ActivateExec for the
+// chosen pin and then ctx.Finish(). Routing to an "error" pin is different
+// from ctx.Fail: the run continues along the error branch, whereas Fail
+// aborts the node and surfaces the error to the runtime.
+//
+// Register router.Node{} in main.go to ship it with your pack.
+package router
+
+import (
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+type Node struct{}
+
+func (Node) Define() sdk.NodeDefinition {
+	def := sdk.NewNodeDefinition()
+	def.Name = "cache_lookup_router_go"
+	def.FriendlyName = "Cache Lookup Router (Go)"
+	def.Description = "Looks up a key in the cache and continues on the matching branch"
+	def.Category = "Control/Branch"
+
+	def.AddPin(sdk.InputPin("exec", "Execute", "Trigger execution", "Exec"))
+	def.AddPin(sdk.InputPin("key", "Key", "Cache key to look up", "String").WithDefault(`""`))
+
+	def.AddPin(sdk.OutputPin("success", "Found", "Fires when the key exists", "Exec"))
+	def.AddPin(sdk.OutputPin("not_found", "Not Found", "Fires when the key is missing", "Exec"))
+	def.AddPin(sdk.OutputPin("error", "Error", "Fires when the lookup is invalid", "Exec"))
+	def.AddPin(sdk.OutputPin("value", "Value", "Cached value, set on Found", "String"))
+	def.AddPin(sdk.OutputPin("message", "Message", "Reason, set on Error", "String"))
+
+	return def
+}
+
+func (Node) Run(ctx *sdk.Context) sdk.ExecutionResult {
+	key := ctx.GetString("key", "")
+
+	// Each branch sets the outputs it owns and skips the rest, so output
+	// verification (registry.SetVerifyOutputs) accepts every path.
+	switch {
+	case key == "":
+		ctx.SetOutput("message", sdk.JSONString("key must not be empty"))
+		ctx.SkipOutput("value")
+		ctx.ActivateExec("error")
+	case ctx.CacheHas(key):
+		ctx.SetOutput("value", sdk.JSONString(ctx.CacheGet(key)))
+		ctx.SkipOutput("message")
+		ctx.ActivateExec("success")
+	default:
+		ctx.SkipOutput("value").SkipOutput("message")
+		ctx.ActivateExec("not_found")
+	}
+
+	return ctx.Finish()
+}
@@ -1,5 +1,9 @@
 // Flow-Like WASM Node Template (Go / TinyGo)
 //
+// This module ships a small text node pack. Every node lives in its own
+// package under nodes/ and implements sdk.NodeHandler; main.go only registers
+// them and wires the registry to the exports the runtime calls.
+//
 // Build:
 //
 //	tinygo build -o node.wasm -target wasm -no-debug ./
@@ -8,81 +12,91 @@
 package main
 
 import (
-	"strconv"
-	"strings"
-
 	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
-)
-
-// get_node returns the node definition as a packed i64 (ptr<<32|len).
-//
-//export get_node
-func getNode() int64 {
-	def := sdk.NewNodeDefinition()
-	def.Name = "my_custom_node_go"
-	def.FriendlyName = "My Custom Node (Go)"
-	def.Description = "A template WASM node built with Go / TinyGo"
-	def.Category = "Custom/WASM"
-	def.AddPermission("streaming")
 
-	def.AddPin(sdk.InputPin("exec", "Execute", "Trigger execution", "Exec"))
-	def.AddPin(sdk.InputPin("input_text", "Input Text", "Text to process", "String").WithDefault(`""`))
-	def.AddPin(sdk.InputPin("multiplier", "Multiplier", "Number of times to repeat", "I64").WithDefault("1"))
-
-	def.AddPin(sdk.OutputPin("exec_out", "Done", "Execution complete", "Exec"))
-	def.AddPin(sdk.OutputPin("output_text", "Output Text", "Processed text", "String"))
-	def.AddPin(sdk.OutputPin("char_count", "Character Count", "Number of characters in output", "I64"))
+	"github.com/example/flow-like-wasm-node/nodes/repeat"
+	"github.com/example/flow-like-wasm-node/nodes/reverse"
+	"github.com/example/flow-like-wasm-node/nodes/trim"
+	"github.com/example/flow-like-wasm-node/nodes/uppercase"
+	"github.com/example/flow-like-wasm-node/nodes/wordcount"
+)
 
-	return sdk.SerializeDefinition(def)
-}
+// registry holds every node shipped by this module. Add new nodes here.
+var registry = sdk.NewRegistry(
+	sdk.HandleErrors(repeat.Node{}),
+	uppercase.Node{},
+	reverse.Node{},
+	wordcount.Node{},
+	trim.Node{},
+).SetManifest(sdk.Manifest{
+	Module:  "com.example.custom-node-go",
+	Version: "0.1.0",
+	Author:  "Your Name",
+})
 
 // get_nodes returns all node definitions as a packed i64 (ptr<<32|len).
 //
 //export get_nodes
 func getNodes() int64 {
-	def := sdk.NewNodeDefinition()
-	def.Name = "my_custom_node_go"
-	def.FriendlyName = "My Custom Node (Go)"
-	def.Description = "A template WASM node built with Go / TinyGo"
-	def.Category = "Custom/WASM"
-	def.AddPermission("streaming")
-
-	def.AddPin(sdk.InputPin("exec", "Execute", "Trigger execution", "Exec"))
-	def.AddPin(sdk.InputPin("input_text", "Input Text", "Text to process", "String").WithDefault(`""`))
-	def.AddPin(sdk.InputPin("multiplier", "Multiplier", "Number of times to repeat", "I64").WithDefault("1"))
-
-	def.AddPin(sdk.OutputPin("exec_out", "Done", "Execution complete", "Exec"))
-	def.AddPin(sdk.OutputPin("output_text", "Output Text", "Processed text", "String"))
-	def.AddPin(sdk.OutputPin("char_count", "Character Count", "Number of characters in output", "I64"))
+	return registry.GetNodes()
+}
 
-	return sdk.PackResult("[" + def.ToJSON() + "]")
+// get_node is kept for hosts that predate get_nodes; they load only the first
+// registered node.
+//
+//export get_node
+func getNode() int64 {
+	return registry.GetNode()
 }
 
-// run is the main execution function, called every time the node is triggered.
+// run is the main execution function, called every time one of the nodes is
+// triggered. The registry dispatches on the node name in the input.
 //
 //export run
 func run(ptr uint32, length uint32) int64 {
-	input := sdk.ParseInput(ptr, length)
-	ctx := sdk.NewContext(input)
-
-	inputText := ctx.GetString("input_text", "")
-	multiplier := ctx.GetI64("multiplier", 1)
+	return registry.Run(ptr, length)
+}
 
-	ctx.Debug("Processing: '" + inputText + "' x " + strconv.FormatInt(multiplier, 10))
+// get_manifest returns the module manifest (name, version, author, commit,
+// node changelogs). `flowlike-gen sign` embeds it in a signed section of the
+// built module.
+//
+//export get_manifest
+func getManifest() int64 {
+	return registry.GetManifest()
+}
 
-	var b strings.Builder
-	for i := int64(0); i < multiplier; i++ {
-		b.WriteString(inputText)
-	}
-	outputText := b.String()
-	charCount := len(outputText)
+// get_pack returns the pack manifest: the pack's name, its nodes and the
+// settings and permissions shared by all of them.
+//
+//export get_pack
+func getPack() int64 {
+	return registry.GetPack()
+}
 
-	ctx.StreamText("Generated " + strconv.Itoa(charCount) + " characters")
+// check_nodes dry-runs every node and reports declared-vs-used mismatches
+// (missing permissions, reads of undeclared pins). Tooling calls it before
+// publishing; the runtime never does.
+//
+//export check_nodes
+func checkNodes() int64 {
+	return registry.CheckNodes()
+}
 
-	ctx.SetOutput("output_text", sdk.JSONString(outputText))
-	ctx.SetOutput("char_count", strconv.Itoa(charCount))
+// on_init runs once per module instance before the first run, so nodes can
+// build expensive state up front (see sdk.Initializer and Registry.OnInit).
+//
+//export on_init
+func onInit() int32 {
+	return registry.InitInstance()
+}
 
-	return sdk.SerializeResult(ctx.Success())
+// on_shutdown runs when the host recycles the instance, giving nodes a chance
+// to flush buffers (see sdk.Shutdowner and Registry.OnShutdown).
+//
+//export on_shutdown
+func onShutdown() int32 {
+	return registry.ShutdownInstance()
 }
 
 func main() {}
@@ -0,0 +1,57 @@
+// Package repeat implements the "Repeat Text" node.
+package repeat
+
+import (
+	"strconv"
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+type Node struct{}
+
+func (Node) Define() sdk.NodeDefinition {
+	def := sdk.NewNodeDefinition()
+	def.Name = "my_custom_node_go"
+	def.FriendlyName = "My Custom Node (Go)"
+	def.Description = "Repeats the input text a number of times"
+	def.Category = "Custom/WASM/Text"
+	def.AddPermission("streaming")
+
+	def.AddPin(sdk.InputPin("exec", "Execute", "Trigger execution", "Exec"))
+	def.AddPin(sdk.InputPin("input_text", "Input Text", "Text to process", "String").WithDefault(`""`))
+	def.AddPin(sdk.InputPin("multiplier", "Multiplier", "Number of times to repeat", "I64").WithDefault("1"))
+
+	def.AddPin(sdk.OutputPin("exec_out", "Done", "Execution complete", "Exec"))
+	def.AddPin(sdk.OutputPin("output_text", "Output Text", "Processed text", "String"))
+	def.AddPin(sdk.OutputPin("char_count", "Character Count", "Number of characters in output", "I64"))
+
+	return def
+}
+
+// Run uses the error-returning handler style: returning an error fails the
+// node with structured error info, returning nil finishes via exec_out.
+// main.go registers it through sdk.HandleErrors.
+func (Node) Run(ctx *sdk.Context) error {
+	inputText := ctx.GetString("input_text", "")
+	multiplier := ctx.GetI64("multiplier", 1)
+	if multiplier < 0 {
+		return sdk.NewError(sdk.ErrCodeInvalidInput, "multiplier must not be negative").WithPin("multiplier")
+	}
+
+	ctx.Debug("Processing: '" + inputText + "' x " + strconv.FormatInt(multiplier, 10))
+
+	var b strings.Builder
+	for i := int64(0); i < multiplier; i++ {
+		b.WriteString(inputText)
+	}
+	outputText := b.String()
+	charCount := len(outputText)
+
+	ctx.StreamText("Generated " + strconv.Itoa(charCount) + " characters")
+
+	ctx.SetOutput("output_text", sdk.JSONString(outputText))
+	ctx.SetOutput("char_count", strconv.Itoa(charCount))
+
+	return nil
+}
@@ -0,0 +1,33 @@
+// Package reverse implements the "Reverse Text" node.
+package reverse
+
+import (
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+type Node struct{}
+
+func (Node) Define() sdk.NodeDefinition {
+	def := sdk.NewNodeDefinition()
+	def.Name = "reverse_text_go"
+	def.FriendlyName = "Reverse Text (Go)"
+	def.Description = "Reverses the characters of a text"
+	def.Category = "Custom/WASM/Text"
+
+	def.AddPin(sdk.InputPin("exec", "Execute", "Trigger execution", "Exec"))
+	def.AddPin(sdk.InputPin("text", "Text", "Text to reverse", "String").WithDefault(`""`))
+
+	def.AddPin(sdk.OutputPin("exec_out", "Done", "Execution complete", "Exec"))
+	def.AddPin(sdk.OutputPin("result", "Result", "Reversed text", "String"))
+
+	return def
+}
+
+func (Node) Run(ctx *sdk.Context) sdk.ExecutionResult {
+	runes := []rune(ctx.GetString("text", ""))
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	ctx.SetOutput("result", sdk.JSONString(string(runes)))
+	return ctx.Success()
+}
@@ -0,0 +1,39 @@
+// Package trim implements the "Trim Text" node.
+package trim
+
+import (
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+type Node struct{}
+
+func (Node) Define() sdk.NodeDefinition {
+	def := sdk.NewNodeDefinition()
+	def.Name = "trim_text_go"
+	def.FriendlyName = "Trim Text (Go)"
+	def.Description = "Removes leading and trailing whitespace, or a custom cut set"
+	def.Category = "Custom/WASM/Text"
+
+	def.AddPin(sdk.InputPin("exec", "Execute", "Trigger execution", "Exec"))
+	def.AddPin(sdk.InputPin("text", "Text", "Text to trim", "String").WithDefault(`""`))
+	def.AddPin(sdk.InputPin("cutset", "Cut Set", "Characters to trim; whitespace when empty", "String").WithDefault(`""`))
+
+	def.AddPin(sdk.OutputPin("exec_out", "Done", "Execution complete", "Exec"))
+	def.AddPin(sdk.OutputPin("result", "Result", "Trimmed text", "String"))
+
+	return def
+}
+
+func (Node) Run(ctx *sdk.Context) sdk.ExecutionResult {
+	text := ctx.GetString("text", "")
+	cutset := ctx.GetString("cutset", "")
+	if cutset == "" {
+		text = strings.TrimSpace(text)
+	} else {
+		text = strings.Trim(text, cutset)
+	}
+	ctx.SetOutput("result", sdk.JSONString(text))
+	return ctx.Success()
+}
@@ -0,0 +1,31 @@
+// Package uppercase implements the "Uppercase" node.
+package uppercase
+
+import (
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+type Node struct{}
+
+func (Node) Define() sdk.NodeDefinition {
+	def := sdk.NewNodeDefinition()
+	def.Name = "uppercase_go"
+	def.FriendlyName = "Uppercase (Go)"
+	def.Description = "Converts text to upper case"
+	def.Category = "Custom/WASM/Text"
+
+	def.AddPin(sdk.InputPin("exec", "Execute", "Trigger execution", "Exec"))
+	def.AddPin(sdk.InputPin("text", "Text", "Text to convert", "String").WithDefault(`""`))
+
+	def.AddPin(sdk.OutputPin("exec_out", "Done", "Execution complete", "Exec"))
+	def.AddPin(sdk.OutputPin("result", "Result", "Upper-cased text", "String"))
+
+	return def
+}
+
+func (Node) Run(ctx *sdk.Context) sdk.ExecutionResult {
+	text := ctx.GetString("text", "")
+	return ctx.Out("result", strings.ToUpper(text)).Done()
+}
@@ -0,0 +1,39 @@
+// Package wordcount implements the "Word Count" node.
+package wordcount
+
+import (
+	"strconv"
+	"strings"
+
+	sdk "github.com/TM9657/flow-like/libs/wasm-sdk/wasm-sdk-go"
+)
+
+type Node struct{}
+
+func (Node) Define() sdk.NodeDefinition {
+	def := sdk.NewNodeDefinition()
+	def.Name = "word_count_go"
+	def.FriendlyName = "Word Count (Go)"
+	def.Description = "Counts the words and lines of a text"
+	def.Category = "Custom/WASM/Text"
+
+	def.AddPin(sdk.InputPin("exec", "Execute", "Trigger execution", "Exec"))
+	def.AddPin(sdk.InputPin("text", "Text", "Text to analyze", "String").WithDefault(`""`))
+
+	def.AddPin(sdk.OutputPin("exec_out", "Done", "Execution complete", "Exec"))
+	def.AddPin(sdk.OutputPin("words", "Words", "Number of whitespace separated words", "I64"))
+	def.AddPin(sdk.OutputPin("lines", "Lines", "Number of lines", "I64"))
+
+	return def
+}
+
+func (Node) Run(ctx *sdk.Context) sdk.ExecutionResult {
+	text := ctx.GetString("text", "")
+	lines := 0
+	if text != "" {
+		lines = strings.Count(text, "\n") + 1
+	}
+	ctx.SetOutput("words", strconv.Itoa(len(strings.Fields(text))))
+	ctx.SetOutput("lines", strconv.Itoa(lines))
+	return ctx.Success()
+}